@@ -2,12 +2,15 @@ package main
 
 import (
 	"log"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"modbus-server/internal/config"
 	"modbus-server/internal/handlers"
+	"modbus-server/internal/security"
 	"modbus-server/internal/service"
+	"modbus-server/internal/simulator"
 )
 
 func main() {
@@ -29,6 +32,31 @@ func main() {
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
+	// Проверка адреса и аутентификации клиентов, журнал аудита записей
+	auditLog, err := security.NewAuditLog(cfg.AuditLogFile, cfg.AuditLogSize)
+	if err != nil {
+		log.Fatalf("Ошибка открытия журнала аудита: %v", err)
+	}
+	defer auditLog.Close()
+	router.Use(security.NewGuard(cfg, auditLog).Middleware())
+
+	tlsConfig, err := security.ServerTLSConfig(cfg)
+	if err != nil {
+		log.Fatalf("Ошибка настройки TLS: %v", err)
+	}
+
+	// Симулятор ПЛК запускается до сервиса, чтобы устройства подключились к нему при старте
+	var plcSimulator *simulator.Simulator
+	if cfg.SimulatorEnabled {
+		if !cfg.ModbusEnabled {
+			log.Printf("MODBUS_SIMULATOR включен при MODBUS_ENABLED=false: запросы не дойдут до симулятора")
+		}
+		plcSimulator = simulator.NewSimulator(cfg)
+		if err := plcSimulator.Start(); err != nil {
+			log.Fatalf("Ошибка запуска симулятора ПЛК: %v", err)
+		}
+	}
+
 	// Создаем сервисы
 	modbusService := service.NewModbusService(cfg)
 
@@ -37,12 +65,34 @@ func main() {
 
 	// Регистрируем маршруты
 	handler.RegisterRoutes(router)
+	handlers.NewAuditHandler(auditLog).RegisterRoutes(router)
+	if plcSimulator != nil {
+		handlers.NewSimulatorHandler(plcSimulator).RegisterRoutes(router)
+	}
 
 	// Запускаем сервер
 	log.Printf("Запуск Modbus сервера на порту %s", cfg.ServerPort)
-	log.Printf("Modbus: %v (Host: %s, Port: %d)", cfg.ModbusEnabled, cfg.ModbusHost, cfg.ModbusPort)
-	
-	if err := router.Run(":" + cfg.ServerPort); err != nil {
+	log.Printf("Modbus: %v (устройств: %d, по умолчанию: %s, пул: %d)", cfg.ModbusEnabled, len(cfg.Devices), cfg.DefaultDevice, cfg.PoolSize)
+	for _, device := range cfg.Devices {
+		log.Printf("Modbus устройство %s: %s, unit %d", device.Name, device.Address(), device.SlaveID)
+	}
+	log.Printf("Аутентификация: %s (клиентов: %d), разрешенные адреса: %v, TLS: %v, mTLS: %v",
+		cfg.AuthMode, len(cfg.AuthClients), cfg.AllowedIPs, tlsConfig != nil, cfg.TLSClientCAFile != "")
+	if cfg.AuthMode == config.AuthModeNone {
+		log.Printf("AUTH_MODE=none: запись в ПЛК доступна любому, кто достучится до порта %s", cfg.ServerPort)
+	}
+
+	server := &http.Server{
+		Addr:      ":" + cfg.ServerPort,
+		Handler:   router,
+		TLSConfig: tlsConfig,
+	}
+	if tlsConfig != nil {
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil {
 		log.Fatalf("Ошибка запуска сервера: %v", err)
 	}
 }
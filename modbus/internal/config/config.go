@@ -1,12 +1,44 @@
 package config
 
 import (
+	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
+// DefaultDeviceName имя устройства, собранного из MODBUS_HOST/MODBUS_PORT, если MODBUS_DEVICES не задан
+const DefaultDeviceName = "default"
+
+// Типы сигнала жизни сторожевого таймера
+const (
+	HeartbeatTypeCoil     = "coil"     // Coil переключается на каждом такте
+	HeartbeatTypeRegister = "register" // Holding register увеличивается на каждом такте
+)
+
+// Режимы аутентификации запросов к HTTP API
+const (
+	AuthModeNone   = "none"    // Без аутентификации
+	AuthModeAPIKey = "api_key" // Секрет клиента в заголовке X-API-Key
+	AuthModeHMAC   = "hmac"    // Подпись запроса HMAC-SHA256 секретом клиента
+)
+
+// DeviceConfig описывает ПЛК, к которому подключается сервер
+type DeviceConfig struct {
+	Name    string
+	Host    string
+	Port    int
+	SlaveID byte
+}
+
+// Address возвращает адрес устройства host:port
+func (d DeviceConfig) Address() string {
+	return fmt.Sprintf("%s:%d", d.Host, d.Port)
+}
+
 // Config содержит конфигурацию modbus сервера
 type Config struct {
 	// HTTP сервер
@@ -16,6 +48,45 @@ type Config struct {
 	ModbusEnabled bool
 	ModbusHost    string
 	ModbusPort    int
+	ModbusSlaveID int
+
+	// Устройства (ПЛК). Если MODBUS_DEVICES не задан, используется одно устройство из MODBUS_HOST/MODBUS_PORT
+	Devices       []DeviceConfig
+	DefaultDevice string // Устройство для запросов без device
+	PoolSize      int    // Количество соединений с каждым устройством
+
+	// Сторожевой таймер: сигнал жизни для ПЛК и аварийное выключение coils
+	HeartbeatRegister        string // Coil или holding register сигнала жизни на каждом устройстве, пусто - отключен
+	HeartbeatType            string // coil (переключается) или register (счетчик)
+	HeartbeatIntervalSeconds int
+	SafetyMaxOnMinutes       int // Coil, включенный через сервер и не подтвержденный повторно, выключается через это время; 0 - отключено
+
+	// Датчики присутствия автомобиля: дискретные входы, которые бэкенд передает через PUT /api/v1/modbus/sensors
+	SensorPollIntervalMs int    // Как часто опрашиваются дискретные входы
+	SensorDebounceMs     int    // Сколько новое значение должно держаться, чтобы считаться изменением
+	BackendEventsURL     string // Куда отправлять изменения датчиков, пусто - не отправлять
+	BackendEventsToken   string // Bearer токен для BackendEventsURL
+
+	// Симулятор ПЛК для разработки: Modbus TCP slave в памяти процесса
+	SimulatorEnabled        bool
+	SimulatorAddress        string  // Адрес, на котором слушает симулятор
+	SimulatorLatencyMs      int     // Задержка ответа
+	SimulatorJitterMs       int     // Случайная добавка к задержке
+	SimulatorExceptionRate  float64 // Доля запросов, на которые симулятор отвечает исключением
+	SimulatorDisconnectRate float64 // Доля запросов, на которые симулятор разрывает соединение
+
+	// Безопасность HTTP API: аутентификация клиентов, разрешенные адреса и TLS
+	AuthMode           string            // none, api_key или hmac
+	AuthClients        map[string]string // Идентификатор клиента -> секрет
+	AuthMaxSkewSeconds int               // Допустимое расхождение времени подписи HMAC
+	AllowedIPs         []string          // IP и подсети, с которых принимаются запросы, пусто - любые
+	TLSCertFile        string            // Сертификат сервера, пусто - HTTP без TLS
+	TLSKeyFile         string
+	TLSClientCAFile    string // CA клиентских сертификатов: если задан, сервер требует сертификат клиента (mTLS)
+
+	// Журнал аудита записей: каждый принятый и отклоненный запрос на запись
+	AuditLogFile string // JSON Lines файл журнала, пусто - только лог сервера
+	AuditLogSize int    // Сколько последних записей отдает GET /api/v1/modbus/audit
 
 	// Логирование
 	LogLevel string
@@ -33,12 +104,214 @@ func LoadConfig() (*Config, error) {
 		ModbusEnabled: getEnvBool("MODBUS_ENABLED", true),
 		ModbusHost:    getEnv("MODBUS_HOST", "localhost"),
 		ModbusPort:    getEnvInt("MODBUS_PORT", 502),
+		ModbusSlaveID: getEnvInt("MODBUS_SLAVE_ID", 1),
+		PoolSize:      getEnvInt("MODBUS_POOL_SIZE", 1),
 		LogLevel:      getEnv("LOG_LEVEL", "info"),
+
+		HeartbeatRegister:        getEnv("WATCHDOG_HEARTBEAT_REGISTER", ""),
+		HeartbeatType:            getEnv("WATCHDOG_HEARTBEAT_TYPE", HeartbeatTypeCoil),
+		HeartbeatIntervalSeconds: getEnvInt("WATCHDOG_HEARTBEAT_INTERVAL_SECONDS", 1),
+		SafetyMaxOnMinutes:       getEnvInt("SAFETY_MAX_ON_MINUTES", 5),
+
+		SensorPollIntervalMs: getEnvInt("SENSOR_POLL_INTERVAL_MS", 500),
+		SensorDebounceMs:     getEnvInt("SENSOR_DEBOUNCE_MS", 1000),
+		BackendEventsURL:     getEnv("BACKEND_EVENTS_URL", ""),
+		BackendEventsToken:   getEnv("BACKEND_EVENTS_TOKEN", ""),
+
+		AuthMode:           getEnv("AUTH_MODE", AuthModeNone),
+		AuthMaxSkewSeconds: getEnvInt("AUTH_MAX_SKEW_SECONDS", 300),
+		AllowedIPs:         splitList(getEnv("ALLOWED_IPS", "")),
+		TLSCertFile:        getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:         getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:    getEnv("TLS_CLIENT_CA_FILE", ""),
+		AuditLogFile:       getEnv("AUDIT_LOG_FILE", ""),
+		AuditLogSize:       getEnvInt("AUDIT_LOG_SIZE", 500),
+
+		SimulatorEnabled:        getEnvBool("MODBUS_SIMULATOR", false),
+		SimulatorAddress:        getEnv("MODBUS_SIMULATOR_ADDRESS", "127.0.0.1:1502"),
+		SimulatorLatencyMs:      getEnvInt("SIMULATOR_LATENCY_MS", 0),
+		SimulatorJitterMs:       getEnvInt("SIMULATOR_JITTER_MS", 0),
+		SimulatorExceptionRate:  getEnvFloat("SIMULATOR_EXCEPTION_RATE", 0),
+		SimulatorDisconnectRate: getEnvFloat("SIMULATOR_DISCONNECT_RATE", 0),
 	}
 
+	if cfg.PoolSize < 1 {
+		cfg.PoolSize = 1
+	}
+	if cfg.HeartbeatType != HeartbeatTypeCoil && cfg.HeartbeatType != HeartbeatTypeRegister {
+		return nil, fmt.Errorf("WATCHDOG_HEARTBEAT_TYPE: ожидается %s или %s, получено %q", HeartbeatTypeCoil, HeartbeatTypeRegister, cfg.HeartbeatType)
+	}
+	if cfg.HeartbeatIntervalSeconds < 1 {
+		cfg.HeartbeatIntervalSeconds = 1
+	}
+	if cfg.SafetyMaxOnMinutes < 0 {
+		cfg.SafetyMaxOnMinutes = 0
+	}
+	if cfg.SensorPollIntervalMs < 50 {
+		cfg.SensorPollIntervalMs = 50
+	}
+	if cfg.SensorDebounceMs < 0 {
+		cfg.SensorDebounceMs = 0
+	}
+
+	if err := validateSecurity(cfg); err != nil {
+		return nil, err
+	}
+
+	devices, err := parseDevices(getEnv("MODBUS_DEVICES", ""))
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 && cfg.SimulatorEnabled {
+		// Без MODBUS_DEVICES сервер работает с симулятором вместо MODBUS_HOST/MODBUS_PORT
+		host, port, err := net.SplitHostPort(cfg.SimulatorAddress)
+		if err != nil {
+			return nil, fmt.Errorf("MODBUS_SIMULATOR_ADDRESS: ожидается host:port, получено %q", cfg.SimulatorAddress)
+		}
+		portValue, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("MODBUS_SIMULATOR_ADDRESS: неверный порт %q", port)
+		}
+		cfg.ModbusHost = host
+		cfg.ModbusPort = portValue
+	}
+	if len(devices) == 0 {
+		devices = []DeviceConfig{{
+			Name:    DefaultDeviceName,
+			Host:    cfg.ModbusHost,
+			Port:    cfg.ModbusPort,
+			SlaveID: byte(cfg.ModbusSlaveID),
+		}}
+	}
+	cfg.Devices = devices
+	cfg.DefaultDevice = getEnv("MODBUS_DEFAULT_DEVICE", devices[0].Name)
+
 	return cfg, nil
 }
 
+// parseDevices разбирает список устройств вида "plc1=192.168.1.100:502/1,plc2=192.168.1.101:502/2"
+// Порт по умолчанию 502, unit ID (slave ID) по умолчанию 1
+func parseDevices(value string) ([]DeviceConfig, error) {
+	var devices []DeviceConfig
+	seen := make(map[string]bool)
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, address, ok := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("MODBUS_DEVICES: ожидается имя=host:port/unit, получено %q", item)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("MODBUS_DEVICES: устройство %q указано несколько раз", name)
+		}
+		seen[name] = true
+
+		device := DeviceConfig{Name: name, Port: 502, SlaveID: 1}
+
+		address, unit, hasUnit := strings.Cut(strings.TrimSpace(address), "/")
+		if hasUnit {
+			slaveID, err := strconv.Atoi(unit)
+			if err != nil || slaveID < 0 || slaveID > 247 {
+				return nil, fmt.Errorf("MODBUS_DEVICES: неверный unit ID устройства %q: %q", name, unit)
+			}
+			device.SlaveID = byte(slaveID)
+		}
+
+		host, port, hasPort := strings.Cut(address, ":")
+		if host == "" {
+			return nil, fmt.Errorf("MODBUS_DEVICES: не указан адрес устройства %q", name)
+		}
+		device.Host = host
+		if hasPort {
+			portValue, err := strconv.Atoi(port)
+			if err != nil {
+				return nil, fmt.Errorf("MODBUS_DEVICES: неверный порт устройства %q: %q", name, port)
+			}
+			device.Port = portValue
+		}
+
+		devices = append(devices, device)
+	}
+
+	return devices, nil
+}
+
+// validateSecurity разбирает клиентов API и проверяет настройки аутентификации, адресов и TLS
+func validateSecurity(cfg *Config) error {
+	clients, err := parseClients(getEnv("AUTH_CLIENTS", ""))
+	if err != nil {
+		return err
+	}
+	cfg.AuthClients = clients
+
+	switch cfg.AuthMode {
+	case AuthModeNone:
+	case AuthModeAPIKey, AuthModeHMAC:
+		if len(clients) == 0 {
+			return fmt.Errorf("AUTH_CLIENTS: для AUTH_MODE=%s нужен хотя бы один клиент", cfg.AuthMode)
+		}
+	default:
+		return fmt.Errorf("AUTH_MODE: ожидается %s, %s или %s, получено %q", AuthModeNone, AuthModeAPIKey, AuthModeHMAC, cfg.AuthMode)
+	}
+	if cfg.AuthMaxSkewSeconds < 1 {
+		cfg.AuthMaxSkewSeconds = 1
+	}
+
+	for _, allowed := range cfg.AllowedIPs {
+		if net.ParseIP(allowed) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(allowed); err != nil {
+			return fmt.Errorf("ALLOWED_IPS: ожидается IP или подсеть, получено %q", allowed)
+		}
+	}
+
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE и TLS_KEY_FILE задаются вместе")
+	}
+	if cfg.TLSClientCAFile != "" && cfg.TLSCertFile == "" {
+		return fmt.Errorf("TLS_CLIENT_CA_FILE требует TLS_CERT_FILE и TLS_KEY_FILE")
+	}
+
+	if cfg.AuditLogSize < 0 {
+		cfg.AuditLogSize = 0
+	}
+	return nil
+}
+
+// parseClients разбирает клиентов API вида "backend=secret1,ops=secret2"
+func parseClients(value string) (map[string]string, error) {
+	clients := make(map[string]string)
+	for _, item := range splitList(value) {
+		name, secret, ok := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || secret == "" {
+			return nil, fmt.Errorf("AUTH_CLIENTS: ожидается имя=секрет, получено %q", name)
+		}
+		if _, exists := clients[name]; exists {
+			return nil, fmt.Errorf("AUTH_CLIENTS: клиент %q указан несколько раз", name)
+		}
+		clients[name] = secret
+	}
+	return clients, nil
+}
+
+// splitList разбирает список через запятую без пустых элементов
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // getEnv получает переменную окружения или возвращает значение по умолчанию
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -57,6 +330,16 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvFloat получает переменную окружения как float64 или возвращает значение по умолчанию
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 // getEnvBool получает переменную окружения как bool или возвращает значение по умолчанию
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
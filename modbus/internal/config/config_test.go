@@ -0,0 +1,222 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDevices(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []DeviceConfig
+		wantErr  bool
+	}{
+		{
+			name:     "Empty list",
+			value:    "",
+			expected: nil,
+		},
+		{
+			name:  "Full address",
+			value: "plc1=192.168.1.100:1502/3",
+			expected: []DeviceConfig{
+				{Name: "plc1", Host: "192.168.1.100", Port: 1502, SlaveID: 3},
+			},
+		},
+		{
+			name:  "Default port and unit ID",
+			value: "plc1=192.168.1.100",
+			expected: []DeviceConfig{
+				{Name: "plc1", Host: "192.168.1.100", Port: 502, SlaveID: 1},
+			},
+		},
+		{
+			name:  "Several devices with spaces and empty items",
+			value: " plc1 = 10.0.0.1:502/1 ,, plc2=10.0.0.2/2 ,",
+			expected: []DeviceConfig{
+				{Name: "plc1", Host: "10.0.0.1", Port: 502, SlaveID: 1},
+				{Name: "plc2", Host: "10.0.0.2", Port: 502, SlaveID: 2},
+			},
+		},
+		{
+			name:    "Missing name",
+			value:   "=10.0.0.1:502",
+			wantErr: true,
+		},
+		{
+			name:    "Missing separator",
+			value:   "10.0.0.1:502",
+			wantErr: true,
+		},
+		{
+			name:    "Duplicate name",
+			value:   "plc1=10.0.0.1,plc1=10.0.0.2",
+			wantErr: true,
+		},
+		{
+			name:    "Missing host",
+			value:   "plc1=:502",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid port",
+			value:   "plc1=10.0.0.1:abc",
+			wantErr: true,
+		},
+		{
+			name:    "Unit ID out of range",
+			value:   "plc1=10.0.0.1:502/248",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid unit ID",
+			value:   "plc1=10.0.0.1:502/x",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDevices(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseDevices(%q) error = nil, want error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDevices(%q) unexpected error: %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseDevices(%q) = %+v, want %+v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadConfigDevices(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expected      []DeviceConfig
+		defaultDevice string
+		poolSize      int
+		wantErr       bool
+	}{
+		{
+			name: "Single device from MODBUS_HOST",
+			env: map[string]string{
+				"MODBUS_HOST":     "192.168.1.50",
+				"MODBUS_PORT":     "1502",
+				"MODBUS_SLAVE_ID": "4",
+			},
+			expected: []DeviceConfig{
+				{Name: DefaultDeviceName, Host: "192.168.1.50", Port: 1502, SlaveID: 4},
+			},
+			defaultDevice: DefaultDeviceName,
+			poolSize:      1,
+		},
+		{
+			name: "MODBUS_DEVICES overrides MODBUS_HOST",
+			env: map[string]string{
+				"MODBUS_HOST":    "192.168.1.50",
+				"MODBUS_DEVICES": "plc1=10.0.0.1/1,plc2=10.0.0.2/2",
+			},
+			expected: []DeviceConfig{
+				{Name: "plc1", Host: "10.0.0.1", Port: 502, SlaveID: 1},
+				{Name: "plc2", Host: "10.0.0.2", Port: 502, SlaveID: 2},
+			},
+			defaultDevice: "plc1",
+			poolSize:      1,
+		},
+		{
+			name: "Explicit default device and pool size",
+			env: map[string]string{
+				"MODBUS_DEVICES":        "plc1=10.0.0.1,plc2=10.0.0.2/2",
+				"MODBUS_DEFAULT_DEVICE": "plc2",
+				"MODBUS_POOL_SIZE":      "3",
+			},
+			expected: []DeviceConfig{
+				{Name: "plc1", Host: "10.0.0.1", Port: 502, SlaveID: 1},
+				{Name: "plc2", Host: "10.0.0.2", Port: 502, SlaveID: 2},
+			},
+			defaultDevice: "plc2",
+			poolSize:      3,
+		},
+		{
+			name: "Pool size below one falls back to one",
+			env: map[string]string{
+				"MODBUS_HOST":      "192.168.1.50",
+				"MODBUS_POOL_SIZE": "0",
+			},
+			expected: []DeviceConfig{
+				{Name: DefaultDeviceName, Host: "192.168.1.50", Port: 502, SlaveID: 1},
+			},
+			defaultDevice: DefaultDeviceName,
+			poolSize:      1,
+		},
+		{
+			name: "Simulator replaces MODBUS_HOST",
+			env: map[string]string{
+				"MODBUS_HOST":              "192.168.1.50",
+				"MODBUS_SIMULATOR":         "true",
+				"MODBUS_SIMULATOR_ADDRESS": "127.0.0.1:1602",
+			},
+			expected: []DeviceConfig{
+				{Name: DefaultDeviceName, Host: "127.0.0.1", Port: 1602, SlaveID: 1},
+			},
+			defaultDevice: DefaultDeviceName,
+			poolSize:      1,
+		},
+		{
+			name: "Invalid MODBUS_DEVICES",
+			env: map[string]string{
+				"MODBUS_DEVICES": "plc1=10.0.0.1,plc1=10.0.0.2",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid simulator address",
+			env: map[string]string{
+				"MODBUS_SIMULATOR":         "true",
+				"MODBUS_SIMULATOR_ADDRESS": "127.0.0.1",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{
+				"MODBUS_HOST", "MODBUS_PORT", "MODBUS_SLAVE_ID", "MODBUS_DEVICES", "MODBUS_DEFAULT_DEVICE",
+				"MODBUS_POOL_SIZE", "MODBUS_SIMULATOR", "MODBUS_SIMULATOR_ADDRESS", "AUTH_MODE", "AUTH_CLIENTS",
+			} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadConfig()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LoadConfig() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Devices, tt.expected) {
+				t.Errorf("LoadConfig() devices = %+v, want %+v", cfg.Devices, tt.expected)
+			}
+			if cfg.DefaultDevice != tt.defaultDevice {
+				t.Errorf("LoadConfig() default device = %q, want %q", cfg.DefaultDevice, tt.defaultDevice)
+			}
+			if cfg.PoolSize != tt.poolSize {
+				t.Errorf("LoadConfig() pool size = %d, want %d", cfg.PoolSize, tt.poolSize)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"modbus-server/internal/security"
+)
+
+// AuditHandler предоставляет HTTP обработчики журнала аудита записей
+type AuditHandler struct {
+	audit *security.AuditLog
+}
+
+// NewAuditHandler создает новый экземпляр AuditHandler
+func NewAuditHandler(audit *security.AuditLog) *AuditHandler {
+	return &AuditHandler{
+		audit: audit,
+	}
+}
+
+// GetAudit возвращает последние записи журнала аудита, новые первыми
+func (h *AuditHandler) GetAudit(c *gin.Context) {
+	limit := 100
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный параметр limit"})
+			return
+		}
+		limit = parsed
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"entries": h.audit.Recent(limit),
+	})
+}
+
+// RegisterRoutes регистрирует маршруты журнала аудита
+func (h *AuditHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/api/v1/modbus/audit", h.GetAudit)
+}
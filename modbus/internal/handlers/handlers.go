@@ -33,6 +33,18 @@ func (h *Handler) WriteCoil(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// WriteCoils записывает несколько coils одного устройства за один запрос
+func (h *Handler) WriteCoils(c *gin.Context) {
+	var req models.WriteCoilsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	response := h.modbusService.WriteCoils(&req)
+	c.JSON(http.StatusOK, response)
+}
+
 // WriteLightCoil включает или выключает свет для бокса
 func (h *Handler) WriteLightCoil(c *gin.Context) {
 	var req models.WriteLightCoilRequest
@@ -81,6 +93,74 @@ func (h *Handler) TestCoil(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// ReadCoils читает фактическое состояние coils
+func (h *Handler) ReadCoils(c *gin.Context) {
+	var req models.ReadCoilsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	response := h.modbusService.ReadCoils(&req)
+	c.JSON(http.StatusOK, response)
+}
+
+// WriteRegister записывает значение в holding register
+func (h *Handler) WriteRegister(c *gin.Context) {
+	var req models.WriteRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	response := h.modbusService.WriteRegister(&req)
+	c.JSON(http.StatusOK, response)
+}
+
+// ReadRegisters читает значения holding registers
+func (h *Handler) ReadRegisters(c *gin.Context) {
+	var req models.ReadRegistersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	response := h.modbusService.ReadRegisters(&req)
+	c.JSON(http.StatusOK, response)
+}
+
+// SetSensors заменяет список опрашиваемых датчиков присутствия
+func (h *Handler) SetSensors(c *gin.Context) {
+	var req models.SetSensorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	response := h.modbusService.SetSensors(&req)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSensors возвращает состояние датчиков присутствия
+func (h *Handler) GetSensors(c *gin.Context) {
+	c.JSON(http.StatusOK, h.modbusService.GetSensors())
+}
+
+// GetDevices возвращает состояние подключений ко всем Modbus устройствам
+func (h *Handler) GetDevices(c *gin.Context) {
+	c.JSON(http.StatusOK, h.modbusService.GetDevices())
+}
+
+// GetWatchdog возвращает состояние сигнала жизни и аварийного выключения coils
+func (h *Handler) GetWatchdog(c *gin.Context) {
+	response, err := h.modbusService.GetWatchdog()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 // RegisterRoutes регистрирует маршруты для Modbus API
 func (h *Handler) RegisterRoutes(router *gin.Engine) {
 	// API v1 группа
@@ -90,10 +170,18 @@ func (h *Handler) RegisterRoutes(router *gin.Engine) {
 		modbus := v1.Group("/modbus")
 		{
 			modbus.POST("/coil", h.WriteCoil)
+			modbus.POST("/coils", h.WriteCoils)
 			modbus.POST("/light", h.WriteLightCoil)
 			modbus.POST("/chemistry", h.WriteChemistryCoil)
 			modbus.POST("/test-connection", h.TestConnection)
 			modbus.POST("/test-coil", h.TestCoil)
+			modbus.POST("/read-coils", h.ReadCoils)
+			modbus.POST("/register", h.WriteRegister)
+			modbus.POST("/read-registers", h.ReadRegisters)
+			modbus.PUT("/sensors", h.SetSensors)
+			modbus.GET("/sensors", h.GetSensors)
+			modbus.GET("/devices", h.GetDevices)
+			modbus.GET("/watchdog", h.GetWatchdog)
 		}
 	}
 
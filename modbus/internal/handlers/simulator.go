@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"modbus-server/internal/models"
+	"modbus-server/internal/simulator"
+)
+
+// SimulatorHandler предоставляет HTTP обработчики для управления симулятором ПЛК
+type SimulatorHandler struct {
+	simulator *simulator.Simulator
+}
+
+// NewSimulatorHandler создает новый экземпляр SimulatorHandler
+func NewSimulatorHandler(simulator *simulator.Simulator) *SimulatorHandler {
+	return &SimulatorHandler{
+		simulator: simulator,
+	}
+}
+
+// GetState возвращает память симулятора, сбои и счетчики запросов
+func (h *SimulatorHandler) GetState(c *gin.Context) {
+	c.JSON(http.StatusOK, h.simulator.State())
+}
+
+// SetFaults меняет сбои симулятора: задержку, исключения, разрывы соединения и недоступность
+func (h *SimulatorHandler) SetFaults(c *gin.Context) {
+	var req models.SimulatorFaults
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+	if req.LatencyMs < 0 || req.JitterMs < 0 || req.ExceptionRate < 0 || req.ExceptionRate > 1 ||
+		req.DisconnectRate < 0 || req.DisconnectRate > 1 || req.ExceptionCode < 0 || req.ExceptionCode > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверные параметры сбоев"})
+		return
+	}
+
+	h.simulator.SetFaults(req)
+	c.JSON(http.StatusOK, h.simulator.Faults())
+}
+
+// SetDiscreteInput устанавливает дискретный вход симулятора (имитация датчика)
+func (h *SimulatorHandler) SetDiscreteInput(c *gin.Context) {
+	var req models.SetDiscreteInputRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	address, err := parseRegister(req.Register)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат регистра: " + req.Register})
+		return
+	}
+	unitID := req.UnitID
+	if unitID == 0 {
+		unitID = 1
+	}
+
+	h.simulator.SetDiscreteInput(byte(unitID), address, req.Value)
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Reset обнуляет память и счетчики симулятора
+func (h *SimulatorHandler) Reset(c *gin.Context) {
+	h.simulator.Reset()
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// RegisterRoutes регистрирует маршруты симулятора ПЛК
+func (h *SimulatorHandler) RegisterRoutes(router *gin.Engine) {
+	sim := router.Group("/api/v1/simulator")
+	{
+		sim.GET("/state", h.GetState)
+		sim.PUT("/faults", h.SetFaults)
+		sim.POST("/discrete-inputs", h.SetDiscreteInput)
+		sim.POST("/reset", h.Reset)
+	}
+}
+
+// parseRegister разбирает адрес регистра в hex формате (0x0001)
+func parseRegister(register string) (uint16, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(register), "0x"), 16, 16)
+	if err != nil || !strings.HasPrefix(strings.ToLower(register), "0x") {
+		return 0, strconv.ErrSyntax
+	}
+	return uint16(value), nil
+}
@@ -1,12 +1,18 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // WriteCoilRequest запрос на запись в coil
 type WriteCoilRequest struct {
 	BoxID    uuid.UUID `json:"box_id" binding:"required"`
+	Device   string    `json:"device,omitempty"` // Имя устройства (ПЛК), по умолчанию MODBUS_DEFAULT_DEVICE
 	Register string    `json:"register" binding:"required"`
 	Value    bool      `json:"value"`
+	Inverted bool      `json:"inverted,omitempty"` // Coil с инверсией: устройство включено при false
 }
 
 // WriteCoilResponse ответ на запись в coil
@@ -18,8 +24,10 @@ type WriteCoilResponse struct {
 // WriteLightCoilRequest запрос на управление светом
 type WriteLightCoilRequest struct {
 	BoxID    uuid.UUID `json:"box_id" binding:"required"`
+	Device   string    `json:"device,omitempty"` // Имя устройства (ПЛК), по умолчанию MODBUS_DEFAULT_DEVICE
 	Register string    `json:"register" binding:"required"`
 	Value    bool      `json:"value"`
+	Inverted bool      `json:"inverted,omitempty"` // Coil с инверсией: устройство включено при false
 }
 
 // WriteLightCoilResponse ответ на управление светом
@@ -31,8 +39,10 @@ type WriteLightCoilResponse struct {
 // WriteChemistryCoilRequest запрос на управление химией
 type WriteChemistryCoilRequest struct {
 	BoxID    uuid.UUID `json:"box_id" binding:"required"`
+	Device   string    `json:"device,omitempty"` // Имя устройства (ПЛК), по умолчанию MODBUS_DEFAULT_DEVICE
 	Register string    `json:"register" binding:"required"`
 	Value    bool      `json:"value"`
+	Inverted bool      `json:"inverted,omitempty"` // Coil с инверсией: устройство включено при false
 }
 
 // WriteChemistryCoilResponse ответ на управление химией
@@ -43,7 +53,8 @@ type WriteChemistryCoilResponse struct {
 
 // TestConnectionRequest запрос на тестирование соединения
 type TestConnectionRequest struct {
-	BoxID uuid.UUID `json:"box_id" binding:"required"`
+	BoxID  uuid.UUID `json:"box_id" binding:"required"`
+	Device string    `json:"device,omitempty"` // Имя устройства (ПЛК), по умолчанию MODBUS_DEFAULT_DEVICE
 }
 
 // TestConnectionResponse ответ на тестирование соединения
@@ -55,6 +66,7 @@ type TestConnectionResponse struct {
 // TestCoilRequest запрос на тестирование coil
 type TestCoilRequest struct {
 	BoxID    uuid.UUID `json:"box_id" binding:"required"`
+	Device   string    `json:"device,omitempty"` // Имя устройства (ПЛК), по умолчанию MODBUS_DEFAULT_DEVICE
 	Register string    `json:"register" binding:"required"`
 	Value    bool      `json:"value"`
 }
@@ -65,6 +77,148 @@ type TestCoilResponse struct {
 	Message string `json:"message"`
 }
 
+// ReadCoilsRequest запрос на чтение фактического состояния coils
+type ReadCoilsRequest struct {
+	BoxID     uuid.UUID `json:"box_id" binding:"required"`
+	Device    string    `json:"device,omitempty"` // Имя устройства (ПЛК), по умолчанию MODBUS_DEFAULT_DEVICE
+	Registers []string  `json:"registers" binding:"required,min=1,max=64"`
+}
+
+// ReadCoilsResponse ответ с состоянием coils по регистрам
+type ReadCoilsResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Values  map[string]bool `json:"values,omitempty"` // Ключ - регистр в том виде, в котором он пришел в запросе
+}
+
+// CoilWrite значение одного coil в пакетной записи
+type CoilWrite struct {
+	Register string `json:"register" binding:"required"`
+	Value    bool   `json:"value"`
+	Inverted bool   `json:"inverted,omitempty"` // Coil с инверсией: устройство включено при false
+}
+
+// WriteCoilsRequest запрос на пакетную запись нескольких coils одного устройства
+type WriteCoilsRequest struct {
+	BoxID  uuid.UUID   `json:"box_id" binding:"required"`
+	Device string      `json:"device,omitempty"` // Имя устройства (ПЛК), по умолчанию MODBUS_DEFAULT_DEVICE
+	Coils  []CoilWrite `json:"coils" binding:"required,min=1,max=64,dive"`
+}
+
+// CoilWriteResult результат записи одного coil из пакета
+type CoilWriteResult struct {
+	Register   string `json:"register"`
+	Value      bool   `json:"value"`
+	Success    bool   `json:"success"`               // Coil записан и остался в новом значении
+	RolledBack bool   `json:"rolled_back,omitempty"` // Coil был записан, но возвращен в прежнее значение после ошибки
+	Error      string `json:"error,omitempty"`
+}
+
+// Режимы пакетной записи coils
+const (
+	WriteCoilsModeMultiple = "multiple" // Один запрос WriteMultipleCoils для подряд идущих регистров
+	WriteCoilsModeSequence = "sequence" // Последовательная запись с откатом при ошибке
+)
+
+// WriteCoilsResponse ответ на пакетную запись coils с результатом по каждому coil
+type WriteCoilsResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Mode    string            `json:"mode,omitempty"`
+	Results []CoilWriteResult `json:"results"`
+}
+
+// Типы значений holding registers
+const (
+	RegisterTypeUint16  = "uint16"  // Один регистр без знака
+	RegisterTypeInt16   = "int16"   // Один регистр со знаком (дополнительный код)
+	RegisterTypeFloat32 = "float32" // IEEE 754 в двух подряд идущих регистрах
+)
+
+// Порядок слов float32 в паре регистров
+const (
+	WordOrderBig    = "big"    // Старшее слово в первом регистре (ABCD)
+	WordOrderLittle = "little" // Младшее слово в первом регистре (CDAB)
+)
+
+// RegisterFormat формат значения holding register
+type RegisterFormat struct {
+	Register  string `json:"register" binding:"required"`
+	DataType  string `json:"data_type,omitempty" binding:"omitempty,oneof=uint16 int16 float32"` // По умолчанию uint16
+	WordOrder string `json:"word_order,omitempty" binding:"omitempty,oneof=big little"`         // Для float32, по умолчанию big
+}
+
+// WriteRegisterRequest запрос на запись значения в holding register
+type WriteRegisterRequest struct {
+	BoxID  uuid.UUID `json:"box_id" binding:"required"`
+	Device string    `json:"device,omitempty"` // Имя устройства (ПЛК), по умолчанию MODBUS_DEFAULT_DEVICE
+	RegisterFormat
+	Value float64 `json:"value"`
+}
+
+// WriteRegisterResponse ответ на запись в holding register
+type WriteRegisterResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// ReadRegistersRequest запрос на чтение holding registers
+type ReadRegistersRequest struct {
+	BoxID     uuid.UUID        `json:"box_id" binding:"required"`
+	Device    string           `json:"device,omitempty"` // Имя устройства (ПЛК), по умолчанию MODBUS_DEFAULT_DEVICE
+	Registers []RegisterFormat `json:"registers" binding:"required,min=1,max=32,dive"`
+}
+
+// ReadRegistersResponse ответ со значениями holding registers
+type ReadRegistersResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Values  map[string]float64 `json:"values,omitempty"` // Ключ - регистр в том виде, в котором он пришел в запросе
+}
+
+// Sensor датчик присутствия автомобиля в боксе - дискретный вход устройства
+type Sensor struct {
+	BoxID    uuid.UUID `json:"box_id" binding:"required"`
+	Device   string    `json:"device,omitempty"` // Имя устройства (ПЛК), по умолчанию MODBUS_DEFAULT_DEVICE
+	Register string    `json:"register" binding:"required"`
+}
+
+// SetSensorsRequest полный список опрашиваемых датчиков; заменяет предыдущий
+type SetSensorsRequest struct {
+	Sensors []Sensor `json:"sensors" binding:"max=512,dive"`
+}
+
+// SensorState текущее состояние датчика
+type SensorState struct {
+	BoxID     uuid.UUID  `json:"box_id"`
+	Device    string     `json:"device"`
+	Register  string     `json:"register"`
+	Value     *bool      `json:"value,omitempty"`      // nil - датчик еще не прочитан
+	ChangedAt *time.Time `json:"changed_at,omitempty"` // Когда значение последний раз изменилось
+	Error     string     `json:"error,omitempty"`      // Ошибка последнего опроса
+}
+
+// SensorsResponse ответ со состоянием датчиков
+type SensorsResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message"`
+	Sensors []SensorState `json:"sensors"`
+}
+
+// SensorEvent изменение состояния датчика, отправляемое бэкенду
+type SensorEvent struct {
+	BoxID     uuid.UUID `json:"box_id"`
+	Device    string    `json:"device"`
+	Register  string    `json:"register"`
+	Value     bool      `json:"value"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// SensorEventsRequest пакет изменений датчиков для бэкенда
+type SensorEventsRequest struct {
+	Events []SensorEvent `json:"events"`
+}
+
 // BoxConfigRequest запрос с конфигурацией бокса
 type BoxConfigRequest struct {
 	BoxID                  uuid.UUID `json:"box_id" binding:"required"`
@@ -77,3 +231,88 @@ type BoxConfigResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 }
+
+// DeviceStatus состояние подключения к устройству (ПЛК)
+type DeviceStatus struct {
+	Name      string     `json:"name"`
+	Host      string     `json:"host"`
+	Port      int        `json:"port"`
+	SlaveID   int        `json:"slave_id"`
+	PoolSize  int        `json:"pool_size"`
+	Connected bool       `json:"connected"`
+	LastError string     `json:"last_error,omitempty"`
+	LastSeen  *time.Time `json:"last_seen,omitempty"`
+}
+
+// GetDevicesResponse ответ со списком устройств
+type GetDevicesResponse struct {
+	Enabled       bool           `json:"enabled"`
+	DefaultDevice string         `json:"default_device"`
+	Devices       []DeviceStatus `json:"devices"`
+}
+
+// HeartbeatStatus состояние сигнала жизни устройства
+type HeartbeatStatus struct {
+	Device   string     `json:"device"`
+	Value    int        `json:"value"` // Последнее записанное значение: 0/1 для coil, счетчик для register
+	Failing  bool       `json:"failing"`
+	LastBeat *time.Time `json:"last_beat,omitempty"`
+}
+
+// ArmedCoilStatus coil, включенный через сервер и находящийся под аварийным таймером
+type ArmedCoilStatus struct {
+	BoxID       string    `json:"box_id"`
+	Device      string    `json:"device"`
+	Register    string    `json:"register"`
+	Inverted    bool      `json:"inverted,omitempty"` // Выключается записью true
+	OnSince     time.Time `json:"on_since"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	SwitchOffAt time.Time `json:"switch_off_at"`
+}
+
+// WatchdogStatusResponse ответ с состоянием сторожевого таймера
+type WatchdogStatusResponse struct {
+	HeartbeatEnabled         bool              `json:"heartbeat_enabled"`
+	HeartbeatType            string            `json:"heartbeat_type"`
+	HeartbeatRegister        string            `json:"heartbeat_register,omitempty"`
+	HeartbeatIntervalSeconds int               `json:"heartbeat_interval_seconds"`
+	SafetyMaxOnMinutes       int               `json:"safety_max_on_minutes"`
+	Heartbeats               []HeartbeatStatus `json:"heartbeats"`
+	ArmedCoils               []ArmedCoilStatus `json:"armed_coils"`
+}
+
+// SimulatorFaults сбои, которые симулятор ПЛК вносит в ответы
+type SimulatorFaults struct {
+	LatencyMs      int     `json:"latency_ms"`      // Задержка каждого ответа
+	JitterMs       int     `json:"jitter_ms"`       // Случайная добавка к задержке от 0 до JitterMs
+	ExceptionRate  float64 `json:"exception_rate"`  // Доля запросов с ответом-исключением (0..1)
+	ExceptionCode  int     `json:"exception_code"`  // Код исключения, по умолчанию 4 (отказ устройства)
+	DisconnectRate float64 `json:"disconnect_rate"` // Доля запросов, на которые соединение разрывается без ответа (0..1)
+	Offline        bool    `json:"offline"`         // Устройство недоступно: соединения сразу закрываются
+}
+
+// SimulatorUnitState ненулевая память одного unit ID симулятора
+type SimulatorUnitState struct {
+	UnitID           int               `json:"unit_id"`
+	Coils            map[string]bool   `json:"coils"`
+	DiscreteInputs   map[string]bool   `json:"discrete_inputs"`
+	HoldingRegisters map[string]uint16 `json:"holding_registers"`
+}
+
+// SimulatorStateResponse состояние симулятора ПЛК
+type SimulatorStateResponse struct {
+	Address     string               `json:"address"`
+	Connections int                  `json:"connections"`
+	Requests    int64                `json:"requests"`
+	Exceptions  int64                `json:"exceptions"`
+	Disconnects int64                `json:"disconnects"`
+	Faults      SimulatorFaults      `json:"faults"`
+	Units       []SimulatorUnitState `json:"units"`
+}
+
+// SetDiscreteInputRequest запрос на установку дискретного входа симулятора (имитация датчика)
+type SetDiscreteInputRequest struct {
+	UnitID   int    `json:"unit_id" binding:"min=0,max=247"` // По умолчанию 1
+	Register string `json:"register" binding:"required"`
+	Value    bool   `json:"value"`
+}
@@ -0,0 +1,116 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Результаты проверки запроса на запись
+const (
+	AuditAccepted = "accepted" // Запрос прошел проверку и передан обработчику
+	AuditRejected = "rejected" // Запрос отклонен до обработчика
+)
+
+// AuditEntry запись журнала аудита о запросе на запись
+type AuditEntry struct {
+	Time        time.Time `json:"time"`
+	Outcome     string    `json:"outcome"`                // accepted или rejected
+	Reason      string    `json:"reason,omitempty"`       // Причина отклонения
+	Client      string    `json:"client,omitempty"`       // Идентификатор клиента из AUTH_CLIENTS
+	CertSubject string    `json:"cert_subject,omitempty"` // CN клиентского сертификата при mTLS
+	RemoteIP    string    `json:"remote_ip"`
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	Body        string    `json:"body,omitempty"`   // Тело запроса (регистр, значение), обрезается до maxAuditBody
+	Status      int       `json:"status,omitempty"` // HTTP статус ответа
+	DurationMs  int64     `json:"duration_ms"`
+}
+
+// maxAuditBody сколько байт тела запроса сохраняется в журнале
+const maxAuditBody = 2048
+
+// AuditLog журнал аудита записей: последние записи в памяти и, если задан файл, JSON Lines на диске
+type AuditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry // Кольцевой буфер последних записей
+	next    int
+	full    bool
+	file    *os.File
+}
+
+// NewAuditLog создает журнал аудита; size - сколько последних записей хранится в памяти
+func NewAuditLog(path string, size int) (*AuditLog, error) {
+	audit := &AuditLog{entries: make([]AuditEntry, size)}
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось открыть журнал аудита %s: %w", path, err)
+		}
+		audit.file = file
+	}
+	return audit, nil
+}
+
+// Record сохраняет запись в памяти и в файле журнала и дублирует ее в лог сервера
+func (a *AuditLog) Record(entry AuditEntry) {
+	if len(entry.Body) > maxAuditBody {
+		entry.Body = entry.Body[:maxAuditBody]
+	}
+
+	log.Printf("Аудит: %s %s %s - клиент: %q, сертификат: %q, ip: %s, статус: %d, причина: %s",
+		entry.Outcome, entry.Method, entry.Path, entry.Client, entry.CertSubject, entry.RemoteIP, entry.Status, entry.Reason)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.entries) > 0 {
+		a.entries[a.next] = entry
+		a.next = (a.next + 1) % len(a.entries)
+		if a.next == 0 {
+			a.full = true
+		}
+	}
+
+	if a.file != nil {
+		line, err := json.Marshal(entry)
+		if err == nil {
+			_, err = a.file.Write(append(line, '\n'))
+		}
+		if err != nil {
+			log.Printf("Ошибка записи в журнал аудита: %v", err)
+		}
+	}
+}
+
+// Recent возвращает последние записи журнала, новые первыми; limit <= 0 - все хранимые
+func (a *AuditLog) Recent(limit int) []AuditEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	count := a.next
+	if a.full {
+		count = len(a.entries)
+	}
+	if limit <= 0 || limit > count {
+		limit = count
+	}
+
+	result := make([]AuditEntry, 0, limit)
+	for i := 1; i <= limit; i++ {
+		index := (a.next - i + len(a.entries)) % len(a.entries)
+		result = append(result, a.entries[index])
+	}
+	return result
+}
+
+// Close закрывает файл журнала
+func (a *AuditLog) Close() error {
+	if a.file == nil {
+		return nil
+	}
+	return a.file.Close()
+}
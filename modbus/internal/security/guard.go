@@ -0,0 +1,274 @@
+package security
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"modbus-server/internal/config"
+)
+
+// Заголовки аутентификации запросов
+const (
+	HeaderAPIKey    = "X-API-Key"          // Секрет клиента в режиме api_key
+	HeaderClient    = "X-Modbus-Client"    // Идентификатор клиента в режиме hmac
+	HeaderTimestamp = "X-Modbus-Timestamp" // Время подписи, unix секунды
+	HeaderNonce     = "X-Modbus-Nonce"     // Случайная строка, уникальная для каждого запроса клиента
+	HeaderSignature = "X-Modbus-Signature" // hex HMAC-SHA256 строки подписи
+)
+
+// ContextClient ключ gin контекста с идентификатором аутентифицированного клиента
+const ContextClient = "auth_client"
+
+// maxSignedBody ограничение тела подписанного запроса
+const maxSignedBody = 1 << 20
+
+// errBodyTooLarge тело запроса больше maxSignedBody
+var errBodyTooLarge = errors.New("тело запроса слишком большое")
+
+// readOnlyPaths запросы POST, которые только читают устройство и не попадают в журнал аудита
+var readOnlyPaths = map[string]bool{
+	"/api/v1/modbus/read-coils":      true,
+	"/api/v1/modbus/read-registers":  true,
+	"/api/v1/modbus/test-connection": true,
+}
+
+// SignatureString строка, которая подписывается HMAC: метод, путь с query, время подписи, nonce и sha256 тела
+func SignatureString(method, requestURI, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	return method + "\n" + requestURI + "\n" + timestamp + "\n" + nonce + "\n" + hex.EncodeToString(bodyHash[:])
+}
+
+// Sign подписывает строку секретом клиента
+func Sign(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Guard проверяет адрес и аутентификацию каждого запроса к API и ведет журнал аудита записей
+// Адрес клиента берется из TCP соединения: X-Forwarded-For не учитывается, чтобы его нельзя было подделать
+type Guard struct {
+	config   *config.Config
+	networks []*net.IPNet
+	audit    *AuditLog
+	maxSkew  time.Duration
+
+	replayMu sync.Mutex
+	seen     map[string]time.Time // Принятые nonce клиентов и когда их подписи перестанут быть действительными
+}
+
+// NewGuard создает проверку запросов по настройкам безопасности
+func NewGuard(cfg *config.Config, audit *AuditLog) *Guard {
+	guard := &Guard{
+		config:  cfg,
+		audit:   audit,
+		maxSkew: time.Duration(cfg.AuthMaxSkewSeconds) * time.Second,
+		seen:    make(map[string]time.Time),
+	}
+	for _, allowed := range cfg.AllowedIPs {
+		if ip := net.ParseIP(allowed); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			allowed = fmt.Sprintf("%s/%d", ip, bits)
+		}
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			guard.networks = append(guard.networks, network)
+		}
+	}
+	return guard
+}
+
+// Middleware проверяет разрешенные адреса, сертификат клиента и аутентификацию запроса
+// /health доступен без проверки. Каждый запрос на запись - принятый или отклоненный - попадает в журнал аудита
+func (g *Guard) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.URL.Path == "/health" {
+			c.Next()
+			return
+		}
+
+		started := time.Now()
+		entry := AuditEntry{
+			Time:        started,
+			RemoteIP:    c.RemoteIP(),
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			CertSubject: certSubject(c.Request),
+		}
+		write := c.Request.Method != http.MethodGet && !readOnlyPaths[entry.Path]
+
+		body, err := readBody(c)
+		if errors.Is(err, errBodyTooLarge) {
+			g.reject(c, entry, write, http.StatusRequestEntityTooLarge, fmt.Sprintf("тело запроса больше %d байт", maxSignedBody))
+			return
+		}
+		if err != nil {
+			g.reject(c, entry, write, http.StatusBadRequest, "не удалось прочитать тело запроса")
+			return
+		}
+		entry.Body = string(body)
+
+		if !g.ipAllowed(entry.RemoteIP) {
+			g.reject(c, entry, write, http.StatusForbidden, "IP адрес не разрешен")
+			return
+		}
+
+		client, reason := g.authenticate(c.Request, body)
+		entry.Client = client
+		if reason != "" {
+			g.reject(c, entry, write, http.StatusUnauthorized, reason)
+			return
+		}
+		if client != "" {
+			c.Set(ContextClient, client)
+		}
+
+		c.Next()
+
+		if write {
+			entry.Outcome = AuditAccepted
+			entry.Status = c.Writer.Status()
+			entry.DurationMs = time.Since(started).Milliseconds()
+			g.audit.Record(entry)
+		}
+	}
+}
+
+// reject отклоняет запрос и записывает отказ в журнал аудита (отказ чтения только логируется)
+func (g *Guard) reject(c *gin.Context, entry AuditEntry, write bool, status int, reason string) {
+	c.AbortWithStatusJSON(status, gin.H{"success": false, "message": reason})
+
+	if !write {
+		log.Printf("Запрос отклонен: %s %s - клиент: %q, ip: %s, причина: %s", entry.Method, entry.Path, entry.Client, entry.RemoteIP, reason)
+		return
+	}
+	entry.Outcome = AuditRejected
+	entry.Reason = reason
+	entry.Status = status
+	entry.DurationMs = time.Since(entry.Time).Milliseconds()
+	g.audit.Record(entry)
+}
+
+// ipAllowed проверяет адрес клиента по ALLOWED_IPS; пустой список разрешает любые адреса
+func (g *Guard) ipAllowed(remoteIP string) bool {
+	if len(g.config.AllowedIPs) == 0 {
+		return true
+	}
+	ip := net.ParseIP(remoteIP)
+	if ip == nil {
+		return false
+	}
+	for _, network := range g.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate определяет клиента запроса; непустая причина - запрос не аутентифицирован
+func (g *Guard) authenticate(r *http.Request, body []byte) (client, reason string) {
+	switch g.config.AuthMode {
+	case config.AuthModeAPIKey:
+		key := r.Header.Get(HeaderAPIKey)
+		if key == "" {
+			return "", "не указан заголовок " + HeaderAPIKey
+		}
+		for name, secret := range g.config.AuthClients {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(secret)) == 1 {
+				return name, ""
+			}
+		}
+		return "", "неверный API ключ"
+
+	case config.AuthModeHMAC:
+		client = r.Header.Get(HeaderClient)
+		timestamp := r.Header.Get(HeaderTimestamp)
+		nonce := r.Header.Get(HeaderNonce)
+		signature := r.Header.Get(HeaderSignature)
+		if client == "" || timestamp == "" || nonce == "" || signature == "" {
+			return client, fmt.Sprintf("не указаны заголовки %s, %s, %s, %s", HeaderClient, HeaderTimestamp, HeaderNonce, HeaderSignature)
+		}
+		secret, ok := g.config.AuthClients[client]
+		if !ok {
+			return client, "неизвестный клиент"
+		}
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return client, "неверный формат " + HeaderTimestamp
+		}
+		signedAt := time.Unix(seconds, 0)
+		if skew := time.Since(signedAt); skew > g.maxSkew || skew < -g.maxSkew {
+			return client, "время подписи вне допустимого окна"
+		}
+		expected := Sign(secret, SignatureString(r.Method, r.URL.RequestURI(), timestamp, nonce, body))
+		if !hmac.Equal([]byte(signature), []byte(expected)) {
+			return client, "неверная подпись"
+		}
+		if !g.remember(client+"\n"+nonce, signedAt.Add(g.maxSkew)) {
+			return client, "повтор подписанного запроса"
+		}
+		return client, ""
+	}
+
+	return "", ""
+}
+
+// remember запоминает nonce клиента до конца окна действия подписи; false - nonce уже использовался
+func (g *Guard) remember(nonce string, expires time.Time) bool {
+	g.replayMu.Lock()
+	defer g.replayMu.Unlock()
+
+	now := time.Now()
+	for seen, until := range g.seen {
+		if now.After(until) {
+			delete(g.seen, seen)
+		}
+	}
+	if _, used := g.seen[nonce]; used {
+		return false
+	}
+	g.seen[nonce] = expires
+	return true
+}
+
+// readBody читает тело запроса и возвращает его обработчику нетронутым
+// Читается на байт больше лимита, чтобы отличить тело ровно в лимит от обрезанного
+func readBody(c *gin.Context) ([]byte, error) {
+	if c.Request.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSignedBody+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxSignedBody {
+		return nil, errBodyTooLarge
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// certSubject CN проверенного клиентского сертификата
+func certSubject(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+	return r.TLS.PeerCertificates[0].Subject.CommonName
+}
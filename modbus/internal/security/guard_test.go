@@ -0,0 +1,259 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"modbus-server/internal/config"
+)
+
+// Тестовый вектор подписи; тот же вектор проверяет клиент бэкенда
+// (smart_carwash/backend/internal/domain/modbus/client/auth_test.go)
+const (
+	testSignSecret    = "test-secret"
+	testSignMethod    = http.MethodPost
+	testSignURI       = "/api/v1/modbus/coil?device=plc1"
+	testSignTimestamp = "1767960000"
+	testSignNonce     = "7d6f0a3e-2f1b-4c2a-9d51-0b6c1f4e8a11"
+	testSignBody      = `{"box_id":"4f1c2a9e-8b3d-4e6f-9a1b-2c3d4e5f6a7b","register":"0x0010","value":true}`
+	testSignSignature = "99f96e3e20418c7c2e56679fdf71f12ea7b8eb1d216d618b5c74a2c6e7c5417d"
+)
+
+func TestSignMatchesBackendClient(t *testing.T) {
+	got := Sign(testSignSecret, SignatureString(testSignMethod, testSignURI, testSignTimestamp, testSignNonce, []byte(testSignBody)))
+	if got != testSignSignature {
+		t.Errorf("Sign() = %s, want %s", got, testSignSignature)
+	}
+}
+
+// newTestRouter роутер с проверкой запросов и обработчиком записи coil
+func newTestRouter(t *testing.T, cfg *config.Config) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	audit, err := NewAuditLog("", 10)
+	if err != nil {
+		t.Fatalf("NewAuditLog() unexpected error: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(NewGuard(cfg, audit).Middleware())
+	router.POST("/api/v1/modbus/coil", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"success": true, "client": c.GetString(ContextClient)})
+	})
+	return router
+}
+
+// signedRequest запрос, подписанный так же, как его подписывает клиент бэкенда
+func signedRequest(secret, client, timestamp, nonce, body string) *http.Request {
+	req := httptest.NewRequest(testSignMethod, testSignURI, strings.NewReader(body))
+	req.Header.Set(HeaderClient, client)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, Sign(secret, SignatureString(testSignMethod, testSignURI, timestamp, nonce, []byte(body))))
+	return req
+}
+
+func TestGuardHMAC(t *testing.T) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name     string
+		request  func() *http.Request
+		expected int
+	}{
+		{
+			name: "Valid signature",
+			request: func() *http.Request {
+				return signedRequest(testSignSecret, "backend", now, "nonce-valid", testSignBody)
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name: "Wrong secret",
+			request: func() *http.Request {
+				return signedRequest("other-secret", "backend", now, "nonce-secret", testSignBody)
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "Unknown client",
+			request: func() *http.Request {
+				return signedRequest(testSignSecret, "ops", now, "nonce-client", testSignBody)
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "Body changed after signing",
+			request: func() *http.Request {
+				req := signedRequest(testSignSecret, "backend", now, "nonce-body", testSignBody)
+				tampered := strings.Replace(testSignBody, "true", "false", 1)
+				return withBody(req, tampered)
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "Query changed after signing",
+			request: func() *http.Request {
+				req := signedRequest(testSignSecret, "backend", now, "nonce-query", testSignBody)
+				req.URL.RawQuery = "device=plc2"
+				req.RequestURI = req.URL.RequestURI()
+				return req
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "Missing signature headers",
+			request: func() *http.Request {
+				return httptest.NewRequest(testSignMethod, testSignURI, strings.NewReader(testSignBody))
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "Signature too old",
+			request: func() *http.Request {
+				return signedRequest(testSignSecret, "backend", stale, "nonce-stale", testSignBody)
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "Signature from the future",
+			request: func() *http.Request {
+				return signedRequest(testSignSecret, "backend", future, "nonce-future", testSignBody)
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "Invalid timestamp",
+			request: func() *http.Request {
+				return signedRequest(testSignSecret, "backend", "yesterday", "nonce-timestamp", testSignBody)
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "Body over the limit",
+			request: func() *http.Request {
+				body := strings.Repeat("a", maxSignedBody+1)
+				return signedRequest(testSignSecret, "backend", now, "nonce-large", body)
+			},
+			expected: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	cfg := &config.Config{
+		AuthMode:           config.AuthModeHMAC,
+		AuthClients:        map[string]string{"backend": testSignSecret},
+		AuthMaxSkewSeconds: 300,
+	}
+	router := newTestRouter(t, cfg)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, tt.request())
+			if recorder.Code != tt.expected {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tt.expected, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestGuardHMACRejectsReplay(t *testing.T) {
+	cfg := &config.Config{
+		AuthMode:           config.AuthModeHMAC,
+		AuthClients:        map[string]string{"backend": testSignSecret, "ops": "ops-secret"},
+		AuthMaxSkewSeconds: 300,
+	}
+	router := newTestRouter(t, cfg)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	steps := []struct {
+		name     string
+		request  *http.Request
+		expected int
+	}{
+		{name: "First request", request: signedRequest(testSignSecret, "backend", now, "nonce-1", testSignBody), expected: http.StatusOK},
+		{name: "Same request replayed", request: signedRequest(testSignSecret, "backend", now, "nonce-1", testSignBody), expected: http.StatusUnauthorized},
+		{name: "New nonce", request: signedRequest(testSignSecret, "backend", now, "nonce-2", testSignBody), expected: http.StatusOK},
+		{name: "Same nonce of another client", request: signedRequest("ops-secret", "ops", now, "nonce-1", testSignBody), expected: http.StatusOK},
+	}
+
+	for _, step := range steps {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, step.request)
+		if recorder.Code != step.expected {
+			t.Errorf("%s: status = %d, want %d: %s", step.name, recorder.Code, step.expected, recorder.Body.String())
+		}
+	}
+}
+
+func TestGuardRejectedNonceNotRemembered(t *testing.T) {
+	cfg := &config.Config{
+		AuthMode:           config.AuthModeHMAC,
+		AuthClients:        map[string]string{"backend": testSignSecret},
+		AuthMaxSkewSeconds: 300,
+	}
+	router := newTestRouter(t, cfg)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	// Запрос с неверной подписью не должен занимать nonce настоящего клиента
+	forged := signedRequest("other-secret", "backend", now, "nonce-1", testSignBody)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, forged)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("forged request status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, signedRequest(testSignSecret, "backend", now, "nonce-1", testSignBody))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("valid request status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+}
+
+func TestGuardAPIKey(t *testing.T) {
+	cfg := &config.Config{
+		AuthMode:    config.AuthModeAPIKey,
+		AuthClients: map[string]string{"backend": testSignSecret},
+	}
+	router := newTestRouter(t, cfg)
+
+	tests := []struct {
+		name     string
+		key      string
+		expected int
+	}{
+		{name: "Valid key", key: testSignSecret, expected: http.StatusOK},
+		{name: "Wrong key", key: "other-secret", expected: http.StatusUnauthorized},
+		{name: "Missing key", key: "", expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(testSignMethod, testSignURI, strings.NewReader(testSignBody))
+			if tt.key != "" {
+				req.Header.Set(HeaderAPIKey, tt.key)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			if recorder.Code != tt.expected {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tt.expected, recorder.Body.String())
+			}
+		})
+	}
+}
+
+// withBody заменяет тело запроса, сохраняя заголовки подписи
+func withBody(req *http.Request, body string) *http.Request {
+	replaced := httptest.NewRequest(req.Method, req.RequestURI, strings.NewReader(body))
+	replaced.Header = req.Header.Clone()
+	return replaced
+}
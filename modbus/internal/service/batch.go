@@ -0,0 +1,247 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/goburrow/modbus"
+
+	"modbus-server/internal/models"
+)
+
+// WriteCoils записывает несколько coils одного устройства за один запрос
+// Подряд идущие регистры записываются одной Modbus транзакцией WriteMultipleCoils - устройство
+// применяет их вместе. Иначе coils записываются по порядку запроса; при ошибке уже записанные
+// coils возвращаются в прежнее значение (прочитанное перед записью) в обратном порядке
+func (s *ModbusService) WriteCoils(req *models.WriteCoilsRequest) *models.WriteCoilsResponse {
+	log.Printf("WriteCoils - box_id: %s, device: %s, coils: %v", req.BoxID, req.Device, req.Coils)
+
+	results := make([]models.CoilWriteResult, len(req.Coils))
+	for i, coil := range req.Coils {
+		results[i] = models.CoilWriteResult{Register: coil.Register, Value: coil.Value}
+	}
+
+	// Проверяем, включен ли Modbus
+	if !s.config.ModbusEnabled {
+		for i := range results {
+			results[i].Success = true
+		}
+		return &models.WriteCoilsResponse{
+			Success: true,
+			Message: "Modbus отключен в конфигурации",
+			Results: results,
+		}
+	}
+
+	addresses := make([]uint16, len(req.Coils))
+	seen := make(map[uint16]bool, len(req.Coils))
+	for i, coil := range req.Coils {
+		if !s.isValidHexRegister(coil.Register) {
+			return writeCoilsFailed(results, fmt.Sprintf("Неверный формат регистра: %s", coil.Register))
+		}
+		address, err := s.hexToUint16(coil.Register)
+		if err != nil {
+			return writeCoilsFailed(results, fmt.Sprintf("Неверный формат регистра: %v", err))
+		}
+		if seen[address] {
+			return writeCoilsFailed(results, fmt.Sprintf("Регистр %s указан несколько раз", coil.Register))
+		}
+		seen[address] = true
+		addresses[i] = address
+	}
+
+	device, err := s.device(req.Device)
+	if err != nil {
+		log.Printf("Modbus устройство не найдено - box_id: %s, device: %s, error: %v", req.BoxID, req.Device, err)
+		return writeCoilsFailed(results, err.Error())
+	}
+
+	if isContiguous(addresses) {
+		return s.writeMultipleCoils(req, device, addresses, results)
+	}
+	return s.writeCoilSequence(req, device, addresses, results)
+}
+
+// writeMultipleCoils записывает подряд идущие coils одной транзакцией с повторами, как WriteCoil
+func (s *ModbusService) writeMultipleCoils(req *models.WriteCoilsRequest, device *Device, addresses []uint16, results []models.CoilWriteResult) *models.WriteCoilsResponse {
+	start, packed := packCoils(req.Coils, addresses)
+
+	var lastError error
+	for attempt := 1; attempt <= 3; attempt++ {
+		err := device.Exec(func(client modbus.Client) error {
+			_, err := client.WriteMultipleCoils(start, uint16(len(addresses)), packed)
+			return err
+		})
+		if err == nil {
+			for i := range results {
+				results[i].Success = true
+				s.watchdog.trackWrite(device.Name(), addresses[i], req.BoxID.String(), req.Coils[i].Register, req.Coils[i].Value, req.Coils[i].Inverted)
+			}
+			log.Printf("Modbus: успешно записано %d coils одной транзакцией - box_id: %s, device: %s", len(addresses), req.BoxID, device.Name())
+			return &models.WriteCoilsResponse{
+				Success: true,
+				Message: fmt.Sprintf("Успешно записано %d coils", len(addresses)),
+				Mode:    models.WriteCoilsModeMultiple,
+				Results: results,
+			}
+		}
+
+		lastError = err
+		log.Printf("Modbus: попытка %d пакетной записи неудачна - box_id: %s, device: %s, error: %v", attempt, req.BoxID, device.Name(), err)
+
+		// Исключение устройства (неверный адрес и т.п.) не исправится повтором
+		if isModbusException(err) {
+			break
+		}
+		if attempt < 3 {
+			time.Sleep(2 * time.Second)
+		}
+	}
+
+	resp := writeCoilsFailed(results, fmt.Sprintf("Не удалось записать coils в Modbus: %v", lastError))
+	resp.Mode = models.WriteCoilsModeMultiple
+	return resp
+}
+
+// writeCoilSequence записывает coils по порядку на одном соединении и откатывает записанные при ошибке
+func (s *ModbusService) writeCoilSequence(req *models.WriteCoilsRequest, device *Device, addresses []uint16, results []models.CoilWriteResult) *models.WriteCoilsResponse {
+	previous := make([]bool, len(addresses))
+	written := 0
+	var writeErr error
+
+	execErr := device.Exec(func(client modbus.Client) error {
+		written, writeErr = applyCoilSequence(client, req.Coils, addresses, previous, results)
+		return writeErr
+	})
+	// Exec мог завершиться ошибкой до записи: устройство недоступно, нет свободного соединения, не удалось подключиться
+	if writeErr == nil && execErr != nil {
+		writeErr = execErr
+	}
+
+	for i := 0; i < written; i++ {
+		value := req.Coils[i].Value
+		if results[i].RolledBack {
+			value = previous[i]
+		} else if writeErr != nil {
+			// Откат не удался - coil остался в новом значении
+			results[i].Success = true
+		}
+		s.watchdog.trackWrite(device.Name(), addresses[i], req.BoxID.String(), req.Coils[i].Register, value, req.Coils[i].Inverted)
+	}
+
+	if writeErr != nil {
+		log.Printf("Modbus: пакетная запись прервана после %d из %d coils - box_id: %s, device: %s, error: %v",
+			written, len(addresses), req.BoxID, device.Name(), writeErr)
+		for i := written; i < len(results); i++ {
+			results[i].Error = writeErr.Error()
+		}
+		for i := 0; i < written; i++ {
+			if results[i].RolledBack {
+				results[i].Error = writeErr.Error()
+			}
+		}
+		return &models.WriteCoilsResponse{
+			Success: false,
+			Message: fmt.Sprintf("Не удалось записать coils в Modbus: %v", writeErr),
+			Mode:    models.WriteCoilsModeSequence,
+			Results: results,
+		}
+	}
+
+	for i := range results {
+		results[i].Success = true
+	}
+	log.Printf("Modbus: успешно записано %d coils по порядку - box_id: %s, device: %s", len(addresses), req.BoxID, device.Name())
+	return &models.WriteCoilsResponse{
+		Success: true,
+		Message: fmt.Sprintf("Успешно записано %d coils", len(addresses)),
+		Mode:    models.WriteCoilsModeSequence,
+		Results: results,
+	}
+}
+
+// applyCoilSequence записывает coils по порядку, запоминая прежние значения в previous
+// Возвращает, сколько coils записано, и ошибку, прервавшую запись; в этом случае записанные coils
+// откатываются в обратном порядке, а результат отката отмечается в results
+func applyCoilSequence(client modbus.Client, coils []models.CoilWrite, addresses []uint16, previous []bool, results []models.CoilWriteResult) (int, error) {
+	written := 0
+	var writeErr error
+
+	for i, address := range addresses {
+		state, err := client.ReadCoils(address, 1)
+		if err == nil && len(state) == 0 {
+			err = fmt.Errorf("пустой ответ устройства")
+		}
+		if err != nil {
+			writeErr = fmt.Errorf("чтение регистра %s перед записью: %w", coils[i].Register, err)
+			break
+		}
+		previous[i] = state[0]&1 == 1
+
+		if _, err := client.WriteSingleCoil(address, boolToUint16(coils[i].Value)); err != nil {
+			writeErr = fmt.Errorf("запись регистра %s: %w", coils[i].Register, err)
+			break
+		}
+		written++
+	}
+
+	if writeErr == nil {
+		return written, nil
+	}
+
+	// Откатываем записанные coils в обратном порядке
+	for i := written - 1; i >= 0; i-- {
+		if _, err := client.WriteSingleCoil(addresses[i], boolToUint16(previous[i])); err != nil {
+			results[i].Error = fmt.Sprintf("откат не выполнен: %v", err)
+			continue
+		}
+		results[i].RolledBack = true
+	}
+	return written, writeErr
+}
+
+// packCoils упаковывает значения подряд идущих coils для WriteMultipleCoils
+// Значения упаковываются по битам начиная с младшего, в порядке адресов; возвращает начальный адрес
+func packCoils(coils []models.CoilWrite, addresses []uint16) (uint16, []byte) {
+	start := addresses[0]
+	for _, address := range addresses {
+		if address < start {
+			start = address
+		}
+	}
+
+	packed := make([]byte, (len(addresses)+7)/8)
+	for i, address := range addresses {
+		if coils[i].Value {
+			offset := address - start
+			packed[offset/8] |= 1 << (offset % 8)
+		}
+	}
+	return start, packed
+}
+
+// writeCoilsFailed ответ, в котором ни один coil не записан
+func writeCoilsFailed(results []models.CoilWriteResult, message string) *models.WriteCoilsResponse {
+	for i := range results {
+		results[i].Error = message
+	}
+	return &models.WriteCoilsResponse{
+		Success: false,
+		Message: message,
+		Results: results,
+	}
+}
+
+// isContiguous проверяет, что адреса без повторов образуют непрерывный диапазон
+func isContiguous(addresses []uint16) bool {
+	sorted := append([]uint16(nil), addresses...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] != sorted[i-1]+1 {
+			return false
+		}
+	}
+	return true
+}
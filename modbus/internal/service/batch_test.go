@@ -0,0 +1,465 @@
+package service
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/goburrow/modbus"
+	"github.com/google/uuid"
+
+	"modbus-server/internal/config"
+	"modbus-server/internal/models"
+	"modbus-server/internal/simulator"
+)
+
+// fakeCoilClient Modbus клиент с coils в памяти; запись с номером из writeErrors завершается ошибкой
+type fakeCoilClient struct {
+	modbus.Client
+	coils       map[uint16]bool
+	readErrors  map[uint16]error
+	writeErrors map[int]error
+	writes      []string
+}
+
+func (c *fakeCoilClient) ReadCoils(address, quantity uint16) ([]byte, error) {
+	if err := c.readErrors[address]; err != nil {
+		return nil, err
+	}
+	if c.coils[address] {
+		return []byte{1}, nil
+	}
+	return []byte{0}, nil
+}
+
+func (c *fakeCoilClient) WriteSingleCoil(address, value uint16) ([]byte, error) {
+	index := len(c.writes)
+	c.writes = append(c.writes, formatTestCoil(address, value == 0xFF00))
+	if err := c.writeErrors[index]; err != nil {
+		return nil, err
+	}
+	c.coils[address] = value == 0xFF00
+	return nil, nil
+}
+
+func formatTestCoil(address uint16, value bool) string {
+	return "0x" + strconv.FormatUint(uint64(address), 16) + "=" + strconv.FormatBool(value)
+}
+
+func testCoilWrites(values map[string]bool, registers ...string) []models.CoilWrite {
+	coils := make([]models.CoilWrite, len(registers))
+	for i, register := range registers {
+		coils[i] = models.CoilWrite{Register: register, Value: values[register]}
+	}
+	return coils
+}
+
+func TestIsContiguous(t *testing.T) {
+	tests := []struct {
+		name      string
+		addresses []uint16
+		expected  bool
+	}{
+		{name: "Single address", addresses: []uint16{5}, expected: true},
+		{name: "Ascending range", addresses: []uint16{5, 6, 7}, expected: true},
+		{name: "Unordered range", addresses: []uint16{7, 5, 6}, expected: true},
+		{name: "Gap", addresses: []uint16{5, 7}, expected: false},
+		{name: "Unordered with gap", addresses: []uint16{8, 5, 6}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isContiguous(tt.addresses); got != tt.expected {
+				t.Errorf("isContiguous(%v) = %v, want %v", tt.addresses, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPackCoils(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []bool
+		addresses []uint16
+		start     uint16
+		packed    []byte
+	}{
+		{
+			name:      "Single coil on",
+			values:    []bool{true},
+			addresses: []uint16{0x10},
+			start:     0x10,
+			packed:    []byte{0x01},
+		},
+		{
+			name:      "Bits start from lowest address",
+			values:    []bool{true, false, true},
+			addresses: []uint16{0x10, 0x11, 0x12},
+			start:     0x10,
+			packed:    []byte{0x05},
+		},
+		{
+			name:      "Request order does not matter",
+			values:    []bool{true, true, false},
+			addresses: []uint16{0x12, 0x10, 0x11},
+			start:     0x10,
+			packed:    []byte{0x05},
+		},
+		{
+			name:      "Second byte",
+			values:    []bool{false, false, false, false, false, false, false, false, true},
+			addresses: []uint16{0, 1, 2, 3, 4, 5, 6, 7, 8},
+			start:     0,
+			packed:    []byte{0x00, 0x01},
+		},
+		{
+			name:      "All off",
+			values:    []bool{false, false},
+			addresses: []uint16{0x20, 0x21},
+			start:     0x20,
+			packed:    []byte{0x00},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coils := make([]models.CoilWrite, len(tt.values))
+			for i, value := range tt.values {
+				coils[i] = models.CoilWrite{Value: value}
+			}
+
+			start, packed := packCoils(coils, tt.addresses)
+			if start != tt.start {
+				t.Errorf("packCoils() start = %#x, want %#x", start, tt.start)
+			}
+			if !reflect.DeepEqual(packed, tt.packed) {
+				t.Errorf("packCoils() packed = %08b, want %08b", packed, tt.packed)
+			}
+		})
+	}
+}
+
+func TestApplyCoilSequence(t *testing.T) {
+	deviceErr := errors.New("device error")
+	values := map[string]bool{"0x10": true, "0x11": true, "0x12": false}
+
+	tests := []struct {
+		name        string
+		initial     map[uint16]bool
+		readErrors  map[uint16]error
+		writeErrors map[int]error
+		written     int
+		wantErr     bool
+		previous    []bool
+		rolledBack  []bool
+		rollbackErr []bool
+		writes      []string
+		final       map[uint16]bool
+	}{
+		{
+			name:       "All coils written",
+			initial:    map[uint16]bool{0x12: true},
+			written:    3,
+			previous:   []bool{false, false, true},
+			rolledBack: []bool{false, false, false},
+			writes:     []string{"0x10=true", "0x11=true", "0x12=false"},
+			final:      map[uint16]bool{0x10: true, 0x11: true, 0x12: false},
+		},
+		{
+			name:        "Write failure rolls back written coils",
+			initial:     map[uint16]bool{0x11: true},
+			writeErrors: map[int]error{2: deviceErr},
+			written:     2,
+			wantErr:     true,
+			previous:    []bool{false, true, false},
+			rolledBack:  []bool{true, true, false},
+			writes:      []string{"0x10=true", "0x11=true", "0x12=false", "0x11=true", "0x10=false"},
+			final:       map[uint16]bool{0x10: false, 0x11: true},
+		},
+		{
+			name:       "Read failure rolls back in reverse order",
+			readErrors: map[uint16]error{0x12: deviceErr},
+			written:    2,
+			wantErr:    true,
+			previous:   []bool{false, false, false},
+			rolledBack: []bool{true, true, false},
+			writes:     []string{"0x10=true", "0x11=true", "0x11=false", "0x10=false"},
+			final:      map[uint16]bool{0x10: false, 0x11: false},
+		},
+		{
+			name:        "Failed rollback is reported",
+			readErrors:  map[uint16]error{0x12: deviceErr},
+			writeErrors: map[int]error{3: deviceErr},
+			written:     2,
+			wantErr:     true,
+			previous:    []bool{false, false, false},
+			rolledBack:  []bool{false, true, false},
+			rollbackErr: []bool{true, false, false},
+			writes:      []string{"0x10=true", "0x11=true", "0x11=false", "0x10=false"},
+			final:       map[uint16]bool{0x10: true, 0x11: false},
+		},
+		{
+			name:       "First read failure writes nothing",
+			readErrors: map[uint16]error{0x10: deviceErr},
+			written:    0,
+			wantErr:    true,
+			previous:   []bool{false, false, false},
+			rolledBack: []bool{false, false, false},
+			final:      map[uint16]bool{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coils := make(map[uint16]bool)
+			for address, value := range tt.initial {
+				coils[address] = value
+			}
+			client := &fakeCoilClient{coils: coils, readErrors: tt.readErrors, writeErrors: tt.writeErrors}
+			addresses := []uint16{0x10, 0x11, 0x12}
+			previous := make([]bool, len(addresses))
+			results := make([]models.CoilWriteResult, len(addresses))
+
+			written, err := applyCoilSequence(client, testCoilWrites(values, "0x10", "0x11", "0x12"), addresses, previous, results)
+
+			if written != tt.written {
+				t.Errorf("applyCoilSequence() written = %d, want %d", written, tt.written)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyCoilSequence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(previous, tt.previous) {
+				t.Errorf("applyCoilSequence() previous = %v, want %v", previous, tt.previous)
+			}
+			if !reflect.DeepEqual(client.writes, tt.writes) {
+				t.Errorf("applyCoilSequence() writes = %v, want %v", client.writes, tt.writes)
+			}
+			for i, result := range results {
+				if result.RolledBack != tt.rolledBack[i] {
+					t.Errorf("results[%d].RolledBack = %v, want %v", i, result.RolledBack, tt.rolledBack[i])
+				}
+				wantRollbackErr := tt.rollbackErr != nil && tt.rollbackErr[i]
+				if gotRollbackErr := strings.Contains(result.Error, "откат не выполнен"); gotRollbackErr != wantRollbackErr {
+					t.Errorf("results[%d].Error = %q, want rollback error %v", i, result.Error, wantRollbackErr)
+				}
+			}
+			for address, value := range tt.final {
+				if client.coils[address] != value {
+					t.Errorf("coil %#x = %v, want %v", address, client.coils[address], value)
+				}
+			}
+		})
+	}
+}
+
+// newTestService запускает симулятор ПЛК на свободном порту и сервис, подключенный к нему
+func newTestService(t *testing.T) (*ModbusService, *simulator.Simulator) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("не удалось выбрать порт: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	cfg := &config.Config{
+		ModbusEnabled:        true,
+		Devices:              []config.DeviceConfig{{Name: "plc1", Host: "127.0.0.1", Port: port, SlaveID: 1}},
+		DefaultDevice:        "plc1",
+		PoolSize:             1,
+		HeartbeatType:        config.HeartbeatTypeCoil,
+		SafetyMaxOnMinutes:   5,
+		SensorPollIntervalMs: 500,
+		SimulatorAddress:     "127.0.0.1:" + strconv.Itoa(port),
+	}
+
+	sim := simulator.NewSimulator(cfg)
+	if err := sim.Start(); err != nil {
+		t.Fatalf("не удалось запустить симулятор: %v", err)
+	}
+	t.Cleanup(sim.Close)
+
+	service := NewModbusService(cfg)
+	t.Cleanup(service.Close)
+	return service, sim
+}
+
+// simulatorCoils возвращает включенные coils unit 1 симулятора
+func simulatorCoils(sim *simulator.Simulator) map[string]bool {
+	for _, unit := range sim.State().Units {
+		if unit.UnitID == 1 {
+			return unit.Coils
+		}
+	}
+	return map[string]bool{}
+}
+
+func TestWriteCoils(t *testing.T) {
+	tests := []struct {
+		name      string
+		coils     []models.CoilWrite
+		faults    *models.SimulatorFaults
+		success   bool
+		mode      string
+		simCoils  map[string]bool
+		armed     int
+		resultsOK bool
+	}{
+		{
+			name: "Contiguous coils in one transaction",
+			coils: []models.CoilWrite{
+				{Register: "0x0012", Value: true},
+				{Register: "0x0010", Value: true},
+				{Register: "0x0011", Value: false},
+			},
+			success:   true,
+			mode:      models.WriteCoilsModeMultiple,
+			simCoils:  map[string]bool{"0x0010": true, "0x0012": true},
+			armed:     2,
+			resultsOK: true,
+		},
+		{
+			name: "Non-contiguous coils in sequence",
+			coils: []models.CoilWrite{
+				{Register: "0x0010", Value: true},
+				{Register: "0x0020", Value: true},
+			},
+			success:   true,
+			mode:      models.WriteCoilsModeSequence,
+			simCoils:  map[string]bool{"0x0010": true, "0x0020": true},
+			armed:     2,
+			resultsOK: true,
+		},
+		{
+			name: "Device exception fails transaction",
+			coils: []models.CoilWrite{
+				{Register: "0x0010", Value: true},
+				{Register: "0x0011", Value: true},
+			},
+			faults:   &models.SimulatorFaults{ExceptionRate: 1},
+			success:  false,
+			mode:     models.WriteCoilsModeMultiple,
+			simCoils: map[string]bool{},
+		},
+		{
+			name: "Device exception fails sequence",
+			coils: []models.CoilWrite{
+				{Register: "0x0010", Value: true},
+				{Register: "0x0020", Value: true},
+			},
+			faults:   &models.SimulatorFaults{ExceptionRate: 1},
+			success:  false,
+			mode:     models.WriteCoilsModeSequence,
+			simCoils: map[string]bool{},
+		},
+		{
+			name: "Duplicate register",
+			coils: []models.CoilWrite{
+				{Register: "0x0010", Value: true},
+				{Register: "0x10", Value: false},
+			},
+			success:  false,
+			simCoils: map[string]bool{},
+		},
+		{
+			name: "Invalid register",
+			coils: []models.CoilWrite{
+				{Register: "0x0010", Value: true},
+				{Register: "light", Value: true},
+			},
+			success:  false,
+			simCoils: map[string]bool{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, sim := newTestService(t)
+			if tt.faults != nil {
+				sim.SetFaults(*tt.faults)
+			}
+
+			resp := service.WriteCoils(&models.WriteCoilsRequest{BoxID: uuid.New(), Coils: tt.coils})
+
+			if resp.Success != tt.success {
+				t.Fatalf("WriteCoils() success = %v, want %v: %s", resp.Success, tt.success, resp.Message)
+			}
+			if resp.Mode != tt.mode {
+				t.Errorf("WriteCoils() mode = %q, want %q", resp.Mode, tt.mode)
+			}
+			if len(resp.Results) != len(tt.coils) {
+				t.Fatalf("WriteCoils() returned %d results, want %d", len(resp.Results), len(tt.coils))
+			}
+			for i, result := range resp.Results {
+				if result.Register != tt.coils[i].Register || result.Value != tt.coils[i].Value {
+					t.Errorf("results[%d] = %s=%v, want %s=%v", i, result.Register, result.Value, tt.coils[i].Register, tt.coils[i].Value)
+				}
+				if result.Success != tt.resultsOK {
+					t.Errorf("results[%d].Success = %v, want %v", i, result.Success, tt.resultsOK)
+				}
+				if !tt.resultsOK && result.Error == "" {
+					t.Errorf("results[%d].Error is empty", i)
+				}
+			}
+
+			sim.SetFaults(models.SimulatorFaults{})
+			if got := simulatorCoils(sim); !reflect.DeepEqual(got, tt.simCoils) {
+				t.Errorf("simulator coils = %v, want %v", got, tt.simCoils)
+			}
+
+			watchdog, err := service.GetWatchdog()
+			if err != nil {
+				t.Fatalf("GetWatchdog() unexpected error: %v", err)
+			}
+			if len(watchdog.ArmedCoils) != tt.armed {
+				t.Errorf("armed coils = %d, want %d", len(watchdog.ArmedCoils), tt.armed)
+			}
+		})
+	}
+}
+
+func TestWriteCoilsUnavailableDevice(t *testing.T) {
+	service, sim := newTestService(t)
+	sim.SetFaults(models.SimulatorFaults{Offline: true})
+
+	req := &models.WriteCoilsRequest{
+		BoxID: uuid.New(),
+		Coils: []models.CoilWrite{
+			{Register: "0x0010", Value: true},
+			{Register: "0x0020", Value: true},
+		},
+	}
+
+	// Первый запрос обнаруживает обрыв связи, второй не доходит до устройства
+	for attempt := 1; attempt <= 2; attempt++ {
+		resp := service.WriteCoils(req)
+		if resp.Success {
+			t.Fatalf("attempt %d: WriteCoils() success = true, want false", attempt)
+		}
+		if resp.Mode != models.WriteCoilsModeSequence {
+			t.Errorf("attempt %d: WriteCoils() mode = %q, want %q", attempt, resp.Mode, models.WriteCoilsModeSequence)
+		}
+		for i, result := range resp.Results {
+			if result.Success || result.RolledBack || result.Error == "" {
+				t.Errorf("attempt %d: results[%d] = %+v, want failed without rollback", attempt, i, result)
+			}
+		}
+	}
+}
+
+func TestWriteCoilsModbusDisabled(t *testing.T) {
+	service := NewModbusService(&config.Config{ModbusEnabled: false})
+	t.Cleanup(service.Close)
+
+	resp := service.WriteCoils(&models.WriteCoilsRequest{
+		BoxID: uuid.New(),
+		Coils: []models.CoilWrite{{Register: "0x0010", Value: true}},
+	})
+	if !resp.Success || len(resp.Results) != 1 || !resp.Results[0].Success {
+		t.Errorf("WriteCoils() = %+v, want success without device", resp)
+	}
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/goburrow/modbus"
+
+	"modbus-server/internal/config"
+	"modbus-server/internal/models"
+)
+
+const (
+	// deviceTimeout таймаут одной Modbus транзакции
+	deviceTimeout = 10 * time.Second
+	// reconnectMinBackoff и reconnectMaxBackoff пределы паузы между попытками переподключения
+	reconnectMinBackoff = 1 * time.Second
+	reconnectMaxBackoff = 30 * time.Second
+)
+
+// deviceConn одно соединение с устройством из пула
+type deviceConn struct {
+	handler    *modbus.TCPClientHandler
+	client     modbus.Client
+	connected  bool
+	generation uint64 // Поколение устройства, в котором соединение было проверено
+}
+
+// connect устанавливает соединение, если оно еще не установлено
+func (c *deviceConn) connect() error {
+	if c.connected {
+		return nil
+	}
+	if err := c.handler.Connect(); err != nil {
+		return err
+	}
+	c.connected = true
+	return nil
+}
+
+// close закрывает соединение, следующее использование переподключится
+func (c *deviceConn) close() {
+	_ = c.handler.Close()
+	c.connected = false
+}
+
+// Device ПЛК со своим пулом соединений и циклом переподключения
+// Недоступность одного устройства не влияет на остальные: пока устройство недоступно,
+// запросы к нему сразу завершаются ошибкой, а цикл переподключения восстанавливает связь в фоне.
+// Сигнал жизни идет по отдельному соединению вне пула, чтобы частые такты не задерживали команды
+// и долгая команда не задерживала сигнал жизни
+type Device struct {
+	config config.DeviceConfig
+	pool   chan *deviceConn
+
+	heartbeatMu   sync.Mutex // Защищает heartbeatConn
+	heartbeatConn *deviceConn
+
+	mu          sync.Mutex // Защищает состояние устройства
+	available   bool
+	generation  uint64 // Увеличивается при каждом сбое; соединения прошлых поколений переподключаются
+	lastError   string
+	lastSeen    time.Time
+	reconnectCh chan struct{}
+	done        chan struct{}
+}
+
+// newDevice создает устройство с пулом из poolSize соединений и запускает цикл переподключения
+func newDevice(cfg config.DeviceConfig, poolSize int) *Device {
+	d := &Device{
+		config:      cfg,
+		pool:        make(chan *deviceConn, poolSize),
+		available:   true,
+		reconnectCh: make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+
+	for i := 0; i < poolSize; i++ {
+		d.pool <- newDeviceConn(cfg)
+	}
+	d.heartbeatConn = newDeviceConn(cfg)
+
+	go d.reconnectLoop()
+	return d
+}
+
+// newDeviceConn создает неподключенное соединение с устройством
+func newDeviceConn(cfg config.DeviceConfig) *deviceConn {
+	handler := modbus.NewTCPClientHandler(cfg.Address())
+	handler.Timeout = deviceTimeout
+	handler.SlaveId = cfg.SlaveID
+	return &deviceConn{handler: handler, client: modbus.NewClient(handler)}
+}
+
+// Name возвращает имя устройства
+func (d *Device) Name() string {
+	return d.config.Name
+}
+
+// Exec выполняет операцию на свободном соединении из пула
+// При ошибке соединение закрывается, устройство помечается недоступным и запускается переподключение
+func (d *Device) Exec(op func(client modbus.Client) error) error {
+	if !d.isAvailable() {
+		return fmt.Errorf("устройство %s (%s) недоступно, идет переподключение: %s", d.config.Name, d.config.Address(), d.getLastError())
+	}
+
+	var conn *deviceConn
+	select {
+	case conn = <-d.pool:
+	case <-time.After(deviceTimeout):
+		return fmt.Errorf("устройство %s: нет свободного соединения за %s", d.config.Name, deviceTimeout)
+	}
+	defer func() { d.pool <- conn }()
+
+	return d.run(conn, op)
+}
+
+// ExecHeartbeat выполняет операцию сигнала жизни на отдельном соединении, не занимая пул
+// Ошибка, как и в Exec, помечает устройство недоступным
+func (d *Device) ExecHeartbeat(op func(client modbus.Client) error) error {
+	if !d.isAvailable() {
+		return fmt.Errorf("устройство %s (%s) недоступно, идет переподключение: %s", d.config.Name, d.config.Address(), d.getLastError())
+	}
+
+	d.heartbeatMu.Lock()
+	defer d.heartbeatMu.Unlock()
+	return d.run(d.heartbeatConn, op)
+}
+
+// run выполняет операцию на соединении, переподключая его при необходимости
+func (d *Device) run(conn *deviceConn, op func(client modbus.Client) error) error {
+	// Соединение было занято во время сбоя или не проверялось после восстановления - открываем заново
+	if generation := d.getGeneration(); conn.generation != generation {
+		conn.close()
+		conn.generation = generation
+	}
+
+	if err := conn.connect(); err != nil {
+		d.markFailed(err)
+		return fmt.Errorf("устройство %s: не удалось подключиться: %w", d.config.Name, err)
+	}
+
+	if err := op(conn.client); err != nil {
+		// Исключение Modbus (неверный адрес и т.п.) - ответ устройства, связь в порядке
+		if isModbusException(err) {
+			d.markSeen()
+			return err
+		}
+		conn.close()
+		d.markFailed(err)
+		return err
+	}
+
+	d.markSeen()
+	return nil
+}
+
+// Connect проверяет связь с устройством при старте; ошибка не фатальна, устройство переподключится в фоне
+func (d *Device) Connect() error {
+	return d.Exec(func(client modbus.Client) error { return nil })
+}
+
+// Status возвращает состояние устройства
+func (d *Device) Status() models.DeviceStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status := models.DeviceStatus{
+		Name:      d.config.Name,
+		Host:      d.config.Host,
+		Port:      d.config.Port,
+		SlaveID:   int(d.config.SlaveID),
+		PoolSize:  cap(d.pool),
+		Connected: d.available,
+		LastError: d.lastError,
+	}
+	if !d.lastSeen.IsZero() {
+		lastSeen := d.lastSeen
+		status.LastSeen = &lastSeen
+	}
+	return status
+}
+
+// Close останавливает цикл переподключения и закрывает соединения
+func (d *Device) Close() {
+	close(d.done)
+	for i := 0; i < cap(d.pool); i++ {
+		conn := <-d.pool
+		conn.close()
+	}
+	d.heartbeatMu.Lock()
+	d.heartbeatConn.close()
+	d.heartbeatMu.Unlock()
+}
+
+// isAvailable проверяет, доступно ли устройство
+func (d *Device) isAvailable() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.available
+}
+
+// getLastError возвращает последнюю ошибку устройства
+func (d *Device) getLastError() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastError
+}
+
+// getGeneration возвращает текущее поколение соединений устройства
+func (d *Device) getGeneration() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.generation
+}
+
+// markSeen отмечает успешную операцию
+func (d *Device) markSeen() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastSeen = time.Now()
+	d.lastError = ""
+}
+
+// markFailed помечает устройство недоступным, сбрасывает соединения пула и будит цикл переподключения
+// Сбой одного соединения обычно означает, что устройство перезагрузилось или пропала сеть,
+// поэтому остальные соединения пула тоже считаются неисправными
+func (d *Device) markFailed(err error) {
+	d.mu.Lock()
+	wasAvailable := d.available
+	d.available = false
+	d.lastError = err.Error()
+	d.generation++
+	d.mu.Unlock()
+
+	if wasAvailable {
+		log.Printf("Modbus: устройство %s (%s) недоступно: %v", d.config.Name, d.config.Address(), err)
+	}
+
+	// Свободные соединения закрываем сразу, занятые закроются при следующем использовании
+	d.closeIdle()
+
+	select {
+	case d.reconnectCh <- struct{}{}:
+	default:
+	}
+}
+
+// reconnectLoop восстанавливает связь с недоступным устройством с экспоненциальной паузой
+func (d *Device) reconnectLoop() {
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-d.reconnectCh:
+		}
+
+		backoff := reconnectMinBackoff
+		for attempt := 1; ; attempt++ {
+			err := d.probe()
+			if err == nil {
+				d.mu.Lock()
+				d.available = true
+				d.lastError = ""
+				d.lastSeen = time.Now()
+				d.mu.Unlock()
+				log.Printf("Modbus: устройство %s (%s) снова доступно после %d попыток", d.config.Name, d.config.Address(), attempt)
+				break
+			}
+
+			d.mu.Lock()
+			d.lastError = err.Error()
+			d.mu.Unlock()
+			log.Printf("Modbus: переподключение к %s, попытка %d неудачна: %v", d.config.Name, attempt, err)
+
+			select {
+			case <-d.done:
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > reconnectMaxBackoff {
+				backoff = reconnectMaxBackoff
+			}
+		}
+	}
+}
+
+// closeIdle закрывает свободные соединения пула
+func (d *Device) closeIdle() {
+	idle := make([]*deviceConn, 0, cap(d.pool))
+	for len(idle) < cap(d.pool) {
+		select {
+		case conn := <-d.pool:
+			conn.close()
+			idle = append(idle, conn)
+			continue
+		default:
+		}
+		break
+	}
+	for _, conn := range idle {
+		d.pool <- conn
+	}
+}
+
+// probe переподключает все соединения пула и проверяет каждое чтением coil
+// Устройство считается доступным, только если исправны все соединения
+func (d *Device) probe() error {
+	// Пока устройство недоступно, новые операции соединения не берут - дожидаемся возврата занятых
+	conns := make([]*deviceConn, 0, cap(d.pool))
+	defer func() {
+		for _, conn := range conns {
+			d.pool <- conn
+		}
+	}()
+	for len(conns) < cap(d.pool) {
+		select {
+		case conn := <-d.pool:
+			conns = append(conns, conn)
+		case <-d.done:
+			return fmt.Errorf("устройство остановлено")
+		}
+	}
+
+	generation := d.getGeneration()
+	for i, conn := range conns {
+		conn.close()
+		err := conn.connect()
+		if err == nil {
+			if _, err = conn.client.ReadCoils(0, 1); err != nil && isModbusException(err) {
+				err = nil
+			}
+		}
+		if err != nil {
+			for _, c := range conns {
+				c.close()
+			}
+			return fmt.Errorf("соединение %d из %d: %w", i+1, len(conns), err)
+		}
+		conn.generation = generation
+	}
+	return nil
+}
+
+// isModbusException проверяет, что ошибка - исключение, которое вернуло устройство
+func isModbusException(err error) bool {
+	var modbusErr *modbus.ModbusError
+	return errors.As(err, &modbusErr)
+}
@@ -5,7 +5,6 @@ import (
 	"log"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/goburrow/modbus"
@@ -15,87 +14,151 @@ import (
 )
 
 // ModbusService предоставляет методы для работы с Modbus устройствами
+// Каждое устройство (ПЛК) имеет свой пул соединений и цикл переподключения
 type ModbusService struct {
-	config    *config.Config
-	handler   *modbus.TCPClientHandler
-	client    modbus.Client
-	mu        sync.Mutex
-	connected bool
+	config      *config.Config
+	devices     map[string]*Device
+	deviceOrder []string
+	watchdog    *Watchdog
+	sensors     *SensorPoller
 }
 
 // NewModbusService создает новый экземпляр ModbusService
 func NewModbusService(config *config.Config) *ModbusService {
 	s := &ModbusService{
-		config: config,
+		config:  config,
+		devices: make(map[string]*Device),
 	}
 
-	// Инициализируем соединение при старте, если включен Modbus
+	// Подключаемся к устройствам при старте, если включен Modbus
 	if s.config.ModbusEnabled {
-		if s.config.ModbusHost == "" {
-			log.Fatalf("MODBUS_HOST не задан в конфигурации")
-		}
+		for _, deviceConfig := range s.config.Devices {
+			if deviceConfig.Host == "" {
+				log.Fatalf("Не задан адрес Modbus устройства %s (MODBUS_HOST или MODBUS_DEVICES)", deviceConfig.Name)
+			}
+
+			device := newDevice(deviceConfig, s.config.PoolSize)
+			s.devices[deviceConfig.Name] = device
+			s.deviceOrder = append(s.deviceOrder, deviceConfig.Name)
 
-		handler := modbus.NewTCPClientHandler(fmt.Sprintf("%s:%d", s.config.ModbusHost, s.config.ModbusPort))
-		handler.Timeout = 10 * time.Second
-		handler.SlaveId = 1
+			// Недоступное при старте устройство не мешает работе остальных, оно переподключится в фоне
+			if err := device.Connect(); err != nil {
+				log.Printf("Modbus устройство %s недоступно при старте: %v", deviceConfig.Name, err)
+				continue
+			}
+			log.Printf("Modbus подключение установлено при старте: %s (%s, unit %d)", deviceConfig.Name, deviceConfig.Address(), deviceConfig.SlaveID)
+		}
 
-		if err := handler.Connect(); err != nil {
-			log.Fatalf("Не удалось установить соединение с Modbus при старте: %v", err)
+		if _, ok := s.devices[s.config.DefaultDevice]; !ok {
+			log.Fatalf("MODBUS_DEFAULT_DEVICE: устройство %s не найдено", s.config.DefaultDevice)
 		}
 
-		client := modbus.NewClient(handler)
-		s.handler = handler
-		s.client = client
-		s.connected = true
-		log.Printf("Modbus подключение установлено при старте: %s:%d", s.config.ModbusHost, s.config.ModbusPort)
+		s.watchdog = newWatchdog(s, s.config)
+		s.watchdog.start()
+
+		s.sensors = newSensorPoller(s, s.config)
+		s.sensors.start()
 	}
 
 	return s
 }
 
-// Close закрывает соединение с Modbus, используется при остановке сервера
+// Close закрывает соединения со всеми устройствами, используется при остановке сервера
 func (s *ModbusService) Close() {
-	if s.handler == nil {
-		return
+	if s.watchdog != nil {
+		s.watchdog.stop()
+	}
+	if s.sensors != nil {
+		s.sensors.stop()
+	}
+	for _, device := range s.devices {
+		device.Close()
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	_ = s.handler.Close()
-	s.connected = false
 }
 
-// ensureConnectedLocked проверяет и при необходимости восстанавливает соединение (требует удержания mu)
-func (s *ModbusService) ensureConnectedLocked() error {
+// device возвращает устройство по имени (пустое имя - устройство по умолчанию)
+func (s *ModbusService) device(name string) (*Device, error) {
 	if !s.config.ModbusEnabled {
-		return fmt.Errorf("Modbus протокол отключен в конфигурации")
+		return nil, fmt.Errorf("Modbus протокол отключен в конфигурации")
 	}
-	if s.handler == nil {
-		handler := modbus.NewTCPClientHandler(fmt.Sprintf("%s:%d", s.config.ModbusHost, s.config.ModbusPort))
-		handler.Timeout = 10 * time.Second
-		handler.SlaveId = 1
-		s.handler = handler
-		s.client = modbus.NewClient(handler)
+	if name == "" {
+		name = s.config.DefaultDevice
 	}
-	if s.connected {
-		return nil
+	device, ok := s.devices[name]
+	if !ok {
+		return nil, fmt.Errorf("неизвестное Modbus устройство: %s", name)
 	}
-	// Пытаемся переподключиться с небольшим бэкоффом
-	var lastErr error
-	for attempt := 1; attempt <= 3; attempt++ {
-		if err := s.handler.Connect(); err != nil {
-			lastErr = err
-			log.Printf("Modbus reconnect попытка %d неудачна: %v", attempt, err)
-			time.Sleep(time.Duration(attempt) * time.Second)
-			continue
+	return device, nil
+}
+
+// GetDevices возвращает состояние всех устройств
+func (s *ModbusService) GetDevices() *models.GetDevicesResponse {
+	resp := &models.GetDevicesResponse{
+		Enabled:       s.config.ModbusEnabled,
+		DefaultDevice: s.config.DefaultDevice,
+		Devices:       make([]models.DeviceStatus, 0, len(s.deviceOrder)),
+	}
+	for _, name := range s.deviceOrder {
+		resp.Devices = append(resp.Devices, s.devices[name].Status())
+	}
+	return resp
+}
+
+// GetWatchdog возвращает состояние сигнала жизни и coils под аварийным таймером
+func (s *ModbusService) GetWatchdog() (*models.WatchdogStatusResponse, error) {
+	if s.watchdog == nil {
+		return nil, fmt.Errorf("Modbus протокол отключен в конфигурации")
+	}
+	return s.watchdog.Status(), nil
+}
+
+// SetSensors заменяет список опрашиваемых датчиков присутствия и возвращает их состояние
+func (s *ModbusService) SetSensors(req *models.SetSensorsRequest) *models.SensorsResponse {
+	if s.sensors == nil {
+		return &models.SensorsResponse{
+			Success: false,
+			Message: "Modbus протокол отключен в конфигурации",
+			Sensors: []models.SensorState{},
+		}
+	}
+
+	if err := s.sensors.SetSensors(req.Sensors); err != nil {
+		return &models.SensorsResponse{
+			Success: false,
+			Message: err.Error(),
+			Sensors: []models.SensorState{},
+		}
+	}
+
+	return &models.SensorsResponse{
+		Success: true,
+		Message: fmt.Sprintf("Опрашивается датчиков: %d", len(req.Sensors)),
+		Sensors: s.sensors.States(),
+	}
+}
+
+// GetSensors возвращает состояние датчиков присутствия
+func (s *ModbusService) GetSensors() *models.SensorsResponse {
+	if s.sensors == nil {
+		return &models.SensorsResponse{
+			Success: false,
+			Message: "Modbus протокол отключен в конфигурации",
+			Sensors: []models.SensorState{},
 		}
-		s.connected = true
-		log.Printf("Modbus reconnect успешен")
-		return nil
 	}
-	return fmt.Errorf("не удалось переподключиться к Modbus после 3 попыток: %v", lastErr)
+
+	states := s.sensors.States()
+	return &models.SensorsResponse{
+		Success: true,
+		Message: fmt.Sprintf("Опрашивается датчиков: %d", len(states)),
+		Sensors: states,
+	}
 }
 
 // WriteCoil записывает значение в coil Modbus устройства
+// Включенный coil попадает под аварийный таймер: если его не подтвердить повторной записью,
+// сторожевой таймер выключит его через SAFETY_MAX_ON_MINUTES. Для coil с инверсией (inverted)
+// включенным считается false и выключается он записью true
 func (s *ModbusService) WriteCoil(req *models.WriteCoilRequest) *models.WriteCoilResponse {
 	log.Printf("WriteCoil - box_id: %s, register: %s, value: %v", req.BoxID, req.Register, req.Value)
 
@@ -118,11 +181,9 @@ func (s *ModbusService) WriteCoil(req *models.WriteCoilRequest) *models.WriteCoi
 		}
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if err := s.ensureConnectedLocked(); err != nil {
-		log.Printf("Modbus не готов - box_id: %s, error: %v", req.BoxID, err)
+	device, err := s.device(req.Device)
+	if err != nil {
+		log.Printf("Modbus устройство не найдено - box_id: %s, device: %s, error: %v", req.BoxID, req.Device, err)
 		return &models.WriteCoilResponse{Success: false, Message: err.Error()}
 	}
 
@@ -136,13 +197,17 @@ func (s *ModbusService) WriteCoil(req *models.WriteCoilRequest) *models.WriteCoi
 		}
 	}
 
-	// Выполняем запись с retry механизмом
+	// Выполняем запись с retry механизмом (между попытками устройство переподключается в фоне)
 	var lastError error
 	for attempt := 1; attempt <= 3; attempt++ {
-		_, err := s.client.WriteSingleCoil(address, uint16(boolToUint16(req.Value)))
+		err := device.Exec(func(client modbus.Client) error {
+			_, err := client.WriteSingleCoil(address, boolToUint16(req.Value))
+			return err
+		})
 		if err == nil {
-			log.Printf("Modbus: успешно записано значение %v в регистр %s - box_id: %s, register: %s, value: %v",
-				req.Value, req.Register, req.BoxID, req.Register, req.Value)
+			s.watchdog.trackWrite(device.Name(), address, req.BoxID.String(), req.Register, req.Value, req.Inverted)
+			log.Printf("Modbus: успешно записано значение %v в регистр %s - box_id: %s, device: %s, register: %s, value: %v",
+				req.Value, req.Register, req.BoxID, device.Name(), req.Register, req.Value)
 			return &models.WriteCoilResponse{
 				Success: true,
 				Message: fmt.Sprintf("Успешно записано значение %v в регистр %s", req.Value, req.Register),
@@ -150,24 +215,22 @@ func (s *ModbusService) WriteCoil(req *models.WriteCoilRequest) *models.WriteCoi
 		}
 
 		lastError = err
-		log.Printf("Modbus: попытка %d неудачна - box_id: %s, register: %s, attempt: %d, error: %v",
-			attempt, req.BoxID, req.Register, attempt, err)
-
-		// Пробуем переподключиться и повторить
-		s.connected = false
-		_ = s.handler.Close()
-		if recErr := s.ensureConnectedLocked(); recErr != nil {
-			log.Printf("Modbus: не удалось переподключиться после ошибки: %v", recErr)
+		log.Printf("Modbus: попытка %d неудачна - box_id: %s, device: %s, register: %s, attempt: %d, error: %v",
+			attempt, req.BoxID, device.Name(), req.Register, attempt, err)
+
+		// Исключение устройства (неверный адрес и т.п.) не исправится повтором
+		if isModbusException(err) {
+			break
 		}
 		if attempt < 3 {
 			time.Sleep(2 * time.Second)
 		}
 	}
 
-	log.Printf("Modbus: все попытки неудачны - box_id: %s, register: %s, error: %v", req.BoxID, req.Register, lastError)
+	log.Printf("Modbus: все попытки неудачны - box_id: %s, device: %s, register: %s, error: %v", req.BoxID, device.Name(), req.Register, lastError)
 	return &models.WriteCoilResponse{
 		Success: false,
-		Message: fmt.Sprintf("Не удалось записать в Modbus после 3 попыток: %v", lastError),
+		Message: fmt.Sprintf("Не удалось записать в Modbus: %v", lastError),
 	}
 }
 
@@ -195,8 +258,10 @@ func (s *ModbusService) WriteLightCoil(req *models.WriteLightCoilRequest) *model
 
 	coilReq := &models.WriteCoilRequest{
 		BoxID:    req.BoxID,
+		Device:   req.Device,
 		Register: req.Register,
 		Value:    req.Value,
+		Inverted: req.Inverted,
 	}
 
 	coilResp := s.WriteCoil(coilReq)
@@ -231,8 +296,10 @@ func (s *ModbusService) WriteChemistryCoil(req *models.WriteChemistryCoilRequest
 
 	coilReq := &models.WriteCoilRequest{
 		BoxID:    req.BoxID,
+		Device:   req.Device,
 		Register: req.Register,
 		Value:    req.Value,
+		Inverted: req.Inverted,
 	}
 
 	coilResp := s.WriteCoil(coilReq)
@@ -256,17 +323,19 @@ func (s *ModbusService) TestConnection(req *models.TestConnectionRequest) *model
 		}
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if err := s.ensureConnectedLocked(); err != nil {
-		log.Printf("Modbus не готов для теста - box_id: %s, error: %v", req.BoxID, err)
+	device, err := s.device(req.Device)
+	if err != nil {
+		log.Printf("Modbus устройство не найдено для теста - box_id: %s, device: %s, error: %v", req.BoxID, req.Device, err)
 		return &models.TestConnectionResponse{Success: false, Message: err.Error()}
 	}
 
 	// Пытаемся прочитать регистр для проверки соединения
-	_, err := s.client.ReadCoils(1, 1)
+	err = device.Exec(func(client modbus.Client) error {
+		_, err := client.ReadCoils(1, 1)
+		return err
+	})
 	if err != nil {
-		log.Printf("Ошибка чтения coil для теста - box_id: %s, error: %v", req.BoxID, err)
+		log.Printf("Ошибка чтения coil для теста - box_id: %s, device: %s, error: %v", req.BoxID, device.Name(), err)
 		return &models.TestConnectionResponse{
 			Success: false,
 			Message: fmt.Sprintf("Не удалось подключиться к Modbus устройству: %v", err),
@@ -305,6 +374,7 @@ func (s *ModbusService) TestCoil(req *models.TestCoilRequest) *models.TestCoilRe
 	// Тестируем запись
 	coilReq := &models.WriteCoilRequest{
 		BoxID:    req.BoxID,
+		Device:   req.Device,
 		Register: req.Register,
 		Value:    req.Value,
 	}
@@ -317,7 +387,69 @@ func (s *ModbusService) TestCoil(req *models.TestCoilRequest) *models.TestCoilRe
 	}
 }
 
-// удалено: создание клиента на каждый запрос, используем долгоживущее соединение
+// ReadCoils читает фактическое состояние coils устройства
+// Все регистры читаются на одном соединении; ошибка любого регистра завершает чтение целиком
+func (s *ModbusService) ReadCoils(req *models.ReadCoilsRequest) *models.ReadCoilsResponse {
+	log.Printf("ReadCoils - box_id: %s, device: %s, registers: %v", req.BoxID, req.Device, req.Registers)
+
+	if !s.config.ModbusEnabled {
+		return &models.ReadCoilsResponse{
+			Success: false,
+			Message: "Modbus протокол отключен в конфигурации",
+		}
+	}
+
+	addresses := make([]uint16, len(req.Registers))
+	for i, register := range req.Registers {
+		if !s.isValidHexRegister(register) {
+			return &models.ReadCoilsResponse{
+				Success: false,
+				Message: fmt.Sprintf("Неверный формат регистра: %s", register),
+			}
+		}
+		address, err := s.hexToUint16(register)
+		if err != nil {
+			return &models.ReadCoilsResponse{
+				Success: false,
+				Message: fmt.Sprintf("Неверный формат регистра: %v", err),
+			}
+		}
+		addresses[i] = address
+	}
+
+	device, err := s.device(req.Device)
+	if err != nil {
+		return &models.ReadCoilsResponse{Success: false, Message: err.Error()}
+	}
+
+	values := make(map[string]bool, len(req.Registers))
+	err = device.Exec(func(client modbus.Client) error {
+		for i, address := range addresses {
+			result, err := client.ReadCoils(address, 1)
+			if err != nil {
+				return fmt.Errorf("регистр %s: %w", req.Registers[i], err)
+			}
+			if len(result) == 0 {
+				return fmt.Errorf("регистр %s: пустой ответ устройства", req.Registers[i])
+			}
+			values[req.Registers[i]] = result[0]&1 == 1
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Ошибка чтения coils - box_id: %s, device: %s, error: %v", req.BoxID, device.Name(), err)
+		return &models.ReadCoilsResponse{
+			Success: false,
+			Message: fmt.Sprintf("Не удалось прочитать coils: %v", err),
+		}
+	}
+
+	return &models.ReadCoilsResponse{
+		Success: true,
+		Message: "Состояние coils прочитано",
+		Values:  values,
+	}
+}
 
 // isValidHexRegister проверяет, что регистр в правильном hex формате
 func (s *ModbusService) isValidHexRegister(register string) bool {
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/goburrow/modbus"
+
+	"modbus-server/internal/models"
+)
+
+// WriteRegister записывает значение в holding register устройства в заданной кодировке
+// uint16 и int16 занимают один регистр, float32 - два подряд идущих с указанным порядком слов
+func (s *ModbusService) WriteRegister(req *models.WriteRegisterRequest) *models.WriteRegisterResponse {
+	log.Printf("WriteRegister - box_id: %s, register: %s, data_type: %s, value: %v", req.BoxID, req.Register, req.DataType, req.Value)
+
+	if !s.config.ModbusEnabled {
+		log.Printf("Modbus отключен, пропускаем запись в регистр %s - box_id: %s, value: %v", req.Register, req.BoxID, req.Value)
+		return &models.WriteRegisterResponse{
+			Success: true,
+			Message: "Modbus отключен в конфигурации",
+		}
+	}
+
+	address, err := s.parseRegister(req.Register)
+	if err != nil {
+		return &models.WriteRegisterResponse{Success: false, Message: err.Error()}
+	}
+
+	words, err := encodeRegister(req.RegisterFormat, req.Value)
+	if err != nil {
+		return &models.WriteRegisterResponse{Success: false, Message: err.Error()}
+	}
+
+	device, err := s.device(req.Device)
+	if err != nil {
+		log.Printf("Modbus устройство не найдено - box_id: %s, device: %s, error: %v", req.BoxID, req.Device, err)
+		return &models.WriteRegisterResponse{Success: false, Message: err.Error()}
+	}
+
+	// Выполняем запись с retry механизмом, как для coils
+	var lastError error
+	for attempt := 1; attempt <= 3; attempt++ {
+		err := device.Exec(func(client modbus.Client) error {
+			if len(words) == 1 {
+				_, err := client.WriteSingleRegister(address, words[0])
+				return err
+			}
+			_, err := client.WriteMultipleRegisters(address, uint16(len(words)), wordsToBytes(words))
+			return err
+		})
+		if err == nil {
+			log.Printf("Modbus: успешно записано значение %v в регистр %s - box_id: %s, device: %s, data_type: %s",
+				req.Value, req.Register, req.BoxID, device.Name(), registerType(req.RegisterFormat))
+			return &models.WriteRegisterResponse{
+				Success: true,
+				Message: fmt.Sprintf("Успешно записано значение %v в регистр %s", req.Value, req.Register),
+			}
+		}
+
+		lastError = err
+		log.Printf("Modbus: попытка %d записи регистра неудачна - box_id: %s, device: %s, register: %s, error: %v",
+			attempt, req.BoxID, device.Name(), req.Register, err)
+
+		if isModbusException(err) {
+			break
+		}
+		if attempt < 3 {
+			time.Sleep(2 * time.Second)
+		}
+	}
+
+	return &models.WriteRegisterResponse{
+		Success: false,
+		Message: fmt.Sprintf("Не удалось записать регистр в Modbus: %v", lastError),
+	}
+}
+
+// ReadRegisters читает holding registers устройства и декодирует их в заданных кодировках
+func (s *ModbusService) ReadRegisters(req *models.ReadRegistersRequest) *models.ReadRegistersResponse {
+	log.Printf("ReadRegisters - box_id: %s, device: %s, registers: %v", req.BoxID, req.Device, req.Registers)
+
+	if !s.config.ModbusEnabled {
+		return &models.ReadRegistersResponse{
+			Success: false,
+			Message: "Modbus протокол отключен в конфигурации",
+		}
+	}
+
+	addresses := make([]uint16, len(req.Registers))
+	for i, format := range req.Registers {
+		address, err := s.parseRegister(format.Register)
+		if err != nil {
+			return &models.ReadRegistersResponse{Success: false, Message: err.Error()}
+		}
+		addresses[i] = address
+	}
+
+	device, err := s.device(req.Device)
+	if err != nil {
+		return &models.ReadRegistersResponse{Success: false, Message: err.Error()}
+	}
+
+	values := make(map[string]float64, len(req.Registers))
+	err = device.Exec(func(client modbus.Client) error {
+		for i, format := range req.Registers {
+			count := registerWords(format)
+			result, err := client.ReadHoldingRegisters(addresses[i], count)
+			if err != nil {
+				return fmt.Errorf("регистр %s: %w", format.Register, err)
+			}
+			if len(result) != int(count)*2 {
+				return fmt.Errorf("регистр %s: неверная длина ответа устройства", format.Register)
+			}
+			words := make([]uint16, count)
+			for j := range words {
+				words[j] = binary.BigEndian.Uint16(result[2*j:])
+			}
+			values[format.Register] = decodeRegister(format, words)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Ошибка чтения регистров - box_id: %s, device: %s, error: %v", req.BoxID, device.Name(), err)
+		return &models.ReadRegistersResponse{
+			Success: false,
+			Message: fmt.Sprintf("Не удалось прочитать регистры: %v", err),
+		}
+	}
+
+	return &models.ReadRegistersResponse{
+		Success: true,
+		Message: "Регистры прочитаны",
+		Values:  values,
+	}
+}
+
+// parseRegister проверяет формат регистра и возвращает его адрес
+func (s *ModbusService) parseRegister(register string) (uint16, error) {
+	if !s.isValidHexRegister(register) {
+		return 0, fmt.Errorf("Неверный формат регистра: %s", register)
+	}
+	address, err := s.hexToUint16(register)
+	if err != nil {
+		return 0, fmt.Errorf("Неверный формат регистра: %v", err)
+	}
+	return address, nil
+}
+
+// registerType возвращает тип значения с учетом значения по умолчанию
+func registerType(format models.RegisterFormat) string {
+	if format.DataType == "" {
+		return models.RegisterTypeUint16
+	}
+	return format.DataType
+}
+
+// registerWords количество регистров, которое занимает значение
+func registerWords(format models.RegisterFormat) uint16 {
+	if registerType(format) == models.RegisterTypeFloat32 {
+		return 2
+	}
+	return 1
+}
+
+// encodeRegister кодирует значение в слова регистров; значения вне диапазона типа отклоняются
+func encodeRegister(format models.RegisterFormat, value float64) ([]uint16, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, fmt.Errorf("недопустимое значение %v", value)
+	}
+
+	switch registerType(format) {
+	case models.RegisterTypeUint16:
+		rounded := math.Round(value)
+		if rounded < 0 || rounded > math.MaxUint16 {
+			return nil, fmt.Errorf("значение %v вне диапазона uint16", value)
+		}
+		return []uint16{uint16(rounded)}, nil
+
+	case models.RegisterTypeInt16:
+		rounded := math.Round(value)
+		if rounded < math.MinInt16 || rounded > math.MaxInt16 {
+			return nil, fmt.Errorf("значение %v вне диапазона int16", value)
+		}
+		return []uint16{uint16(int16(rounded))}, nil
+
+	case models.RegisterTypeFloat32:
+		if math.Abs(value) > math.MaxFloat32 {
+			return nil, fmt.Errorf("значение %v вне диапазона float32", value)
+		}
+		bits := math.Float32bits(float32(value))
+		high, low := uint16(bits>>16), uint16(bits)
+		if format.WordOrder == models.WordOrderLittle {
+			return []uint16{low, high}, nil
+		}
+		return []uint16{high, low}, nil
+	}
+
+	return nil, fmt.Errorf("неизвестный тип регистра: %s", format.DataType)
+}
+
+// decodeRegister декодирует слова регистров в значение
+func decodeRegister(format models.RegisterFormat, words []uint16) float64 {
+	switch registerType(format) {
+	case models.RegisterTypeInt16:
+		return float64(int16(words[0]))
+	case models.RegisterTypeFloat32:
+		high, low := words[0], words[1]
+		if format.WordOrder == models.WordOrderLittle {
+			high, low = low, high
+		}
+		return float64(math.Float32frombits(uint32(high)<<16 | uint32(low)))
+	}
+	return float64(words[0])
+}
+
+// wordsToBytes упаковывает слова регистров в байты в порядке Modbus (старший байт первым)
+func wordsToBytes(words []uint16) []byte {
+	data := make([]byte, 2*len(words))
+	for i, word := range words {
+		binary.BigEndian.PutUint16(data[2*i:], word)
+	}
+	return data
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"modbus-server/internal/models"
+)
+
+func TestEncodeRegister(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   models.RegisterFormat
+		value    float64
+		expected []uint16
+		wantErr  bool
+	}{
+		{name: "Default type is uint16", format: models.RegisterFormat{}, value: 1234, expected: []uint16{1234}},
+		{name: "Uint16 rounds", format: models.RegisterFormat{DataType: models.RegisterTypeUint16}, value: 41.6, expected: []uint16{42}},
+		{name: "Uint16 max", format: models.RegisterFormat{DataType: models.RegisterTypeUint16}, value: 65535, expected: []uint16{0xFFFF}},
+		{name: "Uint16 above max", format: models.RegisterFormat{DataType: models.RegisterTypeUint16}, value: 65536, wantErr: true},
+		{name: "Uint16 negative", format: models.RegisterFormat{DataType: models.RegisterTypeUint16}, value: -1, wantErr: true},
+		{name: "Uint16 small negative rounds to zero", format: models.RegisterFormat{DataType: models.RegisterTypeUint16}, value: -0.4, expected: []uint16{0}},
+		{name: "Int16 positive", format: models.RegisterFormat{DataType: models.RegisterTypeInt16}, value: 300, expected: []uint16{300}},
+		{name: "Int16 negative is two's complement", format: models.RegisterFormat{DataType: models.RegisterTypeInt16}, value: -2, expected: []uint16{0xFFFE}},
+		{name: "Int16 min", format: models.RegisterFormat{DataType: models.RegisterTypeInt16}, value: -32768, expected: []uint16{0x8000}},
+		{name: "Int16 max", format: models.RegisterFormat{DataType: models.RegisterTypeInt16}, value: 32767, expected: []uint16{0x7FFF}},
+		{name: "Int16 below min", format: models.RegisterFormat{DataType: models.RegisterTypeInt16}, value: -32769, wantErr: true},
+		{name: "Int16 above max", format: models.RegisterFormat{DataType: models.RegisterTypeInt16}, value: 32768, wantErr: true},
+		{name: "Float32 big word order by default", format: models.RegisterFormat{DataType: models.RegisterTypeFloat32}, value: 1.5, expected: []uint16{0x3FC0, 0x0000}},
+		{name: "Float32 big word order", format: models.RegisterFormat{DataType: models.RegisterTypeFloat32, WordOrder: models.WordOrderBig}, value: -2.5, expected: []uint16{0xC020, 0x0000}},
+		{name: "Float32 little word order", format: models.RegisterFormat{DataType: models.RegisterTypeFloat32, WordOrder: models.WordOrderLittle}, value: -2.5, expected: []uint16{0x0000, 0xC020}},
+		{name: "Float32 both words used", format: models.RegisterFormat{DataType: models.RegisterTypeFloat32, WordOrder: models.WordOrderLittle}, value: 3.1415927, expected: []uint16{0x0FDB, 0x4049}},
+		{name: "Float32 above max", format: models.RegisterFormat{DataType: models.RegisterTypeFloat32}, value: math.MaxFloat64, wantErr: true},
+		{name: "NaN", format: models.RegisterFormat{DataType: models.RegisterTypeFloat32}, value: math.NaN(), wantErr: true},
+		{name: "Infinity", format: models.RegisterFormat{}, value: math.Inf(1), wantErr: true},
+		{name: "Unknown type", format: models.RegisterFormat{DataType: "int32"}, value: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeRegister(tt.format, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("encodeRegister(%v) = %#04x, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("encodeRegister(%v) unexpected error: %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("encodeRegister(%v) = %#04x, want %#04x", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeRegister(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   models.RegisterFormat
+		words    []uint16
+		expected float64
+	}{
+		{name: "Default type is uint16", format: models.RegisterFormat{}, words: []uint16{0xFFFE}, expected: 65534},
+		{name: "Int16 negative", format: models.RegisterFormat{DataType: models.RegisterTypeInt16}, words: []uint16{0xFFFE}, expected: -2},
+		{name: "Int16 min", format: models.RegisterFormat{DataType: models.RegisterTypeInt16}, words: []uint16{0x8000}, expected: -32768},
+		{name: "Float32 big word order by default", format: models.RegisterFormat{DataType: models.RegisterTypeFloat32}, words: []uint16{0x3FC0, 0x0000}, expected: 1.5},
+		{name: "Float32 little word order", format: models.RegisterFormat{DataType: models.RegisterTypeFloat32, WordOrder: models.WordOrderLittle}, words: []uint16{0x0000, 0xC020}, expected: -2.5},
+		{name: "Float32 words swapped in wrong order", format: models.RegisterFormat{DataType: models.RegisterTypeFloat32, WordOrder: models.WordOrderBig}, words: []uint16{0x0000, 0x3FC0}, expected: float64(math.Float32frombits(0x00003FC0))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeRegister(tt.format, tt.words); got != tt.expected {
+				t.Errorf("decodeRegister(%#04x) = %v, want %v", tt.words, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeDecodeRegisterRoundTrip(t *testing.T) {
+	formats := []models.RegisterFormat{
+		{DataType: models.RegisterTypeUint16},
+		{DataType: models.RegisterTypeInt16},
+		{DataType: models.RegisterTypeFloat32, WordOrder: models.WordOrderBig},
+		{DataType: models.RegisterTypeFloat32, WordOrder: models.WordOrderLittle},
+	}
+	values := []float64{0, 1, 100, 32767}
+
+	for _, format := range formats {
+		for _, value := range values {
+			words, err := encodeRegister(format, value)
+			if err != nil {
+				t.Fatalf("encodeRegister(%+v, %v) unexpected error: %v", format, value, err)
+			}
+			if len(words) != int(registerWords(format)) {
+				t.Errorf("encodeRegister(%+v, %v) returned %d words, want %d", format, value, len(words), registerWords(format))
+			}
+			if got := decodeRegister(format, words); got != value {
+				t.Errorf("decodeRegister(encodeRegister(%+v, %v)) = %v", format, value, got)
+			}
+		}
+	}
+}
+
+func TestWriteReadRegistersFloat32WordOrder(t *testing.T) {
+	service, sim := newTestService(t)
+
+	little := models.RegisterFormat{Register: "0x0100", DataType: models.RegisterTypeFloat32, WordOrder: models.WordOrderLittle}
+	resp := service.WriteRegister(&models.WriteRegisterRequest{BoxID: uuid.New(), RegisterFormat: little, Value: -2.5})
+	if !resp.Success {
+		t.Fatalf("WriteRegister() failed: %s", resp.Message)
+	}
+
+	// Младшее слово в первом регистре
+	var registers map[string]uint16
+	for _, unit := range sim.State().Units {
+		if unit.UnitID == 1 {
+			registers = unit.HoldingRegisters
+		}
+	}
+	if expected := map[string]uint16{"0x0101": 0xC020}; !reflect.DeepEqual(registers, expected) {
+		t.Errorf("simulator holding registers = %#04x, want %#04x", registers, expected)
+	}
+
+	big := little
+	big.Register = "0x100"
+	big.WordOrder = models.WordOrderBig
+	read := service.ReadRegisters(&models.ReadRegistersRequest{BoxID: uuid.New(), Registers: []models.RegisterFormat{little, big}})
+	if !read.Success {
+		t.Fatalf("ReadRegisters() failed: %s", read.Message)
+	}
+	if read.Values["0x0100"] != -2.5 {
+		t.Errorf("little word order value = %v, want -2.5", read.Values["0x0100"])
+	}
+	if expected := float64(math.Float32frombits(0x0000C020)); read.Values["0x100"] != expected {
+		t.Errorf("big word order value = %v, want %v", read.Values["0x100"], expected)
+	}
+}
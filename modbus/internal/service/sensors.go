@@ -0,0 +1,416 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goburrow/modbus"
+	"github.com/google/uuid"
+
+	"modbus-server/internal/config"
+	"modbus-server/internal/models"
+)
+
+const (
+	// maxDiscreteInputs сколько дискретных входов можно прочитать одним запросом (ограничение протокола)
+	maxDiscreteInputs = 2000
+	// maxPendingEvents сколько неотправленных изменений хранится, пока бэкенд недоступен
+	maxPendingEvents = 1000
+	// sensorPushRetryInterval пауза перед повторной отправкой изменений недоступному бэкенду
+	sensorPushRetryInterval = 5 * time.Second
+)
+
+// sensorKey дискретный вход конкретного устройства
+type sensorKey struct {
+	device  string
+	address uint16
+}
+
+// sensorState состояние одного датчика присутствия
+type sensorState struct {
+	boxID     uuid.UUID
+	register  string
+	value     *bool // Подтвержденное значение, nil - датчик еще не прочитан
+	changedAt time.Time
+
+	pending      *bool // Новое значение, которое еще не продержалось SENSOR_DEBOUNCE_MS
+	pendingSince time.Time
+
+	lastError string
+}
+
+// SensorPoller опрашивает дискретные входы датчиков присутствия и отправляет изменения бэкенду
+// Список датчиков задает бэкенд (PUT /api/v1/modbus/sensors), поэтому после перезапуска сервера
+// датчики опрашиваются снова с его следующей синхронизации. Значение считается изменившимся, только
+// если оно продержалось SENSOR_DEBOUNCE_MS: так дребезг датчика не запускает сессии
+type SensorPoller struct {
+	service    *ModbusService
+	config     *config.Config
+	debounce   time.Duration
+	httpClient *http.Client
+
+	mu      sync.Mutex // Защищает sensors
+	sensors map[sensorKey]*sensorState
+
+	eventsMu   sync.Mutex // Защищает events и eventsHead
+	events     []models.SensorEvent
+	eventsHead uint64 // Порядковый номер первого изменения в events
+	pushSignal chan struct{}
+
+	// Используются только в циклах опроса и отправки
+	failingDevices map[string]bool
+	pushFailing    bool
+
+	done chan struct{}
+}
+
+// newSensorPoller создает опрос датчиков; циклы запускаются методом start
+func newSensorPoller(service *ModbusService, cfg *config.Config) *SensorPoller {
+	return &SensorPoller{
+		service:        service,
+		config:         cfg,
+		debounce:       time.Duration(cfg.SensorDebounceMs) * time.Millisecond,
+		httpClient:     &http.Client{Timeout: 5 * time.Second},
+		sensors:        make(map[sensorKey]*sensorState),
+		pushSignal:     make(chan struct{}, 1),
+		failingDevices: make(map[string]bool),
+		done:           make(chan struct{}),
+	}
+}
+
+// start запускает циклы опроса датчиков и отправки изменений
+func (p *SensorPoller) start() {
+	go p.pollLoop()
+	if p.config.BackendEventsURL != "" {
+		log.Printf("Датчики: изменения отправляются на %s", p.config.BackendEventsURL)
+		go p.pushLoop()
+	}
+}
+
+// stop останавливает циклы опроса и отправки
+func (p *SensorPoller) stop() {
+	close(p.done)
+}
+
+// SetSensors заменяет список опрашиваемых датчиков
+// Датчики, которые уже опрашивались для того же бокса, сохраняют свое состояние
+func (p *SensorPoller) SetSensors(sensors []models.Sensor) error {
+	next := make(map[sensorKey]*sensorState, len(sensors))
+	for _, sensor := range sensors {
+		if !p.service.isValidHexRegister(sensor.Register) {
+			return fmt.Errorf("неверный формат регистра: %s", sensor.Register)
+		}
+		address, err := p.service.hexToUint16(sensor.Register)
+		if err != nil {
+			return fmt.Errorf("неверный формат регистра: %v", err)
+		}
+		device, err := p.service.device(sensor.Device)
+		if err != nil {
+			return err
+		}
+
+		key := sensorKey{device: device.Name(), address: address}
+		if _, ok := next[key]; ok {
+			return fmt.Errorf("регистр %s устройства %s указан несколько раз", sensor.Register, device.Name())
+		}
+		next[key] = &sensorState{boxID: sensor.BoxID, register: sensor.Register}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, state := range next {
+		if current, ok := p.sensors[key]; ok && current.boxID == state.boxID {
+			current.register = state.register
+			next[key] = current
+		}
+	}
+	if len(next) != len(p.sensors) {
+		log.Printf("Датчики: опрашивается %d датчиков (было %d)", len(next), len(p.sensors))
+	}
+	p.sensors = next
+	return nil
+}
+
+// States возвращает состояние всех датчиков по устройствам и адресам
+func (p *SensorPoller) States() []models.SensorState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys := make([]sensorKey, 0, len(p.sensors))
+	for key := range p.sensors {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].device != keys[j].device {
+			return keys[i].device < keys[j].device
+		}
+		return keys[i].address < keys[j].address
+	})
+
+	states := make([]models.SensorState, 0, len(keys))
+	for _, key := range keys {
+		sensor := p.sensors[key]
+		state := models.SensorState{
+			BoxID:    sensor.boxID,
+			Device:   key.device,
+			Register: sensor.register,
+			Error:    sensor.lastError,
+		}
+		if sensor.value != nil {
+			value := *sensor.value
+			changedAt := sensor.changedAt
+			state.Value = &value
+			state.ChangedAt = &changedAt
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// pollLoop опрашивает датчики с интервалом SENSOR_POLL_INTERVAL_MS
+func (p *SensorPoller) pollLoop() {
+	ticker := time.NewTicker(time.Duration(p.config.SensorPollIntervalMs) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.poll()
+		}
+	}
+}
+
+// poll читает дискретные входы всех датчиков: по каждому устройству подряд идущими диапазонами
+func (p *SensorPoller) poll() {
+	p.mu.Lock()
+	byDevice := make(map[string][]uint16)
+	for key := range p.sensors {
+		byDevice[key.device] = append(byDevice[key.device], key.address)
+	}
+	p.mu.Unlock()
+
+	for _, name := range p.service.deviceOrder {
+		addresses := byDevice[name]
+		if len(addresses) == 0 {
+			continue
+		}
+		sort.Slice(addresses, func(i, j int) bool { return addresses[i] < addresses[j] })
+
+		device := p.service.devices[name]
+		var pollErr error
+		for start := 0; start < len(addresses); {
+			end := start
+			for end+1 < len(addresses) && addresses[end+1]-addresses[start] < maxDiscreteInputs {
+				end++
+			}
+			if err := p.readSpan(device, addresses[start:end+1]); err != nil {
+				pollErr = err
+			}
+			start = end + 1
+		}
+
+		// Ошибка логируется только при смене состояния, чтобы недоступное устройство не засоряло лог
+		if pollErr != nil && !p.failingDevices[name] {
+			log.Printf("Датчики: не удалось опросить устройство %s: %v", name, pollErr)
+		} else if pollErr == nil && p.failingDevices[name] {
+			log.Printf("Датчики: опрос устройства %s восстановлен", name)
+		}
+		p.failingDevices[name] = pollErr != nil
+	}
+}
+
+// readSpan читает одним запросом дискретные входы от первого до последнего адреса и применяет значения
+func (p *SensorPoller) readSpan(device *Device, addresses []uint16) error {
+	start := addresses[0]
+	quantity := addresses[len(addresses)-1] - start + 1
+
+	var bits []byte
+	err := device.Exec(func(client modbus.Client) error {
+		result, err := client.ReadDiscreteInputs(start, quantity)
+		if err == nil && len(result) < int(quantity+7)/8 {
+			err = fmt.Errorf("короткий ответ устройства: %d байт", len(result))
+		}
+		bits = result
+		return err
+	})
+
+	now := time.Now()
+	var events []models.SensorEvent
+
+	p.mu.Lock()
+	for _, address := range addresses {
+		state, ok := p.sensors[sensorKey{device: device.Name(), address: address}]
+		if !ok {
+			// Датчик убрали из списка, пока шел опрос
+			continue
+		}
+		if err != nil {
+			state.lastError = err.Error()
+			continue
+		}
+		state.lastError = ""
+
+		offset := address - start
+		value := bits[offset/8]&(1<<(offset%8)) != 0
+		if event, changed := p.apply(state, value, now); changed {
+			event.Device = device.Name()
+			events = append(events, event)
+		}
+	}
+	p.mu.Unlock()
+
+	p.enqueue(events)
+	return err
+}
+
+// apply применяет прочитанное значение с подавлением дребезга; вызывается под mu
+// Первое прочитанное значение принимается сразу, чтобы бэкенд узнал начальное состояние
+func (p *SensorPoller) apply(state *sensorState, value bool, now time.Time) (models.SensorEvent, bool) {
+	if state.value != nil && *state.value == value {
+		state.pending = nil
+		return models.SensorEvent{}, false
+	}
+
+	changedAt := now
+	if state.value != nil {
+		if state.pending == nil || *state.pending != value {
+			pending := value
+			state.pending = &pending
+			state.pendingSince = now
+		}
+		if now.Sub(state.pendingSince) < p.debounce {
+			return models.SensorEvent{}, false
+		}
+		changedAt = state.pendingSince
+	}
+
+	confirmed := value
+	state.value = &confirmed
+	state.changedAt = changedAt
+	state.pending = nil
+
+	return models.SensorEvent{
+		BoxID:     state.boxID,
+		Register:  state.register,
+		Value:     value,
+		ChangedAt: changedAt,
+	}, true
+}
+
+// enqueue добавляет изменения в очередь отправки бэкенду
+func (p *SensorPoller) enqueue(events []models.SensorEvent) {
+	if len(events) == 0 {
+		return
+	}
+	for _, event := range events {
+		log.Printf("Датчики: box_id: %s, device: %s, register: %s, значение: %v", event.BoxID, event.Device, event.Register, event.Value)
+	}
+	if p.config.BackendEventsURL == "" {
+		return
+	}
+
+	p.eventsMu.Lock()
+	p.events = append(p.events, events...)
+	if dropped := len(p.events) - maxPendingEvents; dropped > 0 {
+		log.Printf("Датчики: бэкенд недоступен, отброшено %d старых изменений", dropped)
+		p.events = p.events[dropped:]
+		p.eventsHead += uint64(dropped)
+	}
+	p.eventsMu.Unlock()
+
+	select {
+	case p.pushSignal <- struct{}{}:
+	default:
+	}
+}
+
+// pushLoop отправляет накопленные изменения бэкенду сразу после опроса и повторяет при ошибке
+func (p *SensorPoller) pushLoop() {
+	ticker := time.NewTicker(sensorPushRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-p.pushSignal:
+			p.push()
+		case <-ticker.C:
+			p.push()
+		}
+	}
+}
+
+// push отправляет бэкенду все накопленные изменения одним запросом
+// При ошибке изменения остаются в очереди; бэкенд применяет их идемпотентно, поэтому повтор безопасен
+func (p *SensorPoller) push() {
+	p.eventsMu.Lock()
+	events := append([]models.SensorEvent(nil), p.events...)
+	head := p.eventsHead
+	p.eventsMu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	if err := p.post(events); err != nil {
+		if !p.pushFailing {
+			log.Printf("Датчики: не удалось отправить %d изменений бэкенду: %v", len(events), err)
+		}
+		p.pushFailing = true
+		return
+	}
+	if p.pushFailing {
+		log.Printf("Датчики: отправка изменений бэкенду восстановлена")
+	}
+	p.pushFailing = false
+
+	// Пока шла отправка, очередь могла пополниться или сдвинуться из-за переполнения
+	p.eventsMu.Lock()
+	if end := head + uint64(len(events)); end > p.eventsHead {
+		sent := int(end - p.eventsHead)
+		if sent > len(p.events) {
+			sent = len(p.events)
+		}
+		p.events = p.events[sent:]
+		p.eventsHead += uint64(sent)
+	}
+	p.eventsMu.Unlock()
+}
+
+// post выполняет HTTP запрос к бэкенду
+func (p *SensorPoller) post(events []models.SensorEvent) error {
+	body, err := json.Marshal(models.SensorEventsRequest{Events: events})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.config.BackendEventsURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.config.BackendEventsToken != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.BackendEventsToken)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("бэкенд ответил статусом %d", resp.StatusCode)
+	}
+	return nil
+}
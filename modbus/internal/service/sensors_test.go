@@ -0,0 +1,119 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"modbus-server/internal/config"
+)
+
+func TestSensorPollerApply(t *testing.T) {
+	type read struct {
+		at        time.Duration // Время чтения от начала
+		value     bool
+		event     bool          // Ожидается изменение
+		changedAt time.Duration // Ожидаемое время изменения от начала
+	}
+
+	tests := []struct {
+		name     string
+		debounce time.Duration
+		reads    []read
+		final    bool
+	}{
+		{
+			name:     "First value accepted immediately",
+			debounce: time.Second,
+			reads: []read{
+				{at: 0, value: true, event: true, changedAt: 0},
+			},
+			final: true,
+		},
+		{
+			name:     "Same value is not an event",
+			debounce: time.Second,
+			reads: []read{
+				{at: 0, value: false, event: true, changedAt: 0},
+				{at: 500 * time.Millisecond, value: false},
+				{at: 5 * time.Second, value: false},
+			},
+			final: false,
+		},
+		{
+			name:     "Change confirmed after debounce with time of first read",
+			debounce: time.Second,
+			reads: []read{
+				{at: 0, value: false, event: true, changedAt: 0},
+				{at: 2 * time.Second, value: true},
+				{at: 2500 * time.Millisecond, value: true},
+				{at: 3 * time.Second, value: true, event: true, changedAt: 2 * time.Second},
+				{at: 4 * time.Second, value: true},
+			},
+			final: true,
+		},
+		{
+			name:     "Bounce shorter than debounce is ignored",
+			debounce: time.Second,
+			reads: []read{
+				{at: 0, value: false, event: true, changedAt: 0},
+				{at: 1 * time.Second, value: true},
+				{at: 1500 * time.Millisecond, value: false},
+				{at: 3 * time.Second, value: false},
+			},
+			final: false,
+		},
+		{
+			name:     "Bounce restarts debounce",
+			debounce: time.Second,
+			reads: []read{
+				{at: 0, value: false, event: true, changedAt: 0},
+				{at: 1 * time.Second, value: true},
+				{at: 1500 * time.Millisecond, value: false},
+				{at: 1800 * time.Millisecond, value: true},
+				{at: 2500 * time.Millisecond, value: true},
+				{at: 2800 * time.Millisecond, value: true, event: true, changedAt: 1800 * time.Millisecond},
+			},
+			final: true,
+		},
+		{
+			name:     "Zero debounce accepts every change",
+			debounce: 0,
+			reads: []read{
+				{at: 0, value: false, event: true, changedAt: 0},
+				{at: 100 * time.Millisecond, value: true, event: true, changedAt: 100 * time.Millisecond},
+				{at: 200 * time.Millisecond, value: false, event: true, changedAt: 200 * time.Millisecond},
+			},
+			final: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poller := newSensorPoller(nil, &config.Config{SensorDebounceMs: int(tt.debounce / time.Millisecond)})
+			state := &sensorState{boxID: uuid.New(), register: "0x0200"}
+			start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+			for i, r := range tt.reads {
+				event, changed := poller.apply(state, r.value, start.Add(r.at))
+				if changed != r.event {
+					t.Fatalf("read %d: apply(%v) changed = %v, want %v", i, r.value, changed, r.event)
+				}
+				if !changed {
+					continue
+				}
+				if event.BoxID != state.boxID || event.Register != state.register || event.Value != r.value {
+					t.Errorf("read %d: event = %+v, want %s %s=%v", i, event, state.boxID, state.register, r.value)
+				}
+				if want := start.Add(r.changedAt); !event.ChangedAt.Equal(want) {
+					t.Errorf("read %d: event changed at %v, want %v", i, event.ChangedAt, want)
+				}
+			}
+
+			if state.value == nil || *state.value != tt.final {
+				t.Errorf("confirmed value = %v, want %v", state.value, tt.final)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goburrow/modbus"
+
+	"modbus-server/internal/config"
+	"modbus-server/internal/models"
+)
+
+// safetyCheckInterval как часто проверяются coils, включенные дольше допустимого
+const safetyCheckInterval = 10 * time.Second
+
+// coilKey coil конкретного устройства
+type coilKey struct {
+	device  string
+	address uint16
+}
+
+// armedCoil включенный через сервер coil, который выключится, если его не подтвердят повторно
+type armedCoil struct {
+	boxID     string
+	register  string
+	inverted  bool // Устройство включено при false, выключается записью true
+	onSince   time.Time
+	refreshed time.Time
+}
+
+// heartbeatState состояние сигнала жизни одного устройства
+type heartbeatState struct {
+	value    uint16
+	lastBeat time.Time
+	failing  bool
+}
+
+// Watchdog сторожевой таймер сервера
+// Сигнал жизни: на каждом устройстве с фиксированным интервалом переключается coil (или увеличивается
+// holding register), чтобы программа ПЛК выключила все выходы, когда сигнал пропадет.
+// Аварийное выключение: coil, включенный через сервер, выключается, если его не подтвердили
+// повторной записью true в течение SAFETY_MAX_ON_MINUTES (например, при падении бэкенда).
+// Бэкенд подтверждает включенные coils сверкой раз в MODBUS_COIL_REFRESH_SECONDS
+type Watchdog struct {
+	service *ModbusService
+	config  *config.Config
+
+	heartbeatAddress uint16
+	maxOn            time.Duration
+
+	mu         sync.Mutex // Защищает armed и heartbeats
+	armed      map[coilKey]*armedCoil
+	heartbeats map[string]*heartbeatState
+
+	done chan struct{}
+}
+
+// newWatchdog создает сторожевой таймер; циклы запускаются методом start
+func newWatchdog(service *ModbusService, cfg *config.Config) *Watchdog {
+	w := &Watchdog{
+		service:    service,
+		config:     cfg,
+		maxOn:      time.Duration(cfg.SafetyMaxOnMinutes) * time.Minute,
+		armed:      make(map[coilKey]*armedCoil),
+		heartbeats: make(map[string]*heartbeatState),
+		done:       make(chan struct{}),
+	}
+
+	if cfg.HeartbeatRegister != "" {
+		if !service.isValidHexRegister(cfg.HeartbeatRegister) {
+			log.Fatalf("WATCHDOG_HEARTBEAT_REGISTER: неверный формат регистра: %s", cfg.HeartbeatRegister)
+		}
+		address, err := service.hexToUint16(cfg.HeartbeatRegister)
+		if err != nil {
+			log.Fatalf("WATCHDOG_HEARTBEAT_REGISTER: неверный формат регистра: %v", err)
+		}
+		w.heartbeatAddress = address
+	}
+
+	return w
+}
+
+// start запускает циклы сигнала жизни и аварийного выключения
+func (w *Watchdog) start() {
+	if w.config.HeartbeatRegister != "" {
+		log.Printf("Watchdog: сигнал жизни %s %s каждые %d сек", w.config.HeartbeatType, w.config.HeartbeatRegister, w.config.HeartbeatIntervalSeconds)
+		go w.heartbeatLoop()
+	}
+	if w.maxOn > 0 {
+		log.Printf("Watchdog: аварийное выключение coils через %s без подтверждения", w.maxOn)
+		go w.safetyLoop()
+	}
+}
+
+// stop останавливает циклы сторожевого таймера
+func (w *Watchdog) stop() {
+	close(w.done)
+}
+
+// trackWrite учитывает успешную запись coil: включение устройства запускает или продлевает таймер,
+// выключение снимает его. Для coil с инверсией устройство включает запись false
+func (w *Watchdog) trackWrite(device string, address uint16, boxID, register string, value, inverted bool) {
+	if w.maxOn <= 0 {
+		return
+	}
+
+	key := coilKey{device: device, address: address}
+	now := time.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if value == inverted {
+		delete(w.armed, key)
+		return
+	}
+	if coil, ok := w.armed[key]; ok && coil.inverted == inverted {
+		coil.boxID = boxID
+		coil.refreshed = now
+		return
+	}
+	w.armed[key] = &armedCoil{boxID: boxID, register: register, inverted: inverted, onSince: now, refreshed: now}
+}
+
+// heartbeatLoop отправляет сигнал жизни на все устройства с фиксированным интервалом
+func (w *Watchdog) heartbeatLoop() {
+	ticker := time.NewTicker(time.Duration(w.config.HeartbeatIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			for _, name := range w.service.deviceOrder {
+				w.beat(w.service.devices[name])
+			}
+		}
+	}
+}
+
+// beat отправляет один такт сигнала жизни на устройство
+// Ошибки логируются только при смене состояния, чтобы недоступное устройство не засоряло лог
+func (w *Watchdog) beat(device *Device) {
+	w.mu.Lock()
+	state, ok := w.heartbeats[device.Name()]
+	if !ok {
+		state = &heartbeatState{}
+		w.heartbeats[device.Name()] = state
+	}
+	next := state.value + 1
+	if w.config.HeartbeatType == config.HeartbeatTypeCoil {
+		next = state.value ^ 1
+	}
+	w.mu.Unlock()
+
+	err := device.ExecHeartbeat(func(client modbus.Client) error {
+		if w.config.HeartbeatType == config.HeartbeatTypeCoil {
+			_, err := client.WriteSingleCoil(w.heartbeatAddress, boolToUint16(next == 1))
+			return err
+		}
+		_, err := client.WriteSingleRegister(w.heartbeatAddress, next)
+		return err
+	})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err != nil {
+		if !state.failing {
+			log.Printf("Watchdog: не удалось отправить сигнал жизни на устройство %s: %v", device.Name(), err)
+		}
+		state.failing = true
+		return
+	}
+
+	if state.failing {
+		log.Printf("Watchdog: сигнал жизни на устройство %s восстановлен", device.Name())
+	}
+	state.failing = false
+	state.value = next
+	state.lastBeat = time.Now()
+}
+
+// safetyLoop периодически выключает coils, включенные дольше допустимого без подтверждения
+func (w *Watchdog) safetyLoop() {
+	ticker := time.NewTicker(safetyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.switchOffExpired()
+		}
+	}
+}
+
+// switchOffExpired выключает просроченные coils; при ошибке coil остается и выключится на следующей проверке
+func (w *Watchdog) switchOffExpired() {
+	now := time.Now()
+
+	w.mu.Lock()
+	var expired []coilKey
+	for key, coil := range w.armed {
+		if now.Sub(coil.refreshed) >= w.maxOn {
+			expired = append(expired, key)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, key := range expired {
+		device, ok := w.service.devices[key.device]
+		if !ok {
+			continue
+		}
+
+		w.mu.Lock()
+		coil, ok := w.armed[key]
+		if !ok || now.Sub(coil.refreshed) < w.maxOn {
+			// Coil выключили или подтвердили, пока шла проверка
+			w.mu.Unlock()
+			continue
+		}
+		boxID, register, inverted, onSince, refreshed := coil.boxID, coil.register, coil.inverted, coil.onSince, coil.refreshed
+		w.mu.Unlock()
+
+		// Coil с инверсией выключает устройство значением true
+		err := device.Exec(func(client modbus.Client) error {
+			_, err := client.WriteSingleCoil(key.address, boolToUint16(inverted))
+			return err
+		})
+		if err != nil {
+			log.Printf("Watchdog: не удалось выключить coil - box_id: %s, device: %s, register: %s, error: %v", boxID, key.device, register, err)
+			continue
+		}
+
+		w.mu.Lock()
+		// Coil, подтвержденный во время выключения, остается под таймером
+		if current, ok := w.armed[key]; ok && current.refreshed.Equal(refreshed) {
+			delete(w.armed, key)
+		}
+		w.mu.Unlock()
+
+		log.Printf("Watchdog: coil выключен аварийно, не подтвержден %s - box_id: %s, device: %s, register: %s, включен: %s",
+			w.maxOn, boxID, key.device, register, onSince.Format(time.RFC3339))
+	}
+}
+
+// Status возвращает состояние сторожевого таймера
+func (w *Watchdog) Status() *models.WatchdogStatusResponse {
+	resp := &models.WatchdogStatusResponse{
+		HeartbeatEnabled:         w.config.HeartbeatRegister != "",
+		HeartbeatType:            w.config.HeartbeatType,
+		HeartbeatRegister:        w.config.HeartbeatRegister,
+		HeartbeatIntervalSeconds: w.config.HeartbeatIntervalSeconds,
+		SafetyMaxOnMinutes:       w.config.SafetyMaxOnMinutes,
+		Heartbeats:               []models.HeartbeatStatus{},
+		ArmedCoils:               []models.ArmedCoilStatus{},
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, name := range w.service.deviceOrder {
+		status := models.HeartbeatStatus{Device: name}
+		if state, ok := w.heartbeats[name]; ok {
+			status.Value = int(state.value)
+			status.Failing = state.failing
+			if !state.lastBeat.IsZero() {
+				lastBeat := state.lastBeat
+				status.LastBeat = &lastBeat
+			}
+		}
+		resp.Heartbeats = append(resp.Heartbeats, status)
+	}
+
+	for key, coil := range w.armed {
+		resp.ArmedCoils = append(resp.ArmedCoils, models.ArmedCoilStatus{
+			BoxID:       coil.boxID,
+			Device:      key.device,
+			Register:    coil.register,
+			Inverted:    coil.inverted,
+			OnSince:     coil.onSince,
+			RefreshedAt: coil.refreshed,
+			SwitchOffAt: coil.refreshed.Add(w.maxOn),
+		})
+	}
+	sort.Slice(resp.ArmedCoils, func(i, j int) bool {
+		return resp.ArmedCoils[i].SwitchOffAt.Before(resp.ArmedCoils[j].SwitchOffAt)
+	})
+
+	return resp
+}
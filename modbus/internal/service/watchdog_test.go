@@ -0,0 +1,128 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"modbus-server/internal/models"
+)
+
+func TestWatchdogSwitchOffExpired(t *testing.T) {
+	type write struct {
+		value    bool
+		inverted bool
+	}
+
+	tests := []struct {
+		name     string
+		writes   []write
+		armed    []bool // Inverted каждого coil под таймером
+		simCoils map[string]bool
+	}{
+		{
+			name:     "Coil switched on is switched off",
+			writes:   []write{{value: true}},
+			armed:    []bool{false},
+			simCoils: map[string]bool{},
+		},
+		{
+			name:     "Coil switched off is not armed",
+			writes:   []write{{value: true}, {value: false}},
+			armed:    []bool{},
+			simCoils: map[string]bool{},
+		},
+		{
+			name:     "Inverted coil switched off with true is not armed",
+			writes:   []write{{value: true, inverted: true}},
+			armed:    []bool{},
+			simCoils: map[string]bool{"0x0010": true},
+		},
+		{
+			name:     "Inverted coil switched on with false is switched off with true",
+			writes:   []write{{value: true, inverted: true}, {value: false, inverted: true}},
+			armed:    []bool{true},
+			simCoils: map[string]bool{"0x0010": true},
+		},
+		{
+			name:     "Inverted coil switched on and off again is not armed",
+			writes:   []write{{value: false, inverted: true}, {value: true, inverted: true}},
+			armed:    []bool{},
+			simCoils: map[string]bool{"0x0010": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, sim := newTestService(t)
+			// Любой включенный coil сразу считается просроченным
+			service.watchdog.maxOn = time.Nanosecond
+
+			boxID := uuid.New()
+			for i, w := range tt.writes {
+				resp := service.WriteCoil(&models.WriteCoilRequest{BoxID: boxID, Register: "0x0010", Value: w.value, Inverted: w.inverted})
+				if !resp.Success {
+					t.Fatalf("write %d: WriteCoil() failed: %s", i, resp.Message)
+				}
+			}
+
+			status, err := service.GetWatchdog()
+			if err != nil {
+				t.Fatalf("GetWatchdog() unexpected error: %v", err)
+			}
+			armed := make([]bool, 0, len(status.ArmedCoils))
+			for _, coil := range status.ArmedCoils {
+				armed = append(armed, coil.Inverted)
+			}
+			if !reflect.DeepEqual(armed, tt.armed) {
+				t.Errorf("armed coils inverted = %v, want %v", armed, tt.armed)
+			}
+
+			service.watchdog.switchOffExpired()
+
+			if got := simulatorCoils(sim); !reflect.DeepEqual(got, tt.simCoils) {
+				t.Errorf("simulator coils after expiry = %v, want %v", got, tt.simCoils)
+			}
+			status, err = service.GetWatchdog()
+			if err != nil {
+				t.Fatalf("GetWatchdog() unexpected error: %v", err)
+			}
+			if len(status.ArmedCoils) != 0 {
+				t.Errorf("armed coils after expiry = %d, want 0", len(status.ArmedCoils))
+			}
+		})
+	}
+}
+
+func TestWriteCoilsInvertedArmsWatchdog(t *testing.T) {
+	service, sim := newTestService(t)
+	service.watchdog.maxOn = time.Nanosecond
+
+	// Свет с инверсией включен (false), химия без инверсии выключена (false)
+	resp := service.WriteCoils(&models.WriteCoilsRequest{
+		BoxID: uuid.New(),
+		Coils: []models.CoilWrite{
+			{Register: "0x0010", Value: false, Inverted: true},
+			{Register: "0x0011", Value: false},
+		},
+	})
+	if !resp.Success {
+		t.Fatalf("WriteCoils() failed: %s", resp.Message)
+	}
+
+	status, err := service.GetWatchdog()
+	if err != nil {
+		t.Fatalf("GetWatchdog() unexpected error: %v", err)
+	}
+	if len(status.ArmedCoils) != 1 || status.ArmedCoils[0].Register != "0x0010" || !status.ArmedCoils[0].Inverted {
+		t.Fatalf("armed coils = %+v, want inverted 0x0010", status.ArmedCoils)
+	}
+
+	service.watchdog.switchOffExpired()
+
+	if got, want := simulatorCoils(sim), map[string]bool{"0x0010": true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("simulator coils after expiry = %v, want %v", got, want)
+	}
+}
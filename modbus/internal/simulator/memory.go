@@ -0,0 +1,104 @@
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"modbus-server/internal/models"
+)
+
+// addressSpace размер каждой области памяти Modbus (адреса 0x0000-0xFFFF)
+const addressSpace = 1 << 16
+
+// unitMemory память одного ведомого устройства (unit ID): coils, дискретные входы и holding registers
+type unitMemory struct {
+	coils            []bool
+	discreteInputs   []bool
+	holdingRegisters []uint16
+}
+
+// newUnitMemory создает обнуленную память устройства
+func newUnitMemory() *unitMemory {
+	return &unitMemory{
+		coils:            make([]bool, addressSpace),
+		discreteInputs:   make([]bool, addressSpace),
+		holdingRegisters: make([]uint16, addressSpace),
+	}
+}
+
+// memory память симулятора по unit ID; память устройства создается при первом обращении
+type memory struct {
+	mu    sync.Mutex
+	units map[byte]*unitMemory
+}
+
+// newMemory создает пустую память симулятора
+func newMemory() *memory {
+	return &memory{units: make(map[byte]*unitMemory)}
+}
+
+// unit возвращает память устройства; вызывается под mu
+func (m *memory) unit(unitID byte) *unitMemory {
+	unit, ok := m.units[unitID]
+	if !ok {
+		unit = newUnitMemory()
+		m.units[unitID] = unit
+	}
+	return unit
+}
+
+// reset обнуляет память всех устройств
+func (m *memory) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.units = make(map[byte]*unitMemory)
+}
+
+// setDiscreteInput устанавливает дискретный вход, как это сделал бы датчик
+func (m *memory) setDiscreteInput(unitID byte, address uint16, value bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unit(unitID).discreteInputs[address] = value
+}
+
+// snapshot возвращает ненулевые значения памяти всех устройств
+func (m *memory) snapshot() []models.SimulatorUnitState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unitIDs := make([]int, 0, len(m.units))
+	for unitID := range m.units {
+		unitIDs = append(unitIDs, int(unitID))
+	}
+	sort.Ints(unitIDs)
+
+	result := make([]models.SimulatorUnitState, 0, len(unitIDs))
+	for _, unitID := range unitIDs {
+		unit := m.units[byte(unitID)]
+		state := models.SimulatorUnitState{
+			UnitID:           unitID,
+			Coils:            map[string]bool{},
+			DiscreteInputs:   map[string]bool{},
+			HoldingRegisters: map[string]uint16{},
+		}
+		for address := 0; address < addressSpace; address++ {
+			if unit.coils[address] {
+				state.Coils[formatRegister(uint16(address))] = true
+			}
+			if unit.discreteInputs[address] {
+				state.DiscreteInputs[formatRegister(uint16(address))] = true
+			}
+			if unit.holdingRegisters[address] != 0 {
+				state.HoldingRegisters[formatRegister(uint16(address))] = unit.holdingRegisters[address]
+			}
+		}
+		result = append(result, state)
+	}
+	return result
+}
+
+// formatRegister форматирует адрес так же, как регистры в API сервера
+func formatRegister(address uint16) string {
+	return fmt.Sprintf("0x%04x", address)
+}
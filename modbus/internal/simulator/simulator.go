@@ -0,0 +1,396 @@
+package simulator
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"modbus-server/internal/config"
+	"modbus-server/internal/models"
+)
+
+// Функции Modbus, которые поддерживает симулятор
+const (
+	funcReadCoils              = 0x01
+	funcReadDiscreteInputs     = 0x02
+	funcReadHoldingRegisters   = 0x03
+	funcReadInputRegisters     = 0x04
+	funcWriteSingleCoil        = 0x05
+	funcWriteSingleRegister    = 0x06
+	funcWriteMultipleCoils     = 0x0F
+	funcWriteMultipleRegisters = 0x10
+)
+
+// Коды исключений Modbus
+const (
+	exceptionIllegalFunction     = 0x01
+	exceptionIllegalDataAddress  = 0x02
+	exceptionIllegalDataValue    = 0x03
+	exceptionServerDeviceFailure = 0x04
+)
+
+// mbapHeaderSize размер заголовка Modbus TCP (MBAP) без unit ID
+const mbapHeaderSize = 6
+
+// Simulator ПЛК в памяти процесса: Modbus TCP slave с coils, дискретными входами и holding registers
+// Каждый unit ID имеет свою память, поэтому несколько устройств из MODBUS_DEVICES можно направить
+// на один симулятор. Сбои (задержка, исключения, разрывы соединения) настраиваются на ходу
+type Simulator struct {
+	address  string
+	listener net.Listener
+	memory   *memory
+
+	mu     sync.Mutex // Защищает faults, rnd и conns
+	faults models.SimulatorFaults
+	rnd    *rand.Rand
+	conns  map[net.Conn]struct{}
+
+	stats simulatorStats
+}
+
+// simulatorStats счетчики запросов симулятора
+type simulatorStats struct {
+	mu          sync.Mutex
+	requests    int64
+	exceptions  int64
+	disconnects int64
+}
+
+// NewSimulator создает симулятор с начальными сбоями из конфигурации
+func NewSimulator(cfg *config.Config) *Simulator {
+	return &Simulator{
+		address: cfg.SimulatorAddress,
+		memory:  newMemory(),
+		faults: models.SimulatorFaults{
+			LatencyMs:      cfg.SimulatorLatencyMs,
+			JitterMs:       cfg.SimulatorJitterMs,
+			ExceptionRate:  cfg.SimulatorExceptionRate,
+			ExceptionCode:  exceptionServerDeviceFailure,
+			DisconnectRate: cfg.SimulatorDisconnectRate,
+		},
+		rnd:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		conns: make(map[net.Conn]struct{}),
+	}
+}
+
+// Start начинает принимать Modbus TCP соединения
+func (s *Simulator) Start() error {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	log.Printf("Симулятор ПЛК слушает Modbus TCP на %s", listener.Addr())
+
+	go s.acceptLoop()
+	return nil
+}
+
+// Close останавливает симулятор и закрывает соединения
+func (s *Simulator) Close() {
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	s.dropConnections()
+}
+
+// Faults возвращает текущие настройки сбоев
+func (s *Simulator) Faults() models.SimulatorFaults {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.faults
+}
+
+// SetFaults меняет настройки сбоев; при переводе в offline открытые соединения разрываются
+func (s *Simulator) SetFaults(faults models.SimulatorFaults) {
+	if faults.ExceptionCode == 0 {
+		faults.ExceptionCode = exceptionServerDeviceFailure
+	}
+
+	s.mu.Lock()
+	s.faults = faults
+	s.mu.Unlock()
+
+	log.Printf("Симулятор ПЛК: сбои изменены - %+v", faults)
+	if faults.Offline {
+		s.dropConnections()
+	}
+}
+
+// SetDiscreteInput устанавливает дискретный вход (имитация датчика)
+func (s *Simulator) SetDiscreteInput(unitID byte, address uint16, value bool) {
+	s.memory.setDiscreteInput(unitID, address, value)
+}
+
+// Reset обнуляет память симулятора и счетчики
+func (s *Simulator) Reset() {
+	s.memory.reset()
+	s.stats.mu.Lock()
+	s.stats.requests, s.stats.exceptions, s.stats.disconnects = 0, 0, 0
+	s.stats.mu.Unlock()
+}
+
+// State возвращает состояние симулятора: ненулевую память по unit ID, сбои и счетчики
+func (s *Simulator) State() *models.SimulatorStateResponse {
+	s.mu.Lock()
+	resp := &models.SimulatorStateResponse{
+		Address:     s.address,
+		Connections: len(s.conns),
+		Faults:      s.faults,
+	}
+	s.mu.Unlock()
+
+	s.stats.mu.Lock()
+	resp.Requests = s.stats.requests
+	resp.Exceptions = s.stats.exceptions
+	resp.Disconnects = s.stats.disconnects
+	s.stats.mu.Unlock()
+
+	resp.Units = s.memory.snapshot()
+	return resp
+}
+
+// acceptLoop принимает соединения до закрытия listener
+func (s *Simulator) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Printf("Симулятор ПЛК: ошибка приема соединения: %v", err)
+			continue
+		}
+
+		s.mu.Lock()
+		offline := s.faults.Offline
+		if !offline {
+			s.conns[conn] = struct{}{}
+		}
+		s.mu.Unlock()
+
+		if offline {
+			_ = conn.Close()
+			continue
+		}
+		go s.serve(conn)
+	}
+}
+
+// dropConnections разрывает все открытые соединения
+func (s *Simulator) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		_ = conn.Close()
+		delete(s.conns, conn)
+	}
+}
+
+// serve обрабатывает запросы одного соединения по очереди
+func (s *Simulator) serve(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	header := make([]byte, mbapHeaderSize)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		length := binary.BigEndian.Uint16(header[4:6])
+		if length < 2 || length > 254 {
+			// Некорректный кадр, синхронизация потока потеряна
+			return
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+		unitID, pdu := body[0], body[1:]
+
+		s.stats.mu.Lock()
+		s.stats.requests++
+		s.stats.mu.Unlock()
+
+		delay, disconnect, exception := s.rollFaults()
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		if disconnect {
+			s.stats.mu.Lock()
+			s.stats.disconnects++
+			s.stats.mu.Unlock()
+			return
+		}
+
+		var response []byte
+		if exception != 0 {
+			response = exceptionResponse(pdu[0], exception)
+		} else {
+			response = s.handle(unitID, pdu)
+		}
+		if response[0]&0x80 != 0 {
+			s.stats.mu.Lock()
+			s.stats.exceptions++
+			s.stats.mu.Unlock()
+		}
+
+		frame := make([]byte, mbapHeaderSize+1+len(response))
+		copy(frame[0:4], header[0:4]) // Transaction ID и Protocol ID возвращаются как в запросе
+		binary.BigEndian.PutUint16(frame[4:6], uint16(1+len(response)))
+		frame[6] = unitID
+		copy(frame[7:], response)
+		if _, err := conn.Write(frame); err != nil {
+			return
+		}
+	}
+}
+
+// rollFaults определяет сбои для очередного запроса
+func (s *Simulator) rollFaults() (delay time.Duration, disconnect bool, exception byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delay = time.Duration(s.faults.LatencyMs) * time.Millisecond
+	if s.faults.JitterMs > 0 {
+		delay += time.Duration(s.rnd.Intn(s.faults.JitterMs+1)) * time.Millisecond
+	}
+	if s.faults.Offline || s.rnd.Float64() < s.faults.DisconnectRate {
+		return delay, true, 0
+	}
+	if s.rnd.Float64() < s.faults.ExceptionRate {
+		return delay, false, byte(s.faults.ExceptionCode)
+	}
+	return delay, false, 0
+}
+
+// handle выполняет запрос над памятью устройства и возвращает PDU ответа
+func (s *Simulator) handle(unitID byte, pdu []byte) []byte {
+	function := pdu[0]
+	data := pdu[1:]
+
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+	unit := s.memory.unit(unitID)
+
+	switch function {
+	case funcReadCoils, funcReadDiscreteInputs:
+		if len(data) != 4 {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		address, quantity := binary.BigEndian.Uint16(data[0:2]), binary.BigEndian.Uint16(data[2:4])
+		if quantity < 1 || quantity > 2000 {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		if int(address)+int(quantity) > addressSpace {
+			return exceptionResponse(function, exceptionIllegalDataAddress)
+		}
+		bits := unit.coils
+		if function == funcReadDiscreteInputs {
+			bits = unit.discreteInputs
+		}
+		return append([]byte{function}, packBits(bits[address:int(address)+int(quantity)])...)
+
+	case funcReadHoldingRegisters, funcReadInputRegisters:
+		// Input registers симулятор отдает из той же памяти, что и holding registers
+		if len(data) != 4 {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		address, quantity := binary.BigEndian.Uint16(data[0:2]), binary.BigEndian.Uint16(data[2:4])
+		if quantity < 1 || quantity > 125 {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		if int(address)+int(quantity) > addressSpace {
+			return exceptionResponse(function, exceptionIllegalDataAddress)
+		}
+		response := make([]byte, 2+2*int(quantity))
+		response[0] = function
+		response[1] = byte(2 * quantity)
+		for i := 0; i < int(quantity); i++ {
+			binary.BigEndian.PutUint16(response[2+2*i:], unit.holdingRegisters[int(address)+i])
+		}
+		return response
+
+	case funcWriteSingleCoil:
+		if len(data) != 4 {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		address, value := binary.BigEndian.Uint16(data[0:2]), binary.BigEndian.Uint16(data[2:4])
+		if value != 0xFF00 && value != 0x0000 {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		unit.coils[address] = value == 0xFF00
+		return append([]byte{function}, data...)
+
+	case funcWriteSingleRegister:
+		if len(data) != 4 {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		address, value := binary.BigEndian.Uint16(data[0:2]), binary.BigEndian.Uint16(data[2:4])
+		unit.holdingRegisters[address] = value
+		return append([]byte{function}, data...)
+
+	case funcWriteMultipleCoils:
+		if len(data) < 5 {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		address, quantity := binary.BigEndian.Uint16(data[0:2]), binary.BigEndian.Uint16(data[2:4])
+		byteCount := int(data[4])
+		if quantity < 1 || quantity > 1968 || byteCount != (int(quantity)+7)/8 || len(data) != 5+byteCount {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		if int(address)+int(quantity) > addressSpace {
+			return exceptionResponse(function, exceptionIllegalDataAddress)
+		}
+		values := data[5:]
+		for i := 0; i < int(quantity); i++ {
+			unit.coils[int(address)+i] = values[i/8]&(1<<(i%8)) != 0
+		}
+		return append([]byte{function}, data[0:4]...)
+
+	case funcWriteMultipleRegisters:
+		if len(data) < 5 {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		address, quantity := binary.BigEndian.Uint16(data[0:2]), binary.BigEndian.Uint16(data[2:4])
+		byteCount := int(data[4])
+		if quantity < 1 || quantity > 123 || byteCount != 2*int(quantity) || len(data) != 5+byteCount {
+			return exceptionResponse(function, exceptionIllegalDataValue)
+		}
+		if int(address)+int(quantity) > addressSpace {
+			return exceptionResponse(function, exceptionIllegalDataAddress)
+		}
+		for i := 0; i < int(quantity); i++ {
+			unit.holdingRegisters[int(address)+i] = binary.BigEndian.Uint16(data[5+2*i:])
+		}
+		return append([]byte{function}, data[0:4]...)
+	}
+
+	return exceptionResponse(function, exceptionIllegalFunction)
+}
+
+// exceptionResponse PDU ответа-исключения
+func exceptionResponse(function, code byte) []byte {
+	return []byte{function | 0x80, code}
+}
+
+// packBits упаковывает значения по битам начиная с младшего, с байтом длины впереди
+func packBits(values []bool) []byte {
+	packed := make([]byte, 1+(len(values)+7)/8)
+	packed[0] = byte(len(packed) - 1)
+	for i, value := range values {
+		if value {
+			packed[1+i/8] |= 1 << (i % 8)
+		}
+	}
+	return packed
+}
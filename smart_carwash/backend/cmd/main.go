@@ -45,6 +45,24 @@ import (
 	washboxHandlers "carwash_backend/internal/domain/washbox/handlers"
 	washboxRepo "carwash_backend/internal/domain/washbox/repository"
 	washboxService "carwash_backend/internal/domain/washbox/service"
+	plateListHandlers "carwash_backend/internal/domain/platelist/handlers"
+	plateListRepo "carwash_backend/internal/domain/platelist/repository"
+	plateListService "carwash_backend/internal/domain/platelist/service"
+	signageHandlers "carwash_backend/internal/domain/signage/handlers"
+	signageRepo "carwash_backend/internal/domain/signage/repository"
+	signageService "carwash_backend/internal/domain/signage/service"
+	siteHandlers "carwash_backend/internal/domain/site/handlers"
+	siteRepo "carwash_backend/internal/domain/site/repository"
+	siteService "carwash_backend/internal/domain/site/service"
+	realtimeHandlers "carwash_backend/internal/domain/realtime/handlers"
+	realtimeService "carwash_backend/internal/domain/realtime/service"
+	debtHandlers "carwash_backend/internal/domain/debt/handlers"
+	debtRepo "carwash_backend/internal/domain/debt/repository"
+	debtService "carwash_backend/internal/domain/debt/service"
+	presenceHandlers "carwash_backend/internal/domain/presence/handlers"
+	presenceRepo "carwash_backend/internal/domain/presence/repository"
+	presenceService "carwash_backend/internal/domain/presence/service"
+	sessionMiddleware "carwash_backend/internal/domain/session/middleware"
 	washboxlogHandlers "carwash_backend/internal/domain/washboxlog/handlers"
 	washboxlogRepo "carwash_backend/internal/domain/washboxlog/repository"
 	washboxlogService "carwash_backend/internal/domain/washboxlog/service"
@@ -124,24 +142,44 @@ func main() {
 	carwashStatusRepository := carwashStatusRepo.NewPostgresRepository(db)
 	// Репозиторий логов изменений боксов
 	washboxLogRepository := washboxlogRepo.NewPostgresRepository(db)
+	// Репозиторий черного и VIP списков госномеров
+	plateListRepository := plateListRepo.NewPostgresRepository(db)
+	// Репозиторий долгов за простой
+	debtRepository := debtRepo.NewPostgresRepository(db)
+	// Репозиторий табло у въезда
+	signageRepository := signageRepo.NewPostgresRepository(db)
+	// Репозиторий площадок
+	siteRepository := siteRepo.NewPostgresRepository(db)
+	// Репозиторий датчиков присутствия автомобиля
+	presenceRepository := presenceRepo.NewPostgresRepository(db)
 
 	// Создаем Tinkoff клиент
 	tinkoffClient := paymentTinkoff.NewClient(cfg.TinkoffTerminalKey, cfg.TinkoffSecretKey, cfg.TinkoffSuccessURL, cfg.TinkoffFailURL)
 
+	// Поток событий в реальном времени (боксы, очередь, сессии, катушки)
+	realtimeSvc := realtimeService.NewService()
+
 	// Сервис логирования изменений боксов
 	washboxLogSvc := washboxlogService.NewService(washboxLogRepository, washboxRepository)
+	washboxLogSvc.SetEventPublisher(realtimeSvc)
 
 	// Создаем Modbus HTTP адаптер
 	modbusAdapter := modbusAdapter.NewModbusAdapter(cfg, db, washboxLogSvc)
+	modbusAdapter.SetMetrics(appMetrics)
+	modbusAdapter.SetEventPublisher(realtimeSvc)
 
 	// Создаем сервисы
 	userSvc := userService.NewService(userRepository)
 	settingsSvc := settingsService.NewService(settingsRepository)
+	plateListSvc := plateListService.NewService(plateListRepository)
+	debtSvc := debtService.NewService(debtRepository, settingsSvc)
 	washboxSvc := washboxService.NewService(washboxRepository, sessionRepository, settingsSvc, db, modbusAdapter, washboxLogSvc)
 	authSvc := authService.NewService(authRepository, cfg)
+	siteSvc := siteService.NewService(siteRepository)
 
 	// Создаем Modbus service для админских операций
 	modbusSvc := modbusService.NewModbusService(db, cfg)
+	modbusSvc.SetChannelWriter(modbusAdapter)
 
 	// Создаем фоновые задачи для кассиров
 	backgroundTasks := authService.NewBackgroundTasks(authRepository)
@@ -164,13 +202,20 @@ func main() {
 	// Создаем сервис очереди, который зависит от сервисов сессий, боксов и пользователей
 	queueSvc := queueService.NewService(sessionSvc, washboxSvc, userSvc, appMetrics)
 
+	// Создаем сервис публичного табло у въезда
+	signageSvc := signageService.NewService(signageRepository, queueSvc, sessionSvc)
+
 	// Устанавливаем вебхук для бота
 	if err := bot.SetWebhook(); err != nil {
 		log.WithField("error", err).Warn("Ошибка установки вебхука")
 	}
 
 	// Создаем Dahua сервис
-	dahuaSvc := dahuaService.NewService(sessionSvc, washboxSvc)
+	dahuaSvc := dahuaService.NewService(sessionSvc, washboxSvc, plateListSvc, debtSvc)
+
+	// Создаем сервис датчиков присутствия автомобиля в боксах
+	presenceSvc := presenceService.NewService(presenceRepository, sessionSvc, washboxSvc, modbusAdapter, cfg)
+	presenceSvc.SetEventPublisher(realtimeSvc)
 
 	// Создаем сервис статуса мойки
 	carwashStatusSvc := carwashStatusService.NewService(carwashStatusRepository, sessionSvc)
@@ -178,23 +223,55 @@ func main() {
 	// Устанавливаем carwashStatusRepo в sessionSvc для проверки статуса при создании сессий
 	sessionSvc.SetCarwashStatusRepo(carwashStatusRepository)
 
+	// Устанавливаем сервис списков госномеров для проверки черного и VIP списков
+	sessionSvc.SetPlateListService(plateListSvc)
+
+	// Устанавливаем сервис долгов для оплаты простоя вместе со следующей сессией
+	sessionSvc.SetDebtService(debtSvc)
+
+	// Устанавливаем отправку изменений сессий и очереди в поток событий
+	sessionSvc.SetEventPublisher(realtimeSvc)
+
+	// Устанавливаем сервис площадок для привязки сессий и подписок на очередь к площадке
+	sessionSvc.SetSiteService(siteSvc)
+	userSvc.SetSiteService(siteSvc)
+
+	// Оплаченный пропуск очереди применяет итоговый sessionSvc, обрабатывающий очередь
+	paymentSvc.SetSessionQueueJumpUpdater(sessionSvc)
+
 	// Создаем обработчики
 	userHandler := userHandlers.NewHandler(userSvc)
 	washboxHandler := washboxHandlers.NewHandler(washboxSvc)
-	sessionHandler := sessionHandlers.NewHandler(sessionSvc, paymentSvc, authSvc, cfg.APIKey1C)
+	sessionHandler := sessionHandlers.NewHandler(sessionSvc, paymentSvc, authSvc, cfg.APIKey1C, siteSvc)
 	queueHandler := queueHandlers.NewHandler(queueSvc)
 	settingsHandler := settingsHandlers.NewHandler(settingsSvc)
 	authHandler := authHandlers.NewHandler(authSvc)
 	paymentHandler := paymentHandlers.NewHandler(paymentSvc, authSvc)
 	modbusHandler := modbusHandlers.NewHandler(modbusSvc)
 	dahuaHandler := dahuaHandlers.NewHandler(dahuaSvc)
+	presenceHandler := presenceHandlers.NewHandler(presenceSvc)
 	carwashStatusHandler := carwashStatusHandlers.NewHandler(carwashStatusSvc, authHandler.GetAdminMiddleware())
 	// Хендлер истории изменений боксов
 	washboxLogHandler := washboxlogHandlers.NewHandler(washboxLogSvc)
+	// Хендлер черного и VIP списков госномеров
+	plateListHandler := plateListHandlers.NewHandler(plateListSvc)
+	// Хендлер долгов за простой
+	debtHandler := debtHandlers.NewHandler(debtSvc)
+	// Хендлер потока событий в реальном времени
+	realtimeHandler := realtimeHandlers.NewHandler(realtimeSvc)
+	// Хендлер табло у въезда
+	signageHandler := signageHandlers.NewHandler(signageSvc)
+	// Хендлер площадок
+	siteHandler := siteHandlers.NewHandler(siteSvc)
 
 	// Создаем роутер
 	router := gin.Default()
 
+	// X-Forwarded-For принимается только от доверенных прокси, иначе адрес клиента подделывается заголовком
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Fatal("Ошибка настройки доверенных прокси", err)
+	}
+
 	// Добавляем middleware для метрик
 	router.Use(appMetrics.PrometheusMiddleware())
 
@@ -235,9 +312,14 @@ func main() {
 		authHandler.RegisterRoutes(api)
 		paymentHandler.RegisterRoutes(api)
 		modbusHandler.RegisterRoutes(api)
-		dahuaHandlers.SetupRoutes(api, dahuaHandler)
+		dahuaHandlers.SetupRoutes(api, dahuaHandler, siteSvc)
+		presenceHandlers.SetupRoutes(api, presenceHandler, cfg.ModbusEventsToken)
 		carwashStatusHandler.RegisterRoutes(api)
 		washboxLogHandler.RegisterRoutes(api, authHandler.GetAdminMiddleware())
+		plateListHandler.RegisterRoutes(api, authHandler.GetAdminMiddleware())
+		debtHandler.RegisterRoutes(api, authHandler.GetAdminMiddleware(), sessionMiddleware.Auth1CMiddleware(cfg.APIKey1C, siteSvc))
+		signageHandler.RegisterRoutes(api, authHandler.GetAdminMiddleware())
+		siteHandler.RegisterRoutes(api, authHandler.GetAdminMiddleware())
 
 		// Вебхук для Telegram бота
 		api.POST("/webhook", func(c *gin.Context) {
@@ -262,6 +344,11 @@ func main() {
 		})
 	}
 
+	// Поток событий держит соединение открытым, поэтому регистрируется без TimeoutMiddleware
+	streamRoutes := router.Group("/")
+	streamRoutes.Use(middleware.LoggingMiddleware())
+	realtimeHandler.RegisterRoutes(streamRoutes, middleware.TelegramUserMiddleware(cfg.TelegramToken, userSvc), middleware.CashierMiddleware(authSvc), authHandler.GetAdminMiddleware())
+
 	// Создаем HTTP сервер с таймаутами
 	server := &http.Server{
 		Addr:         ":" + os.Getenv("BACKEND_PORT"),
@@ -508,6 +595,141 @@ func main() {
 		}
 	}()
 
+	// Запускаем периодическую задачу открытия и закрытия моек по расписанию (старт через 9 сек)
+	go func() {
+		time.Sleep(9 * time.Second) // Разносим запуск задач
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				func() {
+					// Закрытие мойки отменяет сессии с возвратом денег, поэтому таймаут больше обычного
+					ctx2, cancel := context.WithTimeout(context.Background(), 50*time.Second)
+					defer cancel()
+					if err := carwashStatusSvc.RunSchedule(ctx2); err != nil {
+						log.WithField("error", err).Error("Ошибка применения расписания работы моек")
+					}
+				}()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// Запускаем периодическую задачу закрытия моек после дообслуживания очереди (старт через 10 сек)
+	go func() {
+		time.Sleep(10 * time.Second) // Разносим запуск задач
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				func() {
+					ctx2, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+					defer cancel()
+					if err := carwashStatusSvc.CompleteDrains(ctx2); err != nil {
+						log.WithField("error", err).Error("Ошибка закрытия моек после дообслуживания")
+					}
+				}()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// Запускаем периодическую сверку света и химии боксов с состоянием сессий (старт через 20 сек)
+	if cfg.ModbusReconcileIntervalSeconds > 0 {
+		go func() {
+			time.Sleep(20 * time.Second) // Разносим запуск задач
+			ticker := time.NewTicker(time.Duration(cfg.ModbusReconcileIntervalSeconds) * time.Second)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					func() {
+						ctx2, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+						defer cancel()
+						if err := modbusAdapter.ReconcileCoils(ctx2); err != nil {
+							log.WithField("error", err).Error("Ошибка сверки состояния coils")
+						}
+					}()
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	// Запускаем отправку очереди команд Modbus на контроллеры (каждую секунду)
+	go func() {
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				func() {
+					ctx2, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+					defer cancel()
+					if err := modbusAdapter.DispatchOutbox(ctx2); err != nil {
+						log.WithField("error", err).Error("Ошибка выполнения очереди команд Modbus")
+					}
+				}()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// Запускаем синхронизацию датчиков присутствия с modbus сервером (старт через 25 сек)
+	go func() {
+		time.Sleep(25 * time.Second) // Разносим запуск задач
+		ticker := time.NewTicker(time.Duration(cfg.PresenceSyncIntervalSeconds) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				func() {
+					ctx2, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+					defer cancel()
+					if err := presenceSvc.SyncSensors(ctx2); err != nil {
+						log.WithField("error", err).Error("Ошибка синхронизации датчиков присутствия")
+					}
+				}()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// Запускаем проверку активных сессий без машины в боксе (каждые 30 секунд)
+	if cfg.PresenceNoCarMinutes > 0 {
+		go func() {
+			ticker := time.NewTicker(30 * time.Second)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					func() {
+						ctx2, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+						defer cancel()
+						if err := presenceSvc.CheckNoCarSessions(ctx2); err != nil {
+							log.WithField("error", err).Error("Ошибка проверки сессий без машины в боксе")
+						}
+					}()
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
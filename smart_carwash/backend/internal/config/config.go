@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -19,6 +20,9 @@ type Config struct {
 	TelegramToken    string
 	TelegramUsername string
 	ServerIP         string
+	// Прокси, которым разрешено передавать адрес клиента в X-Forwarded-For (IP или CIDR через запятую).
+	// Пусто - заголовок не учитывается и адрес клиента берется из соединения
+	TrustedProxies []string
 
 	// Настройки авторизации
 	AdminUsername string
@@ -45,6 +49,33 @@ type Config struct {
 	// Настройки Modbus HTTP сервера
 	ModbusServerHost string
 	ModbusServerPort int
+	// Аутентификация на modbus сервере: none, api_key или hmac (совпадает с AUTH_MODE modbus сервера),
+	// идентификатор и секрет клиента из AUTH_CLIENTS modbus сервера
+	ModbusServerAuthMode string
+	ModbusServerClientID string
+	ModbusServerSecret   string
+	// TLS до modbus сервера: CA для проверки его сертификата (пусто - системные CA)
+	// и сертификат клиента для mTLS (пусто - без сертификата клиента)
+	ModbusServerTLS      bool
+	ModbusServerCAFile   string
+	ModbusClientCertFile string
+	ModbusClientKeyFile  string
+	// Интервал сверки фактического состояния coils с состоянием сессий (0 - сверка отключена)
+	ModbusReconcileIntervalSeconds int
+	// Как часто сверка повторно записывает включенные coils, чтобы сторожевой таймер modbus сервера
+	// (SAFETY_MAX_ON_MINUTES) не выключил их; должно быть заметно меньше SAFETY_MAX_ON_MINUTES (0 - не подтверждать)
+	ModbusCoilRefreshSeconds int
+	// Очередь команд Modbus: через сколько секунд невыполненная команда вызывает тревогу
+	// и через сколько минут перестает повторяться
+	ModbusCommandDeadlineSeconds int
+	ModbusCommandTTLMinutes      int
+	// Датчики присутствия автомобиля: токен, с которым modbus сервер отправляет изменения датчиков,
+	// как часто список датчиков передается modbus серверу, автостарт назначенной сессии по въезду машины
+	// и через сколько минут без машины активная сессия помечается (0 - не помечать)
+	ModbusEventsToken           string
+	PresenceSyncIntervalSeconds int
+	PresenceAutoStart           bool
+	PresenceNoCarMinutes        int
 
 	// Настройки Dahua интеграции
 	DahuaWebhookUsername string
@@ -80,6 +111,50 @@ func LoadConfig() (*Config, error) {
 
 	modbusEnabled := getEnv("MODBUS_ENABLED", "false") == "true"
 
+	modbusReconcileInterval, err := strconv.Atoi(getEnv("MODBUS_RECONCILE_INTERVAL_SECONDS", "30"))
+	if err != nil || modbusReconcileInterval < 0 {
+		return nil, fmt.Errorf("неверный формат MODBUS_RECONCILE_INTERVAL_SECONDS: %v", err)
+	}
+
+	modbusCoilRefresh, err := strconv.Atoi(getEnv("MODBUS_COIL_REFRESH_SECONDS", "60"))
+	if err != nil || modbusCoilRefresh < 0 {
+		return nil, fmt.Errorf("неверный формат MODBUS_COIL_REFRESH_SECONDS: %v", err)
+	}
+
+	modbusCommandDeadline, err := strconv.Atoi(getEnv("MODBUS_COMMAND_DEADLINE_SECONDS", "60"))
+	if err != nil || modbusCommandDeadline <= 0 {
+		return nil, fmt.Errorf("неверный формат MODBUS_COMMAND_DEADLINE_SECONDS: %v", err)
+	}
+
+	modbusCommandTTL, err := strconv.Atoi(getEnv("MODBUS_COMMAND_TTL_MINUTES", "30"))
+	if err != nil || modbusCommandTTL <= 0 {
+		return nil, fmt.Errorf("неверный формат MODBUS_COMMAND_TTL_MINUTES: %v", err)
+	}
+
+	modbusServerAuthMode := getEnv("MODBUS_SERVER_AUTH_MODE", "none")
+	switch modbusServerAuthMode {
+	case "none":
+	case "api_key", "hmac":
+		if getEnv("MODBUS_SERVER_SECRET", "") == "" {
+			return nil, fmt.Errorf("MODBUS_SERVER_SECRET обязателен для MODBUS_SERVER_AUTH_MODE=%s", modbusServerAuthMode)
+		}
+	default:
+		return nil, fmt.Errorf("неверный MODBUS_SERVER_AUTH_MODE: ожидается none, api_key или hmac, получено %q", modbusServerAuthMode)
+	}
+	if (getEnv("MODBUS_CLIENT_CERT_FILE", "") == "") != (getEnv("MODBUS_CLIENT_KEY_FILE", "") == "") {
+		return nil, fmt.Errorf("MODBUS_CLIENT_CERT_FILE и MODBUS_CLIENT_KEY_FILE задаются вместе")
+	}
+
+	presenceSyncInterval, err := strconv.Atoi(getEnv("PRESENCE_SYNC_INTERVAL_SECONDS", "30"))
+	if err != nil || presenceSyncInterval <= 0 {
+		return nil, fmt.Errorf("неверный формат PRESENCE_SYNC_INTERVAL_SECONDS: %v", err)
+	}
+
+	presenceNoCarMinutes, err := strconv.Atoi(getEnv("PRESENCE_NO_CAR_MINUTES", "5"))
+	if err != nil || presenceNoCarMinutes < 0 {
+		return nil, fmt.Errorf("неверный формат PRESENCE_NO_CAR_MINUTES: %v", err)
+	}
+
 	return &Config{
 		PostgresUser:     getEnv("POSTGRES_USER", "postgres"),
 		PostgresPassword: getEnv("POSTGRES_PASSWORD", "postgres"),
@@ -90,6 +165,7 @@ func LoadConfig() (*Config, error) {
 		TelegramToken:    getEnv("TELEGRAM_BOT_TOKEN", ""),
 		TelegramUsername: getEnv("TELEGRAM_BOT_USERNAME", ""),
 		ServerIP:         getEnv("SERVER_IP", "localhost"),
+		TrustedProxies:   splitList(getEnv("TRUSTED_PROXIES", "")),
 
 		// Настройки авторизации
 		AdminUsername: getEnv("ADMIN_USERNAME", "admin"),
@@ -117,6 +193,24 @@ func LoadConfig() (*Config, error) {
 		ModbusServerHost: getEnv("MODBUS_SERVER_HOST", "localhost"),
 		ModbusServerPort: modbusServerPort,
 
+		ModbusServerAuthMode: modbusServerAuthMode,
+		ModbusServerClientID: getEnv("MODBUS_SERVER_CLIENT_ID", "backend"),
+		ModbusServerSecret:   getEnv("MODBUS_SERVER_SECRET", ""),
+		ModbusServerTLS:      getEnv("MODBUS_SERVER_TLS", "false") == "true",
+		ModbusServerCAFile:   getEnv("MODBUS_SERVER_CA_FILE", ""),
+		ModbusClientCertFile: getEnv("MODBUS_CLIENT_CERT_FILE", ""),
+		ModbusClientKeyFile:  getEnv("MODBUS_CLIENT_KEY_FILE", ""),
+
+		ModbusReconcileIntervalSeconds: modbusReconcileInterval,
+		ModbusCoilRefreshSeconds:       modbusCoilRefresh,
+		ModbusCommandDeadlineSeconds:   modbusCommandDeadline,
+		ModbusCommandTTLMinutes:        modbusCommandTTL,
+
+		ModbusEventsToken:           getEnv("MODBUS_EVENTS_TOKEN", ""),
+		PresenceSyncIntervalSeconds: presenceSyncInterval,
+		PresenceAutoStart:           getEnv("PRESENCE_AUTO_START", "true") == "true",
+		PresenceNoCarMinutes:        presenceNoCarMinutes,
+
 		// Настройки Dahua интеграции
 		DahuaWebhookUsername: getEnv("DAHUA_WEBHOOK_USERNAME", ""),
 		DahuaWebhookPassword: getEnv("DAHUA_WEBHOOK_PASSWORD", ""),
@@ -138,3 +232,14 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// splitList разбирает список значений через запятую, пропуская пустые
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
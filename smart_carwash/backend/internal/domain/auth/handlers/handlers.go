@@ -324,6 +324,9 @@ func (h *Handler) cleanerMiddleware() gin.HandlerFunc {
 		c.Set("cleaner_id", claims.ID)
 		c.Set("username", claims.Username)
 		c.Set("is_admin", false) // Уборщик не администратор
+		if claims.SiteID != nil {
+			c.Set(middleware.SiteIDContextKey, *claims.SiteID)
+		}
 
 		c.Next()
 	}
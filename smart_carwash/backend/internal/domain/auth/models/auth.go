@@ -12,6 +12,7 @@ type Cashier struct {
 	ID           uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	Username     string         `json:"username" gorm:"uniqueIndex"`
 	PasswordHash string         `json:"-" gorm:"column:password_hash"`
+	SiteID       uuid.UUID      `json:"site_id" gorm:"type:uuid;index"` // Площадка, на которой работает сотрудник
 	IsActive     bool           `json:"is_active" gorm:"default:true"`
 	LastLogin    *time.Time     `json:"last_login"`
 	CreatedAt    time.Time      `json:"created_at"`
@@ -35,6 +36,7 @@ type Cleaner struct {
 	ID           uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	Username     string         `json:"username" gorm:"uniqueIndex"`
 	PasswordHash string         `json:"-" gorm:"column:password_hash"`
+	SiteID       uuid.UUID      `json:"site_id" gorm:"type:uuid;index"` // Площадка, на которой работает сотрудник
 	IsActive     bool           `json:"is_active" gorm:"default:true"`
 	LastLogin    *time.Time     `json:"last_login"`
 	CreatedAt    time.Time      `json:"created_at"`
@@ -61,44 +63,50 @@ type LoginRequest struct {
 
 // LoginResponse представляет ответ на успешную авторизацию
 type LoginResponse struct {
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
-	IsAdmin   bool      `json:"is_admin"`
-	Role      string    `json:"role,omitempty"`
+	Token     string     `json:"token"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	IsAdmin   bool       `json:"is_admin"`
+	Role      string     `json:"role,omitempty"`
+	SiteID    *uuid.UUID `json:"site_id,omitempty"` // Площадка кассира или уборщика
 }
 
 // CreateCashierRequest представляет запрос на создание кассира
 type CreateCashierRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username string     `json:"username" binding:"required"`
+	Password string     `json:"password" binding:"required"`
+	SiteID   *uuid.UUID `json:"site_id"` // Площадка сотрудника, по умолчанию основная
 }
 
 // CreateCashierResponse представляет ответ на создание кассира
 type CreateCashierResponse struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
+	SiteID    uuid.UUID `json:"site_id"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
 // CreateCleanerRequest представляет запрос на создание уборщика
 type CreateCleanerRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username string     `json:"username" binding:"required"`
+	Password string     `json:"password" binding:"required"`
+	SiteID   *uuid.UUID `json:"site_id"` // Площадка сотрудника, по умолчанию основная
 }
 
 // CreateCleanerResponse представляет ответ на создание уборщика
 type CreateCleanerResponse struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
+	SiteID    uuid.UUID `json:"site_id"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
 // UpdateCashierRequest представляет запрос на обновление кассира
 type UpdateCashierRequest struct {
-	ID       uuid.UUID `json:"id" binding:"required"`
-	Username string    `json:"username"`
-	Password string    `json:"password"`
-	IsActive bool      `json:"is_active"`
+	ID       uuid.UUID  `json:"id" binding:"required"`
+	Username string     `json:"username"`
+	Password string     `json:"password"`
+	IsActive bool       `json:"is_active"`
+	SiteID   *uuid.UUID `json:"site_id"` // Перевод сотрудника на другую площадку
 }
 
 // UpdateCashierResponse представляет ответ на обновление кассира
@@ -106,6 +114,7 @@ type UpdateCashierResponse struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
 	IsActive  bool      `json:"is_active"`
+	SiteID    uuid.UUID `json:"site_id"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
@@ -116,10 +125,11 @@ type GetCashiersResponse struct {
 
 // UpdateCleanerRequest представляет запрос на обновление уборщика
 type UpdateCleanerRequest struct {
-	ID       uuid.UUID `json:"id" binding:"required"`
-	Username string    `json:"username"`
-	Password string    `json:"password"`
-	IsActive bool      `json:"is_active"`
+	ID       uuid.UUID  `json:"id" binding:"required"`
+	Username string     `json:"username"`
+	Password string     `json:"password"`
+	IsActive bool       `json:"is_active"`
+	SiteID   *uuid.UUID `json:"site_id"` // Перевод сотрудника на другую площадку
 }
 
 // UpdateCleanerResponse представляет ответ на обновление уборщика
@@ -127,6 +137,7 @@ type UpdateCleanerResponse struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
 	IsActive  bool      `json:"is_active"`
+	SiteID    uuid.UUID `json:"site_id"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
@@ -147,10 +158,11 @@ type DeleteCleanerRequest struct {
 
 // TokenClaims представляет данные, хранящиеся в JWT токене
 type TokenClaims struct {
-	ID       uuid.UUID `json:"id"`
-	Username string    `json:"username"`
-	IsAdmin  bool      `json:"is_admin"`
-	Role     string    `json:"role,omitempty"`
+	ID       uuid.UUID  `json:"id"`
+	Username string     `json:"username"`
+	IsAdmin  bool       `json:"is_admin"`
+	Role     string     `json:"role,omitempty"`
+	SiteID   *uuid.UUID `json:"site_id,omitempty"` // Площадка кассира или уборщика
 }
 
 // TwoFactorAuthSettings представляет настройки двухфакторной аутентификации
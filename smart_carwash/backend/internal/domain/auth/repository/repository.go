@@ -60,7 +60,7 @@ type Repository interface {
 
 	// Методы для работы со сменами кассиров
 	CreateCashierShift(ctx context.Context, shift *models.CashierShift) error
-	GetActiveCashierShift(ctx context.Context) (*models.CashierShift, error)
+	GetActiveCashierShift(ctx context.Context, cashierID uuid.UUID) (*models.CashierShift, error)
 	GetActiveCashierShifts(ctx context.Context) ([]models.CashierShift, error)
 	UpdateCashierShift(ctx context.Context, shift *models.CashierShift) error
 	DeleteCashierShift(ctx context.Context, id uuid.UUID) error
@@ -153,14 +153,16 @@ func (r *PostgresRepository) ListCashiers(ctx context.Context) ([]models.Cashier
 
 // CreateCashierSession создает новую сессию кассира
 func (r *PostgresRepository) CreateCashierSession(ctx context.Context, session *models.CashierSession) error {
-	// Проверяем, есть ли уже активные сессии кассиров
+	// Проверяем, есть ли уже активные сессии кассиров той же площадки
 	var activeSessions []models.CashierSession
-	err := r.db.WithContext(ctx).Where("expires_at > ?", time.Now()).Find(&activeSessions).Error
+	err := r.db.WithContext(ctx).
+		Where("expires_at > ? AND cashier_id IN (?)", time.Now(), sameSiteCashierIDs(r.db, session.CashierID)).
+		Find(&activeSessions).Error
 	if err != nil {
 		return err
 	}
 
-	// Если есть активные сессии других кассиров, возвращаем ошибку
+	// Если на площадке есть активные сессии других кассиров, возвращаем ошибку
 	for _, s := range activeSessions {
 		if s.CashierID != session.CashierID {
 			return ErrActiveCashierSessionExists
@@ -243,11 +245,21 @@ func (r *PostgresRepository) SaveTwoFactorAuthSettings(ctx context.Context, sett
 	return r.db.WithContext(ctx).Save(&existingSettings).Error
 }
 
+// sameSiteCashierIDs подзапрос ID кассиров, работающих на той же площадке, что и указанный кассир
+// Один активный кассир и одна смена допускаются на каждой площадке отдельно
+func sameSiteCashierIDs(db *gorm.DB, cashierID uuid.UUID) *gorm.DB {
+	return db.Model(&models.Cashier{}).
+		Select("id").
+		Where("site_id = (?)", db.Model(&models.Cashier{}).Select("site_id").Where("id = ?", cashierID))
+}
+
 // CreateCashierShift создает новую смену для кассира
 func (r *PostgresRepository) CreateCashierShift(ctx context.Context, shift *models.CashierShift) error {
-	// Проверяем, есть ли уже активная смена
+	// Проверяем, есть ли уже активная смена на площадке кассира
 	var activeShift models.CashierShift
-	err := r.db.WithContext(ctx).Where("is_active = ? AND expires_at > ?", true, time.Now()).First(&activeShift).Error
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND expires_at > ? AND cashier_id IN (?)", true, time.Now(), sameSiteCashierIDs(r.db, shift.CashierID)).
+		First(&activeShift).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Если активной смены нет, создаем новую
@@ -258,10 +270,12 @@ func (r *PostgresRepository) CreateCashierShift(ctx context.Context, shift *mode
 	return ErrActiveShiftExists
 }
 
-// GetActiveCashierShift получает активную смену
-func (r *PostgresRepository) GetActiveCashierShift(ctx context.Context) (*models.CashierShift, error) {
+// GetActiveCashierShift получает активную смену на площадке кассира
+func (r *PostgresRepository) GetActiveCashierShift(ctx context.Context, cashierID uuid.UUID) (*models.CashierShift, error) {
 	var shift models.CashierShift
-	err := r.db.WithContext(ctx).Where("is_active = ? AND expires_at > ?", true, time.Now()).First(&shift).Error
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND expires_at > ? AND cashier_id IN (?)", true, time.Now(), sameSiteCashierIDs(r.db, cashierID)).
+		First(&shift).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNoActiveShift
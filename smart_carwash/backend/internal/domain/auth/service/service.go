@@ -11,6 +11,7 @@ import (
 	"carwash_backend/internal/config"
 	"carwash_backend/internal/domain/auth/models"
 	"carwash_backend/internal/domain/auth/repository"
+	siteModels "carwash_backend/internal/domain/site/models"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
@@ -24,8 +25,8 @@ var (
 	// ErrCashierInactive возвращается, когда кассир неактивен
 	ErrCashierInactive = errors.New("кассир неактивен")
 
-	// ErrAnotherCashierActive возвращается, когда другой кассир уже активен
-	ErrAnotherCashierActive = errors.New("другой кассир уже активен в системе")
+	// ErrAnotherCashierActive возвращается, когда другой кассир уже активен на площадке
+	ErrAnotherCashierActive = errors.New("другой кассир уже активен на этой площадке")
 
 	// ErrCleanerInactive возвращается, когда уборщик неактивен
 	ErrCleanerInactive = errors.New("уборщик неактивен")
@@ -168,11 +169,13 @@ func (s *ServiceImpl) LoginCashier(ctx context.Context, username, password strin
 
 	logger.Printf("Пароль верный для кассира: ID=%s", cashier.ID)
 
-	// Создаем JWT токен для кассира
+	// Создаем JWT токен для кассира, площадка кассира определяет видимые ему боксы и сессии
+	siteID := cashier.SiteID
 	claims := models.TokenClaims{
 		ID:       cashier.ID,
 		Username: cashier.Username,
 		IsAdmin:  false,
+		SiteID:   &siteID,
 	}
 
 	// Генерируем токен
@@ -207,6 +210,7 @@ func (s *ServiceImpl) LoginCashier(ctx context.Context, username, password strin
 		Token:     token,
 		ExpiresAt: expiresAt,
 		IsAdmin:   false,
+		SiteID:    &siteID,
 	}, nil
 }
 
@@ -359,6 +363,7 @@ func (s *ServiceImpl) ValidateToken(ctx context.Context, tokenString string) (*m
 		Username: username,
 		IsAdmin:  isAdmin,
 		Role:     role,
+		SiteID:   siteIDFromClaims(claims),
 	}, nil
 }
 
@@ -457,6 +462,7 @@ func (s *ServiceImpl) ValidateCleanerToken(ctx context.Context, tokenString stri
 		ID:       id,
 		Username: username,
 		IsAdmin:  false, // Уборщик никогда не администратор
+		SiteID:   siteIDFromClaims(claims),
 	}, nil
 }
 
@@ -500,6 +506,7 @@ func (s *ServiceImpl) CreateCashier(ctx context.Context, req *models.CreateCashi
 	cashier := &models.Cashier{
 		Username:     req.Username,
 		PasswordHash: string(hashedPassword),
+		SiteID:       staffSiteID(req.SiteID),
 		IsActive:     true,
 	}
 
@@ -514,6 +521,7 @@ func (s *ServiceImpl) CreateCashier(ctx context.Context, req *models.CreateCashi
 	return &models.CreateCashierResponse{
 		ID:        cashier.ID,
 		Username:  cashier.Username,
+		SiteID:    cashier.SiteID,
 		CreatedAt: cashier.CreatedAt,
 	}, nil
 }
@@ -545,6 +553,10 @@ func (s *ServiceImpl) UpdateCashier(ctx context.Context, req *models.UpdateCashi
 
 	cashier.IsActive = req.IsActive
 
+	if req.SiteID != nil {
+		cashier.SiteID = *req.SiteID
+	}
+
 	// Сохраняем обновленного кассира
 	if err := s.repo.UpdateCashier(ctx, cashier); err != nil {
 		return nil, err
@@ -554,6 +566,7 @@ func (s *ServiceImpl) UpdateCashier(ctx context.Context, req *models.UpdateCashi
 		ID:        cashier.ID,
 		Username:  cashier.Username,
 		IsActive:  cashier.IsActive,
+		SiteID:    cashier.SiteID,
 		UpdatedAt: cashier.UpdatedAt,
 	}, nil
 }
@@ -654,7 +667,7 @@ func (s *ServiceImpl) EndShift(ctx context.Context, req *models.EndShiftRequest)
 	}
 
 	// Получаем активную смену
-	shift, err := s.repo.GetActiveCashierShift(ctx)
+	shift, err := s.repo.GetActiveCashierShift(ctx, cashier.ID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNoActiveShift) {
 			return nil, fmt.Errorf("нет активной смены")
@@ -701,7 +714,7 @@ func (s *ServiceImpl) GetShiftStatus(ctx context.Context, cashierID uuid.UUID) (
 	}
 
 	// Получаем активную смену
-	shift, err := s.repo.GetActiveCashierShift(ctx)
+	shift, err := s.repo.GetActiveCashierShift(ctx, cashier.ID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNoActiveShift) {
 			return &models.ShiftStatusResponse{
@@ -780,13 +793,17 @@ func (s *ServiceImpl) generateToken(claims models.TokenClaims) (string, time.Tim
 	expiresAt := time.Now().Add(24 * time.Hour)
 
 	// Создаем JWT токен
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	mapClaims := jwt.MapClaims{
 		"id":       claims.ID.String(),
 		"username": claims.Username,
 		"is_admin": claims.IsAdmin,
 		"role":     claims.Role,
 		"exp":      expiresAt.Unix(),
-	})
+	}
+	if claims.SiteID != nil {
+		mapClaims["site_id"] = claims.SiteID.String()
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, mapClaims)
 
 	// Подписываем токен
 	tokenString, err := token.SignedString([]byte(s.config.JWTSecret))
@@ -828,11 +845,13 @@ func (s *ServiceImpl) LoginCleaner(ctx context.Context, username, password strin
 
 	logger.Printf("Пароль верный для уборщика: ID=%s", cleaner.ID)
 
-	// Создаем JWT токен для уборщика
+	// Создаем JWT токен для уборщика, площадка уборщика определяет видимые ему боксы
+	siteID := cleaner.SiteID
 	claims := models.TokenClaims{
 		ID:       cleaner.ID,
 		Username: cleaner.Username,
 		IsAdmin:  false,
+		SiteID:   &siteID,
 	}
 
 	// Генерируем токен
@@ -867,6 +886,7 @@ func (s *ServiceImpl) LoginCleaner(ctx context.Context, username, password strin
 		Token:     token,
 		ExpiresAt: expiresAt,
 		IsAdmin:   false,
+		SiteID:    &siteID,
 	}, nil
 }
 
@@ -883,6 +903,7 @@ func (s *ServiceImpl) CreateCleaner(ctx context.Context, req *models.CreateClean
 		ID:           uuid.New(),
 		Username:     req.Username,
 		PasswordHash: string(hashedPassword),
+		SiteID:       staffSiteID(req.SiteID),
 		IsActive:     true,
 	}
 
@@ -895,6 +916,7 @@ func (s *ServiceImpl) CreateCleaner(ctx context.Context, req *models.CreateClean
 	return &models.CreateCleanerResponse{
 		ID:        cleaner.ID,
 		Username:  cleaner.Username,
+		SiteID:    cleaner.SiteID,
 		CreatedAt: cleaner.CreatedAt,
 	}, nil
 }
@@ -910,6 +932,9 @@ func (s *ServiceImpl) UpdateCleaner(ctx context.Context, req *models.UpdateClean
 	// Обновляем поля
 	cleaner.Username = req.Username
 	cleaner.IsActive = req.IsActive
+	if req.SiteID != nil {
+		cleaner.SiteID = *req.SiteID
+	}
 
 	// Если передан новый пароль, хешируем его
 	if req.Password != "" {
@@ -930,6 +955,7 @@ func (s *ServiceImpl) UpdateCleaner(ctx context.Context, req *models.UpdateClean
 		ID:        cleaner.ID,
 		Username:  cleaner.Username,
 		IsActive:  cleaner.IsActive,
+		SiteID:    cleaner.SiteID,
 		UpdatedAt: cleaner.UpdatedAt,
 	}, nil
 }
@@ -955,3 +981,24 @@ func (s *ServiceImpl) GetCleaners(ctx context.Context) (*models.GetCleanersRespo
 func (s *ServiceImpl) GetCleanerByID(ctx context.Context, id uuid.UUID) (*models.Cleaner, error) {
 	return s.repo.GetCleanerByID(ctx, id)
 }
+
+// staffSiteID возвращает площадку сотрудника из запроса, по умолчанию - основную
+func staffSiteID(siteID *uuid.UUID) uuid.UUID {
+	if siteID == nil || *siteID == uuid.Nil {
+		return siteModels.DefaultSiteID
+	}
+	return *siteID
+}
+
+// siteIDFromClaims читает площадку из токена, в токенах до появления площадок ее нет
+func siteIDFromClaims(claims jwt.MapClaims) *uuid.UUID {
+	value, _ := claims["site_id"].(string)
+	if value == "" {
+		return nil
+	}
+	siteID, err := uuid.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return &siteID
+}
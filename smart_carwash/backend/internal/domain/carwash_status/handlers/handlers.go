@@ -4,6 +4,7 @@ import (
 	"carwash_backend/internal/domain/carwash_status/models"
 	"carwash_backend/internal/domain/carwash_status/service"
 	"carwash_backend/internal/logger"
+	"carwash_backend/internal/middleware"
 	"net/http"
 	"strconv"
 
@@ -37,12 +38,32 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 		adminRoutes.POST("/close", h.closeCarwash)
 		adminRoutes.POST("/open", h.openCarwash)
 		adminRoutes.GET("/history", h.getHistory)
+
+		// Расписание работы
+		adminRoutes.GET("/schedule", h.getSchedule)
+		adminRoutes.PUT("/schedule", h.updateSchedule)
+		adminRoutes.POST("/special-days", h.upsertSpecialDay)
+		adminRoutes.DELETE("/special-days", h.deleteSpecialDay)
+
+		// Закрытие с дообслуживанием очереди
+		adminRoutes.GET("/drain", h.getDrainStatus)
+		adminRoutes.POST("/drain", h.startDrain)
+		adminRoutes.POST("/drain/cancel", h.cancelDrain)
+
+		// Статус типов услуг
+		adminRoutes.GET("/service-types", h.getServiceTypeStatuses)
+		adminRoutes.PUT("/service-types", h.updateServiceTypeStatus)
 	}
 }
 
 // getCurrentStatus получает текущий статус мойки (публичный)
 func (h *Handler) getCurrentStatus(c *gin.Context) {
-	req := &models.GetCurrentStatusRequest{}
+	siteID, err := middleware.RequestSiteID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := &models.GetCurrentStatusRequest{SiteID: siteID}
 
 	resp, err := h.service.GetCurrentStatus(c.Request.Context(), req)
 	if err != nil {
@@ -56,7 +77,12 @@ func (h *Handler) getCurrentStatus(c *gin.Context) {
 
 // adminGetCurrentStatus получает текущий статус мойки (админка)
 func (h *Handler) adminGetCurrentStatus(c *gin.Context) {
-	req := &models.GetCurrentStatusRequest{}
+	siteID, err := middleware.RequestSiteID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := &models.GetCurrentStatusRequest{SiteID: siteID}
 
 	resp, err := h.service.GetCurrentStatus(c.Request.Context(), req)
 	if err != nil {
@@ -97,6 +123,7 @@ func (h *Handler) closeCarwash(c *gin.Context) {
 	logger.WithContext(c).Infof("closeCarwash: запрос на закрытие мойки, admin_id: %s, reason: %v", adminID, req.Reason)
 	c.Set("meta", gin.H{
 		"admin_id": adminID,
+		"site_id":  req.SiteID,
 		"reason":   req.Reason,
 	})
 
@@ -110,6 +137,7 @@ func (h *Handler) closeCarwash(c *gin.Context) {
 	// Обновляем мета-параметры с результатами
 	c.Set("meta", gin.H{
 		"admin_id":           adminID,
+		"site_id":            req.SiteID,
 		"reason":             req.Reason,
 		"completed_sessions": resp.CompletedSessions,
 		"canceled_sessions":  resp.CanceledSessions,
@@ -147,6 +175,7 @@ func (h *Handler) openCarwash(c *gin.Context) {
 	logger.WithContext(c).Infof("openCarwash: запрос на открытие мойки, admin_id: %s", adminID)
 	c.Set("meta", gin.H{
 		"admin_id": adminID,
+		"site_id":  req.SiteID,
 	})
 
 	resp, err := h.service.OpenCarwash(c.Request.Context(), &req, adminID)
@@ -163,6 +192,13 @@ func (h *Handler) openCarwash(c *gin.Context) {
 func (h *Handler) getHistory(c *gin.Context) {
 	var req models.GetHistoryRequest
 
+	siteID, err := middleware.RequestSiteID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.SiteID = siteID
+
 	// Получаем параметры из query
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if limit, err := strconv.Atoi(limitStr); err == nil {
@@ -185,3 +221,243 @@ func (h *Handler) getHistory(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// getSchedule получает расписание работы площадки (админка)
+func (h *Handler) getSchedule(c *gin.Context) {
+	siteID, err := middleware.RequestSiteID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := &models.GetScheduleRequest{SiteID: siteID}
+
+	resp, err := h.service.GetSchedule(c.Request.Context(), req)
+	if err != nil {
+		logger.WithContext(c).Errorf("getSchedule: ошибка получения расписания: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// updateSchedule изменяет расписание работы площадки (админка)
+func (h *Handler) updateSchedule(c *gin.Context) {
+	var req models.UpdateScheduleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("updateSchedule: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"site_id":                            req.SiteID,
+		"enabled":                            req.Enabled,
+		"stop_sessions_before_close_minutes": req.StopSessionsBeforeCloseMinutes,
+	})
+
+	resp, err := h.service.UpdateSchedule(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("updateSchedule: ошибка изменения расписания: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// upsertSpecialDay добавляет или изменяет особый день (админка)
+func (h *Handler) upsertSpecialDay(c *gin.Context) {
+	var req models.UpsertSpecialDayRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("upsertSpecialDay: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"site_id":   req.SiteID,
+		"date":      req.Date,
+		"is_closed": req.IsClosed,
+	})
+
+	day, err := h.service.UpsertSpecialDay(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("upsertSpecialDay: ошибка сохранения особого дня: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, day)
+}
+
+// deleteSpecialDay удаляет особый день (админка)
+func (h *Handler) deleteSpecialDay(c *gin.Context) {
+	var req models.DeleteSpecialDayRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("deleteSpecialDay: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{"special_day_id": req.ID})
+
+	if err := h.service.DeleteSpecialDay(c.Request.Context(), &req); err != nil {
+		logger.WithContext(c).Errorf("deleteSpecialDay: ошибка удаления особого дня: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// getDrainStatus получает ход закрытия с дообслуживанием (админка)
+func (h *Handler) getDrainStatus(c *gin.Context) {
+	siteID, err := middleware.RequestSiteID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := &models.GetDrainStatusRequest{SiteID: siteID}
+
+	resp, err := h.service.GetDrainStatus(c.Request.Context(), req)
+	if err != nil {
+		logger.WithContext(c).Errorf("getDrainStatus: ошибка получения хода закрытия: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// startDrain включает закрытие мойки с дообслуживанием очереди (админка)
+func (h *Handler) startDrain(c *gin.Context) {
+	var req models.StartDrainRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("startDrain: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Получаем ID администратора из контекста
+	adminIDValue, exists := c.Get("user_id")
+	if !exists {
+		logger.WithContext(c).Errorf("startDrain: user_id не найден в контексте")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Требуется авторизация"})
+		return
+	}
+
+	adminID, ok := adminIDValue.(uuid.UUID)
+	if !ok {
+		logger.WithContext(c).Errorf("startDrain: некорректный тип user_id")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения ID администратора"})
+		return
+	}
+
+	logger.WithContext(c).Infof("startDrain: запрос на закрытие с дообслуживанием, admin_id: %s, deadline_minutes: %d", adminID, req.DeadlineMinutes)
+	c.Set("meta", gin.H{
+		"admin_id":         adminID,
+		"site_id":          req.SiteID,
+		"reason":           req.Reason,
+		"deadline_minutes": req.DeadlineMinutes,
+	})
+
+	resp, err := h.service.StartDrain(c.Request.Context(), &req, adminID)
+	if err != nil {
+		logger.WithContext(c).Errorf("startDrain: ошибка включения закрытия с дообслуживанием: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// cancelDrain отменяет закрытие с дообслуживанием (админка)
+func (h *Handler) cancelDrain(c *gin.Context) {
+	var req models.CancelDrainRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("cancelDrain: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{"site_id": req.SiteID})
+
+	resp, err := h.service.CancelDrain(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("cancelDrain: ошибка отмены закрытия с дообслуживанием: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// getServiceTypeStatuses получает статусы и очереди типов услуг (админка)
+func (h *Handler) getServiceTypeStatuses(c *gin.Context) {
+	siteID, err := middleware.RequestSiteID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := &models.GetServiceTypeStatusesRequest{SiteID: siteID}
+
+	resp, err := h.service.GetServiceTypeStatuses(c.Request.Context(), req)
+	if err != nil {
+		logger.WithContext(c).Errorf("getServiceTypeStatuses: ошибка получения статусов услуг: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// updateServiceTypeStatus открывает или закрывает тип услуги и меняет лимит очереди (админка)
+func (h *Handler) updateServiceTypeStatus(c *gin.Context) {
+	var req models.UpdateServiceTypeStatusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("updateServiceTypeStatus: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Получаем ID администратора из контекста
+	adminIDValue, exists := c.Get("user_id")
+	if !exists {
+		logger.WithContext(c).Errorf("updateServiceTypeStatus: user_id не найден в контексте")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Требуется авторизация"})
+		return
+	}
+
+	adminID, ok := adminIDValue.(uuid.UUID)
+	if !ok {
+		logger.WithContext(c).Errorf("updateServiceTypeStatus: некорректный тип user_id")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения ID администратора"})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"admin_id":       adminID,
+		"site_id":        req.SiteID,
+		"service_type":   req.ServiceType,
+		"is_closed":      req.IsClosed,
+		"closed_reason":  req.ClosedReason,
+		"max_queue_size": req.MaxQueueSize,
+		"cancel_queued":  req.CancelQueued,
+	})
+
+	resp, err := h.service.UpdateServiceTypeStatus(c.Request.Context(), &req, adminID)
+	if err != nil {
+		logger.WithContext(c).Errorf("updateServiceTypeStatus: ошибка изменения статуса услуги: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -6,14 +6,28 @@ import (
 	"github.com/google/uuid"
 )
 
-// CarwashStatus представляет текущий статус мойки (singleton)
+// CarwashStatus представляет текущий статус мойки (одна запись на площадку)
 type CarwashStatus struct {
 	ID           uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SiteID       uuid.UUID  `json:"site_id" gorm:"type:uuid;uniqueIndex"`
 	IsClosed     bool       `json:"is_closed" gorm:"default:false;not null"`
 	ClosedReason *string    `json:"closed_reason,omitempty"`
 	UpdatedAt    time.Time  `json:"updated_at" gorm:"not null"`
 	UpdatedBy    *uuid.UUID `json:"updated_by,omitempty" gorm:"type:uuid"`
 	CreatedAt    time.Time  `json:"created_at" gorm:"not null"`
+
+	// Расписание работы
+	ScheduleEnabled                bool       `json:"schedule_enabled" gorm:"default:false;not null"`               // Автоматическое открытие и закрытие по расписанию
+	StopSessionsBeforeCloseMinutes int        `json:"stop_sessions_before_close_minutes" gorm:"default:0;not null"` // За сколько минут до закрытия перестать принимать новые сессии
+	ClosedBySchedule               bool       `json:"closed_by_schedule" gorm:"default:false;not null"`             // Мойка закрыта расписанием (откроется автоматически)
+	ScheduleOverrideUntil          *time.Time `json:"schedule_override_until,omitempty"`                            // До этого времени расписание не меняет статус, выставленный вручную
+	ClosingNoticeFor               *time.Time `json:"-"`                                                            // Время закрытия, о котором уже предупреждены клиенты в очереди
+
+	// Закрытие с дообслуживанием очереди
+	DrainStartedAt *time.Time `json:"drain_started_at,omitempty"`                  // Начало закрытия с дообслуживанием (nil - режим не включен)
+	DrainDeadline  *time.Time `json:"drain_deadline,omitempty"`                    // Крайний срок: мойка закроется, даже если очередь не обслужена
+	DrainReason    *string    `json:"drain_reason,omitempty"`                      // Причина закрытия, которая будет записана при закрытии
+	DrainStartedBy *uuid.UUID `json:"drain_started_by,omitempty" gorm:"type:uuid"` // Администратор, включивший режим
 }
 
 // TableName указывает имя таблицы для GORM
@@ -24,10 +38,12 @@ func (CarwashStatus) TableName() string {
 // CarwashStatusHistory представляет запись истории изменений статуса мойки
 type CarwashStatusHistory struct {
 	ID           uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SiteID       uuid.UUID  `json:"site_id" gorm:"type:uuid;index"`
 	IsClosed     bool       `json:"is_closed" gorm:"not null"`
 	ClosedReason *string    `json:"closed_reason,omitempty"`
 	CreatedAt    time.Time  `json:"created_at" gorm:"not null"`
 	CreatedBy    *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	Source       string     `json:"source" gorm:"default:admin;not null"` // admin - вручную, system - по расписанию, drain - после дообслуживания очереди
 }
 
 // TableName указывает имя таблицы для GORM
@@ -35,19 +51,41 @@ func (CarwashStatusHistory) TableName() string {
 	return "carwash_status_history"
 }
 
+// Источники изменения статуса мойки
+const (
+	HistorySourceAdmin  = "admin"
+	HistorySourceSystem = "system"
+	HistorySourceDrain  = "drain"
+)
+
 // GetCurrentStatusRequest запрос на получение текущего статуса
-type GetCurrentStatusRequest struct{}
+type GetCurrentStatusRequest struct {
+	SiteID uuid.UUID `json:"-"` // Площадка (по умолчанию основная)
+}
 
 // GetCurrentStatusResponse ответ на получение текущего статуса
 type GetCurrentStatusResponse struct {
+	SiteID       uuid.UUID `json:"site_id"`
 	IsClosed     bool      `json:"is_closed"`
 	ClosedReason *string   `json:"closed_reason,omitempty"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	ScheduleEnabled   bool       `json:"schedule_enabled"`
+	AcceptingSessions bool       `json:"accepting_sessions"`         // Принимаются ли новые сессии прямо сейчас
+	ClosesAt          *time.Time `json:"closes_at,omitempty"`        // Закрытие по расписанию (если мойка работает)
+	StopSessionsAt    *time.Time `json:"stop_sessions_at,omitempty"` // Окончание приема новых сессий перед закрытием
+	NextOpenAt        *time.Time `json:"next_open_at,omitempty"`     // Ближайшее открытие по расписанию (если мойка закрыта)
+
+	IsDraining    bool       `json:"is_draining"`              // Мойка закрывается: обслуживается только текущая очередь
+	DrainDeadline *time.Time `json:"drain_deadline,omitempty"` // Крайний срок закрытия
+
+	ServiceTypes []ServiceTypeStatusInfo `json:"service_types"` // Статус и очередь отдельных типов услуг
 }
 
 // CloseCarwashRequest запрос на закрытие мойки
 type CloseCarwashRequest struct {
-	Reason *string `json:"reason,omitempty"` // Опциональная причина закрытия
+	Reason *string    `json:"reason,omitempty"`  // Опциональная причина закрытия
+	SiteID *uuid.UUID `json:"site_id,omitempty"` // Площадка (по умолчанию основная)
 }
 
 // CloseCarwashResponse ответ на закрытие мойки
@@ -59,7 +97,9 @@ type CloseCarwashResponse struct {
 }
 
 // OpenCarwashRequest запрос на открытие мойки
-type OpenCarwashRequest struct{}
+type OpenCarwashRequest struct {
+	SiteID *uuid.UUID `json:"site_id,omitempty"` // Площадка (по умолчанию основная)
+}
 
 // OpenCarwashResponse ответ на открытие мойки
 type OpenCarwashResponse struct {
@@ -69,8 +109,9 @@ type OpenCarwashResponse struct {
 
 // GetHistoryRequest запрос на получение истории изменений статуса
 type GetHistoryRequest struct {
-	Limit  int `json:"limit" form:"limit"`   // Лимит записей (по умолчанию 50)
-	Offset int `json:"offset" form:"offset"` // Смещение (по умолчанию 0)
+	Limit  int       `json:"limit" form:"limit"`   // Лимит записей (по умолчанию 50)
+	Offset int       `json:"offset" form:"offset"` // Смещение (по умолчанию 0)
+	SiteID uuid.UUID `json:"-"`                    // Площадка (по умолчанию основная)
 }
 
 // GetHistoryResponse ответ на получение истории изменений статуса
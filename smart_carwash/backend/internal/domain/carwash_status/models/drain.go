@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StartDrainRequest запрос на закрытие мойки с дообслуживанием очереди
+type StartDrainRequest struct {
+	SiteID          *uuid.UUID `json:"site_id,omitempty"`                                 // Площадка (по умолчанию основная)
+	Reason          *string    `json:"reason,omitempty"`                                  // Причина закрытия
+	DeadlineMinutes int        `json:"deadline_minutes" binding:"required,min=1,max=720"` // Через сколько минут закрыть мойку в любом случае
+}
+
+// CancelDrainRequest запрос на отмену закрытия с дообслуживанием
+type CancelDrainRequest struct {
+	SiteID *uuid.UUID `json:"site_id,omitempty"` // Площадка (по умолчанию основная)
+}
+
+// GetDrainStatusRequest запрос на получение хода закрытия
+type GetDrainStatusRequest struct {
+	SiteID uuid.UUID `json:"-"` // Площадка (по умолчанию основная)
+}
+
+// DrainSession сессия, которую мойка дообслуживает перед закрытием
+type DrainSession struct {
+	ID            uuid.UUID  `json:"id"`
+	Status        string     `json:"status"`
+	ServiceType   string     `json:"service_type"`
+	CarNumber     string     `json:"car_number"`
+	BoxID         *uuid.UUID `json:"box_id,omitempty"`
+	ExpectedEndAt *time.Time `json:"expected_end_at,omitempty"` // Для активных сессий - окончание оплаченного времени
+}
+
+// DrainStatusResponse ход закрытия мойки с дообслуживанием
+type DrainStatusResponse struct {
+	SiteID           uuid.UUID      `json:"site_id"`
+	IsDraining       bool           `json:"is_draining"`
+	IsClosed         bool           `json:"is_closed"`
+	StartedAt        *time.Time     `json:"started_at,omitempty"`
+	Deadline         *time.Time     `json:"deadline,omitempty"`
+	Reason           *string        `json:"reason,omitempty"`
+	QueuedSessions   int            `json:"queued_sessions"`   // В очереди
+	AssignedSessions int            `json:"assigned_sessions"` // Назначен бокс, мойка не начата
+	ActiveSessions   int            `json:"active_sessions"`   // Моются
+	Sessions         []DrainSession `json:"sessions"`
+}
@@ -0,0 +1,282 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleLocation часовой пояс расписания (Москва, без перехода на летнее время)
+var ScheduleLocation = time.FixedZone("MSK", 3*60*60)
+
+// scheduleLookaheadDays на сколько дней вперед ищется ближайшее открытие
+const scheduleLookaheadDays = 31
+
+// WorkingHours рабочие часы площадки в день недели
+// Время закрытия раньше или равное времени открытия означает работу после полуночи (00:00-00:00 - круглосуточно)
+type WorkingHours struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SiteID    uuid.UUID `json:"site_id" gorm:"type:uuid;not null"`
+	Weekday   int       `json:"weekday" gorm:"not null"`    // 0 - воскресенье ... 6 - суббота
+	OpenTime  string    `json:"open_time" gorm:"not null"`  // ЧЧ:ММ
+	CloseTime string    `json:"close_time" gorm:"not null"` // ЧЧ:ММ
+	IsDayOff  bool      `json:"is_day_off" gorm:"default:false;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (WorkingHours) TableName() string {
+	return "carwash_working_hours"
+}
+
+// SpecialDay исключение из недельного расписания: праздник (IsClosed) или день с особыми часами
+type SpecialDay struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SiteID    uuid.UUID `json:"site_id" gorm:"type:uuid;not null"`
+	Date      time.Time `json:"date" gorm:"type:date;not null"`
+	IsClosed  bool      `json:"is_closed" gorm:"default:false;not null"`
+	OpenTime  *string   `json:"open_time,omitempty"`
+	CloseTime *string   `json:"close_time,omitempty"`
+	Reason    *string   `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (SpecialDay) TableName() string {
+	return "carwash_special_days"
+}
+
+// Schedule расписание площадки для расчета открытия и закрытия
+type Schedule struct {
+	Enabled                        bool
+	StopSessionsBeforeCloseMinutes int
+	WorkingHours                   map[time.Weekday]WorkingHours
+	SpecialDays                    map[string]SpecialDay // ключ - дата ГГГГ-ММ-ДД
+}
+
+// NewSchedule собирает расписание из настроек статуса, рабочих часов и особых дней
+func NewSchedule(status *CarwashStatus, hours []WorkingHours, specialDays []SpecialDay) *Schedule {
+	schedule := &Schedule{
+		WorkingHours: make(map[time.Weekday]WorkingHours, len(hours)),
+		SpecialDays:  make(map[string]SpecialDay, len(specialDays)),
+	}
+	if status != nil {
+		schedule.Enabled = status.ScheduleEnabled
+		schedule.StopSessionsBeforeCloseMinutes = status.StopSessionsBeforeCloseMinutes
+	}
+	for _, h := range hours {
+		schedule.WorkingHours[time.Weekday(h.Weekday)] = h
+	}
+	for _, d := range specialDays {
+		schedule.SpecialDays[d.Date.Format("2006-01-02")] = d
+	}
+	return schedule
+}
+
+// ParseClockTime разбирает время в формате ЧЧ:ММ и возвращает количество минут от начала суток
+func ParseClockTime(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("некорректное время %q, ожидается ЧЧ:ММ", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// dayWindow возвращает интервал работы, начинающийся в указанный календарный день
+// Особый день заменяет недельное расписание; день без рабочих часов считается выходным
+func (s *Schedule) dayWindow(day time.Time) (time.Time, time.Time, bool) {
+	var openTime, closeTime string
+
+	if special, ok := s.SpecialDays[day.Format("2006-01-02")]; ok {
+		if special.IsClosed || special.OpenTime == nil || special.CloseTime == nil {
+			return time.Time{}, time.Time{}, false
+		}
+		openTime, closeTime = *special.OpenTime, *special.CloseTime
+	} else {
+		hours, ok := s.WorkingHours[day.Weekday()]
+		if !ok || hours.IsDayOff {
+			return time.Time{}, time.Time{}, false
+		}
+		openTime, closeTime = hours.OpenTime, hours.CloseTime
+	}
+
+	openMinutes, err := ParseClockTime(openTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	closeMinutes, err := ParseClockTime(closeTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, ScheduleLocation)
+	open := dayStart.Add(time.Duration(openMinutes) * time.Minute)
+	closeAt := dayStart.Add(time.Duration(closeMinutes) * time.Minute)
+	if closeMinutes <= openMinutes {
+		closeAt = closeAt.AddDate(0, 0, 1)
+	}
+	return open, closeAt, true
+}
+
+// CurrentWindow возвращает интервал работы, в который попадает момент t
+// Проверяется и предыдущий день, так как его интервал может заканчиваться после полуночи
+func (s *Schedule) CurrentWindow(t time.Time) (time.Time, time.Time, bool) {
+	local := t.In(ScheduleLocation)
+	for _, day := range []time.Time{local.AddDate(0, 0, -1), local} {
+		open, closeAt, ok := s.dayWindow(day)
+		if ok && !t.Before(open) && t.Before(closeAt) {
+			return open, closeAt, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// IsOpenAt проверяет, работает ли мойка по расписанию в момент t
+func (s *Schedule) IsOpenAt(t time.Time) bool {
+	_, _, ok := s.CurrentWindow(t)
+	return ok
+}
+
+// StopSessionsAt возвращает момент окончания приема новых сессий в текущем интервале работы
+func (s *Schedule) StopSessionsAt(t time.Time) (time.Time, bool) {
+	_, closeAt, ok := s.CurrentWindow(t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return closeAt.Add(-time.Duration(s.StopSessionsBeforeCloseMinutes) * time.Minute), true
+}
+
+// AcceptsSessionsAt проверяет, принимаются ли новые сессии по расписанию в момент t
+func (s *Schedule) AcceptsSessionsAt(t time.Time) bool {
+	stopAt, ok := s.StopSessionsAt(t)
+	return ok && t.Before(stopAt)
+}
+
+// NextOpenAt возвращает ближайшее открытие после момента t
+// Возвращает nil, если в ближайший месяц открытий нет
+func (s *Schedule) NextOpenAt(t time.Time) *time.Time {
+	local := t.In(ScheduleLocation)
+	for i := 0; i <= scheduleLookaheadDays; i++ {
+		open, _, ok := s.dayWindow(local.AddDate(0, 0, i))
+		if ok && open.After(t) {
+			return &open
+		}
+	}
+	return nil
+}
+
+// NextTransition возвращает момент следующей смены состояния по расписанию (закрытие или открытие)
+func (s *Schedule) NextTransition(t time.Time) *time.Time {
+	if _, closeAt, ok := s.CurrentWindow(t); ok {
+		return &closeAt
+	}
+	return s.NextOpenAt(t)
+}
+
+// ClosedReason возвращает причину закрытия по расписанию в момент t
+func (s *Schedule) ClosedReason(t time.Time) string {
+	local := t.In(ScheduleLocation)
+	if special, ok := s.SpecialDays[local.Format("2006-01-02")]; ok && special.IsClosed && special.Reason != nil && *special.Reason != "" {
+		return *special.Reason
+	}
+	return "Нерабочее время"
+}
+
+// WorkingHoursInput рабочие часы дня недели в запросе
+type WorkingHoursInput struct {
+	Weekday   int    `json:"weekday" binding:"min=0,max=6"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+	IsDayOff  bool   `json:"is_day_off"`
+}
+
+// GetScheduleRequest запрос на получение расписания
+type GetScheduleRequest struct {
+	SiteID uuid.UUID `json:"-"` // Площадка (по умолчанию основная)
+}
+
+// GetScheduleResponse расписание площадки
+type GetScheduleResponse struct {
+	SiteID                         uuid.UUID      `json:"site_id"`
+	Enabled                        bool           `json:"enabled"`
+	StopSessionsBeforeCloseMinutes int            `json:"stop_sessions_before_close_minutes"`
+	WorkingHours                   []WorkingHours `json:"working_hours"`
+	SpecialDays                    []SpecialDay   `json:"special_days"` // Сегодняшние и будущие
+	NextOpenAt                     *time.Time     `json:"next_open_at,omitempty"`
+}
+
+// UpdateScheduleRequest запрос на изменение расписания
+// Переданные рабочие часы заменяют недельное расписание целиком
+type UpdateScheduleRequest struct {
+	SiteID                         *uuid.UUID          `json:"site_id,omitempty"`
+	Enabled                        bool                `json:"enabled"`
+	StopSessionsBeforeCloseMinutes int                 `json:"stop_sessions_before_close_minutes" binding:"min=0,max=240"`
+	WorkingHours                   []WorkingHoursInput `json:"working_hours" binding:"dive"`
+}
+
+// UpsertSpecialDayRequest запрос на добавление или изменение особого дня
+type UpsertSpecialDayRequest struct {
+	SiteID    *uuid.UUID `json:"site_id,omitempty"`
+	Date      string     `json:"date" binding:"required"` // ГГГГ-ММ-ДД
+	IsClosed  bool       `json:"is_closed"`
+	OpenTime  *string    `json:"open_time,omitempty"`
+	CloseTime *string    `json:"close_time,omitempty"`
+	Reason    *string    `json:"reason,omitempty" binding:"omitempty,max=255"`
+}
+
+// DeleteSpecialDayRequest запрос на удаление особого дня
+type DeleteSpecialDayRequest struct {
+	ID uuid.UUID `json:"id" binding:"required"`
+}
+
+// CarwashClosedError ошибка создания сессии, когда площадка не принимает новые сессии
+type CarwashClosedError struct {
+	Reason     string     `json:"reason"`
+	NextOpenAt *time.Time `json:"next_open_at,omitempty"`
+}
+
+// Error реализует интерфейс error
+func (e *CarwashClosedError) Error() string {
+	if e.NextOpenAt != nil {
+		return fmt.Sprintf("%s, ближайшее открытие: %s", e.Reason, e.NextOpenAt.In(ScheduleLocation).Format("02.01 15:04"))
+	}
+	return e.Reason
+}
+
+// CheckAcceptingSessions проверяет, принимает ли площадка новые сессии в момент now
+// Сессии не принимаются, если мойка закрыта или закрывается с дообслуживанием очереди, а при включенном расписании - вне рабочих часов и за
+// StopSessionsBeforeCloseMinutes минут до закрытия. Пока действует ручное открытие администратором
+// в нерабочее время (ScheduleOverrideUntil), расписание не проверяется. schedule равен nil, если расписание выключено
+func CheckAcceptingSessions(status *CarwashStatus, schedule *Schedule, now time.Time) *CarwashClosedError {
+	if status.IsClosed {
+		closedErr := &CarwashClosedError{Reason: "мойка закрыта"}
+		if status.ClosedReason != nil && *status.ClosedReason != "" {
+			closedErr.Reason = fmt.Sprintf("мойка закрыта: %s", *status.ClosedReason)
+		}
+		if schedule != nil {
+			closedErr.NextOpenAt = schedule.NextOpenAt(now)
+		}
+		return closedErr
+	}
+
+	if status.DrainStartedAt != nil {
+		return &CarwashClosedError{Reason: "мойка закрывается, новые сессии не принимаются"}
+	}
+
+	overridden := status.ScheduleOverrideUntil != nil && now.Before(*status.ScheduleOverrideUntil)
+	if schedule == nil || !schedule.Enabled || overridden || schedule.AcceptsSessionsAt(now) {
+		return nil
+	}
+
+	closedErr := &CarwashClosedError{
+		Reason:     "прием новых сессий остановлен перед закрытием мойки",
+		NextOpenAt: schedule.NextOpenAt(now),
+	}
+	if !schedule.IsOpenAt(now) {
+		closedErr.Reason = "мойка не работает в это время"
+	}
+	return closedErr
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Коды отказа в создании сессии по типу услуги
+const (
+	ServiceTypeUnavailableClosed    = "service_type_closed"
+	ServiceTypeUnavailableQueueFull = "queue_full"
+)
+
+// ServiceTypeStatus статус типа услуги на площадке
+// Отсутствие записи означает, что услуга открыта и очередь не ограничена
+type ServiceTypeStatus struct {
+	ID           uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SiteID       uuid.UUID  `json:"site_id" gorm:"type:uuid;not null"`
+	ServiceType  string     `json:"service_type" gorm:"not null"`
+	IsClosed     bool       `json:"is_closed" gorm:"default:false;not null"`
+	ClosedReason *string    `json:"closed_reason,omitempty"`
+	MaxQueueSize int        `json:"max_queue_size" gorm:"default:0;not null"` // 0 - без ограничения
+	UpdatedBy    *uuid.UUID `json:"updated_by,omitempty" gorm:"type:uuid"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (ServiceTypeStatus) TableName() string {
+	return "carwash_service_type_status"
+}
+
+// ServiceTypeStatusInfo статус типа услуги с текущей очередью
+type ServiceTypeStatusInfo struct {
+	ServiceType  string  `json:"service_type"`
+	IsClosed     bool    `json:"is_closed"`
+	ClosedReason *string `json:"closed_reason,omitempty"`
+	MaxQueueSize int     `json:"max_queue_size"` // 0 - без ограничения
+	QueueSize    int     `json:"queue_size"`     // Сессий в очереди
+	QueueFull    bool    `json:"queue_full"`     // Очередь заполнена, новые сессии не принимаются
+}
+
+// NewServiceTypeStatusInfo собирает статус типа услуги; status равен nil, если настроек нет
+func NewServiceTypeStatusInfo(serviceType string, status *ServiceTypeStatus, queueSize int) ServiceTypeStatusInfo {
+	info := ServiceTypeStatusInfo{
+		ServiceType: serviceType,
+		QueueSize:   queueSize,
+	}
+	if status != nil {
+		info.IsClosed = status.IsClosed
+		info.ClosedReason = status.ClosedReason
+		info.MaxQueueSize = status.MaxQueueSize
+		info.QueueFull = !status.IsClosed && CheckServiceTypeAvailable(serviceType, status, queueSize) != nil
+	}
+	return info
+}
+
+// GetServiceTypeStatusesRequest запрос на получение статусов типов услуг
+type GetServiceTypeStatusesRequest struct {
+	SiteID uuid.UUID `json:"-"` // Площадка (по умолчанию основная)
+}
+
+// GetServiceTypeStatusesResponse статусы типов услуг площадки
+type GetServiceTypeStatusesResponse struct {
+	SiteID       uuid.UUID               `json:"site_id"`
+	ServiceTypes []ServiceTypeStatusInfo `json:"service_types"`
+}
+
+// UpdateServiceTypeStatusRequest запрос на изменение статуса типа услуги
+type UpdateServiceTypeStatusRequest struct {
+	SiteID       *uuid.UUID `json:"site_id,omitempty"` // Площадка (по умолчанию основная)
+	ServiceType  string     `json:"service_type" binding:"required,oneof=wash air_dry vacuum"`
+	IsClosed     bool       `json:"is_closed"`
+	ClosedReason *string    `json:"closed_reason,omitempty" binding:"omitempty,max=255"` // Показывается в приложении
+	MaxQueueSize int        `json:"max_queue_size" binding:"min=0,max=1000"`             // 0 - без ограничения
+	CancelQueued bool       `json:"cancel_queued"`                                       // При закрытии отменить сессии в очереди и с назначенным боксом с возвратом
+}
+
+// UpdateServiceTypeStatusResponse ответ на изменение статуса типа услуги
+type UpdateServiceTypeStatusResponse struct {
+	ServiceType      ServiceTypeStatusInfo `json:"service_type"`
+	CanceledSessions int                   `json:"canceled_sessions"` // Отменено сессий с возвратом
+}
+
+// ServiceTypeUnavailableError ошибка создания сессии, когда тип услуги закрыт или очередь заполнена
+type ServiceTypeUnavailableError struct {
+	ServiceType string `json:"service_type"`
+	Code        string `json:"code"`
+	Reason      string `json:"reason"`
+}
+
+// Error реализует интерфейс error
+func (e *ServiceTypeUnavailableError) Error() string {
+	return e.Reason
+}
+
+// CheckServiceTypeAvailable проверяет, принимает ли тип услуги новые сессии
+// status равен nil, если настроек типа услуги нет; queueSize - текущее количество сессий в очереди
+func CheckServiceTypeAvailable(serviceType string, status *ServiceTypeStatus, queueSize int) *ServiceTypeUnavailableError {
+	if status == nil {
+		return nil
+	}
+
+	if status.IsClosed {
+		reason := "услуга временно не работает"
+		if status.ClosedReason != nil && *status.ClosedReason != "" {
+			reason = fmt.Sprintf("услуга временно не работает: %s", *status.ClosedReason)
+		}
+		return &ServiceTypeUnavailableError{ServiceType: serviceType, Code: ServiceTypeUnavailableClosed, Reason: reason}
+	}
+
+	if status.MaxQueueSize > 0 && queueSize >= status.MaxQueueSize {
+		return &ServiceTypeUnavailableError{
+			ServiceType: serviceType,
+			Code:        ServiceTypeUnavailableQueueFull,
+			Reason:      fmt.Sprintf("очередь заполнена (%d), попробуйте позже", status.MaxQueueSize),
+		}
+	}
+
+	return nil
+}
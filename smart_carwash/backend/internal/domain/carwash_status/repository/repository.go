@@ -4,17 +4,37 @@ import (
 	"carwash_backend/internal/domain/carwash_status/models"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repository интерфейс для работы со статусом мойки в базе данных
 type Repository interface {
-	GetCurrentStatus(ctx context.Context) (*models.CarwashStatus, error)
-	UpdateStatus(ctx context.Context, isClosed bool, reason *string, updatedBy *uuid.UUID) error
-	CreateHistoryRecord(ctx context.Context, isClosed bool, reason *string, createdBy *uuid.UUID) error
-	GetHistory(ctx context.Context, limit, offset int) ([]models.CarwashStatusHistory, int, error)
+	GetCurrentStatus(ctx context.Context, siteID uuid.UUID) (*models.CarwashStatus, error)
+	UpdateStatus(ctx context.Context, siteID uuid.UUID, isClosed bool, reason *string, updatedBy *uuid.UUID) error
+	UpdateStatusFields(ctx context.Context, siteID uuid.UUID, updates map[string]interface{}) error
+	CreateHistoryRecord(ctx context.Context, siteID uuid.UUID, isClosed bool, reason *string, createdBy *uuid.UUID, source string) error
+	GetHistory(ctx context.Context, siteID uuid.UUID, limit, offset int) ([]models.CarwashStatusHistory, int, error)
+
+	// Расписание работы
+	GetSchedule(ctx context.Context, siteID uuid.UUID, now time.Time) (*models.Schedule, error)
+	GetWorkingHours(ctx context.Context, siteID uuid.UUID) ([]models.WorkingHours, error)
+	ReplaceWorkingHours(ctx context.Context, siteID uuid.UUID, hours []models.WorkingHours) error
+	GetSpecialDays(ctx context.Context, siteID uuid.UUID, from time.Time) ([]models.SpecialDay, error)
+	UpsertSpecialDay(ctx context.Context, day *models.SpecialDay) error
+	DeleteSpecialDay(ctx context.Context, id uuid.UUID) error
+	GetScheduledSiteIDs(ctx context.Context) ([]uuid.UUID, error)
+
+	// Закрытие с дообслуживанием
+	GetDrainingSiteIDs(ctx context.Context) ([]uuid.UUID, error)
+
+	// Статус типов услуг
+	GetServiceTypeStatuses(ctx context.Context, siteID uuid.UUID) ([]models.ServiceTypeStatus, error)
+	GetServiceTypeStatus(ctx context.Context, siteID uuid.UUID, serviceType string) (*models.ServiceTypeStatus, error)
+	UpsertServiceTypeStatus(ctx context.Context, status *models.ServiceTypeStatus) error
 }
 
 // PostgresRepository реализация Repository для PostgreSQL
@@ -29,16 +49,17 @@ func NewPostgresRepository(db *gorm.DB) *PostgresRepository {
 	}
 }
 
-// GetCurrentStatus получает текущий статус мойки
-func (r *PostgresRepository) GetCurrentStatus(ctx context.Context) (*models.CarwashStatus, error) {
+// GetCurrentStatus получает текущий статус мойки на площадке
+func (r *PostgresRepository) GetCurrentStatus(ctx context.Context, siteID uuid.UUID) (*models.CarwashStatus, error) {
 	var status models.CarwashStatus
 
-	// Получаем первую (и единственную) запись
-	err := r.db.WithContext(ctx).First(&status).Error
+	// Получаем единственную запись площадки
+	err := r.db.WithContext(ctx).Where("site_id = ?", siteID).First(&status).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Если записи нет, создаем начальную запись со статусом "открыта"
 			status = models.CarwashStatus{
+				SiteID:    siteID,
 				IsClosed:  false,
 				UpdatedAt: r.db.NowFunc(),
 				CreatedAt: r.db.NowFunc(),
@@ -54,17 +75,18 @@ func (r *PostgresRepository) GetCurrentStatus(ctx context.Context) (*models.Carw
 	return &status, nil
 }
 
-// UpdateStatus обновляет текущий статус мойки
-func (r *PostgresRepository) UpdateStatus(ctx context.Context, isClosed bool, reason *string, updatedBy *uuid.UUID) error {
+// UpdateStatus обновляет текущий статус мойки на площадке
+func (r *PostgresRepository) UpdateStatus(ctx context.Context, siteID uuid.UUID, isClosed bool, reason *string, updatedBy *uuid.UUID) error {
 	now := r.db.NowFunc()
 
-	// Обновляем статус (так как запись всегда одна, используем FirstOrCreate)
+	// Обновляем статус (так как запись на площадку одна, используем FirstOrCreate)
 	var status models.CarwashStatus
-	err := r.db.WithContext(ctx).First(&status).Error
+	err := r.db.WithContext(ctx).Where("site_id = ?", siteID).First(&status).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Создаем новую запись
 			status = models.CarwashStatus{
+				SiteID:       siteID,
 				IsClosed:     isClosed,
 				ClosedReason: reason,
 				UpdatedAt:    now,
@@ -87,25 +109,37 @@ func (r *PostgresRepository) UpdateStatus(ctx context.Context, isClosed bool, re
 	return r.db.WithContext(ctx).Model(&status).Updates(updates).Error
 }
 
+// UpdateStatusFields обновляет отдельные поля статуса мойки на площадке (настройки и состояние расписания)
+func (r *PostgresRepository) UpdateStatusFields(ctx context.Context, siteID uuid.UUID, updates map[string]interface{}) error {
+	// Гарантируем наличие записи площадки
+	if _, err := r.GetCurrentStatus(ctx, siteID); err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Model(&models.CarwashStatus{}).Where("site_id = ?", siteID).Updates(updates).Error
+}
+
 // CreateHistoryRecord создает запись в истории изменений статуса
-func (r *PostgresRepository) CreateHistoryRecord(ctx context.Context, isClosed bool, reason *string, createdBy *uuid.UUID) error {
+func (r *PostgresRepository) CreateHistoryRecord(ctx context.Context, siteID uuid.UUID, isClosed bool, reason *string, createdBy *uuid.UUID, source string) error {
 	history := models.CarwashStatusHistory{
+		SiteID:       siteID,
 		IsClosed:     isClosed,
 		ClosedReason: reason,
 		CreatedAt:    r.db.NowFunc(),
 		CreatedBy:    createdBy,
+		Source:       source,
 	}
 
 	return r.db.WithContext(ctx).Create(&history).Error
 }
 
-// GetHistory получает историю изменений статуса мойки
-func (r *PostgresRepository) GetHistory(ctx context.Context, limit, offset int) ([]models.CarwashStatusHistory, int, error) {
+// GetHistory получает историю изменений статуса мойки на площадке
+func (r *PostgresRepository) GetHistory(ctx context.Context, siteID uuid.UUID, limit, offset int) ([]models.CarwashStatusHistory, int, error) {
 	var history []models.CarwashStatusHistory
 	var total int64
 
 	// Подсчитываем общее количество записей
-	if err := r.db.WithContext(ctx).Model(&models.CarwashStatusHistory{}).Count(&total).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.CarwashStatusHistory{}).Where("site_id = ?", siteID).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -119,6 +153,7 @@ func (r *PostgresRepository) GetHistory(ctx context.Context, limit, offset int)
 
 	// Получаем записи с пагинацией
 	query := r.db.WithContext(ctx).
+		Where("site_id = ?", siteID).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset)
@@ -129,3 +164,129 @@ func (r *PostgresRepository) GetHistory(ctx context.Context, limit, offset int)
 
 	return history, int(total), nil
 }
+
+// GetSchedule загружает расписание площадки: настройки, недельные часы и особые дни начиная со вчерашнего
+func (r *PostgresRepository) GetSchedule(ctx context.Context, siteID uuid.UUID, now time.Time) (*models.Schedule, error) {
+	status, err := r.GetCurrentStatus(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := r.GetWorkingHours(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Вчерашний особый день может продолжаться после полуночи
+	specialDays, err := r.GetSpecialDays(ctx, siteID, now.In(models.ScheduleLocation).AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewSchedule(status, hours, specialDays), nil
+}
+
+// GetWorkingHours получает недельные рабочие часы площадки
+func (r *PostgresRepository) GetWorkingHours(ctx context.Context, siteID uuid.UUID) ([]models.WorkingHours, error) {
+	var hours []models.WorkingHours
+	err := r.db.WithContext(ctx).Where("site_id = ?", siteID).Order("weekday ASC").Find(&hours).Error
+	return hours, err
+}
+
+// ReplaceWorkingHours заменяет недельные рабочие часы площадки
+func (r *PostgresRepository) ReplaceWorkingHours(ctx context.Context, siteID uuid.UUID, hours []models.WorkingHours) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("site_id = ?", siteID).Delete(&models.WorkingHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		return tx.Create(&hours).Error
+	})
+}
+
+// GetSpecialDays получает особые дни площадки начиная с указанной даты
+func (r *PostgresRepository) GetSpecialDays(ctx context.Context, siteID uuid.UUID, from time.Time) ([]models.SpecialDay, error) {
+	var days []models.SpecialDay
+	err := r.db.WithContext(ctx).
+		Where("site_id = ? AND date >= ?", siteID, from.Format("2006-01-02")).
+		Order("date ASC").
+		Find(&days).Error
+	return days, err
+}
+
+// UpsertSpecialDay создает или обновляет особый день площадки (одна запись на дату)
+func (r *PostgresRepository) UpsertSpecialDay(ctx context.Context, day *models.SpecialDay) error {
+	now := r.db.NowFunc()
+	day.CreatedAt = now
+	day.UpdatedAt = now
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "site_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_closed", "open_time", "close_time", "reason", "updated_at"}),
+	}).Create(day).Error
+}
+
+// DeleteSpecialDay удаляет особый день
+func (r *PostgresRepository) DeleteSpecialDay(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.SpecialDay{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetScheduledSiteIDs получает площадки с включенным расписанием
+func (r *PostgresRepository) GetScheduledSiteIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var siteIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&models.CarwashStatus{}).
+		Where("schedule_enabled = ?", true).
+		Pluck("site_id", &siteIDs).Error
+	return siteIDs, err
+}
+
+// GetDrainingSiteIDs получает площадки, закрывающиеся с дообслуживанием очереди
+func (r *PostgresRepository) GetDrainingSiteIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var siteIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&models.CarwashStatus{}).
+		Where("drain_started_at IS NOT NULL AND is_closed = ?", false).
+		Pluck("site_id", &siteIDs).Error
+	return siteIDs, err
+}
+
+// GetServiceTypeStatuses получает настройки типов услуг площадки
+func (r *PostgresRepository) GetServiceTypeStatuses(ctx context.Context, siteID uuid.UUID) ([]models.ServiceTypeStatus, error) {
+	var statuses []models.ServiceTypeStatus
+	err := r.db.WithContext(ctx).Where("site_id = ?", siteID).Find(&statuses).Error
+	return statuses, err
+}
+
+// GetServiceTypeStatus получает настройки типа услуги площадки
+// Возвращает nil без ошибки, если настроек нет
+func (r *PostgresRepository) GetServiceTypeStatus(ctx context.Context, siteID uuid.UUID, serviceType string) (*models.ServiceTypeStatus, error) {
+	var status models.ServiceTypeStatus
+	err := r.db.WithContext(ctx).Where("site_id = ? AND service_type = ?", siteID, serviceType).First(&status).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &status, nil
+}
+
+// UpsertServiceTypeStatus создает или обновляет настройки типа услуги (одна запись на площадку и тип)
+func (r *PostgresRepository) UpsertServiceTypeStatus(ctx context.Context, status *models.ServiceTypeStatus) error {
+	now := r.db.NowFunc()
+	status.CreatedAt = now
+	status.UpdatedAt = now
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "site_id"}, {Name: "service_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_closed", "closed_reason", "max_queue_size", "updated_by", "updated_at"}),
+	}).Create(status).Error
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"carwash_backend/internal/domain/carwash_status/models"
+	sessionModels "carwash_backend/internal/domain/session/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// drainResetFields поля статуса, сбрасываемые при выходе из режима дообслуживания
+var drainResetFields = map[string]interface{}{
+	"drain_started_at": nil,
+	"drain_deadline":   nil,
+	"drain_reason":     nil,
+	"drain_started_by": nil,
+}
+
+// drainSessionStatuses статусы сессий, которые мойка дообслуживает перед закрытием
+var drainSessionStatuses = []string{
+	sessionModels.SessionStatusInQueue,
+	sessionModels.SessionStatusAssigned,
+	sessionModels.SessionStatusActive,
+}
+
+// StartDrain включает закрытие мойки с дообслуживанием очереди
+// Новые сессии не принимаются, сессии в очереди и в боксах обслуживаются, мойка закрывается,
+// когда сессий не останется, или в крайний срок (оставшиеся сессии закрываются как при обычном закрытии)
+func (s *ServiceImpl) StartDrain(ctx context.Context, req *models.StartDrainRequest, adminID uuid.UUID) (*models.DrainStatusResponse, error) {
+	siteID := siteIDOrDefault(req.SiteID)
+
+	status, err := s.repo.GetCurrentStatus(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статуса мойки: %w", err)
+	}
+	if status.IsClosed {
+		return nil, fmt.Errorf("мойка уже закрыта")
+	}
+
+	now := time.Now()
+	deadline := now.Add(time.Duration(req.DeadlineMinutes) * time.Minute)
+	startedAt := now
+	if status.DrainStartedAt != nil {
+		// Повторный запрос меняет крайний срок и причину, не сбрасывая начало
+		startedAt = *status.DrainStartedAt
+	}
+
+	err = s.repo.UpdateStatusFields(ctx, siteID, map[string]interface{}{
+		"drain_started_at": startedAt,
+		"drain_deadline":   deadline,
+		"drain_reason":     req.Reason,
+		"drain_started_by": adminID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка включения закрытия с дообслуживанием: %w", err)
+	}
+
+	logger.Printf("StartDrain: закрытие с дообслуживанием, site_id: %s, admin_id: %s, deadline: %s", siteID, adminID, deadline.Format(time.RFC3339))
+
+	return s.GetDrainStatus(ctx, &models.GetDrainStatusRequest{SiteID: siteID})
+}
+
+// CancelDrain отменяет закрытие с дообслуживанием, мойка снова принимает сессии
+func (s *ServiceImpl) CancelDrain(ctx context.Context, req *models.CancelDrainRequest) (*models.DrainStatusResponse, error) {
+	siteID := siteIDOrDefault(req.SiteID)
+
+	status, err := s.repo.GetCurrentStatus(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статуса мойки: %w", err)
+	}
+	if status.DrainStartedAt == nil || status.IsClosed {
+		return nil, fmt.Errorf("мойка не находится в режиме закрытия")
+	}
+
+	if err := s.repo.UpdateStatusFields(ctx, siteID, drainResetFields); err != nil {
+		return nil, fmt.Errorf("ошибка отмены закрытия с дообслуживанием: %w", err)
+	}
+
+	logger.Printf("CancelDrain: закрытие с дообслуживанием отменено, site_id: %s", siteID)
+
+	return s.GetDrainStatus(ctx, &models.GetDrainStatusRequest{SiteID: siteID})
+}
+
+// GetDrainStatus получает ход закрытия: сколько сессий осталось обслужить
+func (s *ServiceImpl) GetDrainStatus(ctx context.Context, req *models.GetDrainStatusRequest) (*models.DrainStatusResponse, error) {
+	siteID := siteIDOrDefault(&req.SiteID)
+
+	status, err := s.repo.GetCurrentStatus(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статуса мойки: %w", err)
+	}
+
+	resp := &models.DrainStatusResponse{
+		SiteID:   siteID,
+		IsClosed: status.IsClosed,
+		Sessions: []models.DrainSession{},
+	}
+	if status.DrainStartedAt == nil || status.IsClosed {
+		return resp, nil
+	}
+
+	resp.IsDraining = true
+	resp.StartedAt = status.DrainStartedAt
+	resp.Deadline = status.DrainDeadline
+	resp.Reason = status.DrainReason
+
+	sessions, err := s.drainSessions(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		switch session.Status {
+		case sessionModels.SessionStatusInQueue:
+			resp.QueuedSessions++
+		case sessionModels.SessionStatusAssigned:
+			resp.AssignedSessions++
+		case sessionModels.SessionStatusActive:
+			resp.ActiveSessions++
+		}
+
+		item := models.DrainSession{
+			ID:          session.ID,
+			Status:      session.Status,
+			ServiceType: session.ServiceType,
+			CarNumber:   session.CarNumber,
+			BoxID:       session.BoxID,
+		}
+		if session.Status == sessionModels.SessionStatusActive && session.StartedAt != nil {
+			endAt := session.StartedAt.Add(time.Duration(session.RentalTimeMinutes+session.ExtensionTimeMinutes) * time.Minute)
+			item.ExpectedEndAt = &endAt
+		}
+		resp.Sessions = append(resp.Sessions, item)
+	}
+
+	return resp, nil
+}
+
+// CompleteDrains закрывает площадки в режиме дообслуживания, на которых не осталось сессий
+// или наступил крайний срок. Вызывается планировщиком
+func (s *ServiceImpl) CompleteDrains(ctx context.Context) error {
+	siteIDs, err := s.repo.GetDrainingSiteIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка получения закрывающихся площадок: %w", err)
+	}
+
+	now := time.Now()
+	for _, siteID := range siteIDs {
+		if err := s.completeDrain(ctx, siteID, now); err != nil {
+			logger.Printf("CompleteDrains: ошибка закрытия площадки, site_id: %s, error: %v", siteID, err)
+		}
+	}
+
+	return nil
+}
+
+// completeDrain закрывает площадку, если очередь обслужена или наступил крайний срок
+func (s *ServiceImpl) completeDrain(ctx context.Context, siteID uuid.UUID, now time.Time) error {
+	status, err := s.repo.GetCurrentStatus(ctx, siteID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения статуса мойки: %w", err)
+	}
+	if status.DrainStartedAt == nil || status.IsClosed {
+		return nil
+	}
+
+	deadlineReached := status.DrainDeadline != nil && !now.Before(*status.DrainDeadline)
+	if !deadlineReached {
+		sessions, err := s.drainSessions(ctx, siteID)
+		if err != nil {
+			return err
+		}
+		if len(sessions) > 0 {
+			return nil
+		}
+	}
+
+	logger.Printf("CompleteDrains: закрытие мойки после дообслуживания, site_id: %s, deadline_reached: %t", siteID, deadlineReached)
+	_, err = s.closeSite(ctx, siteID, status.DrainReason, status.DrainStartedBy, models.HistorySourceDrain)
+	return err
+}
+
+// drainSessions получает сессии площадки, которые мойка дообслуживает перед закрытием
+func (s *ServiceImpl) drainSessions(ctx context.Context, siteID uuid.UUID) ([]sessionModels.Session, error) {
+	var result []sessionModels.Session
+	for _, status := range drainSessionStatuses {
+		sessions, err := s.sessionService.GetSessionsByStatus(ctx, status)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения сессий со статусом %s: %w", status, err)
+		}
+		for _, session := range sessions {
+			if session.SiteID == siteID {
+				result = append(result, session)
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"carwash_backend/internal/domain/carwash_status/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetSchedule получает расписание работы площадки
+func (s *ServiceImpl) GetSchedule(ctx context.Context, req *models.GetScheduleRequest) (*models.GetScheduleResponse, error) {
+	return s.buildScheduleResponse(ctx, siteIDOrDefault(&req.SiteID))
+}
+
+// UpdateSchedule изменяет настройки расписания и заменяет недельные рабочие часы
+func (s *ServiceImpl) UpdateSchedule(ctx context.Context, req *models.UpdateScheduleRequest) (*models.GetScheduleResponse, error) {
+	siteID := siteIDOrDefault(req.SiteID)
+
+	hours := make([]models.WorkingHours, 0, len(req.WorkingHours))
+	seen := make(map[int]bool, len(req.WorkingHours))
+	for _, input := range req.WorkingHours {
+		if seen[input.Weekday] {
+			return nil, fmt.Errorf("день недели %d указан несколько раз", input.Weekday)
+		}
+		seen[input.Weekday] = true
+
+		if !input.IsDayOff {
+			if _, err := models.ParseClockTime(input.OpenTime); err != nil {
+				return nil, err
+			}
+			if _, err := models.ParseClockTime(input.CloseTime); err != nil {
+				return nil, err
+			}
+		}
+
+		hours = append(hours, models.WorkingHours{
+			SiteID:    siteID,
+			Weekday:   input.Weekday,
+			OpenTime:  input.OpenTime,
+			CloseTime: input.CloseTime,
+			IsDayOff:  input.IsDayOff,
+		})
+	}
+
+	if req.Enabled && len(hours) == 0 {
+		return nil, fmt.Errorf("для включения расписания укажите рабочие часы")
+	}
+
+	if err := s.repo.ReplaceWorkingHours(ctx, siteID, hours); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения рабочих часов: %w", err)
+	}
+
+	updates := map[string]interface{}{
+		"schedule_enabled":                   req.Enabled,
+		"stop_sessions_before_close_minutes": req.StopSessionsBeforeCloseMinutes,
+		"closing_notice_for":                 nil,
+	}
+	if !req.Enabled {
+		// Без расписания мойка открывается и закрывается только вручную
+		updates["closed_by_schedule"] = false
+		updates["schedule_override_until"] = nil
+	}
+	if err := s.repo.UpdateStatusFields(ctx, siteID, updates); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения настроек расписания: %w", err)
+	}
+
+	logger.Printf("UpdateSchedule: расписание обновлено, site_id: %s, enabled: %t, дней: %d", siteID, req.Enabled, len(hours))
+
+	return s.buildScheduleResponse(ctx, siteID)
+}
+
+// UpsertSpecialDay добавляет или изменяет особый день (праздник или сокращенный день)
+func (s *ServiceImpl) UpsertSpecialDay(ctx context.Context, req *models.UpsertSpecialDayRequest) (*models.SpecialDay, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная дата %q, ожидается ГГГГ-ММ-ДД", req.Date)
+	}
+
+	day := &models.SpecialDay{
+		SiteID:   siteIDOrDefault(req.SiteID),
+		Date:     date,
+		IsClosed: req.IsClosed,
+		Reason:   req.Reason,
+	}
+
+	if !req.IsClosed {
+		if req.OpenTime == nil || req.CloseTime == nil {
+			return nil, fmt.Errorf("для рабочего особого дня укажите время открытия и закрытия")
+		}
+		if _, err := models.ParseClockTime(*req.OpenTime); err != nil {
+			return nil, err
+		}
+		if _, err := models.ParseClockTime(*req.CloseTime); err != nil {
+			return nil, err
+		}
+		day.OpenTime = req.OpenTime
+		day.CloseTime = req.CloseTime
+	}
+
+	if err := s.repo.UpsertSpecialDay(ctx, day); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения особого дня: %w", err)
+	}
+
+	return day, nil
+}
+
+// DeleteSpecialDay удаляет особый день
+func (s *ServiceImpl) DeleteSpecialDay(ctx context.Context, req *models.DeleteSpecialDayRequest) error {
+	if err := s.repo.DeleteSpecialDay(ctx, req.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("особый день не найден")
+		}
+		return fmt.Errorf("ошибка удаления особого дня: %w", err)
+	}
+	return nil
+}
+
+// buildScheduleResponse собирает ответ с расписанием площадки
+func (s *ServiceImpl) buildScheduleResponse(ctx context.Context, siteID uuid.UUID) (*models.GetScheduleResponse, error) {
+	now := time.Now()
+
+	status, err := s.repo.GetCurrentStatus(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статуса мойки: %w", err)
+	}
+
+	hours, err := s.repo.GetWorkingHours(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения рабочих часов: %w", err)
+	}
+
+	specialDays, err := s.repo.GetSpecialDays(ctx, siteID, now.In(models.ScheduleLocation))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения особых дней: %w", err)
+	}
+
+	resp := &models.GetScheduleResponse{
+		SiteID:                         siteID,
+		Enabled:                        status.ScheduleEnabled,
+		StopSessionsBeforeCloseMinutes: status.StopSessionsBeforeCloseMinutes,
+		WorkingHours:                   hours,
+		SpecialDays:                    specialDays,
+	}
+
+	if status.ScheduleEnabled {
+		schedule, err := s.repo.GetSchedule(ctx, siteID, now)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения расписания: %w", err)
+		}
+		resp.NextOpenAt = schedule.NextOpenAt(now)
+	}
+
+	return resp, nil
+}
+
+// RunSchedule открывает и закрывает мойки по расписанию и предупреждает очередь о скором закрытии
+// Вызывается планировщиком раз в минуту. Ошибка одной площадки не останавливает обработку остальных
+func (s *ServiceImpl) RunSchedule(ctx context.Context) error {
+	siteIDs, err := s.repo.GetScheduledSiteIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка получения площадок с расписанием: %w", err)
+	}
+
+	now := time.Now()
+	for _, siteID := range siteIDs {
+		if err := s.applySchedule(ctx, siteID, now); err != nil {
+			logger.Printf("RunSchedule: ошибка применения расписания, site_id: %s, error: %v", siteID, err)
+		}
+	}
+
+	return nil
+}
+
+// applySchedule приводит статус площадки в соответствие с расписанием
+// Закрытая вручную мойка по расписанию не открывается; открытая вручную в нерабочее время
+// не закрывается до окончания ScheduleOverrideUntil
+func (s *ServiceImpl) applySchedule(ctx context.Context, siteID uuid.UUID, now time.Time) error {
+	status, err := s.repo.GetCurrentStatus(ctx, siteID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения статуса мойки: %w", err)
+	}
+
+	if status.ScheduleOverrideUntil != nil && now.Before(*status.ScheduleOverrideUntil) {
+		return nil
+	}
+
+	schedule, err := s.repo.GetSchedule(ctx, siteID, now)
+	if err != nil {
+		return fmt.Errorf("ошибка получения расписания: %w", err)
+	}
+
+	_, closeAt, open := schedule.CurrentWindow(now)
+
+	switch {
+	case status.IsClosed && open && status.ClosedBySchedule:
+		logger.Printf("RunSchedule: открытие мойки по расписанию, site_id: %s", siteID)
+		return s.openSite(ctx, siteID, nil, models.HistorySourceSystem, nil)
+
+	case !status.IsClosed && !open:
+		reason := schedule.ClosedReason(now)
+		logger.Printf("RunSchedule: закрытие мойки по расписанию, site_id: %s, reason: %s", siteID, reason)
+		_, err := s.closeSite(ctx, siteID, &reason, nil, models.HistorySourceSystem)
+		return err
+
+	case !status.IsClosed && open && schedule.StopSessionsBeforeCloseMinutes > 0:
+		stopAt, _ := schedule.StopSessionsAt(now)
+		if now.Before(stopAt) || (status.ClosingNoticeFor != nil && status.ClosingNoticeFor.Equal(closeAt)) {
+			return nil
+		}
+
+		// Отмечаем уведомление до отправки, чтобы не разослать его повторно при ошибке записи
+		if err := s.repo.UpdateStatusFields(ctx, siteID, map[string]interface{}{"closing_notice_for": closeAt}); err != nil {
+			return fmt.Errorf("ошибка сохранения отметки об уведомлении: %w", err)
+		}
+		if _, err := s.sessionService.NotifyQueueBeforeClosing(ctx, siteID, closeAt); err != nil {
+			return fmt.Errorf("ошибка уведомления очереди о закрытии: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -5,9 +5,11 @@ import (
 	"carwash_backend/internal/domain/carwash_status/repository"
 	sessionModels "carwash_backend/internal/domain/session/models"
 	sessionService "carwash_backend/internal/domain/session/service"
+	siteModels "carwash_backend/internal/domain/site/models"
 	"carwash_backend/internal/logger"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -18,6 +20,23 @@ type Service interface {
 	CloseCarwash(ctx context.Context, req *models.CloseCarwashRequest, adminID uuid.UUID) (*models.CloseCarwashResponse, error)
 	OpenCarwash(ctx context.Context, req *models.OpenCarwashRequest, adminID uuid.UUID) (*models.OpenCarwashResponse, error)
 	GetHistory(ctx context.Context, req *models.GetHistoryRequest) (*models.GetHistoryResponse, error)
+
+	// Расписание работы
+	GetSchedule(ctx context.Context, req *models.GetScheduleRequest) (*models.GetScheduleResponse, error)
+	UpdateSchedule(ctx context.Context, req *models.UpdateScheduleRequest) (*models.GetScheduleResponse, error)
+	UpsertSpecialDay(ctx context.Context, req *models.UpsertSpecialDayRequest) (*models.SpecialDay, error)
+	DeleteSpecialDay(ctx context.Context, req *models.DeleteSpecialDayRequest) error
+	RunSchedule(ctx context.Context) error
+
+	// Закрытие с дообслуживанием очереди
+	StartDrain(ctx context.Context, req *models.StartDrainRequest, adminID uuid.UUID) (*models.DrainStatusResponse, error)
+	CancelDrain(ctx context.Context, req *models.CancelDrainRequest) (*models.DrainStatusResponse, error)
+	GetDrainStatus(ctx context.Context, req *models.GetDrainStatusRequest) (*models.DrainStatusResponse, error)
+	CompleteDrains(ctx context.Context) error
+
+	// Статус типов услуг
+	GetServiceTypeStatuses(ctx context.Context, req *models.GetServiceTypeStatusesRequest) (*models.GetServiceTypeStatusesResponse, error)
+	UpdateServiceTypeStatus(ctx context.Context, req *models.UpdateServiceTypeStatusRequest, adminID uuid.UUID) (*models.UpdateServiceTypeStatusResponse, error)
 }
 
 // ServiceImpl реализация Service
@@ -34,24 +53,75 @@ func NewService(repo repository.Repository, sessionService sessionService.Servic
 	}
 }
 
-// GetCurrentStatus получает текущий статус мойки
+// siteIDOrDefault возвращает площадку запроса или площадку по умолчанию
+func siteIDOrDefault(siteID *uuid.UUID) uuid.UUID {
+	if siteID == nil || *siteID == uuid.Nil {
+		return siteModels.DefaultSiteID
+	}
+	return *siteID
+}
+
+// GetCurrentStatus получает текущий статус мойки на площадке
+// При включенном расписании дополнительно возвращает ближайшие закрытие и открытие
 func (s *ServiceImpl) GetCurrentStatus(ctx context.Context, req *models.GetCurrentStatusRequest) (*models.GetCurrentStatusResponse, error) {
-	status, err := s.repo.GetCurrentStatus(ctx)
+	siteID := siteIDOrDefault(&req.SiteID)
+
+	status, err := s.repo.GetCurrentStatus(ctx, siteID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения статуса мойки: %w", err)
 	}
 
-	return &models.GetCurrentStatusResponse{
-		IsClosed:     status.IsClosed,
-		ClosedReason: status.ClosedReason,
-		UpdatedAt:    status.UpdatedAt,
-	}, nil
+	resp := &models.GetCurrentStatusResponse{
+		SiteID:          siteID,
+		IsClosed:        status.IsClosed,
+		ClosedReason:    status.ClosedReason,
+		UpdatedAt:       status.UpdatedAt,
+		ScheduleEnabled: status.ScheduleEnabled,
+	}
+
+	now := time.Now()
+	var schedule *models.Schedule
+	if status.ScheduleEnabled {
+		schedule, err = s.repo.GetSchedule(ctx, siteID, now)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения расписания: %w", err)
+		}
+
+		if _, closeAt, ok := schedule.CurrentWindow(now); ok && !status.IsClosed {
+			stopAt, _ := schedule.StopSessionsAt(now)
+			resp.ClosesAt = &closeAt
+			resp.StopSessionsAt = &stopAt
+		} else {
+			resp.NextOpenAt = schedule.NextOpenAt(now)
+		}
+	}
+
+	resp.AcceptingSessions = models.CheckAcceptingSessions(status, schedule, now) == nil
+	resp.IsDraining = status.DrainStartedAt != nil && !status.IsClosed
+	if resp.IsDraining {
+		resp.DrainDeadline = status.DrainDeadline
+	}
+
+	resp.ServiceTypes, err = s.serviceTypeInfos(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
 }
 
-// CloseCarwash закрывает мойку
+// CloseCarwash закрывает мойку на площадке вручную
+// Сессии других площадок не затрагиваются. Закрытая вручную мойка не открывается по расписанию
 func (s *ServiceImpl) CloseCarwash(ctx context.Context, req *models.CloseCarwashRequest, adminID uuid.UUID) (*models.CloseCarwashResponse, error) {
-	logger.Printf("CloseCarwash: начало закрытия мойки, admin_id: %s, reason: %v", adminID, req.Reason)
+	siteID := siteIDOrDefault(req.SiteID)
+	logger.Printf("CloseCarwash: начало закрытия мойки, site_id: %s, admin_id: %s, reason: %v", siteID, adminID, req.Reason)
+
+	return s.closeSite(ctx, siteID, req.Reason, &adminID, models.HistorySourceAdmin)
+}
 
+// closeSite завершает и отменяет сессии площадки и закрывает мойку
+// source определяет, кто закрыл мойку: администратор или расписание
+func (s *ServiceImpl) closeSite(ctx context.Context, siteID uuid.UUID, reason *string, adminID *uuid.UUID, source string) (*models.CloseCarwashResponse, error) {
 	completedSessions := 0
 	canceledSessions := 0
 
@@ -64,6 +134,10 @@ func (s *ServiceImpl) CloseCarwash(ctx context.Context, req *models.CloseCarwash
 		logger.Printf("CloseCarwash: найдено %d активных сессий", len(activeSessions))
 
 		for _, session := range activeSessions {
+			if session.SiteID != siteID {
+				continue
+			}
+
 			// Завершаем сессию без возврата (время уже использовано)
 			err := s.sessionService.CompleteSessionWithoutRefund(ctx, session.ID)
 			if err != nil {
@@ -93,6 +167,10 @@ func (s *ServiceImpl) CloseCarwash(ctx context.Context, req *models.CloseCarwash
 		logger.Printf("CloseCarwash: найдено %d сессий со статусом %s", len(sessions), status)
 
 		for _, session := range sessions {
+			if session.SiteID != siteID {
+				continue
+			}
+
 			// Для created сессий возврат не нужен (они не оплачены)
 			// Для in_queue и assigned - возвращаем деньги
 			skipRefund := (status == sessionModels.SessionStatusCreated)
@@ -115,19 +193,32 @@ func (s *ServiceImpl) CloseCarwash(ctx context.Context, req *models.CloseCarwash
 	}
 
 	// 3. Обновляем статус мойки в БД
-	err = s.repo.UpdateStatus(ctx, true, req.Reason, &adminID)
+	err = s.repo.UpdateStatus(ctx, siteID, true, reason, adminID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка обновления статуса мойки: %w", err)
 	}
 
+	// Ручное закрытие отменяет действие расписания до ручного открытия
+	updates := map[string]interface{}{
+		"closed_by_schedule":      source == models.HistorySourceSystem,
+		"schedule_override_until": nil,
+	}
+	for column, value := range drainResetFields {
+		updates[column] = value
+	}
+	err = s.repo.UpdateStatusFields(ctx, siteID, updates)
+	if err != nil {
+		logger.Printf("CloseCarwash: ошибка обновления состояния расписания: %v", err)
+	}
+
 	// 4. Создаем запись в истории
-	err = s.repo.CreateHistoryRecord(ctx, true, req.Reason, &adminID)
+	err = s.repo.CreateHistoryRecord(ctx, siteID, true, reason, adminID, source)
 	if err != nil {
 		logger.Printf("CloseCarwash: ошибка создания записи в истории: %v", err)
 		// Не возвращаем ошибку, так как статус уже обновлен
 	}
 
-	logger.Printf("CloseCarwash: мойка закрыта, завершено сессий: %d, отменено сессий: %d", completedSessions, canceledSessions)
+	logger.Printf("CloseCarwash: мойка закрыта, site_id: %s, source: %s, завершено сессий: %d, отменено сессий: %d", siteID, source, completedSessions, canceledSessions)
 
 	return &models.CloseCarwashResponse{
 		Success:           true,
@@ -137,29 +228,74 @@ func (s *ServiceImpl) CloseCarwash(ctx context.Context, req *models.CloseCarwash
 	}, nil
 }
 
-// OpenCarwash открывает мойку
+// OpenCarwash открывает мойку вручную
+// Открытие в нерабочее время по расписанию действует до ближайшего открытия по расписанию,
+// после чего мойка закрывается в обычное время
 func (s *ServiceImpl) OpenCarwash(ctx context.Context, req *models.OpenCarwashRequest, adminID uuid.UUID) (*models.OpenCarwashResponse, error) {
-	logger.Printf("OpenCarwash: начало открытия мойки, admin_id: %s", adminID)
+	siteID := siteIDOrDefault(req.SiteID)
+	logger.Printf("OpenCarwash: начало открытия мойки, site_id: %s, admin_id: %s", siteID, adminID)
+
+	var overrideUntil *time.Time
+	status, err := s.repo.GetCurrentStatus(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статуса мойки: %w", err)
+	}
+	if status.ScheduleEnabled {
+		now := time.Now()
+		schedule, err := s.repo.GetSchedule(ctx, siteID, now)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения расписания: %w", err)
+		}
+		if !schedule.AcceptsSessionsAt(now) {
+			overrideUntil = schedule.NextOpenAt(now)
+			if overrideUntil == nil {
+				// Открытий по расписанию в ближайший месяц нет - ручное открытие действует сутки
+				until := now.Add(24 * time.Hour)
+				overrideUntil = &until
+			}
+		}
+	}
+
+	if err := s.openSite(ctx, siteID, &adminID, models.HistorySourceAdmin, overrideUntil); err != nil {
+		return nil, err
+	}
+
+	return &models.OpenCarwashResponse{
+		Success: true,
+		Message: "Мойка успешно открыта",
+	}, nil
+}
 
+// openSite открывает мойку на площадке
+// overrideUntil - до какого момента расписание не закрывает мойку (nil - расписание действует сразу)
+func (s *ServiceImpl) openSite(ctx context.Context, siteID uuid.UUID, adminID *uuid.UUID, source string, overrideUntil *time.Time) error {
 	// 1. Обновляем статус мойки в БД
-	err := s.repo.UpdateStatus(ctx, false, nil, &adminID)
+	err := s.repo.UpdateStatus(ctx, siteID, false, nil, adminID)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка обновления статуса мойки: %w", err)
+		return fmt.Errorf("ошибка обновления статуса мойки: %w", err)
+	}
+
+	updates := map[string]interface{}{
+		"closed_by_schedule":      false,
+		"schedule_override_until": overrideUntil,
+	}
+	for column, value := range drainResetFields {
+		updates[column] = value
+	}
+	err = s.repo.UpdateStatusFields(ctx, siteID, updates)
+	if err != nil {
+		logger.Printf("OpenCarwash: ошибка обновления состояния расписания: %v", err)
 	}
 
 	// 2. Создаем запись в истории
-	err = s.repo.CreateHistoryRecord(ctx, false, nil, &adminID)
+	err = s.repo.CreateHistoryRecord(ctx, siteID, false, nil, adminID, source)
 	if err != nil {
 		logger.Printf("OpenCarwash: ошибка создания записи в истории: %v", err)
 		// Не возвращаем ошибку, так как статус уже обновлен
 	}
 
-	logger.Printf("OpenCarwash: мойка открыта")
-
-	return &models.OpenCarwashResponse{
-		Success: true,
-		Message: "Мойка успешно открыта",
-	}, nil
+	logger.Printf("OpenCarwash: мойка открыта, site_id: %s, source: %s", siteID, source)
+	return nil
 }
 
 // GetHistory получает историю изменений статуса мойки
@@ -174,7 +310,7 @@ func (s *ServiceImpl) GetHistory(ctx context.Context, req *models.GetHistoryRequ
 		offset = 0
 	}
 
-	history, total, err := s.repo.GetHistory(ctx, limit, offset)
+	history, total, err := s.repo.GetHistory(ctx, siteIDOrDefault(&req.SiteID), limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения истории статуса мойки: %w", err)
 	}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"carwash_backend/internal/domain/carwash_status/models"
+	sessionModels "carwash_backend/internal/domain/session/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// serviceTypes типы услуг в порядке отображения
+var serviceTypes = []string{
+	washboxModels.ServiceTypeWash,
+	washboxModels.ServiceTypeAirDry,
+	washboxModels.ServiceTypeVacuum,
+}
+
+// GetServiceTypeStatuses получает статусы и очереди типов услуг площадки
+func (s *ServiceImpl) GetServiceTypeStatuses(ctx context.Context, req *models.GetServiceTypeStatusesRequest) (*models.GetServiceTypeStatusesResponse, error) {
+	siteID := siteIDOrDefault(&req.SiteID)
+
+	infos, err := s.serviceTypeInfos(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GetServiceTypeStatusesResponse{
+		SiteID:       siteID,
+		ServiceTypes: infos,
+	}, nil
+}
+
+// UpdateServiceTypeStatus открывает или закрывает тип услуги и меняет лимит его очереди
+// При закрытии с CancelQueued сессии в очереди и с назначенным боксом отменяются с возвратом,
+// активные сессии дорабатывают свое время
+func (s *ServiceImpl) UpdateServiceTypeStatus(ctx context.Context, req *models.UpdateServiceTypeStatusRequest, adminID uuid.UUID) (*models.UpdateServiceTypeStatusResponse, error) {
+	siteID := siteIDOrDefault(req.SiteID)
+
+	status := &models.ServiceTypeStatus{
+		SiteID:       siteID,
+		ServiceType:  req.ServiceType,
+		IsClosed:     req.IsClosed,
+		MaxQueueSize: req.MaxQueueSize,
+		UpdatedBy:    &adminID,
+	}
+	if req.IsClosed {
+		status.ClosedReason = req.ClosedReason
+	}
+
+	if err := s.repo.UpsertServiceTypeStatus(ctx, status); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения статуса услуги: %w", err)
+	}
+
+	logger.Printf("UpdateServiceTypeStatus: site_id: %s, service_type: %s, is_closed: %t, max_queue_size: %d, admin_id: %s",
+		siteID, req.ServiceType, req.IsClosed, req.MaxQueueSize, adminID)
+
+	canceledSessions := 0
+	if req.IsClosed && req.CancelQueued {
+		canceledSessions = s.cancelServiceTypeQueue(ctx, siteID, req.ServiceType)
+	}
+
+	queueSize, err := s.sessionService.CountQueuedSessions(ctx, siteID, req.ServiceType)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подсчета очереди: %w", err)
+	}
+
+	return &models.UpdateServiceTypeStatusResponse{
+		ServiceType:      models.NewServiceTypeStatusInfo(req.ServiceType, status, queueSize),
+		CanceledSessions: canceledSessions,
+	}, nil
+}
+
+// cancelServiceTypeQueue отменяет с возвратом сессии площадки в очереди и с назначенным боксом на тип услуги
+func (s *ServiceImpl) cancelServiceTypeQueue(ctx context.Context, siteID uuid.UUID, serviceType string) int {
+	canceled := 0
+	for _, status := range []string{sessionModels.SessionStatusInQueue, sessionModels.SessionStatusAssigned} {
+		sessions, err := s.sessionService.GetSessionsByStatus(ctx, status)
+		if err != nil {
+			logger.Printf("UpdateServiceTypeStatus: ошибка получения сессий со статусом %s: %v", status, err)
+			continue
+		}
+
+		for _, session := range sessions {
+			if session.SiteID != siteID || session.ServiceType != serviceType {
+				continue
+			}
+
+			_, err := s.sessionService.CancelSession(ctx, &sessionModels.CancelSessionRequest{
+				SessionID: session.ID,
+				UserID:    session.UserID,
+			})
+			if err != nil {
+				logger.Printf("UpdateServiceTypeStatus: ошибка отмены сессии %s: %v", session.ID, err)
+				continue
+			}
+			canceled++
+		}
+	}
+	return canceled
+}
+
+// serviceTypeInfos собирает статусы всех типов услуг площадки с размерами очередей
+func (s *ServiceImpl) serviceTypeInfos(ctx context.Context, siteID uuid.UUID) ([]models.ServiceTypeStatusInfo, error) {
+	statuses, err := s.repo.GetServiceTypeStatuses(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статусов услуг: %w", err)
+	}
+
+	byType := make(map[string]*models.ServiceTypeStatus, len(statuses))
+	for i := range statuses {
+		byType[statuses[i].ServiceType] = &statuses[i]
+	}
+
+	infos := make([]models.ServiceTypeStatusInfo, 0, len(serviceTypes))
+	for _, serviceType := range serviceTypes {
+		queueSize, err := s.sessionService.CountQueuedSessions(ctx, siteID, serviceType)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка подсчета очереди: %w", err)
+		}
+		infos = append(infos, models.NewServiceTypeStatusInfo(serviceType, byType[serviceType], queueSize))
+	}
+	return infos, nil
+}
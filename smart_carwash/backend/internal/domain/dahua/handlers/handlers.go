@@ -12,6 +12,7 @@ import (
 	"carwash_backend/internal/domain/dahua/models"
 	"carwash_backend/internal/domain/dahua/service"
 	"carwash_backend/internal/logger"
+	authMiddleware "carwash_backend/internal/middleware"
 	"context"
 )
 
@@ -148,6 +149,9 @@ func (h *Handler) ANPRWebhook(c *gin.Context) {
 	if v, ok := c.Get("dahua_username"); ok {
 		ctx = context.WithValue(ctx, "dahua_username", v)
 	}
+	// Площадка камеры определена middleware по учетным данным
+	processReq.SiteID = authMiddleware.SiteIDFromContext(c)
+
 	// Обрабатываем событие
 	response, err := h.dahuaService.ProcessANPREvent(ctx, processReq)
 	if err != nil {
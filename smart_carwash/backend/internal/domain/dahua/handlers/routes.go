@@ -4,12 +4,13 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"carwash_backend/internal/domain/dahua/middleware"
+	siteService "carwash_backend/internal/domain/site/service"
 )
 
 // SetupRoutes настраивает маршруты для Dahua интеграции
-func SetupRoutes(router *gin.RouterGroup, handler *Handler) {
-	// ANPR Webhook (только IP whitelist, без Basic Auth)
-	router.POST("/dahua/anpr-webhook", middleware.DahuaIPWhitelistMiddleware(), handler.ANPRWebhook)
+func SetupRoutes(router *gin.RouterGroup, handler *Handler, sites siteService.Service) {
+	// ANPR Webhook (IP whitelist, Basic Auth необязателен и определяет площадку камеры)
+	router.POST("/dahua/anpr-webhook", middleware.DahuaIPWhitelistMiddleware(), middleware.DahuaSiteMiddleware(sites), handler.ANPRWebhook)
 
 	// Health check (без аутентификации для мониторинга)
 	router.GET("/dahua/health", handler.HealthCheck)
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"errors"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	siteModels "carwash_backend/internal/domain/site/models"
+	siteService "carwash_backend/internal/domain/site/service"
+	"carwash_backend/internal/logger"
+	authMiddleware "carwash_backend/internal/middleware"
+)
+
+// DahuaSiteMiddleware определяет площадку камеры по Basic Auth
+// С общими DAHUA_WEBHOOK_USERNAME/PASSWORD - площадка по умолчанию,
+// с учетными данными площадки - эта площадка, без учетных данных или с неверными - 401
+func DahuaSiteMiddleware(sites siteService.Service) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		username, password, hasAuth := c.Request.BasicAuth()
+		if !hasAuth {
+			// Без учетных данных площадку определить нельзя, а событие чужой камеры не должно попасть на площадку по умолчанию
+			logger.WithFields(logrus.Fields{
+				"middleware": "dahua_site",
+				"client_ip":  c.ClientIP(),
+			}).Warn("Запрос камеры без учетных данных")
+			abortDahua(c, 401, "Требуется аутентификация")
+			return
+		}
+
+		expectedUsername := os.Getenv("DAHUA_WEBHOOK_USERNAME")
+		expectedPassword := os.Getenv("DAHUA_WEBHOOK_PASSWORD")
+		if expectedUsername != "" && username == expectedUsername && password == expectedPassword {
+			c.Set(authMiddleware.SiteIDContextKey, siteModels.DefaultSiteID)
+			c.Set("dahua_authenticated", true)
+			c.Set("dahua_username", username)
+			c.Next()
+			return
+		}
+
+		if sites != nil {
+			site, err := sites.AuthenticateDahua(c.Request.Context(), username, password)
+			if err == nil {
+				c.Set(authMiddleware.SiteIDContextKey, site.ID)
+				c.Set("dahua_authenticated", true)
+				c.Set("dahua_username", username)
+				c.Next()
+				return
+			}
+			if !errors.Is(err, siteService.ErrInvalidCredentials) {
+				logger.WithFields(logrus.Fields{
+					"middleware": "dahua_site",
+					"username":   username,
+					"error":      err,
+				}).Error("Ошибка определения площадки камеры")
+				abortDahua(c, 500, "Ошибка определения площадки")
+				return
+			}
+		}
+
+		logger.WithFields(logrus.Fields{
+			"middleware": "dahua_site",
+			"username":   username,
+		}).Warn("Неверные учетные данные камеры")
+		abortDahua(c, 401, "Неверные учетные данные")
+	})
+}
+
+// abortDahua прерывает запрос с ответом в формате камеры (XML или JSON)
+func abortDahua(c *gin.Context, status int, message string) {
+	contentType := c.GetHeader("Content-Type")
+	if contentType == "application/xml" || contentType == "text/xml" {
+		c.Header("Content-Type", "application/xml")
+		c.String(status, `<?xml version="1.0" encoding="UTF-8"?>
+<Response>
+    <result>ERROR</result>
+    <message>`+message+`</message>
+</Response>`)
+	} else {
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": message,
+		})
+	}
+	c.Abort()
+}
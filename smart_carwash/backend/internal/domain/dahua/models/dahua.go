@@ -3,6 +3,8 @@ package models
 import (
 	"encoding/xml"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // DahuaWebhookRequest представляет входящий webhook от камеры Dahua в формате ITSAPI XML
@@ -68,12 +70,13 @@ type DahuaWebhookResponseJSON struct {
 
 // ProcessANPREventRequest представляет запрос на обработку ANPR события
 type ProcessANPREventRequest struct {
-	LicensePlate string `json:"license_plate" binding:"required"`
-	Direction    string `json:"direction" binding:"required"`
-	Confidence   int    `json:"confidence"`
-	EventType    string `json:"event_type"`
-	CaptureTime  string `json:"capture_time"`
-	ImagePath    string `json:"image_path"`
+	LicensePlate string    `json:"license_plate" binding:"required"`
+	Direction    string    `json:"direction" binding:"required"`
+	Confidence   int       `json:"confidence"`
+	EventType    string    `json:"event_type"`
+	CaptureTime  string    `json:"capture_time"`
+	ImagePath    string    `json:"image_path"`
+	SiteID       uuid.UUID `json:"-"` // Площадка камеры (по учетным данным)
 }
 
 // ProcessANPREventResponse представляет ответ на обработку ANPR события
@@ -84,6 +87,9 @@ type ProcessANPREventResponse struct {
 	SessionFound  bool   `json:"session_found"`
 	SessionID     string `json:"session_id,omitempty"`
 	SessionStatus string `json:"session_status,omitempty"`
+	Blacklisted   bool   `json:"blacklisted"` // Номер находится в черном списке
+	// Долг за простой в копейках, начисленный при выезде
+	OvertimeAmount int `json:"overtime_amount,omitempty"`
 }
 
 // ValidateDirection проверяет корректность направления движения
@@ -2,12 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
 	"carwash_backend/internal/domain/dahua/models"
+	debtModels "carwash_backend/internal/domain/debt/models"
+	plateListModels "carwash_backend/internal/domain/platelist/models"
 	sessionModels "carwash_backend/internal/domain/session/models"
 	"carwash_backend/internal/logger"
 	"carwash_backend/internal/utils"
@@ -20,8 +24,10 @@ type Service interface {
 
 // ServiceImpl реализует интерфейс Service
 type ServiceImpl struct {
-	sessionService SessionService
-	washboxService WashboxService
+	sessionService   SessionService
+	washboxService   WashboxService
+	plateListService PlateListService
+	debtService      DebtService
 }
 
 // SessionService интерфейс для работы с сессиями
@@ -38,11 +44,23 @@ type WashboxService interface {
 	ClearCooldown(ctx context.Context, boxID uuid.UUID) error
 }
 
+// PlateListService интерфейс для проверки черного и VIP списков госномеров
+type PlateListService interface {
+	CheckCarNumber(ctx context.Context, carNumber string, source string, userID *uuid.UUID) (*plateListModels.PlateListEntry, error)
+}
+
+// DebtService интерфейс для начисления долга за простой после завершения сессии
+type DebtService interface {
+	RecordOvertime(ctx context.Context, req *debtModels.RecordOvertimeRequest) (*debtModels.CarNumberDebt, error)
+}
+
 // NewService создает новый экземпляр сервиса
-func NewService(sessionService SessionService, washboxService WashboxService) Service {
+func NewService(sessionService SessionService, washboxService WashboxService, plateListService PlateListService, debtService DebtService) Service {
 	return &ServiceImpl{
-		sessionService: sessionService,
-		washboxService: washboxService,
+		sessionService:   sessionService,
+		washboxService:   washboxService,
+		plateListService: plateListService,
+		debtService:      debtService,
 	}
 }
 
@@ -55,6 +73,20 @@ func (s *ServiceImpl) ProcessANPREvent(ctx context.Context, req *models.ProcessA
 		"direction":     req.Direction,
 	}).Info("Обработка события ANPR")
 
+	// На въезде проверяем номер по черному списку, блокировка фиксируется в журнале блокировок
+	// Выезд не проверяется: машину не блокируют, запись о блокировке была бы ложной
+	if req.Direction != "out" {
+		if blockedErr := s.checkBlacklist(ctx, req.LicensePlate); blockedErr != nil {
+			return &models.ProcessANPREventResponse{
+				Success:      true,
+				Message:      fmt.Sprintf("Автомобиль %s в черном списке: %s", req.LicensePlate, blockedErr.Reason),
+				UserFound:    false,
+				SessionFound: false,
+				Blacklisted:  true,
+			}, nil
+		}
+	}
+
 	// Проверяем, что это событие выезда
 	if req.Direction != "out" {
 		logger.WithFields(logrus.Fields{
@@ -98,6 +130,23 @@ func (s *ServiceImpl) ProcessANPREvent(ctx context.Context, req *models.ProcessA
 		}, nil
 	}
 
+	// Камера другой площадки не управляет сессией
+	if req.SiteID != uuid.Nil && lastSession.SiteID != req.SiteID {
+		logger.WithFields(logrus.Fields{
+			"service":      "dahua",
+			"method":       "ProcessANPREvent",
+			"session_id":   lastSession.ID,
+			"session_site": lastSession.SiteID,
+			"camera_site":  req.SiteID,
+		}).Info("Сессия относится к другой площадке")
+		return &models.ProcessANPREventResponse{
+			Success:      true,
+			Message:      fmt.Sprintf("Сессия с номером %s не найдена на этой площадке", req.LicensePlate),
+			UserFound:    false,
+			SessionFound: false,
+		}, nil
+	}
+
 	logger.WithFields(logrus.Fields{
 		"service":        "dahua",
 		"method":         "ProcessANPREvent",
@@ -189,6 +238,9 @@ func (s *ServiceImpl) ProcessANPREvent(ctx context.Context, req *models.ProcessA
 			}
 		}
 
+		// Начисляем долг за простой между завершением сессии и выездом
+		overtimeAmount := s.recordOvertime(ctx, lastSession, time.Now())
+
 		// Бокс свободен или нет активной сессии - безопасно сбрасываем кулдаун
 		if s.washboxService != nil {
 			err = s.washboxService.ClearCooldown(ctx, *lastSession.BoxID)
@@ -215,11 +267,12 @@ func (s *ServiceImpl) ProcessANPREvent(ctx context.Context, req *models.ProcessA
 
 		return &models.ProcessANPREventResponse{
 			Success:       true,
-			Message:       fmt.Sprintf("Кулдаун сброшен и бокс переведен в статус свободен для автомобиля %s", req.LicensePlate),
-			UserFound:     false,
-			SessionFound:  true,
-			SessionID:     lastSession.ID.String(),
-			SessionStatus: lastSession.Status,
+			Message:        fmt.Sprintf("Кулдаун сброшен и бокс переведен в статус свободен для автомобиля %s", req.LicensePlate),
+			UserFound:      false,
+			SessionFound:   true,
+			SessionID:      lastSession.ID.String(),
+			SessionStatus:  lastSession.Status,
+			OvertimeAmount: overtimeAmount,
 		}, nil
 	}
 
@@ -242,3 +295,78 @@ func (s *ServiceImpl) ProcessANPREvent(ctx context.Context, req *models.ProcessA
 		SessionStatus: lastSession.Status,
 	}, nil
 }
+
+// checkBlacklist проверяет номер от камеры по черному списку
+// Возвращает ошибку блокировки, если номер в черном списке, иначе nil
+func (s *ServiceImpl) checkBlacklist(ctx context.Context, licensePlate string) *plateListModels.PlateBlockedError {
+	if s.plateListService == nil {
+		return nil
+	}
+
+	normalizedLicensePlate := utils.NormalizeLicensePlateForSearch(licensePlate)
+	_, err := s.plateListService.CheckCarNumber(ctx, normalizedLicensePlate, plateListModels.SourceANPR, nil)
+	if err == nil {
+		return nil
+	}
+
+	var blockedErr *plateListModels.PlateBlockedError
+	if errors.As(err, &blockedErr) {
+		logger.WithFields(logrus.Fields{
+			"service":       "dahua",
+			"method":        "ProcessANPREvent",
+			"license_plate": licensePlate,
+			"reason":        blockedErr.Reason,
+		}).Warn("Распознан номер из черного списка")
+		return blockedErr
+	}
+
+	logger.WithFields(logrus.Fields{
+		"service":       "dahua",
+		"method":        "ProcessANPREvent",
+		"license_plate": licensePlate,
+		"error":         err,
+	}).Error("Ошибка проверки черного списка")
+	return nil
+}
+
+// recordOvertime начисляет долг за простой в боксе по завершенной сессии и возвращает его сумму
+// Ошибки только логируются, чтобы не мешать сбросу кулдауна
+func (s *ServiceImpl) recordOvertime(ctx context.Context, session *sessionModels.Session, exitAt time.Time) int {
+	// Простой считается от завершения сессии: StatusUpdatedAt перезаписывается последующими сменами статуса.
+	// Без времени завершения простой не измерить, долг не начисляется
+	if s.debtService == nil || session.CarNumber == "" || session.CompletedAt == nil {
+		return 0
+	}
+
+	debt, err := s.debtService.RecordOvertime(ctx, &debtModels.RecordOvertimeRequest{
+		CarNumber:      session.CarNumber,
+		SessionID:      session.ID,
+		BoxID:          session.BoxID,
+		SessionEndedAt: *session.CompletedAt,
+		ExitAt:         exitAt,
+	})
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"service":    "dahua",
+			"method":     "recordOvertime",
+			"session_id": session.ID,
+			"car_number": session.CarNumber,
+			"error":      err,
+		}).Error("Ошибка начисления долга за простой")
+		return 0
+	}
+	if debt == nil {
+		return 0
+	}
+
+	logger.WithFields(logrus.Fields{
+		"service":          "dahua",
+		"method":           "recordOvertime",
+		"session_id":       session.ID,
+		"car_number":       session.CarNumber,
+		"overtime_minutes": debt.OvertimeMinutes,
+		"amount":           debt.Amount,
+	}).Info("Начислен долг за простой")
+
+	return debt.Amount
+}
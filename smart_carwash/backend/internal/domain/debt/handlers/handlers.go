@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"carwash_backend/internal/domain/debt/models"
+	"carwash_backend/internal/domain/debt/service"
+	"carwash_backend/internal/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler структура для обработчиков HTTP запросов долгов за простой
+type Handler struct {
+	service service.Service
+}
+
+// NewHandler создает новый экземпляр Handler
+func NewHandler(service service.Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для долгов за простой
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup, adminMiddleware gin.HandlerFunc, auth1CMiddleware gin.HandlerFunc) {
+	// Публичный маршрут для mini app: показать долг перед созданием сессии
+	router.GET("/debts/pending", h.getPendingDebts)
+
+	// 1C маршруты
+	router.POST("/1c/settle-debt", auth1CMiddleware, h.handle1CSettleDebt)
+
+	// Административные маршруты
+	adminRoutes := router.Group("/admin/debts", adminMiddleware)
+	{
+		adminRoutes.GET("", h.adminListDebts)
+		adminRoutes.POST("/waive", h.adminWaiveDebt)
+	}
+}
+
+// getPendingDebts получает неоплаченные долги по госномеру
+func (h *Handler) getPendingDebts(c *gin.Context) {
+	var req models.GetPendingDebtsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.GetPendingDebts(c.Request.Context(), req.CarNumber)
+	if err != nil {
+		logger.WithContext(c).Errorf("getPendingDebts: ошибка получения долгов, car_number: %s, error: %v", req.CarNumber, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// handle1CSettleDebt обработчик для погашения долга через кассира без создания сессии
+func (h *Handler) handle1CSettleDebt(c *gin.Context) {
+	var req models.SettleDebts1CRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("handle1CSettleDebt: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, models.SettleDebts1CResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"car_number": req.CarNumber,
+		"amount":     req.Amount,
+	})
+
+	settled, err := h.service.SettleFromCashier(c.Request.Context(), nil, req.CarNumber, req.Amount, nil)
+	if err != nil {
+		logger.WithContext(c).Errorf("handle1CSettleDebt: ошибка погашения долга, car_number: %s, error: %v", req.CarNumber, err)
+		c.JSON(http.StatusBadRequest, models.SettleDebts1CResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	message := "Долг погашен"
+	if settled == 0 {
+		message = "Нет неоплаченных долгов"
+	}
+
+	c.JSON(http.StatusOK, models.SettleDebts1CResponse{
+		Success:       true,
+		SettledAmount: settled,
+		Message:       message,
+	})
+}
+
+// adminListDebts получает долги (админка)
+func (h *Handler) adminListDebts(c *gin.Context) {
+	var req models.AdminListDebtsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.AdminListDebts(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminListDebts: ошибка получения долгов: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// adminWaiveDebt списывает долг (админка)
+func (h *Handler) adminWaiveDebt(c *gin.Context) {
+	var req models.AdminWaiveDebtRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"debt_id": req.ID,
+		"comment": req.Comment,
+	})
+
+	debt, err := h.service.AdminWaiveDebt(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminWaiveDebt: ошибка списания долга: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, debt)
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы долга
+const (
+	DebtStatusPending = "pending" // Ожидает оплаты
+	DebtStatusPaid    = "paid"    // Оплачен
+	DebtStatusWaived  = "waived"  // Списан администратором
+)
+
+// Способы погашения долга
+const (
+	SettledViaApp     = "app"     // Оплачен вместе со следующей сессией в приложении
+	SettledViaCashier = "cashier" // Оплачен через кассира (1С)
+	SettledViaAdmin   = "admin"   // Списан администратором
+)
+
+// CarNumberDebt долг за простой в боксе после завершения сессии
+type CarNumberDebt struct {
+	ID               uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CarNumber        string     `json:"car_number" gorm:"not null;index"`                 // Нормализованный госномер
+	SessionID        uuid.UUID  `json:"session_id" gorm:"type:uuid;not null;uniqueIndex"` // Сессия, после которой был простой
+	BoxID            *uuid.UUID `json:"box_id,omitempty" gorm:"type:uuid"`
+	SessionEndedAt   time.Time  `json:"session_ended_at" gorm:"not null"` // Время завершения сессии
+	ExitAt           time.Time  `json:"exit_at" gorm:"not null"`          // Время выезда по данным ANPR
+	OvertimeMinutes  int        `json:"overtime_minutes" gorm:"not null"` // Тарифицируемые минуты простоя
+	PricePerMinute   int        `json:"price_per_minute" gorm:"not null"` // Цена минуты простоя в копейках на момент начисления
+	Amount           int        `json:"amount" gorm:"not null"`           // Сумма долга в копейках
+	Status           string     `json:"status" gorm:"not null;default:pending;index"`
+	SettledVia       *string    `json:"settled_via,omitempty"`                         // Способ погашения
+	SettledSessionID *uuid.UUID `json:"settled_session_id,omitempty" gorm:"type:uuid"` // Сессия, с которой был оплачен долг
+	SettledAt        *time.Time `json:"settled_at,omitempty"`
+	Comment          *string    `json:"comment,omitempty"` // Комментарий администратора при списании
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (CarNumberDebt) TableName() string {
+	return "car_number_debts"
+}
+
+// RecordOvertimeRequest запрос на начисление долга за простой
+type RecordOvertimeRequest struct {
+	CarNumber      string
+	SessionID      uuid.UUID
+	BoxID          *uuid.UUID
+	SessionEndedAt time.Time
+	ExitAt         time.Time
+}
+
+// PendingDebts неоплаченные долги по госномеру
+type PendingDebts struct {
+	CarNumber   string          `json:"car_number"`
+	TotalAmount int             `json:"total_amount"` // Общая сумма в копейках
+	Debts       []CarNumberDebt `json:"debts"`
+}
+
+// GetPendingDebtsRequest запрос на получение неоплаченных долгов по госномеру
+type GetPendingDebtsRequest struct {
+	CarNumber string `json:"car_number" form:"car_number" binding:"required"`
+}
+
+// SettleDebts1CRequest запрос от 1С на погашение долга через кассира
+type SettleDebts1CRequest struct {
+	CarNumber string `json:"car_number" binding:"required"`
+	Amount    int    `json:"amount" binding:"required,min=1"` // Принятая сумма в копейках
+}
+
+// SettleDebts1CResponse ответ на погашение долга через кассира
+type SettleDebts1CResponse struct {
+	Success       bool   `json:"success"`
+	SettledAmount int    `json:"settled_amount"` // Погашенная сумма в копейках
+	Message       string `json:"message,omitempty"`
+}
+
+// AdminListDebtsRequest запрос на получение долгов (админка)
+type AdminListDebtsRequest struct {
+	CarNumber string `json:"car_number" form:"car_number"`
+	Status    string `json:"status" form:"status"`
+	Limit     int    `json:"limit" form:"limit"`
+	Offset    int    `json:"offset" form:"offset"`
+}
+
+// AdminListDebtsResponse ответ на получение долгов (админка)
+type AdminListDebtsResponse struct {
+	Debts []CarNumberDebt `json:"debts"`
+	Total int             `json:"total"`
+}
+
+// AdminWaiveDebtRequest запрос на списание долга (админка)
+type AdminWaiveDebtRequest struct {
+	ID      uuid.UUID `json:"id" binding:"required"`
+	Comment string    `json:"comment" binding:"required"`
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"carwash_backend/internal/domain/debt/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository интерфейс для работы с долгами в базе данных
+type Repository interface {
+	CreateDebt(ctx context.Context, debt *models.CarNumberDebt) error
+	GetDebtByID(ctx context.Context, id uuid.UUID) (*models.CarNumberDebt, error)
+	GetDebtBySessionID(ctx context.Context, sessionID uuid.UUID) (*models.CarNumberDebt, error)
+	GetPendingDebts(ctx context.Context, tx *gorm.DB, carNumber string, createdBefore *time.Time) ([]models.CarNumberDebt, error)
+	SettleDebts(ctx context.Context, tx *gorm.DB, ids []uuid.UUID, settledVia string, settledSessionID *uuid.UUID, settledAt time.Time) error
+	UpdateDebt(ctx context.Context, debt *models.CarNumberDebt) error
+	ListDebts(ctx context.Context, req *models.AdminListDebtsRequest) ([]models.CarNumberDebt, int, error)
+}
+
+// PostgresRepository реализация Repository для PostgreSQL
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository создает новый экземпляр PostgresRepository
+func NewPostgresRepository(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{
+		db: db,
+	}
+}
+
+// CreateDebt создает долг
+func (r *PostgresRepository) CreateDebt(ctx context.Context, debt *models.CarNumberDebt) error {
+	return r.db.WithContext(ctx).Create(debt).Error
+}
+
+// GetDebtByID получает долг по ID
+func (r *PostgresRepository) GetDebtByID(ctx context.Context, id uuid.UUID) (*models.CarNumberDebt, error) {
+	var debt models.CarNumberDebt
+	err := r.db.WithContext(ctx).First(&debt, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &debt, nil
+}
+
+// GetDebtBySessionID получает долг, начисленный по сессии
+func (r *PostgresRepository) GetDebtBySessionID(ctx context.Context, sessionID uuid.UUID) (*models.CarNumberDebt, error) {
+	var debt models.CarNumberDebt
+	err := r.db.WithContext(ctx).Where("session_id = ?", sessionID).First(&debt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &debt, nil
+}
+
+// GetPendingDebts получает неоплаченные долги по госномеру
+// Если указан createdBefore, возвращаются только долги, начисленные до этого момента.
+// В транзакции tx долги блокируются до ее завершения, чтобы их не погасили дважды
+func (r *PostgresRepository) GetPendingDebts(ctx context.Context, tx *gorm.DB, carNumber string, createdBefore *time.Time) ([]models.CarNumberDebt, error) {
+	var debts []models.CarNumberDebt
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	query = query.
+		Where("car_number = ? AND status = ?", carNumber, models.DebtStatusPending)
+	if createdBefore != nil {
+		query = query.Where("created_at <= ?", *createdBefore)
+	}
+	err := query.Order("created_at ASC").Find(&debts).Error
+	return debts, err
+}
+
+// SettleDebts отмечает долги оплаченными
+// Если передана транзакция, долги погашаются в ней вместе с созданием сессии
+func (r *PostgresRepository) SettleDebts(ctx context.Context, tx *gorm.DB, ids []uuid.UUID, settledVia string, settledSessionID *uuid.UUID, settledAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	return db.WithContext(ctx).Model(&models.CarNumberDebt{}).
+		Where("id IN ? AND status = ?", ids, models.DebtStatusPending).
+		Updates(map[string]interface{}{
+			"status":             models.DebtStatusPaid,
+			"settled_via":        settledVia,
+			"settled_session_id": settledSessionID,
+			"settled_at":         settledAt,
+			"updated_at":         settledAt,
+		}).Error
+}
+
+// UpdateDebt обновляет долг
+func (r *PostgresRepository) UpdateDebt(ctx context.Context, debt *models.CarNumberDebt) error {
+	return r.db.WithContext(ctx).Save(debt).Error
+}
+
+// ListDebts получает долги с фильтрами и пагинацией
+func (r *PostgresRepository) ListDebts(ctx context.Context, req *models.AdminListDebtsRequest) ([]models.CarNumberDebt, int, error) {
+	var debts []models.CarNumberDebt
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.CarNumberDebt{})
+	if req.CarNumber != "" {
+		query = query.Where("car_number LIKE ?", "%"+req.CarNumber+"%")
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := req.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&debts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return debts, int(total), nil
+}
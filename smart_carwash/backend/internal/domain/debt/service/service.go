@@ -0,0 +1,224 @@
+package service
+
+import (
+	"carwash_backend/internal/domain/debt/models"
+	"carwash_backend/internal/domain/debt/repository"
+	settingsService "carwash_backend/internal/domain/settings/service"
+	"carwash_backend/internal/logger"
+	"carwash_backend/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Service интерфейс для бизнес-логики долгов за простой
+type Service interface {
+	RecordOvertime(ctx context.Context, req *models.RecordOvertimeRequest) (*models.CarNumberDebt, error)
+	GetPendingDebts(ctx context.Context, carNumber string) (*models.PendingDebts, error)
+	SettleDebts(ctx context.Context, carNumber string, createdBefore *time.Time, settledVia string, settledSessionID *uuid.UUID) (int, error)
+	SettleFromCashier(ctx context.Context, tx *gorm.DB, carNumber string, amount int, settledSessionID *uuid.UUID) (int, error)
+	AdminListDebts(ctx context.Context, req *models.AdminListDebtsRequest) (*models.AdminListDebtsResponse, error)
+	AdminWaiveDebt(ctx context.Context, req *models.AdminWaiveDebtRequest) (*models.CarNumberDebt, error)
+}
+
+// ServiceImpl реализация Service
+type ServiceImpl struct {
+	repo            repository.Repository
+	settingsService settingsService.Service
+}
+
+// NewService создает новый экземпляр Service
+func NewService(repo repository.Repository, settingsService settingsService.Service) *ServiceImpl {
+	return &ServiceImpl{
+		repo:            repo,
+		settingsService: settingsService,
+	}
+}
+
+// RecordOvertime начисляет долг за простой между завершением сессии и выездом автомобиля
+// Возвращает nil, если простой не превысил бесплатное время или тарификация отключена
+func (s *ServiceImpl) RecordOvertime(ctx context.Context, req *models.RecordOvertimeRequest) (*models.CarNumberDebt, error) {
+	if req.CarNumber == "" {
+		return nil, nil
+	}
+
+	// Простой по одной сессии начисляется один раз
+	existing, err := s.repo.GetDebtBySessionID(ctx, req.SessionID)
+	if err == nil && existing != nil {
+		return existing, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("ошибка проверки существующего долга: %w", err)
+	}
+
+	settings, err := s.settingsService.GetOvertimeSettings(ctx)
+	if err != nil {
+		logger.Printf("Debt - RecordOvertime: ошибка получения настроек простоя, используем значения по умолчанию: %v", err)
+	}
+	if settings == nil || settings.PricePerMinute <= 0 {
+		return nil, nil
+	}
+
+	overstayMinutes := int(math.Ceil(req.ExitAt.Sub(req.SessionEndedAt).Minutes()))
+	if settings.MaxMinutes > 0 && overstayMinutes > settings.MaxMinutes {
+		// Слишком большой интервал - скорее всего это выезд с другого визита
+		logger.Printf("Debt - RecordOvertime: простой %d минут превышает максимум %d, не начисляем, car_number: %s, session_id: %s",
+			overstayMinutes, settings.MaxMinutes, req.CarNumber, req.SessionID)
+		return nil, nil
+	}
+
+	billableMinutes := overstayMinutes - settings.GraceMinutes
+	if billableMinutes <= 0 {
+		return nil, nil
+	}
+
+	debt := &models.CarNumberDebt{
+		CarNumber:       req.CarNumber,
+		SessionID:       req.SessionID,
+		BoxID:           req.BoxID,
+		SessionEndedAt:  req.SessionEndedAt,
+		ExitAt:          req.ExitAt,
+		OvertimeMinutes: billableMinutes,
+		PricePerMinute:  settings.PricePerMinute,
+		Amount:          billableMinutes * settings.PricePerMinute,
+		Status:          models.DebtStatusPending,
+	}
+
+	if err := s.repo.CreateDebt(ctx, debt); err != nil {
+		return nil, fmt.Errorf("ошибка создания долга: %w", err)
+	}
+
+	logger.Printf("Debt - RecordOvertime: начислен долг за простой, car_number: %s, session_id: %s, minutes: %d, amount: %d",
+		req.CarNumber, req.SessionID, billableMinutes, debt.Amount)
+
+	return debt, nil
+}
+
+// GetPendingDebts получает неоплаченные долги по госномеру
+func (s *ServiceImpl) GetPendingDebts(ctx context.Context, carNumber string) (*models.PendingDebts, error) {
+	normalizedCarNumber := utils.NormalizeLicensePlateForSearch(carNumber)
+
+	debts, err := s.repo.GetPendingDebts(ctx, nil, normalizedCarNumber, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения долгов: %w", err)
+	}
+
+	total := 0
+	for _, debt := range debts {
+		total += debt.Amount
+	}
+
+	return &models.PendingDebts{
+		CarNumber:   normalizedCarNumber,
+		TotalAmount: total,
+		Debts:       debts,
+	}, nil
+}
+
+// SettleDebts отмечает неоплаченные долги по госномеру оплаченными и возвращает погашенную сумму
+// createdBefore ограничивает погашение долгами, известными на момент формирования платежа
+func (s *ServiceImpl) SettleDebts(ctx context.Context, carNumber string, createdBefore *time.Time, settledVia string, settledSessionID *uuid.UUID) (int, error) {
+	debts, err := s.repo.GetPendingDebts(ctx, nil, carNumber, createdBefore)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения долгов: %w", err)
+	}
+
+	return s.settle(ctx, nil, carNumber, debts, settledVia, settledSessionID)
+}
+
+// SettleFromCashier погашает долги по госномеру суммой, принятой кассиром
+// Принятая сумма должна покрывать все неоплаченные долги. С транзакцией tx долги погашаются в ней:
+// если транзакция откатится, долги останутся неоплаченными
+func (s *ServiceImpl) SettleFromCashier(ctx context.Context, tx *gorm.DB, carNumber string, amount int, settledSessionID *uuid.UUID) (int, error) {
+	normalizedCarNumber := utils.NormalizeLicensePlateForSearch(carNumber)
+
+	debts, err := s.repo.GetPendingDebts(ctx, tx, normalizedCarNumber, nil)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения долгов: %w", err)
+	}
+
+	total := 0
+	for _, debt := range debts {
+		total += debt.Amount
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	if amount < total {
+		return 0, fmt.Errorf("принятая сумма %d меньше долга %d по номеру '%s'", amount, total, normalizedCarNumber)
+	}
+
+	return s.settle(ctx, tx, normalizedCarNumber, debts, models.SettledViaCashier, settledSessionID)
+}
+
+// settle отмечает переданные долги оплаченными
+func (s *ServiceImpl) settle(ctx context.Context, tx *gorm.DB, carNumber string, debts []models.CarNumberDebt, settledVia string, settledSessionID *uuid.UUID) (int, error) {
+	if len(debts) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(debts))
+	total := 0
+	for _, debt := range debts {
+		ids = append(ids, debt.ID)
+		total += debt.Amount
+	}
+
+	if err := s.repo.SettleDebts(ctx, tx, ids, settledVia, settledSessionID, time.Now()); err != nil {
+		return 0, fmt.Errorf("ошибка погашения долгов: %w", err)
+	}
+
+	logger.Printf("Debt - settle: долги погашены, car_number: %s, count: %d, amount: %d, via: %s", carNumber, len(ids), total, settledVia)
+
+	return total, nil
+}
+
+// AdminListDebts получает долги (админка)
+func (s *ServiceImpl) AdminListDebts(ctx context.Context, req *models.AdminListDebtsRequest) (*models.AdminListDebtsResponse, error) {
+	if req.CarNumber != "" {
+		req.CarNumber = utils.NormalizeLicensePlateForSearch(req.CarNumber)
+	}
+
+	debts, total, err := s.repo.ListDebts(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения долгов: %w", err)
+	}
+
+	return &models.AdminListDebtsResponse{
+		Debts: debts,
+		Total: total,
+	}, nil
+}
+
+// AdminWaiveDebt списывает долг (админка)
+func (s *ServiceImpl) AdminWaiveDebt(ctx context.Context, req *models.AdminWaiveDebtRequest) (*models.CarNumberDebt, error) {
+	debt, err := s.repo.GetDebtByID(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("долг не найден: %w", err)
+	}
+
+	if debt.Status != models.DebtStatusPending {
+		return nil, fmt.Errorf("долг в статусе '%s' не может быть списан", debt.Status)
+	}
+
+	now := time.Now()
+	settledVia := models.SettledViaAdmin
+	comment := req.Comment
+	debt.Status = models.DebtStatusWaived
+	debt.SettledVia = &settledVia
+	debt.SettledAt = &now
+	debt.Comment = &comment
+
+	if err := s.repo.UpdateDebt(ctx, debt); err != nil {
+		return nil, fmt.Errorf("ошибка списания долга: %w", err)
+	}
+
+	logger.Printf("Debt - AdminWaiveDebt: долг списан, debt_id: %s, car_number: %s, amount: %d", debt.ID, debt.CarNumber, debt.Amount)
+
+	return debt, nil
+}
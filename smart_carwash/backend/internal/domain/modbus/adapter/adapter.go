@@ -3,15 +3,20 @@ package adapter
 import (
 	"carwash_backend/internal/logger"
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
 	"carwash_backend/internal/config"
+	realtimeService "carwash_backend/internal/domain/realtime/service"
+	"carwash_backend/internal/metrics"
 	"carwash_backend/internal/domain/modbus/client"
 	"carwash_backend/internal/domain/modbus/models"
 	"carwash_backend/internal/domain/modbus/repository"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
 	washboxlogModels "carwash_backend/internal/domain/washboxlog/models"
 	washboxlogService "carwash_backend/internal/domain/washboxlog/service"
 
@@ -25,6 +30,13 @@ type ModbusAdapter struct {
 	config     *config.Config
 	db         *gorm.DB
 	loggerSvc  washboxlogService.Service
+
+	driftMu   sync.Mutex
+	drift     map[string]bool      // Расхождения, замеченные на предыдущем проходе сверки
+	refreshed map[string]time.Time // Когда сверка последний раз подтвердила включенный coil
+
+	metrics   *metrics.Metrics          // Опциональные метрики очереди команд
+	publisher realtimeService.Publisher // Опциональная отправка тревог администратору
 }
 
 // NewModbusAdapter создает новый адаптер для modbus
@@ -35,83 +47,166 @@ func NewModbusAdapter(config *config.Config, db *gorm.DB, loggerSvc washboxlogSe
 		config:     config,
 		db:         db,
 		loggerSvc:  loggerSvc,
+		drift:      make(map[string]bool),
+		refreshed:  make(map[string]time.Time),
 	}
 }
 
+// SetMetrics устанавливает метрики очереди команд
+func (a *ModbusAdapter) SetMetrics(m *metrics.Metrics) {
+	a.metrics = m
+}
+
+// SetEventPublisher устанавливает отправку тревог об очереди команд в поток событий
+func (a *ModbusAdapter) SetEventPublisher(publisher realtimeService.Publisher) {
+	a.publisher = publisher
+}
+
+// boxDevice возвращает имя ПЛК, к которому подключен бокс (пустая строка - устройство по умолчанию)
+func (a *ModbusAdapter) boxDevice(ctx context.Context, boxID uuid.UUID) string {
+	box, err := a.repository.GetWashBoxByID(ctx, boxID)
+	if err != nil {
+		logger.Printf("ModbusAdapter: не удалось получить бокс для выбора устройства, используется устройство по умолчанию - box_id: %s, error: %v", boxID, err)
+		return ""
+	}
+	if box.ModbusDevice == nil {
+		return ""
+	}
+	return *box.ModbusDevice
+}
+
 // WriteCoil записывает значение в coil Modbus устройства
 func (a *ModbusAdapter) WriteCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error {
 	logger.Printf("ModbusAdapter WriteCoil - box_id: %s, register: %s, value: %v", boxID, register, value)
-	return a.httpClient.WriteCoil(ctx, boxID, register, value)
+	return a.httpClient.WriteCoil(ctx, boxID, a.boxDevice(ctx, boxID), register, value, false)
 }
 
-// WriteLightCoil включает или выключает свет для бокса
-func (a *ModbusAdapter) WriteLightCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error {
-	logger.Printf("ModbusAdapter WriteLightCoil - box_id: %s, register: %s, value: %v", boxID, register, value)
+// WriteChannel сразу включает или выключает канал устройства бокса (свет, ворота, пылесос и т.п.)
+// Coil записывается с учетом инверсии канала, holding register - значением включения или выключения
+func (a *ModbusAdapter) WriteChannel(ctx context.Context, boxID uuid.UUID, channel washboxModels.DeviceChannel, on bool) error {
+	logger.Printf("ModbusAdapter WriteChannel - box_id: %s, role: %s, register: %s, on: %v", boxID, channel.Role, channel.Register, on)
 
-	// Выполняем операцию через HTTP клиент
-	err := a.httpClient.WriteLightCoil(ctx, boxID, register, value)
+	if channel.ChannelType() == washboxModels.ChannelTypeRegister {
+		err := a.applyRegister(ctx, boxID, models.CoilOperation(channel.Role, on), channelRegisterFormat(channel), channel.RegisterValue(on), nil, 0)
+		if err == nil {
+			a.recordChannelState(ctx, boxID, channel.Role, channel.Register, on, nil)
+		}
+		return err
+	}
+	return a.applyCoil(ctx, boxID, channel.Role, channel.Register, channel.CoilValue(on), &on, nil, 0)
+}
+
+// WriteCoils записывает несколько coils бокса за один запрос к modbus серверу
+// Результат возвращается по каждому coil; coils с ролью канала обновляют его состояние с учетом инверсии
+func (a *ModbusAdapter) WriteCoils(ctx context.Context, boxID uuid.UUID, coils []models.CoilWrite) ([]models.CoilWriteResult, error) {
+	logger.Printf("ModbusAdapter WriteCoils - box_id: %s, coils: %v", boxID, coils)
 
-	// Определяем операцию
-	operation := "light_off"
-	if value {
-		operation = "light_on"
+	box, err := a.repository.GetWashBoxByID(ctx, boxID)
+	if err != nil {
+		logger.Printf("ModbusAdapter: не удалось получить каналы бокса, инверсия не учитывается - box_id: %s, error: %v", boxID, err)
 	}
 
-	// Сохраняем операцию в БД
-	modbusOp := &models.ModbusOperation{
-		ID:        uuid.New(),
-		BoxID:     boxID,
-		Operation: operation,
-		Register:  register,
-		Value:     value,
-		Success:   err == nil,
-		CreatedAt: time.Now(),
+	writes := make([]coilWrite, len(coils))
+	for i, coil := range coils {
+		writes[i] = coilWrite{CoilWrite: coil}
+		if box != nil && coil.CoilType != "" {
+			if channel, ok := box.FindChannel(coil.CoilType); ok {
+				on := channel.CoilOn(coil.Value)
+				writes[i].on = &on
+			}
+		}
 	}
+	return a.applyCoils(ctx, boxID, writes)
+}
 
-	if err != nil {
-		modbusOp.Error = err.Error()
+// coilWrite запись coil из пакета; commandID и attempt заполняются для команд из очереди
+type coilWrite struct {
+	models.CoilWrite
+	on        *bool // Включено ли устройство канала после записи; nil - совпадает с Value
+	commandID *uuid.UUID
+	attempt   int
+}
+
+// inverted coil канала с инверсией: значение записи противоположно состоянию устройства.
+// Передается modbus серверу, чтобы таймер безопасности выключал канал записью true
+func (w coilWrite) inverted() bool {
+	return w.on != nil && *w.on != w.Value
+}
+
+// applyCoil записывает coil канала, сохраняет операцию и при успехе обновляет состояние канала
+// Свет и химия пишутся через отдельные методы modbus сервера, остальные каналы - как обычный coil.
+// commandID и attempt заполняются, когда запись - попытка выполнения команды из очереди
+func (a *ModbusAdapter) applyCoil(ctx context.Context, boxID uuid.UUID, role, register string, value bool, on *bool, commandID *uuid.UUID, attempt int) error {
+	write := coilWrite{
+		CoilWrite: models.CoilWrite{CoilType: role, Register: register, Value: value},
+		on:        on,
+		commandID: commandID,
+		attempt:   attempt,
 	}
 
-	// Сохраняем операцию
-	if saveErr := a.repository.SaveModbusOperation(ctx, modbusOp); saveErr != nil {
-		logger.Printf("Ошибка сохранения операции WriteLightCoil - box_id: %s, error: %v", boxID, saveErr)
+	// Выполняем операцию через HTTP клиент
+	var err error
+	device := a.boxDevice(ctx, boxID)
+	switch role {
+	case washboxModels.ChannelChemistry:
+		err = a.httpClient.WriteChemistryCoil(ctx, boxID, device, register, value, write.inverted())
+	case washboxModels.ChannelLight:
+		err = a.httpClient.WriteLightCoil(ctx, boxID, device, register, value, write.inverted())
+	default:
+		err = a.httpClient.WriteCoil(ctx, boxID, device, register, value, write.inverted())
 	}
 
-	// Если операция успешна, обновляем статус койла
-	if err == nil {
-		// Получим предыдущее значение
-		var prevValPtr *bool
-		if status, getErr := a.repository.GetModbusConnectionStatus(ctx, boxID); getErr == nil && status != nil {
-			if status.LightStatus != nil {
-				prev := *status.LightStatus
-				prevValPtr = &prev
-			}
+	a.recordCoilWrite(ctx, boxID, write, err)
+	return err
+}
+
+// applyCoils записывает пакет coils одним запросом и сохраняет операцию по каждому coil
+func (a *ModbusAdapter) applyCoils(ctx context.Context, boxID uuid.UUID, writes []coilWrite) ([]models.CoilWriteResult, error) {
+	coils := make([]client.CoilWrite, len(writes))
+	for i, write := range writes {
+		coils[i] = client.CoilWrite{Register: write.Register, Value: write.Value, Inverted: write.inverted()}
+	}
+
+	responses, err := a.httpClient.WriteCoils(ctx, boxID, a.boxDevice(ctx, boxID), coils)
+
+	results := make([]models.CoilWriteResult, len(writes))
+	for i, write := range writes {
+		result := models.CoilWriteResult{Register: write.Register, Value: write.Value}
+		switch {
+		case i < len(responses):
+			result.Success = responses[i].Success
+			result.RolledBack = responses[i].RolledBack
+			result.Error = responses[i].Error
+		case err != nil:
+			result.Error = err.Error()
+		default:
+			result.Error = "modbus сервер не вернул результат записи"
 		}
-		if updateErr := a.repository.UpdateModbusCoilStatus(ctx, boxID, "light", value); updateErr != nil {
-			logger.Printf("Ошибка обновления статуса света - box_id: %s, error: %v", boxID, updateErr)
-		} else if a.loggerSvc != nil {
-			action := washboxlogModels.ActionLightOff
-			if value {
-				action = washboxlogModels.ActionLightOn
-			}
-			_ = a.loggerSvc.RecordCoilChange(ctx, boxID, action, prevValPtr, value, nil)
+		if !result.Success && result.Error == "" {
+			result.Error = "coil не записан"
+		}
+		results[i] = result
+
+		var writeErr error
+		if !result.Success {
+			writeErr = errors.New(result.Error)
 		}
+		a.recordCoilWrite(ctx, boxID, write, writeErr)
 	}
 
-	return err
+	return results, err
 }
 
-// WriteChemistryCoil включает или выключает химию для бокса
-func (a *ModbusAdapter) WriteChemistryCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error {
-	logger.Printf("ModbusAdapter WriteChemistryCoil - box_id: %s, register: %s, value: %v", boxID, register, value)
-
-	// Выполняем операцию через HTTP клиент
-	err := a.httpClient.WriteChemistryCoil(ctx, boxID, register, value)
+// recordCoilWrite сохраняет операцию записи coil и при успехе обновляет состояние канала бокса
+func (a *ModbusAdapter) recordCoilWrite(ctx context.Context, boxID uuid.UUID, write coilWrite, err error) {
+	on := write.Value
+	if write.on != nil {
+		on = *write.on
+	}
 
-	// Определяем операцию
-	operation := "chemistry_off"
-	if value {
-		operation = "chemistry_on"
+	operation := "write_coil"
+	if write.CoilType != "" {
+		operation = models.CoilOperation(write.CoilType, on)
 	}
 
 	// Сохраняем операцию в БД
@@ -119,9 +214,11 @@ func (a *ModbusAdapter) WriteChemistryCoil(ctx context.Context, boxID uuid.UUID,
 		ID:        uuid.New(),
 		BoxID:     boxID,
 		Operation: operation,
-		Register:  register,
-		Value:     value,
+		Register:  write.Register,
+		Value:     write.Value,
 		Success:   err == nil,
+		CommandID: write.commandID,
+		Attempt:   write.attempt,
 		CreatedAt: time.Now(),
 	}
 
@@ -129,33 +226,34 @@ func (a *ModbusAdapter) WriteChemistryCoil(ctx context.Context, boxID uuid.UUID,
 		modbusOp.Error = err.Error()
 	}
 
-	// Сохраняем операцию
 	if saveErr := a.repository.SaveModbusOperation(ctx, modbusOp); saveErr != nil {
-		logger.Printf("Ошибка сохранения операции WriteChemistryCoil - box_id: %s, error: %v", boxID, saveErr)
+		logger.Printf("Ошибка сохранения операции %s - box_id: %s, error: %v", modbusOp.Operation, boxID, saveErr)
 	}
 
-	// Если операция успешна, обновляем статус койла
-	if err == nil {
-		// Получим предыдущее значение
-		var prevValPtr *bool
-		if status, getErr := a.repository.GetModbusConnectionStatus(ctx, boxID); getErr == nil && status != nil {
-			if status.ChemistryStatus != nil {
-				prev := *status.ChemistryStatus
-				prevValPtr = &prev
-			}
-		}
-		if updateErr := a.repository.UpdateModbusCoilStatus(ctx, boxID, "chemistry", value); updateErr != nil {
-			logger.Printf("Ошибка обновления статуса химии - box_id: %s, error: %v", boxID, updateErr)
-		} else if a.loggerSvc != nil {
-			action := washboxlogModels.ActionChemistryOff
-			if value {
-				action = washboxlogModels.ActionChemistryOn
-			}
-			_ = a.loggerSvc.RecordCoilChange(ctx, boxID, action, prevValPtr, value, nil)
-		}
+	// Если операция успешна, обновляем состояние канала
+	if err == nil && write.CoilType != "" {
+		a.recordChannelState(ctx, boxID, write.CoilType, write.Register, on, nil)
 	}
+}
 
-	return err
+// recordChannelState сохраняет состояние канала бокса и записывает изменение в журнал бокса
+// source - пометка источника изменения в журнале (nil - запись по команде)
+func (a *ModbusAdapter) recordChannelState(ctx context.Context, boxID uuid.UUID, role, register string, on bool, source *string) {
+	// Получим предыдущее значение
+	var prevValPtr *bool
+	if status, getErr := a.repository.GetChannelStatus(ctx, boxID, role); getErr == nil && status != nil {
+		prevValPtr = &status.On
+	}
+	if updateErr := a.repository.UpdateChannelStatus(ctx, boxID, role, register, on); updateErr != nil {
+		logger.Printf("Ошибка обновления состояния канала %s - box_id: %s, error: %v", role, boxID, updateErr)
+		return
+	}
+	// Повторная запись того же состояния (подтверждение для сторожевого таймера) в журнал бокса не попадает
+	if a.loggerSvc != nil && (prevValPtr == nil || *prevValPtr != on) {
+		if err := a.loggerSvc.RecordCoilChange(ctx, boxID, washboxlogModels.ChannelAction(role, on), prevValPtr, on, source); err != nil {
+			logger.Printf("Ошибка записи состояния канала %s в журнал бокса - box_id: %s, error: %v", role, boxID, err)
+		}
+	}
 }
 
 // HandleModbusError обрабатывает ошибку Modbus (только логирует, без продления времени)
@@ -170,7 +268,7 @@ func (a *ModbusAdapter) HandleModbusError(boxID uuid.UUID, operation string, ses
 func (a *ModbusAdapter) TestCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error {
 	logger.Printf("ModbusAdapter TestCoil - box_id: %s, register: %s, value: %v", boxID, register, value)
 
-	resp, err := a.httpClient.TestCoil(ctx, boxID, register, value)
+	resp, err := a.httpClient.TestCoil(ctx, boxID, a.boxDevice(ctx, boxID), register, value)
 	if err != nil {
 		return err
 	}
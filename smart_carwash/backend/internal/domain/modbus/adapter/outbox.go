@@ -0,0 +1,349 @@
+package adapter
+
+import (
+	"carwash_backend/internal/logger"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"carwash_backend/internal/domain/modbus/models"
+	realtimeModels "carwash_backend/internal/domain/realtime/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
+)
+
+const (
+	// outboxBatchSize сколько невыполненных команд диспетчер читает за один проход
+	outboxBatchSize = 500
+	// outboxMinBackoff и outboxMaxBackoff пределы паузы между повторами команды
+	outboxMinBackoff = 2 * time.Second
+	outboxMaxBackoff = 60 * time.Second
+	// outboxMaxBatch сколько команд одного бокса можно отправить одной пакетной записью
+	outboxMaxBatch = 8
+)
+
+// EnqueueChannel ставит включение или выключение канала устройства бокса в очередь команд
+// Если передана транзакция, команда записывается в ней вместе с изменением сессии.
+// Coil пишется с учетом инверсии канала, holding register - значением включения или выключения
+func (a *ModbusAdapter) EnqueueChannel(ctx context.Context, tx *gorm.DB, boxID uuid.UUID, sessionID *uuid.UUID, channel washboxModels.DeviceChannel, on bool) error {
+	return a.enqueueCommand(ctx, tx, a.channelCommand(boxID, sessionID, channel, on))
+}
+
+// channelCommand формирует команду очереди на включение или выключение канала устройства бокса
+func (a *ModbusAdapter) channelCommand(boxID uuid.UUID, sessionID *uuid.UUID, channel washboxModels.DeviceChannel, on bool) *models.OutboxCommand {
+	now := time.Now()
+	command := &models.OutboxCommand{
+		ID:            uuid.New(),
+		BoxID:         boxID,
+		SessionID:     sessionID,
+		CommandType:   models.OutboxCommandCoil,
+		Operation:     models.CoilOperation(channel.Role, on),
+		CoilType:      channel.Role,
+		Register:      channel.Register,
+		Value:         channel.CoilValue(on),
+		ChannelOn:     &on,
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: now,
+		Deadline:      now.Add(time.Duration(a.config.ModbusCommandDeadlineSeconds) * time.Second),
+		ExpiresAt:     now.Add(time.Duration(a.config.ModbusCommandTTLMinutes) * time.Minute),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if channel.ChannelType() == washboxModels.ChannelTypeRegister {
+		value := channel.RegisterValue(on)
+		command.CommandType = models.OutboxCommandRegister
+		command.Value = false
+		command.DataType = optionalString(washboxModels.RegisterTypeUint16)
+		command.NumericValue = &value
+	}
+
+	return command
+}
+
+// enqueueCommand записывает команду в очередь
+func (a *ModbusAdapter) enqueueCommand(ctx context.Context, tx *gorm.DB, command *models.OutboxCommand) error {
+	if err := a.repository.EnqueueCommand(ctx, tx, command); err != nil {
+		return fmt.Errorf("ошибка постановки команды Modbus в очередь: %w", err)
+	}
+
+	logger.Printf("ModbusAdapter: команда поставлена в очередь - command_id: %s, box_id: %s, operation: %s, register: %s",
+		command.ID, command.BoxID, command.Operation, command.Register)
+	return nil
+}
+
+// DispatchOutbox выполняет команды из очереди: по порядку для каждого бокса, боксы параллельно
+// Неудачная команда повторяется с нарастающей паузой и задерживает следующие команды своего бокса,
+// чтобы они не применились в другом порядке. По команде, не выполненной до крайнего срока,
+// поднимается тревога; после истечения срока жизни команда больше не повторяется. Вызывается планировщиком
+func (a *ModbusAdapter) DispatchOutbox(ctx context.Context) error {
+	commands, err := a.repository.GetPendingCommands(ctx, outboxBatchSize)
+	if err != nil {
+		return fmt.Errorf("ошибка получения очереди команд Modbus: %w", err)
+	}
+
+	now := time.Now()
+	overdue := 0
+	byBox := make(map[uuid.UUID][]models.OutboxCommand)
+	var boxOrder []uuid.UUID
+	for _, command := range commands {
+		if now.After(command.Deadline) {
+			overdue++
+		}
+		if _, ok := byBox[command.BoxID]; !ok {
+			boxOrder = append(boxOrder, command.BoxID)
+		}
+		byBox[command.BoxID] = append(byBox[command.BoxID], command)
+	}
+
+	if a.metrics != nil {
+		a.metrics.UpdateModbusOutbox("pending", float64(len(commands)))
+		a.metrics.UpdateModbusOutbox("overdue", float64(overdue))
+	}
+
+	var wg sync.WaitGroup
+	for _, boxID := range boxOrder {
+		wg.Add(1)
+		go func(boxCommands []models.OutboxCommand) {
+			defer wg.Done()
+			a.dispatchBox(ctx, boxCommands)
+		}(byBox[boxID])
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// dispatchBox выполняет команды одного бокса по порядку до первой неудачной
+// Подряд идущие готовые команды на разные регистры отправляются одной пакетной записью
+func (a *ModbusAdapter) dispatchBox(ctx context.Context, commands []models.OutboxCommand) {
+	for i := 0; i < len(commands); {
+		command := &commands[i]
+		now := time.Now()
+
+		if now.After(command.ExpiresAt) {
+			a.expireCommand(ctx, command, now)
+			i++
+			continue
+		}
+		if now.Before(command.NextAttemptAt) {
+			return
+		}
+
+		batch := outboxBatch(commands[i:], now)
+		var ok bool
+		if len(batch) == 1 {
+			ok = a.attemptCommand(ctx, command)
+		} else {
+			ok = a.attemptBatch(ctx, batch)
+		}
+		if !ok {
+			return
+		}
+		i += len(batch)
+	}
+}
+
+// outboxBatch отбирает с начала списка готовые к выполнению команды на разные coils
+// Команда на уже попавший в пакет регистр завершает пакет, чтобы сохранить порядок записей.
+// Запись holding register выполняется отдельно от coils
+func outboxBatch(commands []models.OutboxCommand, now time.Time) []*models.OutboxCommand {
+	batch := make([]*models.OutboxCommand, 0, outboxMaxBatch)
+	registers := make(map[string]bool, outboxMaxBatch)
+	for i := range commands {
+		command := &commands[i]
+		if len(batch) == outboxMaxBatch || registers[command.Register] ||
+			now.After(command.ExpiresAt) || now.Before(command.NextAttemptAt) {
+			break
+		}
+		if command.CommandType == models.OutboxCommandRegister {
+			if len(batch) == 0 {
+				batch = append(batch, command)
+			}
+			break
+		}
+		registers[command.Register] = true
+		batch = append(batch, command)
+	}
+	return batch
+}
+
+// attemptCommand выполняет одну попытку команды и сохраняет ее результат
+func (a *ModbusAdapter) attemptCommand(ctx context.Context, command *models.OutboxCommand) bool {
+	attempt := command.Attempts + 1
+	var err error
+	if command.CommandType == models.OutboxCommandRegister {
+		value := 0.0
+		if command.NumericValue != nil {
+			value = *command.NumericValue
+		}
+		err = a.applyRegister(ctx, command.BoxID, command.Operation, commandRegisterFormat(command), value, &command.ID, attempt)
+		// Запись канала типа register меняет состояние канала, запись параметра - нет
+		if err == nil && command.CoilType != "" && command.ChannelOn != nil {
+			a.recordChannelState(ctx, command.BoxID, command.CoilType, command.Register, *command.ChannelOn, nil)
+		}
+	} else {
+		err = a.applyCoil(ctx, command.BoxID, command.CoilType, command.Register, command.Value, command.ChannelOn, &command.ID, attempt)
+	}
+	return a.finishAttempt(ctx, command, attempt, err)
+}
+
+// attemptBatch выполняет попытку нескольких команд бокса одной пакетной записью
+// Пакет успешен, только если выполнены все команды; откаченные команды повторяются как неудачные
+func (a *ModbusAdapter) attemptBatch(ctx context.Context, batch []*models.OutboxCommand) bool {
+	writes := make([]coilWrite, len(batch))
+	for i, command := range batch {
+		writes[i] = coilWrite{
+			CoilWrite: models.CoilWrite{CoilType: command.CoilType, Register: command.Register, Value: command.Value},
+			on:        command.ChannelOn,
+			commandID: &batch[i].ID,
+			attempt:   command.Attempts + 1,
+		}
+	}
+
+	results, _ := a.applyCoils(ctx, batch[0].BoxID, writes)
+
+	ok := true
+	for i, command := range batch {
+		var err error
+		if !results[i].Success {
+			err = errors.New(results[i].Error)
+		}
+		if !a.finishAttempt(ctx, command, writes[i].attempt, err) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// finishAttempt сохраняет результат попытки команды; false - команда не выполнена
+func (a *ModbusAdapter) finishAttempt(ctx context.Context, command *models.OutboxCommand, attempt int, err error) bool {
+	now := time.Now()
+
+	if err == nil {
+		if updateErr := a.repository.UpdateCommandFields(ctx, command.ID, map[string]interface{}{
+			"status":       models.OutboxStatusDone,
+			"attempts":     attempt,
+			"last_error":   nil,
+			"completed_at": now,
+			"updated_at":   now,
+		}); updateErr != nil {
+			// Команда выполнена, но останется в очереди и будет выполнена повторно; повтор записи безопасен
+			logger.Printf("ModbusAdapter: ошибка отметки выполнения команды - command_id: %s, error: %v", command.ID, updateErr)
+			return false
+		}
+		if attempt > 1 {
+			logger.Printf("ModbusAdapter: команда выполнена с %d попытки - command_id: %s, box_id: %s, operation: %s",
+				attempt, command.ID, command.BoxID, command.Operation)
+		}
+		return true
+	}
+
+	lastError := err.Error()
+	updates := map[string]interface{}{
+		"attempts":        attempt,
+		"next_attempt_at": now.Add(outboxBackoff(attempt)),
+		"last_error":      lastError,
+		"updated_at":      now,
+	}
+
+	command.Attempts = attempt
+	command.LastError = &lastError
+	if command.AlertedAt == nil && now.After(command.Deadline) {
+		a.raiseAlert(ctx, command, false)
+		updates["alerted_at"] = now
+	}
+
+	if updateErr := a.repository.UpdateCommandFields(ctx, command.ID, updates); updateErr != nil {
+		logger.Printf("ModbusAdapter: ошибка сохранения попытки команды - command_id: %s, error: %v", command.ID, updateErr)
+	}
+
+	logger.Printf("ModbusAdapter: попытка %d команды неудачна - command_id: %s, box_id: %s, operation: %s, error: %v",
+		attempt, command.ID, command.BoxID, command.Operation, err)
+	return false
+}
+
+// expireCommand снимает с повтора команду, срок жизни которой истек
+func (a *ModbusAdapter) expireCommand(ctx context.Context, command *models.OutboxCommand, now time.Time) {
+	logger.Printf("ModbusAdapter: команда не выполнена до истечения срока жизни - command_id: %s, box_id: %s, operation: %s, attempts: %d",
+		command.ID, command.BoxID, command.Operation, command.Attempts)
+
+	if err := a.repository.UpdateCommandFields(ctx, command.ID, map[string]interface{}{
+		"status":     models.OutboxStatusExpired,
+		"alerted_at": now,
+		"updated_at": now,
+	}); err != nil {
+		logger.Printf("ModbusAdapter: ошибка снятия команды с повтора - command_id: %s, error: %v", command.ID, err)
+		return
+	}
+
+	a.raiseAlert(ctx, command, true)
+}
+
+// raiseAlert сообщает о команде, не выполненной в срок: в лог, метрики и поток событий администратора
+func (a *ModbusAdapter) raiseAlert(ctx context.Context, command *models.OutboxCommand, expired bool) {
+	lastError := ""
+	if command.LastError != nil {
+		lastError = *command.LastError
+	}
+
+	boxNumber := 0
+	var siteID uuid.UUID
+	if box, err := a.repository.GetWashBoxByID(ctx, command.BoxID); err == nil {
+		boxNumber = box.Number
+		siteID = box.SiteID
+	}
+
+	logger.WithFields(logrus.Fields{
+		"command_id": command.ID,
+		"box_id":     command.BoxID,
+		"box_number": boxNumber,
+		"operation":  command.Operation,
+		"attempts":   command.Attempts,
+		"expired":    expired,
+		"error":      lastError,
+	}).Error("ModbusAdapter: команда Modbus не выполнена в срок")
+
+	if a.metrics != nil {
+		errorCode := "deadline"
+		if expired {
+			errorCode = "expired"
+		}
+		a.metrics.RecordError("modbus_command", "modbus", errorCode)
+	}
+
+	if a.publisher != nil {
+		a.publisher.Publish(realtimeModels.Event{
+			Type:     realtimeModels.EventTypeModbusAlert,
+			Channels: []string{realtimeModels.ChannelAdmin},
+			SiteID:   siteID,
+			Data: realtimeModels.ModbusAlertEvent{
+				CommandID: command.ID,
+				BoxID:     command.BoxID,
+				BoxNumber: boxNumber,
+				SessionID: command.SessionID,
+				Operation: command.Operation,
+				Attempts:  command.Attempts,
+				LastError: lastError,
+				Expired:   expired,
+			},
+		})
+	}
+}
+
+// outboxBackoff пауза перед следующей попыткой: 2с, 4с, 8с ... не больше минуты
+func outboxBackoff(attempt int) time.Duration {
+	backoff := outboxMinBackoff
+	for i := 1; i < attempt && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	return backoff
+}
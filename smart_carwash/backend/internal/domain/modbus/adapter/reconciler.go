@@ -0,0 +1,264 @@
+package adapter
+
+import (
+	"carwash_backend/internal/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	sessionModels "carwash_backend/internal/domain/session/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
+)
+
+// coilCheck проверка одного coil бокса: ожидаемое по состоянию сессий и фактическое значение
+type coilCheck struct {
+	channel  washboxModels.DeviceChannel
+	expected bool // Должно ли устройство быть включено
+}
+
+// ReadCoils читает фактическое состояние coils бокса на его устройстве
+func (a *ModbusAdapter) ReadCoils(ctx context.Context, boxID uuid.UUID, registers []string) (map[string]bool, error) {
+	return a.httpClient.ReadCoils(ctx, boxID, a.boxDevice(ctx, boxID), registers)
+}
+
+// ReconcileCoils сверяет фактическое состояние coils каналов боксов с состоянием сессий и исправляет расхождения
+// Расхождение исправляется, только если оно держится два прохода подряд: так сверка не спорит
+// с командой, которая в этот момент еще выполняется. Включенные coils, которые должны оставаться
+// включенными, раз в MODBUS_COIL_REFRESH_SECONDS подтверждаются повторной записью для сторожевого
+// таймера modbus сервера. Боксы на обслуживании не сверяются, ими администратор управляет вручную.
+// Вызывается планировщиком
+func (a *ModbusAdapter) ReconcileCoils(ctx context.Context) error {
+	boxes, err := a.repository.GetActiveBoxesWithModbusConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка получения боксов с modbus: %w", err)
+	}
+
+	activeSessions, err := a.repository.GetActiveSessionsByBox(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка получения активных сессий: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, box := range boxes {
+		if box.Status == washboxModels.StatusMaintenance {
+			continue
+		}
+
+		var session *sessionModels.Session
+		if s, ok := activeSessions[box.ID]; ok {
+			session = &s
+		}
+
+		checks := expectedCoils(&box, session)
+		for _, check := range checks {
+			seen[box.ID.String()+":"+check.channel.Role] = true
+		}
+		a.reconcileBox(ctx, &box, checks)
+	}
+
+	// Забываем расхождения и подтверждения боксов, которые больше не сверяются
+	a.driftMu.Lock()
+	for key := range a.drift {
+		if !seen[key] {
+			delete(a.drift, key)
+		}
+	}
+	for key := range a.refreshed {
+		if !seen[key] {
+			delete(a.refreshed, key)
+		}
+	}
+	a.driftMu.Unlock()
+
+	return nil
+}
+
+// expectedCoils определяет, в каком состоянии должны быть coils каналов бокса
+// Каналы режима session включены во время активной сессии (свет - и во время уборки), режима
+// chemistry - пока химия включена в активной сессии. Ручные каналы и каналы в holding registers не сверяются
+func expectedCoils(box *washboxModels.WashBox, session *sessionModels.Session) []coilCheck {
+	var checks []coilCheck
+
+	chemistryOn := session != nil && box.ChemistryEnabled &&
+		session.ChemistryStartedAt != nil && session.ChemistryEndedAt == nil
+
+	for _, channel := range box.Channels() {
+		if channel.ChannelType() != washboxModels.ChannelTypeCoil {
+			continue
+		}
+		switch channel.ChannelMode() {
+		case washboxModels.ChannelModeSession:
+			checks = append(checks, coilCheck{
+				channel:  channel,
+				expected: session != nil || (channel.Role == washboxModels.ChannelLight && box.Status == washboxModels.StatusCleaning),
+			})
+		case washboxModels.ChannelModeChemistry:
+			checks = append(checks, coilCheck{channel: channel, expected: chemistryOn})
+		}
+	}
+
+	return checks
+}
+
+// reconcileBox читает coils бокса одним запросом и сверяет каждый с ожидаемым состоянием
+func (a *ModbusAdapter) reconcileBox(ctx context.Context, box *washboxModels.WashBox, checks []coilCheck) {
+	if len(checks) == 0 {
+		return
+	}
+
+	registers := make([]string, 0, len(checks))
+	for _, check := range checks {
+		registers = append(registers, check.channel.Register)
+	}
+
+	values, err := a.ReadCoils(ctx, box.ID, registers)
+	if err != nil {
+		logger.Printf("ReconcileCoils: ошибка чтения coils - box_id: %s, registers: %v, error: %v", box.ID, registers, err)
+		return
+	}
+
+	for _, check := range checks {
+		value, ok := values[check.channel.Register]
+		if !ok {
+			logger.Printf("ReconcileCoils: устройство не вернуло значение - box_id: %s, register: %s", box.ID, check.channel.Register)
+			continue
+		}
+		actual := check.channel.CoilOn(value)
+
+		// Сохраненное состояние отражает фактическое, а не последнюю запись
+		if stored, err := a.repository.GetChannelStatus(ctx, box.ID, check.channel.Role); err == nil && (stored == nil || stored.On != actual) {
+			if err := a.repository.UpdateChannelStatus(ctx, box.ID, check.channel.Role, check.channel.Register, actual); err != nil {
+				logger.Printf("ReconcileCoils: ошибка обновления состояния канала - box_id: %s, channel: %s, error: %v", box.ID, check.channel.Role, err)
+			}
+		}
+
+		a.reconcileCoil(ctx, box, check, actual)
+	}
+
+	a.refreshCoils(ctx, box.ID, checks, values)
+}
+
+// refreshCoils подтверждает повторной записью coils бокса, которые включены и должны оставаться включенными
+// Без подтверждения сторожевой таймер modbus сервера выключит coil через SAFETY_MAX_ON_MINUTES.
+// Подтверждение ставится в очередь команд в транзакции, которая блокирует бокс и его активную сессию
+// и заново проверяет ожидаемое состояние: так оно не окажется в очереди после команды выключения
+func (a *ModbusAdapter) refreshCoils(ctx context.Context, boxID uuid.UUID, checks []coilCheck, values map[string]bool) {
+	if a.config.ModbusCoilRefreshSeconds <= 0 {
+		return
+	}
+
+	interval := time.Duration(a.config.ModbusCoilRefreshSeconds) * time.Second
+	now := time.Now()
+	due := make(map[string]bool)
+
+	a.driftMu.Lock()
+	for _, check := range checks {
+		key := boxID.String() + ":" + check.channel.Role
+		value, ok := values[check.channel.Register]
+		if !check.expected || !ok || !check.channel.CoilOn(value) {
+			delete(a.refreshed, key)
+			continue
+		}
+		// Первое подтверждение - через интервал после того, как сверка увидела coil включенным
+		last, seen := a.refreshed[key]
+		if !seen {
+			a.refreshed[key] = now
+			continue
+		}
+		if now.Sub(last) >= interval {
+			due[check.channel.Role] = true
+		}
+	}
+	a.driftMu.Unlock()
+
+	if len(due) == 0 {
+		return
+	}
+
+	var refreshed []string
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		box, session, err := a.repository.LockBoxState(ctx, tx, boxID)
+		if err != nil {
+			return err
+		}
+		if box.Status == washboxModels.StatusMaintenance {
+			return nil
+		}
+		for _, check := range expectedCoils(box, session) {
+			if !check.expected || !due[check.channel.Role] {
+				continue
+			}
+			command := a.channelCommand(boxID, nil, check.channel, true)
+			command.Operation = "refresh_" + command.Operation
+			if err := a.enqueueCommand(ctx, tx, command); err != nil {
+				return err
+			}
+			refreshed = append(refreshed, check.channel.Role)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Printf("ReconcileCoils: ошибка подтверждения включенных coils - box_id: %s, error: %v", boxID, err)
+		return
+	}
+
+	a.driftMu.Lock()
+	for _, role := range refreshed {
+		a.refreshed[boxID.String()+":"+role] = now
+	}
+	a.driftMu.Unlock()
+}
+
+// reconcileCoil исправляет расхождение coil, если оно замечено второй проход подряд
+// actual и check.expected - состояние устройства; в coil пишется значение с учетом инверсии канала
+func (a *ModbusAdapter) reconcileCoil(ctx context.Context, box *washboxModels.WashBox, check coilCheck, actual bool) {
+	role := check.channel.Role
+	key := box.ID.String() + ":" + role
+
+	a.driftMu.Lock()
+	if actual == check.expected {
+		delete(a.drift, key)
+		a.driftMu.Unlock()
+		return
+	}
+	confirmed := a.drift[key]
+	a.drift[key] = true
+	a.driftMu.Unlock()
+
+	if !confirmed {
+		logger.Printf("ReconcileCoils: замечено расхождение, проверим на следующем проходе - box_id: %s, box_number: %d, coil: %s, actual: %v, expected: %v",
+			box.ID, box.Number, role, actual, check.expected)
+		return
+	}
+
+	// Исправление идет через очередь команд бокса: прямая запись могла бы обогнать еще не выполненную команду
+	// и примениться в другом порядке. Пока команда на этот регистр в очереди, расхождение исправит она
+	pending, err := a.repository.HasPendingCommand(ctx, box.ID, check.channel.Register)
+	if err != nil {
+		logger.Printf("ReconcileCoils: ошибка проверки очереди команд - box_id: %s, channel: %s, error: %v", box.ID, role, err)
+		return
+	}
+	if pending {
+		logger.Printf("ReconcileCoils: расхождение ожидает команду из очереди - box_id: %s, box_number: %d, coil: %s, actual: %v, expected: %v",
+			box.ID, box.Number, role, actual, check.expected)
+		return
+	}
+
+	logger.Printf("ReconcileCoils: исправление расхождения - box_id: %s, box_number: %d, status: %s, coil: %s, actual: %v, expected: %v",
+		box.ID, box.Number, box.Status, role, actual, check.expected)
+
+	// Планировщик вызывает сверку без пользователя в контексте, поэтому в журнал бокса исправление попадает как system
+	command := a.channelCommand(box.ID, nil, check.channel, check.expected)
+	command.Operation = "reconcile_" + command.Operation
+	if err := a.enqueueCommand(ctx, nil, command); err != nil {
+		logger.Printf("ReconcileCoils: ошибка исправления coil - box_id: %s, channel: %s, error: %v", box.ID, role, err)
+		return
+	}
+
+	a.driftMu.Lock()
+	delete(a.drift, key)
+	a.driftMu.Unlock()
+}
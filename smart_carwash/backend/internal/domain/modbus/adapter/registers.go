@@ -0,0 +1,131 @@
+package adapter
+
+import (
+	"carwash_backend/internal/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"carwash_backend/internal/domain/modbus/client"
+	"carwash_backend/internal/domain/modbus/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
+)
+
+// EnqueueSessionRegisters ставит в очередь запись holding registers бокса при изменении сессии
+// Активная сессия: countdown получает оставшееся время в секундах, остальные параметры - ActiveValue.
+// Без сессии: countdown - 0, остальные параметры - IdleValue. Параметры без значения не записываются
+func (a *ModbusAdapter) EnqueueSessionRegisters(ctx context.Context, tx *gorm.DB, boxID, sessionID uuid.UUID, remainingSeconds int, active bool) error {
+	box, err := a.repository.GetWashBoxByID(ctx, boxID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения бокса для записи регистров: %w", err)
+	}
+
+	for _, register := range box.ModbusRegisters {
+		var value *float64
+		switch {
+		case register.Parameter == washboxModels.RegisterCountdown:
+			seconds := 0.0
+			if active && remainingSeconds > 0 {
+				seconds = float64(remainingSeconds)
+			}
+			value = &seconds
+		case active:
+			value = register.ActiveValue
+		default:
+			value = register.IdleValue
+		}
+		if value == nil {
+			continue
+		}
+
+		if err := a.enqueueRegister(ctx, tx, boxID, &sessionID, register, *value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueueRegister записывает команду записи holding register в очередь, выполнит ее диспетчер
+func (a *ModbusAdapter) enqueueRegister(ctx context.Context, tx *gorm.DB, boxID uuid.UUID, sessionID *uuid.UUID, register washboxModels.BoxRegister, value float64) error {
+	now := time.Now()
+	rawValue := register.RawValue(value)
+	command := &models.OutboxCommand{
+		ID:            uuid.New(),
+		BoxID:         boxID,
+		SessionID:     sessionID,
+		CommandType:   models.OutboxCommandRegister,
+		Operation:     models.RegisterOperation(register.Parameter),
+		Register:      register.Register,
+		DataType:      optionalString(register.DataType),
+		WordOrder:     optionalString(register.WordOrder),
+		NumericValue:  &rawValue,
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: now,
+		Deadline:      now.Add(time.Duration(a.config.ModbusCommandDeadlineSeconds) * time.Second),
+		ExpiresAt:     now.Add(time.Duration(a.config.ModbusCommandTTLMinutes) * time.Minute),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := a.repository.EnqueueCommand(ctx, tx, command); err != nil {
+		return fmt.Errorf("ошибка постановки команды Modbus в очередь: %w", err)
+	}
+
+	logger.Printf("ModbusAdapter: команда поставлена в очередь - command_id: %s, box_id: %s, operation: %s, register: %s, value: %v",
+		command.ID, boxID, command.Operation, register.Register, rawValue)
+	return nil
+}
+
+// applyRegister записывает значение в holding register и сохраняет операцию
+// commandID и attempt заполняются, когда запись - попытка выполнения команды из очереди
+func (a *ModbusAdapter) applyRegister(ctx context.Context, boxID uuid.UUID, operation string, format client.RegisterFormat, value float64, commandID *uuid.UUID, attempt int) error {
+	err := a.httpClient.WriteRegister(ctx, boxID, a.boxDevice(ctx, boxID), format, value)
+
+	modbusOp := &models.ModbusOperation{
+		ID:           uuid.New(),
+		BoxID:        boxID,
+		Operation:    operation,
+		Register:     format.Register,
+		Success:      err == nil,
+		CommandID:    commandID,
+		Attempt:      attempt,
+		NumericValue: &value,
+		CreatedAt:    time.Now(),
+	}
+	if err != nil {
+		modbusOp.Error = err.Error()
+	}
+
+	if saveErr := a.repository.SaveModbusOperation(ctx, modbusOp); saveErr != nil {
+		logger.Printf("Ошибка сохранения операции %s - box_id: %s, error: %v", operation, boxID, saveErr)
+	}
+	return err
+}
+
+// commandRegisterFormat формат holding register команды из очереди
+func commandRegisterFormat(command *models.OutboxCommand) client.RegisterFormat {
+	format := client.RegisterFormat{Register: command.Register}
+	if command.DataType != nil {
+		format.DataType = *command.DataType
+	}
+	if command.WordOrder != nil {
+		format.WordOrder = *command.WordOrder
+	}
+	return format
+}
+
+// channelRegisterFormat формат holding register канала устройства: значения включения - uint16
+func channelRegisterFormat(channel washboxModels.DeviceChannel) client.RegisterFormat {
+	return client.RegisterFormat{Register: channel.Register, DataType: washboxModels.RegisterTypeUint16}
+}
+
+// optionalString возвращает nil для пустой строки
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,45 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"carwash_backend/internal/domain/modbus/client"
+	"carwash_backend/internal/domain/modbus/models"
+)
+
+// SyncPresenceSensors передает modbus серверу датчики присутствия всех боксов и возвращает их состояние
+// Modbus сервер не хранит список датчиков, поэтому он передается целиком при каждом вызове: после
+// перезапуска сервера опрос возобновится со следующей синхронизации. Вызывается планировщиком
+func (a *ModbusAdapter) SyncPresenceSensors(ctx context.Context) ([]models.SensorState, error) {
+	boxes, err := a.repository.GetBoxesWithPresenceSensor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения боксов с датчиками присутствия: %w", err)
+	}
+
+	sensors := make([]client.Sensor, 0, len(boxes))
+	for _, box := range boxes {
+		sensor := client.Sensor{BoxID: box.ID, Register: *box.PresenceSensorRegister}
+		if box.ModbusDevice != nil {
+			sensor.Device = *box.ModbusDevice
+		}
+		sensors = append(sensors, sensor)
+	}
+
+	states, err := a.httpClient.SetSensors(ctx, sensors)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.SensorState, 0, len(states))
+	for _, state := range states {
+		result = append(result, models.SensorState{
+			BoxID:     state.BoxID,
+			Register:  state.Register,
+			Value:     state.Value,
+			ChangedAt: state.ChangedAt,
+			Error:     state.Error,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"carwash_backend/internal/config"
+)
+
+// Заголовки аутентификации на modbus сервере
+const (
+	headerAPIKey    = "X-API-Key"
+	headerClient    = "X-Modbus-Client"
+	headerTimestamp = "X-Modbus-Timestamp"
+	headerNonce     = "X-Modbus-Nonce"
+	headerSignature = "X-Modbus-Signature"
+)
+
+// authenticate добавляет к запросу API ключ или подпись HMAC по MODBUS_SERVER_AUTH_MODE
+// Подписываются метод, путь с query, время подписи, nonce и sha256 тела - так же, как проверяет modbus сервер
+func (c *ModbusHTTPClient) authenticate(req *http.Request, body []byte) {
+	switch c.authMode {
+	case "api_key":
+		req.Header.Set(headerAPIKey, c.secret)
+	case "hmac":
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		nonce := uuid.NewString()
+
+		req.Header.Set(headerClient, c.clientID)
+		req.Header.Set(headerTimestamp, timestamp)
+		req.Header.Set(headerNonce, nonce)
+		req.Header.Set(headerSignature, signRequest(c.secret, req.Method, req.URL.RequestURI(), timestamp, nonce, body))
+	}
+}
+
+// signRequest возвращает hex HMAC-SHA256 подпись запроса секретом клиента
+func signRequest(secret, method, requestURI, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	payload := method + "\n" + requestURI + "\n" + timestamp + "\n" + nonce + "\n" + hex.EncodeToString(bodyHash[:])
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newTLSConfig настройки TLS до modbus сервера: CA для проверки сервера и сертификат клиента для mTLS
+func newTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.ModbusServerCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.ModbusServerCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать MODBUS_SERVER_CA_FILE: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("в MODBUS_SERVER_CA_FILE нет сертификатов")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ModbusClientCertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.ModbusClientCertFile, cfg.ModbusClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось загрузить сертификат клиента modbus сервера: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
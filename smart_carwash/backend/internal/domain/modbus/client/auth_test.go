@@ -0,0 +1,85 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Тестовый вектор подписи; тот же вектор проверяет modbus сервер (internal/security/guard_test.go)
+const (
+	testSignSecret    = "test-secret"
+	testSignMethod    = http.MethodPost
+	testSignURI       = "/api/v1/modbus/coil?device=plc1"
+	testSignTimestamp = "1767960000"
+	testSignNonce     = "7d6f0a3e-2f1b-4c2a-9d51-0b6c1f4e8a11"
+	testSignBody      = `{"box_id":"4f1c2a9e-8b3d-4e6f-9a1b-2c3d4e5f6a7b","register":"0x0010","value":true}`
+	testSignSignature = "99f96e3e20418c7c2e56679fdf71f12ea7b8eb1d216d618b5c74a2c6e7c5417d"
+)
+
+func TestSignRequest(t *testing.T) {
+	got := signRequest(testSignSecret, testSignMethod, testSignURI, testSignTimestamp, testSignNonce, []byte(testSignBody))
+	if got != testSignSignature {
+		t.Errorf("signRequest() = %s, want %s", got, testSignSignature)
+	}
+
+	if other := signRequest("other-secret", testSignMethod, testSignURI, testSignTimestamp, testSignNonce, []byte(testSignBody)); other == testSignSignature {
+		t.Error("signRequest() with another secret returned the same signature")
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name     string
+		authMode string
+		headers  []string
+	}{
+		{name: "No authentication", authMode: "none"},
+		{name: "API key", authMode: "api_key", headers: []string{headerAPIKey}},
+		{name: "HMAC", authMode: "hmac", headers: []string{headerClient, headerTimestamp, headerNonce, headerSignature}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &ModbusHTTPClient{authMode: tt.authMode, clientID: "backend", secret: testSignSecret}
+			req, err := http.NewRequest(testSignMethod, "http://modbus:8081"+testSignURI, strings.NewReader(testSignBody))
+			if err != nil {
+				t.Fatalf("http.NewRequest() unexpected error: %v", err)
+			}
+
+			before := time.Now().Unix()
+			client.authenticate(req, []byte(testSignBody))
+
+			for _, header := range []string{headerAPIKey, headerClient, headerTimestamp, headerNonce, headerSignature} {
+				expected := false
+				for _, h := range tt.headers {
+					expected = expected || h == header
+				}
+				if got := req.Header.Get(header) != ""; got != expected {
+					t.Errorf("header %s set = %v, want %v", header, got, expected)
+				}
+			}
+
+			switch tt.authMode {
+			case "api_key":
+				if req.Header.Get(headerAPIKey) != testSignSecret {
+					t.Errorf("header %s = %q, want secret", headerAPIKey, req.Header.Get(headerAPIKey))
+				}
+			case "hmac":
+				timestamp, err := strconv.ParseInt(req.Header.Get(headerTimestamp), 10, 64)
+				if err != nil || timestamp < before || timestamp > time.Now().Unix() {
+					t.Errorf("header %s = %q, want current unix time", headerTimestamp, req.Header.Get(headerTimestamp))
+				}
+				expected := signRequest(testSignSecret, testSignMethod, testSignURI, req.Header.Get(headerTimestamp), req.Header.Get(headerNonce), []byte(testSignBody))
+				if req.Header.Get(headerSignature) != expected {
+					t.Errorf("header %s = %s, want %s", headerSignature, req.Header.Get(headerSignature), expected)
+				}
+				if req.Header.Get(headerClient) != "backend" {
+					t.Errorf("header %s = %q, want backend", headerClient, req.Header.Get(headerClient))
+				}
+			}
+		})
+	}
+}
@@ -12,30 +12,60 @@ import (
 	"github.com/google/uuid"
 
 	"carwash_backend/internal/config"
+	"carwash_backend/internal/logger"
 )
 
 // ModbusHTTPClient HTTP клиент для взаимодействия с modbus сервером
 type ModbusHTTPClient struct {
 	baseURL    string
 	httpClient *http.Client
+
+	authMode string // none, api_key или hmac
+	clientID string
+	secret   string
+	initErr  error // Ошибка настройки TLS: запросы не отправляются без нужной защиты
 }
 
 // NewModbusHTTPClient создает новый HTTP клиент для modbus сервера
+// Если сертификаты TLS не загрузились, ошибка логируется и возвращается каждым запросом
 func NewModbusHTTPClient(config *config.Config) *ModbusHTTPClient {
-	return &ModbusHTTPClient{
+	client := &ModbusHTTPClient{
 		baseURL: fmt.Sprintf("http://%s:%d", config.ModbusServerHost, config.ModbusServerPort),
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second, // ✅ Уменьшил таймаут
 		},
+		authMode: config.ModbusServerAuthMode,
+		clientID: config.ModbusServerClientID,
+		secret:   config.ModbusServerSecret,
+	}
+
+	if config.ModbusServerTLS {
+		client.baseURL = fmt.Sprintf("https://%s:%d", config.ModbusServerHost, config.ModbusServerPort)
+		tlsConfig, err := newTLSConfig(config)
+		if err != nil {
+			logger.Printf("ModbusHTTPClient: ошибка настройки TLS, запросы к modbus серверу отключены: %v", err)
+			client.initErr = err
+			return client
+		}
+		client.httpClient.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}
 	}
+
+	return client
 }
 
 // WriteCoil записывает значение в coil Modbus устройства
-func (c *ModbusHTTPClient) WriteCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error {
+// device - имя ПЛК на modbus сервере, пустая строка - устройство по умолчанию.
+// inverted - coil канала с инверсией: таймер безопасности сервера взводится при записи false
+func (c *ModbusHTTPClient) WriteCoil(ctx context.Context, boxID uuid.UUID, device, register string, value, inverted bool) error {
 	req := WriteCoilRequest{
 		BoxID:    boxID,
+		Device:   device,
 		Register: register,
 		Value:    value,
+		Inverted: inverted,
 	}
 
 	var resp WriteCoilResponse
@@ -50,12 +80,35 @@ func (c *ModbusHTTPClient) WriteCoil(ctx context.Context, boxID uuid.UUID, regis
 	return nil
 }
 
+// WriteCoils записывает несколько coils одного устройства за один запрос
+// Результаты возвращаются по каждому coil и при ошибке записи, чтобы вызывающий видел, что осталось записанным
+func (c *ModbusHTTPClient) WriteCoils(ctx context.Context, boxID uuid.UUID, device string, coils []CoilWrite) ([]CoilWriteResult, error) {
+	req := WriteCoilsRequest{
+		BoxID:  boxID,
+		Device: device,
+		Coils:  coils,
+	}
+
+	var resp WriteCoilsResponse
+	if err := c.makeRequest(ctx, "POST", "/api/v1/modbus/coils", req, &resp); err != nil {
+		return nil, fmt.Errorf("ошибка HTTP запроса: %w", err)
+	}
+
+	if !resp.Success {
+		return resp.Results, fmt.Errorf("ошибка Modbus: %s", resp.Message)
+	}
+
+	return resp.Results, nil
+}
+
 // WriteLightCoil включает или выключает свет для бокса
-func (c *ModbusHTTPClient) WriteLightCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error {
+func (c *ModbusHTTPClient) WriteLightCoil(ctx context.Context, boxID uuid.UUID, device, register string, value, inverted bool) error {
 	req := WriteLightCoilRequest{
 		BoxID:    boxID,
+		Device:   device,
 		Register: register,
 		Value:    value,
+		Inverted: inverted,
 	}
 
 	var resp WriteLightCoilResponse
@@ -71,11 +124,13 @@ func (c *ModbusHTTPClient) WriteLightCoil(ctx context.Context, boxID uuid.UUID,
 }
 
 // WriteChemistryCoil включает или выключает химию для бокса
-func (c *ModbusHTTPClient) WriteChemistryCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error {
+func (c *ModbusHTTPClient) WriteChemistryCoil(ctx context.Context, boxID uuid.UUID, device, register string, value, inverted bool) error {
 	req := WriteChemistryCoilRequest{
 		BoxID:    boxID,
+		Device:   device,
 		Register: register,
 		Value:    value,
+		Inverted: inverted,
 	}
 
 	var resp WriteChemistryCoilResponse
@@ -91,9 +146,10 @@ func (c *ModbusHTTPClient) WriteChemistryCoil(ctx context.Context, boxID uuid.UU
 }
 
 // TestCoil тестирует запись в конкретный регистр
-func (c *ModbusHTTPClient) TestCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) (*TestCoilResponse, error) {
+func (c *ModbusHTTPClient) TestCoil(ctx context.Context, boxID uuid.UUID, device, register string, value bool) (*TestCoilResponse, error) {
 	req := TestCoilRequest{
 		BoxID:    boxID,
+		Device:   device,
 		Register: register,
 		Value:    value,
 	}
@@ -106,8 +162,89 @@ func (c *ModbusHTTPClient) TestCoil(ctx context.Context, boxID uuid.UUID, regist
 	return &resp, nil
 }
 
+// ReadCoils читает фактическое состояние coils устройства
+func (c *ModbusHTTPClient) ReadCoils(ctx context.Context, boxID uuid.UUID, device string, registers []string) (map[string]bool, error) {
+	req := ReadCoilsRequest{
+		BoxID:     boxID,
+		Device:    device,
+		Registers: registers,
+	}
+
+	var resp ReadCoilsResponse
+	if err := c.makeRequest(ctx, "POST", "/api/v1/modbus/read-coils", req, &resp); err != nil {
+		return nil, fmt.Errorf("ошибка HTTP запроса: %w", err)
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("ошибка Modbus: %s", resp.Message)
+	}
+
+	return resp.Values, nil
+}
+
+// WriteRegister записывает значение в holding register устройства
+func (c *ModbusHTTPClient) WriteRegister(ctx context.Context, boxID uuid.UUID, device string, format RegisterFormat, value float64) error {
+	req := WriteRegisterRequest{
+		BoxID:          boxID,
+		Device:         device,
+		RegisterFormat: format,
+		Value:          value,
+	}
+
+	var resp WriteRegisterResponse
+	if err := c.makeRequest(ctx, "POST", "/api/v1/modbus/register", req, &resp); err != nil {
+		return fmt.Errorf("ошибка HTTP запроса: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("ошибка Modbus: %s", resp.Message)
+	}
+
+	return nil
+}
+
+// ReadRegisters читает значения holding registers устройства
+func (c *ModbusHTTPClient) ReadRegisters(ctx context.Context, boxID uuid.UUID, device string, registers []RegisterFormat) (map[string]float64, error) {
+	req := ReadRegistersRequest{
+		BoxID:     boxID,
+		Device:    device,
+		Registers: registers,
+	}
+
+	var resp ReadRegistersResponse
+	if err := c.makeRequest(ctx, "POST", "/api/v1/modbus/read-registers", req, &resp); err != nil {
+		return nil, fmt.Errorf("ошибка HTTP запроса: %w", err)
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("ошибка Modbus: %s", resp.Message)
+	}
+
+	return resp.Values, nil
+}
+
+// SetSensors передает modbus серверу полный список датчиков присутствия и возвращает их состояние
+func (c *ModbusHTTPClient) SetSensors(ctx context.Context, sensors []Sensor) ([]SensorState, error) {
+	req := SetSensorsRequest{Sensors: sensors}
+
+	var resp SensorsResponse
+	if err := c.makeRequest(ctx, "PUT", "/api/v1/modbus/sensors", req, &resp); err != nil {
+		return nil, fmt.Errorf("ошибка HTTP запроса: %w", err)
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("ошибка Modbus: %s", resp.Message)
+	}
+
+	return resp.Sensors, nil
+}
+
 // makeRequest выполняет HTTP запрос с контекстом
 func (c *ModbusHTTPClient) makeRequest(ctx context.Context, method, path string, requestBody interface{}, responseBody interface{}) error {
+	if c.initErr != nil {
+		return fmt.Errorf("клиент modbus сервера не настроен: %w", c.initErr)
+	}
+
 	// Сериализуем тело запроса
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
@@ -123,6 +260,7 @@ func (c *ModbusHTTPClient) makeRequest(ctx context.Context, method, path string,
 
 	// Устанавливаем заголовки
 	req.Header.Set("Content-Type", "application/json")
+	c.authenticate(req, jsonData)
 
 	// Выполняем запрос
 	resp, err := c.httpClient.Do(req)
@@ -1,12 +1,18 @@
 package client
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // WriteCoilRequest запрос на запись в coil
 type WriteCoilRequest struct {
 	BoxID    uuid.UUID `json:"box_id"`
+	Device   string    `json:"device,omitempty"`
 	Register string    `json:"register"`
 	Value    bool      `json:"value"`
+	Inverted bool      `json:"inverted,omitempty"` // Канал с инверсией: устройство включено при Value = false
 }
 
 // WriteCoilResponse ответ на запись в coil
@@ -18,8 +24,10 @@ type WriteCoilResponse struct {
 // WriteLightCoilRequest запрос на управление светом
 type WriteLightCoilRequest struct {
 	BoxID    uuid.UUID `json:"box_id"`
+	Device   string    `json:"device,omitempty"`
 	Register string    `json:"register"`
 	Value    bool      `json:"value"`
+	Inverted bool      `json:"inverted,omitempty"` // Канал с инверсией: устройство включено при Value = false
 }
 
 // WriteLightCoilResponse ответ на управление светом
@@ -31,8 +39,10 @@ type WriteLightCoilResponse struct {
 // WriteChemistryCoilRequest запрос на управление химией
 type WriteChemistryCoilRequest struct {
 	BoxID    uuid.UUID `json:"box_id"`
+	Device   string    `json:"device,omitempty"`
 	Register string    `json:"register"`
 	Value    bool      `json:"value"`
+	Inverted bool      `json:"inverted,omitempty"` // Канал с инверсией: устройство включено при Value = false
 }
 
 // WriteChemistryCoilResponse ответ на управление химией
@@ -44,6 +54,7 @@ type WriteChemistryCoilResponse struct {
 // TestCoilRequest запрос на тестирование coil
 type TestCoilRequest struct {
 	BoxID    uuid.UUID `json:"box_id"`
+	Device   string    `json:"device,omitempty"`
 	Register string    `json:"register"`
 	Value    bool      `json:"value"`
 }
@@ -53,3 +64,112 @@ type TestCoilResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 }
+
+// ReadCoilsRequest запрос на чтение фактического состояния coils
+type ReadCoilsRequest struct {
+	BoxID     uuid.UUID `json:"box_id"`
+	Device    string    `json:"device,omitempty"`
+	Registers []string  `json:"registers"`
+}
+
+// ReadCoilsResponse ответ с состоянием coils по регистрам
+type ReadCoilsResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Values  map[string]bool `json:"values"`
+}
+
+// CoilWrite значение одного coil в пакетной записи
+type CoilWrite struct {
+	Register string `json:"register"`
+	Value    bool   `json:"value"`
+	Inverted bool   `json:"inverted,omitempty"` // Канал с инверсией: устройство включено при Value = false
+}
+
+// WriteCoilsRequest запрос на пакетную запись coils
+type WriteCoilsRequest struct {
+	BoxID  uuid.UUID   `json:"box_id"`
+	Device string      `json:"device,omitempty"`
+	Coils  []CoilWrite `json:"coils"`
+}
+
+// CoilWriteResult результат записи одного coil из пакета
+type CoilWriteResult struct {
+	Register   string `json:"register"`
+	Value      bool   `json:"value"`
+	Success    bool   `json:"success"`
+	RolledBack bool   `json:"rolled_back"`
+	Error      string `json:"error"`
+}
+
+// WriteCoilsResponse ответ на пакетную запись coils
+type WriteCoilsResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Mode    string            `json:"mode"`
+	Results []CoilWriteResult `json:"results"`
+}
+
+// RegisterFormat формат значения holding register
+type RegisterFormat struct {
+	Register  string `json:"register"`
+	DataType  string `json:"data_type,omitempty"`
+	WordOrder string `json:"word_order,omitempty"`
+}
+
+// WriteRegisterRequest запрос на запись значения в holding register
+type WriteRegisterRequest struct {
+	BoxID  uuid.UUID `json:"box_id"`
+	Device string    `json:"device,omitempty"`
+	RegisterFormat
+	Value float64 `json:"value"`
+}
+
+// WriteRegisterResponse ответ на запись в holding register
+type WriteRegisterResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// ReadRegistersRequest запрос на чтение holding registers
+type ReadRegistersRequest struct {
+	BoxID     uuid.UUID        `json:"box_id"`
+	Device    string           `json:"device,omitempty"`
+	Registers []RegisterFormat `json:"registers"`
+}
+
+// ReadRegistersResponse ответ со значениями holding registers по регистрам
+type ReadRegistersResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Values  map[string]float64 `json:"values"`
+}
+
+// Sensor датчик присутствия автомобиля - дискретный вход устройства
+type Sensor struct {
+	BoxID    uuid.UUID `json:"box_id"`
+	Device   string    `json:"device,omitempty"`
+	Register string    `json:"register"`
+}
+
+// SetSensorsRequest полный список датчиков, которые опрашивает modbus сервер
+type SetSensorsRequest struct {
+	Sensors []Sensor `json:"sensors"`
+}
+
+// SensorState состояние датчика на modbus сервере
+type SensorState struct {
+	BoxID     uuid.UUID  `json:"box_id"`
+	Device    string     `json:"device"`
+	Register  string     `json:"register"`
+	Value     *bool      `json:"value,omitempty"`
+	ChangedAt *time.Time `json:"changed_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// SensorsResponse ответ со состоянием датчиков
+type SensorsResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message"`
+	Sensors []SensorState `json:"sensors"`
+}
@@ -131,3 +131,99 @@ func (h *Handler) GetHistory(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// GetOutbox получает команды очереди Modbus
+func (h *Handler) GetOutbox(c *gin.Context) {
+	var req models.GetOutboxRequest
+
+	if boxIDStr := c.Query("box_id"); boxIDStr != "" {
+		boxID, err := uuid.Parse(boxIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат box_id"})
+			return
+		}
+		req.BoxID = &boxID
+	}
+
+	if status := c.Query("status"); status != "" {
+		req.Status = &status
+	}
+
+	req.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	req.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	response, err := h.modbusService.GetOutbox(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// WriteBoxRegister записывает параметр бокса в holding register
+func (h *Handler) WriteBoxRegister(c *gin.Context) {
+	var req models.WriteBoxRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	// Прокидываем роль в контекст сервиса (если есть)
+	ctx := c.Request.Context()
+	if roleAny, ok := c.Get("role"); ok {
+		if role, _ := roleAny.(string); role != "" {
+			ctx = context.WithValue(ctx, "role", role)
+		}
+	}
+
+	response, err := h.modbusService.WriteBoxRegister(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// WriteBoxChannel включает или выключает канал устройства бокса
+func (h *Handler) WriteBoxChannel(c *gin.Context) {
+	var req models.WriteBoxChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	// Прокидываем роль в контекст сервиса (если есть)
+	ctx := c.Request.Context()
+	if roleAny, ok := c.Get("role"); ok {
+		if role, _ := roleAny.(string); role != "" {
+			ctx = context.WithValue(ctx, "role", role)
+		}
+	}
+
+	response, err := h.modbusService.WriteBoxChannel(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBoxRegisters получает текущие значения holding registers бокса
+func (h *Handler) GetBoxRegisters(c *gin.Context) {
+	boxID, err := uuid.Parse(c.Query("box_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат box_id"})
+		return
+	}
+
+	response, err := h.modbusService.GetBoxRegisters(c.Request.Context(), boxID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -12,9 +12,13 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 		// Тестирование и статус
 		adminModbus.POST("/test-coil", h.TestCoil)
 		adminModbus.GET("/status", h.GetStatus)
+		adminModbus.POST("/register", h.WriteBoxRegister)
+		adminModbus.GET("/registers", h.GetBoxRegisters)
+		adminModbus.POST("/channel", h.WriteBoxChannel)
 		
 		// Мониторинг и дашборд
 		adminModbus.GET("/dashboard", h.GetDashboard)
 		adminModbus.GET("/history", h.GetHistory)
+		adminModbus.GET("/outbox", h.GetOutbox)
 	}
 } 
\ No newline at end of file
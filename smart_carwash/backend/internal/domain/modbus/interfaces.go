@@ -4,13 +4,26 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"carwash_backend/internal/domain/modbus/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
 )
 
 // ModbusServiceInterface интерфейс для работы с Modbus
 type ModbusServiceInterface interface {
 	WriteCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error
-	WriteLightCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error
-	WriteChemistryCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error
+	// WriteChannel сразу включает или выключает канал устройства бокса (свет, химия, ворота, пылесос и т.п.)
+	WriteChannel(ctx context.Context, boxID uuid.UUID, channel washboxModels.DeviceChannel, on bool) error
+	// WriteCoils записывает несколько coils бокса одним запросом: подряд идущие регистры - одной
+	// Modbus транзакцией, иначе по порядку с откатом при ошибке; результат возвращается по каждому coil
+	WriteCoils(ctx context.Context, boxID uuid.UUID, coils []models.CoilWrite) ([]models.CoilWriteResult, error)
 	HandleModbusError(boxID uuid.UUID, operation string, sessionID uuid.UUID, err error) error
 	TestCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) error
+	// EnqueueChannel ставит включение или выключение канала в очередь команд; с транзакцией tx команда
+	// сохраняется атомарно с изменением сессии и выполняется диспетчером с повторами
+	EnqueueChannel(ctx context.Context, tx *gorm.DB, boxID uuid.UUID, sessionID *uuid.UUID, channel washboxModels.DeviceChannel, on bool) error
+	// EnqueueSessionRegisters ставит в очередь запись holding registers бокса (давление, пена, табло):
+	// при активной сессии - значения сессии и оставшееся время, без сессии - значения простоя
+	EnqueueSessionRegisters(ctx context.Context, tx *gorm.DB, boxID, sessionID uuid.UUID, remainingSeconds int, active bool) error
 }
@@ -10,11 +10,14 @@ import (
 type ModbusOperation struct {
 	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	BoxID     uuid.UUID `json:"box_id"`
-	Operation string    `json:"operation"` // "light_on", "light_off", "chemistry_on", "chemistry_off"
+	Operation string    `json:"operation"` // "light_on", "light_off", "chemistry_on", "chemistry_off", "set_<параметр>"
 	Register  string    `json:"register"`  // hex формат
 	Value     bool      `json:"value"`
 	Success   bool      `json:"success"`
 	Error     string    `json:"error,omitempty"`
+	CommandID *uuid.UUID `json:"command_id,omitempty"` // Команда очереди, попыткой которой является операция
+	Attempt   int       `json:"attempt,omitempty"`    // Номер попытки выполнения команды очереди
+	NumericValue *float64 `json:"numeric_value,omitempty"` // Значение, записанное в holding register (для coils - nil)
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -122,8 +125,47 @@ type ModbusBoxStatus struct {
 	LastError             string    `json:"last_error,omitempty"`
 	LightCoilRegister     *string   `json:"light_coil_register,omitempty"`
 	ChemistryCoilRegister *string   `json:"chemistry_coil_register,omitempty"`
+	ModbusDevice          *string   `json:"modbus_device,omitempty"` // Имя ПЛК на modbus сервере (nil - устройство по умолчанию)
 	LightStatus           *bool     `json:"light_status,omitempty"`     // Статус света: true - включен, false - выключен, nil - неизвестно
 	ChemistryStatus       *bool     `json:"chemistry_status,omitempty"` // Статус химии: true - включена, false - выключена, nil - неизвестно
+	Channels              []BoxChannelState `json:"channels"`           // Каналы устройств бокса и их состояние
+}
+
+// ModbusChannelStatus последнее известное состояние канала устройства бокса
+type ModbusChannelStatus struct {
+	BoxID     uuid.UUID `json:"box_id" gorm:"primaryKey;type:uuid"`
+	Role      string    `json:"role" gorm:"primaryKey"`
+	Register  string    `json:"register"`
+	On        bool      `json:"on" gorm:"column:on"` // Включено ли устройство (с учетом инверсии)
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName возвращает имя таблицы состояний каналов
+func (ModbusChannelStatus) TableName() string {
+	return "modbus_channel_statuses"
+}
+
+// BoxChannelState канал устройства бокса и его состояние
+type BoxChannelState struct {
+	Role      string     `json:"role"`
+	Register  string     `json:"register"`
+	Type      string     `json:"type"`
+	Mode      string     `json:"mode"`
+	On        *bool      `json:"on,omitempty"` // nil - состояние неизвестно
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// WriteBoxChannelRequest запрос на включение или выключение канала устройства бокса
+type WriteBoxChannelRequest struct {
+	BoxID uuid.UUID `json:"box_id" binding:"required"`
+	Role  string    `json:"role" binding:"required"` // Роль канала: light, chemistry, gate, foam_gun, wax, osmosis, vacuum
+	On    bool      `json:"on"`
+}
+
+// WriteBoxChannelResponse ответ на управление каналом бокса
+type WriteBoxChannelResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
 }
 
 // GetModbusDashboardRequest запрос на получение данных дашборда
@@ -153,3 +195,59 @@ type GetModbusHistoryResponse struct {
 	Limit      int               `json:"limit"`
 	Offset     int               `json:"offset"`
 }
+
+// CoilWrite значение одного coil в пакетной записи
+// CoilType (роль канала: light, chemistry, gate и т.п.) указывается, чтобы обновить состояние канала бокса;
+// пустой - произвольный coil. Для инвертированного канала устройство включено при Value = false
+type CoilWrite struct {
+	CoilType string `json:"coil_type,omitempty"`
+	Register string `json:"register"`
+	Value    bool   `json:"value"`
+}
+
+// CoilWriteResult результат записи одного coil из пакета
+type CoilWriteResult struct {
+	Register   string `json:"register"`
+	Value      bool   `json:"value"`
+	Success    bool   `json:"success"`               // Coil записан и остался в новом значении
+	RolledBack bool   `json:"rolled_back,omitempty"` // Coil был записан, но возвращен в прежнее значение
+	Error      string `json:"error,omitempty"`
+}
+
+// WriteBoxRegisterRequest запрос на запись параметра бокса в holding register
+type WriteBoxRegisterRequest struct {
+	BoxID     uuid.UUID `json:"box_id" binding:"required"`
+	Parameter string    `json:"parameter" binding:"required"` // Параметр из modbus_registers бокса
+	Value     float64   `json:"value"`                        // Значение в единицах параметра, множитель применяется при записи
+}
+
+// WriteBoxRegisterResponse ответ на запись параметра бокса
+type WriteBoxRegisterResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// BoxRegisterValue текущее значение параметра бокса
+type BoxRegisterValue struct {
+	Parameter string   `json:"parameter"`
+	Register  string   `json:"register"`
+	DataType  string   `json:"data_type"`
+	Value     *float64 `json:"value,omitempty"`     // Значение в единицах параметра (nil - не прочитано)
+	RawValue  *float64 `json:"raw_value,omitempty"` // Значение в регистре
+}
+
+// GetBoxRegistersResponse ответ со значениями holding registers бокса
+type GetBoxRegistersResponse struct {
+	BoxID     uuid.UUID          `json:"box_id"`
+	Registers []BoxRegisterValue `json:"registers"`
+	Error     string             `json:"error,omitempty"` // Ошибка чтения с устройства
+}
+
+// SensorState состояние датчика присутствия автомобиля по данным modbus сервера
+type SensorState struct {
+	BoxID     uuid.UUID  `json:"box_id"`
+	Register  string     `json:"register"`
+	Value     *bool      `json:"value,omitempty"`      // nil - датчик еще не прочитан
+	ChangedAt *time.Time `json:"changed_at,omitempty"` // Когда значение последний раз изменилось
+	Error     string     `json:"error,omitempty"`      // Ошибка последнего опроса
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы команд в очереди Modbus
+const (
+	OutboxStatusPending = "pending" // Ожидает выполнения или повтора
+	OutboxStatusDone    = "done"    // Выполнена
+	OutboxStatusExpired = "expired" // Не выполнена до истечения срока жизни
+)
+
+// Типы команд в очереди Modbus
+const (
+	OutboxCommandCoil     = "coil"     // Запись coil
+	OutboxCommandRegister = "register" // Запись holding register
+)
+
+// OutboxCommand команда записи coil или holding register в очереди Modbus
+// Команда записывается в одной транзакции с изменением сессии, поэтому не теряется при недоступности
+// modbus сервера; диспетчер выполняет команды каждого бокса строго в порядке Seq
+type OutboxCommand struct {
+	ID            uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Seq           int64      `json:"seq" gorm:"->"` // Заполняется базой (BIGSERIAL)
+	BoxID         uuid.UUID  `json:"box_id" gorm:"type:uuid"`
+	SessionID     *uuid.UUID `json:"session_id,omitempty" gorm:"type:uuid"`
+	CommandType   string     `json:"command_type"` // coil или register
+	Operation     string     `json:"operation"`    // "<роль>_on", "<роль>_off" (light_on, gate_off ...), "set_<параметр>"
+	CoilType      string     `json:"coil_type"`    // Роль канала; для параметра в holding register - пустой
+	Register      string     `json:"register"`
+	Value         bool       `json:"value"`
+	ChannelOn     *bool      `json:"channel_on,omitempty"`    // Включено ли устройство канала после выполнения (nil - не канал)
+	DataType      *string    `json:"data_type,omitempty"`     // Тип значения holding register
+	WordOrder     *string    `json:"word_order,omitempty"`    // Порядок слов float32
+	NumericValue  *float64   `json:"numeric_value,omitempty"` // Значение holding register с учетом множителя
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	Deadline      time.Time  `json:"deadline"`   // После этого времени по невыполненной команде поднимается тревога
+	ExpiresAt     time.Time  `json:"expires_at"` // После этого времени команда больше не повторяется
+	LastError     *string    `json:"last_error,omitempty"`
+	AlertedAt     *time.Time `json:"alerted_at,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName возвращает имя таблицы очереди
+func (OutboxCommand) TableName() string {
+	return "modbus_outbox"
+}
+
+// CoilOperation возвращает имя операции для записи значения в coil
+func CoilOperation(coilType string, value bool) string {
+	if value {
+		return coilType + "_on"
+	}
+	return coilType + "_off"
+}
+
+// RegisterOperation возвращает имя операции для записи параметра в holding register
+func RegisterOperation(parameter string) string {
+	return "set_" + parameter
+}
+
+// GetOutboxRequest запрос на получение команд очереди Modbus
+type GetOutboxRequest struct {
+	BoxID  *uuid.UUID `json:"box_id" form:"box_id"`
+	Status *string    `json:"status" form:"status"`
+	Limit  int        `json:"limit" form:"limit"`
+	Offset int        `json:"offset" form:"offset"`
+}
+
+// GetOutboxResponse ответ с командами очереди Modbus
+type GetOutboxResponse struct {
+	Commands []OutboxCommand `json:"commands"`
+	Total    int64           `json:"total"`
+	Limit    int             `json:"limit"`
+	Offset   int             `json:"offset"`
+}
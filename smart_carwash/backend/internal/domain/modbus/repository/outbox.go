@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"carwash_backend/internal/domain/modbus/models"
+)
+
+// EnqueueCommand добавляет команду в очередь Modbus
+// Если передана транзакция, команда записывается в ней вместе с изменением сессии
+func (r *ModbusRepository) EnqueueCommand(ctx context.Context, tx *gorm.DB, command *models.OutboxCommand) error {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	return db.WithContext(ctx).Create(command).Error
+}
+
+// GetPendingCommands получает невыполненные команды очереди в порядке постановки
+func (r *ModbusRepository) GetPendingCommands(ctx context.Context, limit int) ([]models.OutboxCommand, error) {
+	var commands []models.OutboxCommand
+	err := r.db.WithContext(ctx).
+		Where("status = ?", models.OutboxStatusPending).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&commands).Error
+	return commands, err
+}
+
+// HasPendingCommand проверяет, есть ли в очереди невыполненная команда на регистр бокса
+func (r *ModbusRepository) HasPendingCommand(ctx context.Context, boxID uuid.UUID, register string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.OutboxCommand{}).
+		Where("box_id = ? AND register = ? AND status = ?", boxID, register, models.OutboxStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateCommandFields обновляет поля команды очереди
+func (r *ModbusRepository) UpdateCommandFields(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&models.OutboxCommand{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// GetCommands получает команды очереди, новые первыми
+func (r *ModbusRepository) GetCommands(ctx context.Context, boxID *uuid.UUID, status *string, limit, offset int) ([]models.OutboxCommand, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.OutboxCommand{})
+	if boxID != nil {
+		query = query.Where("box_id = ?", *boxID)
+	}
+	if status != nil && *status != "" {
+		query = query.Where("status = ?", *status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var commands []models.OutboxCommand
+	err := query.Order("seq DESC").Limit(limit).Offset(offset).Find(&commands).Error
+	return commands, total, err
+}
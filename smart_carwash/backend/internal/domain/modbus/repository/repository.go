@@ -6,8 +6,10 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"carwash_backend/internal/domain/modbus/models"
+	sessionModels "carwash_backend/internal/domain/session/models"
 	washboxModels "carwash_backend/internal/domain/washbox/models"
 )
 
@@ -154,10 +156,56 @@ func (r *ModbusRepository) GetModbusErrorsByType(ctx context.Context, boxID *uui
 // GetActiveBoxesWithModbusConfig получает боксы с настроенным modbus
 func (r *ModbusRepository) GetActiveBoxesWithModbusConfig(ctx context.Context) ([]washboxModels.WashBox, error) {
 	var boxes []washboxModels.WashBox
-	err := r.db.WithContext(ctx).Where("(light_coil_register IS NOT NULL AND light_coil_register != '') OR (chemistry_coil_register IS NOT NULL AND chemistry_coil_register != '')").Find(&boxes).Error
+	err := r.db.WithContext(ctx).Where("(light_coil_register IS NOT NULL AND light_coil_register != '') OR (chemistry_coil_register IS NOT NULL AND chemistry_coil_register != '') OR device_channels != '[]'::jsonb").Find(&boxes).Error
 	return boxes, err
 }
 
+// GetBoxesWithPresenceSensor получает боксы с датчиком присутствия автомобиля
+func (r *ModbusRepository) GetBoxesWithPresenceSensor(ctx context.Context) ([]washboxModels.WashBox, error) {
+	var boxes []washboxModels.WashBox
+	err := r.db.WithContext(ctx).Where("presence_sensor_register IS NOT NULL AND presence_sensor_register != ''").Find(&boxes).Error
+	return boxes, err
+}
+
+// LockBoxState блокирует в транзакции бокс и его активную сессию и возвращает их текущее состояние
+// Сессия nil, если в боксе нет активной сессии
+func (r *ModbusRepository) LockBoxState(ctx context.Context, tx *gorm.DB, boxID uuid.UUID) (*washboxModels.WashBox, *sessionModels.Session, error) {
+	var box washboxModels.WashBox
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", boxID).First(&box).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var sessions []sessionModels.Session
+	err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("box_id = ? AND status = ?", boxID, sessionModels.SessionStatusActive).
+		Limit(1).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(sessions) == 0 {
+		return &box, nil, nil
+	}
+	return &box, &sessions[0], nil
+}
+
+// GetActiveSessionsByBox получает активные сессии, сгруппированные по боксу
+func (r *ModbusRepository) GetActiveSessionsByBox(ctx context.Context) (map[uuid.UUID]sessionModels.Session, error) {
+	var sessions []sessionModels.Session
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND box_id IS NOT NULL", sessionModels.SessionStatusActive).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID]sessionModels.Session, len(sessions))
+	for _, session := range sessions {
+		result[*session.BoxID] = session
+	}
+	return result, nil
+}
+
 // UpdateModbusConnectionStatus обновляет статус подключения для бокса
 func (r *ModbusRepository) UpdateModbusConnectionStatus(ctx context.Context, boxID uuid.UUID, connected bool, lastError string) error {
 	// Создаем или обновляем запись статуса
@@ -236,3 +284,44 @@ func (r *ModbusRepository) UpdateModbusCoilStatus(ctx context.Context, boxID uui
 
 	return err
 }
+
+// UpdateChannelStatus сохраняет состояние канала устройства бокса
+// Для света и химии обновляется и их статус в modbus_connection_statuses
+func (r *ModbusRepository) UpdateChannelStatus(ctx context.Context, boxID uuid.UUID, role, register string, on bool) error {
+	status := models.ModbusChannelStatus{
+		BoxID:     boxID,
+		Role:      role,
+		Register:  register,
+		On:        on,
+		UpdatedAt: time.Now(),
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "box_id"}, {Name: "role"}},
+		DoUpdates: clause.AssignmentColumns([]string{"register", "on", "updated_at"}),
+	}).Create(&status).Error
+	if err != nil {
+		return err
+	}
+
+	if role == "light" || role == "chemistry" {
+		return r.UpdateModbusCoilStatus(ctx, boxID, role, on)
+	}
+	return nil
+}
+
+// GetChannelStatus получает состояние канала бокса; nil - состояние неизвестно
+func (r *ModbusRepository) GetChannelStatus(ctx context.Context, boxID uuid.UUID, role string) (*models.ModbusChannelStatus, error) {
+	var statuses []models.ModbusChannelStatus
+	err := r.db.WithContext(ctx).Where("box_id = ? AND role = ?", boxID, role).Limit(1).Find(&statuses).Error
+	if err != nil || len(statuses) == 0 {
+		return nil, err
+	}
+	return &statuses[0], nil
+}
+
+// GetChannelStatuses получает состояния каналов всех боксов
+func (r *ModbusRepository) GetChannelStatuses(ctx context.Context) ([]models.ModbusChannelStatus, error) {
+	var statuses []models.ModbusChannelStatus
+	err := r.db.WithContext(ctx).Find(&statuses).Error
+	return statuses, err
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"carwash_backend/internal/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"carwash_backend/internal/domain/modbus/models"
+)
+
+// WriteBoxChannel вручную включает или выключает канал устройства бокса по роли (ворота, пылесос и т.п.)
+// Ручное управление не отменяет режим канала: канал сессии может быть переключен следующей сессией или сверкой
+func (s *ModbusService) WriteBoxChannel(ctx context.Context, req *models.WriteBoxChannelRequest) (*models.WriteBoxChannelResponse, error) {
+	logger.Printf("Управление каналом бокса - box_id: %s, role: %s, on: %v", req.BoxID, req.Role, req.On)
+
+	if s.channelWriter == nil {
+		return nil, errors.New("управление каналами бокса не настроено")
+	}
+
+	box, err := s.repository.GetWashBoxByID(ctx, req.BoxID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось найти бокс: %v", err)
+	}
+
+	if !maintenanceAllowed(ctx, box) {
+		return &models.WriteBoxChannelResponse{
+			Success: false,
+			Message: "доступ к управлению разрешен только для боксов в статусе 'maintenance'",
+		}, nil
+	}
+
+	channel, ok := box.FindChannel(req.Role)
+	if !ok {
+		return nil, fmt.Errorf("у бокса нет канала %s", req.Role)
+	}
+
+	if err := s.channelWriter.WriteChannel(ctx, req.BoxID, channel, req.On); err != nil {
+		logger.Printf("Ошибка управления каналом бокса - box_id: %s, role: %s, error: %v", req.BoxID, req.Role, err)
+		return &models.WriteBoxChannelResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	state := "выключен"
+	if req.On {
+		state = "включен"
+	}
+	return &models.WriteBoxChannelResponse{
+		Success: true,
+		Message: fmt.Sprintf("Канал %s (%s) %s", req.Role, channel.Register, state),
+	}, nil
+}
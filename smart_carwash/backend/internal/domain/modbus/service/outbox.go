@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"carwash_backend/internal/domain/modbus/models"
+)
+
+// GetOutbox получает команды очереди Modbus
+func (s *ModbusService) GetOutbox(ctx context.Context, req *models.GetOutboxRequest) (*models.GetOutboxResponse, error) {
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 50
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	commands, total, err := s.repository.GetCommands(ctx, req.BoxID, req.Status, req.Limit, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения очереди команд: %v", err)
+	}
+
+	return &models.GetOutboxResponse{
+		Commands: commands,
+		Total:    total,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"carwash_backend/internal/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"carwash_backend/internal/domain/modbus/client"
+	"carwash_backend/internal/domain/modbus/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
+)
+
+// WriteBoxRegister записывает параметр бокса (давление, концентрацию пены и т.п.) в holding register
+// Значение передается в единицах параметра и умножается на множитель из определения регистра
+func (s *ModbusService) WriteBoxRegister(ctx context.Context, req *models.WriteBoxRegisterRequest) (*models.WriteBoxRegisterResponse, error) {
+	logger.Printf("Запись параметра бокса через HTTP клиент - box_id: %s, parameter: %s, value: %v", req.BoxID, req.Parameter, req.Value)
+
+	box, err := s.repository.GetWashBoxByID(ctx, req.BoxID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось найти бокс: %v", err)
+	}
+
+	if !maintenanceAllowed(ctx, box) {
+		return &models.WriteBoxRegisterResponse{
+			Success: false,
+			Message: "доступ к управлению разрешен только для боксов в статусе 'maintenance'",
+		}, nil
+	}
+
+	register, ok := box.FindRegister(req.Parameter)
+	if !ok {
+		return nil, fmt.Errorf("у бокса нет регистра для параметра %s", req.Parameter)
+	}
+
+	rawValue := register.RawValue(req.Value)
+	err = s.httpClient.WriteRegister(ctx, req.BoxID, boxDevice(box), registerFormat(register), rawValue)
+
+	modbusOp := &models.ModbusOperation{
+		ID:           uuid.New(),
+		BoxID:        req.BoxID,
+		Operation:    models.RegisterOperation(req.Parameter),
+		Register:     register.Register,
+		Success:      err == nil,
+		NumericValue: &rawValue,
+		CreatedAt:    time.Now(),
+	}
+	if err != nil {
+		modbusOp.Error = err.Error()
+	}
+	if saveErr := s.repository.SaveModbusOperation(ctx, modbusOp); saveErr != nil {
+		logger.Printf("Ошибка сохранения операции WriteBoxRegister - box_id: %s, error: %v", req.BoxID, saveErr)
+	}
+
+	if err != nil {
+		logger.Printf("Ошибка записи параметра бокса - box_id: %s, parameter: %s, error: %v", req.BoxID, req.Parameter, err)
+		return &models.WriteBoxRegisterResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &models.WriteBoxRegisterResponse{
+		Success: true,
+		Message: fmt.Sprintf("Параметр %s записан в регистр %s", req.Parameter, register.Register),
+	}, nil
+}
+
+// GetBoxRegisters читает текущие значения holding registers бокса с устройства
+// Если устройство недоступно, возвращаются определения регистров без значений и текст ошибки
+func (s *ModbusService) GetBoxRegisters(ctx context.Context, boxID uuid.UUID) (*models.GetBoxRegistersResponse, error) {
+	box, err := s.repository.GetWashBoxByID(ctx, boxID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось найти бокс: %v", err)
+	}
+
+	resp := &models.GetBoxRegistersResponse{
+		BoxID:     boxID,
+		Registers: make([]models.BoxRegisterValue, 0, len(box.ModbusRegisters)),
+	}
+	if len(box.ModbusRegisters) == 0 {
+		return resp, nil
+	}
+
+	formats := make([]client.RegisterFormat, len(box.ModbusRegisters))
+	for i := range box.ModbusRegisters {
+		formats[i] = registerFormat(&box.ModbusRegisters[i])
+	}
+
+	values, readErr := s.httpClient.ReadRegisters(ctx, boxID, boxDevice(box), formats)
+	if readErr != nil {
+		resp.Error = readErr.Error()
+	}
+
+	for _, register := range box.ModbusRegisters {
+		item := models.BoxRegisterValue{
+			Parameter: register.Parameter,
+			Register:  register.Register,
+			DataType:  registerFormat(&register).DataType,
+		}
+		if raw, ok := values[register.Register]; ok {
+			value := raw
+			if register.Scale != nil {
+				value = raw / *register.Scale
+			}
+			item.RawValue = &raw
+			item.Value = &value
+		}
+		resp.Registers = append(resp.Registers, item)
+	}
+
+	return resp, nil
+}
+
+// registerFormat формат holding register для modbus сервера; тип по умолчанию - uint16
+func registerFormat(register *washboxModels.BoxRegister) client.RegisterFormat {
+	dataType := register.DataType
+	if dataType == "" {
+		dataType = washboxModels.RegisterTypeUint16
+	}
+	return client.RegisterFormat{
+		Register:  register.Register,
+		DataType:  dataType,
+		WordOrder: register.WordOrder,
+	}
+}
+
+// boxDevice возвращает имя ПЛК бокса (пустая строка - устройство по умолчанию)
+func boxDevice(box *washboxModels.WashBox) string {
+	if box.ModbusDevice == nil {
+		return ""
+	}
+	return *box.ModbusDevice
+}
+
+// maintenanceAllowed проверяет роль: ограниченный админ может управлять только боксом в статусе maintenance
+func maintenanceAllowed(ctx context.Context, box *washboxModels.WashBox) bool {
+	if role, ok := ctx.Value("role").(string); ok && role == "limited_admin" {
+		return box.Status == "maintenance"
+	}
+	return true
+}
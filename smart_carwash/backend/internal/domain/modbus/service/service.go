@@ -13,13 +13,21 @@ import (
 	"carwash_backend/internal/domain/modbus/client"
 	"carwash_backend/internal/domain/modbus/models"
 	"carwash_backend/internal/domain/modbus/repository"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
 )
 
+// ChannelWriter включает и выключает каналы устройств бокса с сохранением их состояния
+type ChannelWriter interface {
+	WriteChannel(ctx context.Context, boxID uuid.UUID, channel washboxModels.DeviceChannel, on bool) error
+}
+
 // ModbusService предоставляет методы для работы с Modbus через HTTP клиент и БД
 type ModbusService struct {
 	httpClient *client.ModbusHTTPClient
 	repository *repository.ModbusRepository
 	config     *config.Config
+
+	channelWriter ChannelWriter // Опциональное управление каналами бокса
 }
 
 // NewModbusService создает новый экземпляр ModbusService
@@ -31,6 +39,11 @@ func NewModbusService(db *gorm.DB, config *config.Config) *ModbusService {
 	}
 }
 
+// SetChannelWriter устанавливает управление каналами устройств бокса
+func (s *ModbusService) SetChannelWriter(writer ChannelWriter) {
+	s.channelWriter = writer
+}
+
 // TestCoil тестирует запись в конкретный регистр через HTTP клиент
 func (s *ModbusService) TestCoil(ctx context.Context, boxID uuid.UUID, register string, value bool) (*models.TestModbusCoilResponse, error) {
 	logger.Printf("Тест записи coil через HTTP клиент - box_id: %s, register: %s, value: %v", boxID, register, value)
@@ -57,17 +70,21 @@ func (s *ModbusService) TestCoil(ctx context.Context, boxID uuid.UUID, register
 	}
 
 	// Используем HTTP клиент для тестирования
-	resp, err := s.httpClient.TestCoil(ctx, boxID, register, value)
+	device := ""
+	if box.ModbusDevice != nil {
+		device = *box.ModbusDevice
+	}
+	resp, err := s.httpClient.TestCoil(ctx, boxID, device, register, value)
 
-	// Определяем тип операции по регистру
+	// Определяем тип операции по каналу бокса с этим регистром
 	operation := "test_coil"
-	coilType := ""
-	if box.LightCoilRegister != nil && *box.LightCoilRegister == register {
-		operation = "test_light"
-		coilType = "light"
-	} else if box.ChemistryCoilRegister != nil && *box.ChemistryCoilRegister == register {
-		operation = "test_chemistry"
-		coilType = "chemistry"
+	var channel *washboxModels.DeviceChannel
+	for _, ch := range box.Channels() {
+		if ch.ChannelType() == washboxModels.ChannelTypeCoil && ch.Register == register {
+			channel = &ch
+			operation = "test_" + ch.Role
+			break
+		}
 	}
 
 	// Сохраняем операцию в БД
@@ -105,10 +122,10 @@ func (s *ModbusService) TestCoil(ctx context.Context, boxID uuid.UUID, register
 		logger.Printf("Ошибка сохранения операции TestCoil - box_id: %s, error: %v", boxID, saveErr)
 	}
 
-	// Если операция успешна и это известный койл, обновляем его статус
-	if resp.Success && coilType != "" {
-		if updateErr := s.repository.UpdateModbusCoilStatus(ctx, boxID, coilType, value); updateErr != nil {
-			logger.Printf("Ошибка обновления статуса койла при тесте - box_id: %s, coil_type: %s, error: %v", boxID, coilType, updateErr)
+	// Если операция успешна и это coil канала, обновляем состояние канала с учетом инверсии
+	if resp.Success && channel != nil {
+		if updateErr := s.repository.UpdateChannelStatus(ctx, boxID, channel.Role, register, channel.CoilOn(value)); updateErr != nil {
+			logger.Printf("Ошибка обновления статуса койла при тесте - box_id: %s, coil_type: %s, error: %v", boxID, channel.Role, updateErr)
 		}
 	}
 
@@ -198,6 +215,20 @@ func (s *ModbusService) GetDashboard(ctx context.Context, timeRange string) (*mo
 		statusMap[connectionStatuses[i].BoxID] = &connectionStatuses[i]
 	}
 
+	// Получаем состояния каналов устройств
+	channelStatuses, err := s.repository.GetChannelStatuses(ctx)
+	if err != nil {
+		logger.Printf("Ошибка получения состояний каналов: %v", err)
+	}
+	channelStatusMap := make(map[uuid.UUID]map[string]*models.ModbusChannelStatus)
+	for i := range channelStatuses {
+		status := &channelStatuses[i]
+		if channelStatusMap[status.BoxID] == nil {
+			channelStatusMap[status.BoxID] = make(map[string]*models.ModbusChannelStatus)
+		}
+		channelStatusMap[status.BoxID][status.Role] = status
+	}
+
 	// Формируем обзор
 	overview := models.ModbusDashboardOverview{
 		TotalBoxes: len(boxes),
@@ -211,6 +242,8 @@ func (s *ModbusService) GetDashboard(ctx context.Context, timeRange string) (*mo
 			BoxNumber:             box.Number,
 			LightCoilRegister:     box.LightCoilRegister,
 			ChemistryCoilRegister: box.ChemistryCoilRegister,
+			ModbusDevice:          box.ModbusDevice,
+			Channels:              boxChannelStates(&box, channelStatusMap[box.ID]),
 		}
 
 		// Добавляем информацию о подключении и статусы койлов
@@ -333,3 +366,25 @@ func (s *ModbusService) GetHistory(ctx context.Context, req *models.GetModbusHis
 	logger.Printf("История Modbus получена из БД - операций: %d", len(operations))
 	return response, nil
 }
+
+// boxChannelStates каналы бокса с их последним известным состоянием
+func boxChannelStates(box *washboxModels.WashBox, statuses map[string]*models.ModbusChannelStatus) []models.BoxChannelState {
+	channels := box.Channels()
+	states := make([]models.BoxChannelState, 0, len(channels))
+	for _, channel := range channels {
+		state := models.BoxChannelState{
+			Role:     channel.Role,
+			Register: channel.Register,
+			Type:     channel.ChannelType(),
+			Mode:     channel.ChannelMode(),
+		}
+		if status, ok := statuses[channel.Role]; ok && status.Register == channel.Register {
+			on := status.On
+			updatedAt := status.UpdatedAt
+			state.On = &on
+			state.UpdatedAt = &updatedAt
+		}
+		states = append(states, state)
+	}
+	return states
+}
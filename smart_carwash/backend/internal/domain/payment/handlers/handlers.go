@@ -192,6 +192,13 @@ func (h *Handler) adminListPayments(c *gin.Context) {
 		}
 	}
 
+	siteID, err := middleware.SiteIDFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.SiteID = siteID
+
 	if status := c.Query("status"); status != "" {
 		req.Status = &status
 	}
@@ -235,8 +242,8 @@ func (h *Handler) adminListPayments(c *gin.Context) {
 	}
 
 	// Логируем запрос с мета-параметрами
-	logger.WithContext(c).Infof("Запрос списка платежей (админка): PaymentID=%v, SessionID=%v, UserID=%v, Status=%v, PaymentType=%v, PaymentMethod=%v, DateFrom=%v, DateTo=%v, Limit=%v, Offset=%v",
-		req.PaymentID, req.SessionID, req.UserID, req.Status, req.PaymentType, req.PaymentMethod, req.DateFrom, req.DateTo, req.Limit, req.Offset)
+	logger.WithContext(c).Infof("Запрос списка платежей (админка): PaymentID=%v, SessionID=%v, UserID=%v, SiteID=%v, Status=%v, PaymentType=%v, PaymentMethod=%v, DateFrom=%v, DateTo=%v, Limit=%v, Offset=%v",
+		req.PaymentID, req.SessionID, req.UserID, req.SiteID, req.Status, req.PaymentType, req.PaymentMethod, req.DateFrom, req.DateTo, req.Limit, req.Offset)
 
 	response, err := h.service.ListPayments(c.Request.Context(), &req)
 	if err != nil {
@@ -301,6 +308,13 @@ func (h *Handler) adminGetPaymentStatistics(c *gin.Context) {
 		req.UserID = &userID
 	}
 
+	siteID, err := middleware.SiteIDFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.SiteID = siteID
+
 	if dateFromStr := c.Query("date_from"); dateFromStr != "" {
 		dateFrom, err := time.Parse(time.RFC3339, dateFromStr)
 		if err != nil {
@@ -323,8 +337,8 @@ func (h *Handler) adminGetPaymentStatistics(c *gin.Context) {
 		req.ServiceType = &serviceType
 	}
 
-	logger.WithContext(c).Infof("Admin payment statistics request: user_id=%v, date_from=%v, date_to=%v, service_type=%v",
-		req.UserID, req.DateFrom, req.DateTo, req.ServiceType)
+	logger.WithContext(c).Infof("Admin payment statistics request: user_id=%v, site_id=%v, date_from=%v, date_to=%v, service_type=%v",
+		req.UserID, req.SiteID, req.DateFrom, req.DateTo, req.ServiceType)
 
 	response, err := h.service.GetPaymentStatistics(c.Request.Context(), &req)
 	if err != nil {
@@ -370,6 +384,7 @@ func (h *Handler) cashierListPayments(c *gin.Context) {
 	// Создаем запрос
 	req := &models.CashierPaymentsRequest{
 		ShiftStartedAt: shiftStartedAt,
+		SiteID:         middleware.SiteIDFromContext(c),
 		Limit:          &limit,
 		Offset:         &offset,
 	}
@@ -411,6 +426,7 @@ func (h *Handler) cashierGetLastShiftStatistics(c *gin.Context) {
 	// Создаем запрос
 	req := &models.CashierLastShiftStatisticsRequest{
 		CashierID: cashierID,
+		SiteID:    middleware.SiteIDFromContext(c),
 	}
 
 	// Логируем запрос
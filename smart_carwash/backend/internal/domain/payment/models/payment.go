@@ -19,6 +19,7 @@ const (
 const (
 	PaymentTypeMain      = "main"      // Основной платеж за сессию
 	PaymentTypeExtension = "extension" // Платеж за продление
+	PaymentTypeQueueJump = "queue_jump" // Доплата за пропуск очереди
 )
 
 // Payment представляет платеж
@@ -29,7 +30,7 @@ type Payment struct {
 	RefundedAmount int            `json:"refunded_amount" gorm:"default:0"` // сумма возврата в копейках
 	Currency       string         `json:"currency" gorm:"default:RUB"`
 	Status         string         `json:"status" gorm:"default:pending;index"`
-	PaymentType    string         `json:"payment_type" gorm:"default:main;index"` // тип платежа: main или extension
+	PaymentType    string         `json:"payment_type" gorm:"default:main;index"` // тип платежа: main, extension или queue_jump
 	PaymentMethod  string         `json:"payment_method" gorm:"default:tinkoff"` // метод платежа: tinkoff, cashier
 	PaymentURL     string         `json:"payment_url"`
 	TinkoffID      string         `json:"tinkoff_id" gorm:"index"`
@@ -46,6 +47,7 @@ type CalculatePriceRequest struct {
 	WithChemistry        bool   `json:"with_chemistry"`
 	ChemistryTimeMinutes int    `json:"chemistry_time_minutes"` // Выбранное время химии в минутах
 	RentalTimeMinutes    int    `json:"rental_time_minutes" binding:"required"`
+	SiteID               *uuid.UUID `json:"site_id"` // Площадка (без площадки - общие цены)
 }
 
 // CalculatePriceResponse представляет ответ на расчет цены
@@ -61,6 +63,7 @@ type CalculateExtensionPriceRequest struct {
 	ExtensionTimeMinutes  int    `json:"extension_time_minutes" binding:"required"`
 	WithChemistry         bool   `json:"with_chemistry"`
 	ExtensionChemistryTimeMinutes int `json:"extension_chemistry_time_minutes"` // Время химии при продлении (опционально)
+	SiteID                *uuid.UUID `json:"site_id"` // Площадка (без площадки - общие цены)
 }
 
 // CalculateExtensionPriceResponse представляет ответ на расчет цены продления
@@ -102,6 +105,19 @@ type CreateExtensionPaymentResponse struct {
 	Payment Payment `json:"payment"`
 }
 
+// CreateQueueJumpPaymentRequest представляет запрос на создание платежа за пропуск очереди
+type CreateQueueJumpPaymentRequest struct {
+	SessionID uuid.UUID `json:"session_id" binding:"required"`
+	Amount    int       `json:"amount" binding:"required"`
+	Currency  string    `json:"currency" binding:"required"`
+	Email     string    `json:"email"` // Email для чека
+}
+
+// CreateQueueJumpPaymentResponse представляет ответ на создание платежа за пропуск очереди
+type CreateQueueJumpPaymentResponse struct {
+	Payment Payment `json:"payment"`
+}
+
 // GetPaymentStatusRequest представляет запрос на получение статуса платежа
 type GetPaymentStatusRequest struct {
 	PaymentID uuid.UUID `json:"payment_id" binding:"required"`
@@ -130,8 +146,9 @@ type AdminListPaymentsRequest struct {
 	PaymentID     *uuid.UUID `json:"payment_id"`
 	SessionID     *uuid.UUID `json:"session_id"`
 	UserID        *uuid.UUID `json:"user_id"`
+	SiteID        *uuid.UUID `json:"site_id"` // Площадка сессии платежа
 	Status        *string    `json:"status" binding:"omitempty,oneof=pending succeeded failed refunded"`
-	PaymentType   *string    `json:"payment_type" binding:"omitempty,oneof=main extension"`
+	PaymentType   *string    `json:"payment_type" binding:"omitempty,oneof=main extension queue_jump"`
 	PaymentMethod *string    `json:"payment_method" binding:"omitempty,oneof=tinkoff cashier"`
 	DateFrom      *time.Time `json:"date_from"`
 	DateTo        *time.Time `json:"date_to"`
@@ -209,11 +226,13 @@ type GetPaymentsBySessionRequest struct {
 type GetPaymentsBySessionResponse struct {
 	MainPayment       *Payment   `json:"main_payment,omitempty"`
 	ExtensionPayments []Payment  `json:"extension_payments,omitempty"`
+	QueueJumpPayments []Payment  `json:"queue_jump_payments,omitempty"`
 }
 
 // PaymentStatisticsRequest представляет запрос на получение статистики платежей
 type PaymentStatisticsRequest struct {
 	UserID      *uuid.UUID `json:"user_id"`
+	SiteID      *uuid.UUID `json:"site_id"` // Площадка сессий (без площадки - все площадки)
 	DateFrom    *time.Time `json:"date_from"`
 	DateTo      *time.Time `json:"date_to"`
 	ServiceType *string    `json:"service_type" binding:"omitempty,oneof=wash air_dry vacuum"`
@@ -237,6 +256,7 @@ type PaymentStatisticsResponse struct {
 // CashierPaymentsRequest представляет запрос на получение платежей кассира
 type CashierPaymentsRequest struct {
 	ShiftStartedAt time.Time `json:"shift_started_at" binding:"required"`
+	SiteID         uuid.UUID `json:"-"` // Площадка кассира
 	Limit          *int      `json:"limit"`
 	Offset         *int      `json:"offset"`
 }
@@ -244,6 +264,7 @@ type CashierPaymentsRequest struct {
 // CashierLastShiftStatisticsRequest представляет запрос на получение статистики последней смены кассира
 type CashierLastShiftStatisticsRequest struct {
 	CashierID uuid.UUID `json:"cashier_id" binding:"required"`
+	SiteID    uuid.UUID `json:"-"` // Площадка кассира
 }
 
 // CashierShiftStatistics представляет статистику смены кассира
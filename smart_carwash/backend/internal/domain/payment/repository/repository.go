@@ -138,6 +138,9 @@ func (r *repository) ListPayments(ctx context.Context, req *models.AdminListPaym
 		query = query.Joins("JOIN sessions ON payments.session_id = sessions.id").
 			Where("sessions.user_id = ?", *req.UserID)
 	}
+	if req.SiteID != nil {
+		query = query.Where("payments.session_id IN (SELECT id FROM sessions WHERE site_id = ?)", *req.SiteID)
+	}
 	if req.Status != nil {
 		query = query.Where("status = ?", *req.Status)
 	}
@@ -207,6 +210,9 @@ func (r *repository) GetPaymentStatistics(ctx context.Context, req *models.Payme
 	if req.UserID != nil {
 		query = query.Where("sessions.user_id = ?", *req.UserID)
 	}
+	if req.SiteID != nil {
+		query = query.Where("sessions.site_id = ?", *req.SiteID)
+	}
 	if req.DateFrom != nil {
 		query = query.Where("payments.created_at >= ?", *req.DateFrom)
 	}
@@ -270,6 +276,11 @@ func (r *repository) CashierListPayments(ctx context.Context, req *models.Cashie
 	// Фильтруем по дате начала смены
 	query = query.Where("created_at >= ?", req.ShiftStartedAt)
 
+	// Кассир видит только платежи своей площадки
+	if req.SiteID != uuid.Nil {
+		query = query.Where("session_id IN (SELECT id FROM sessions WHERE site_id = ?)", req.SiteID)
+	}
+
 	// Получаем общее количество
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -351,6 +362,7 @@ func (r *repository) GetCashierLastShiftStatistics(ctx context.Context, req *mod
 		`).
 		Joins("JOIN sessions ON payments.session_id = sessions.id").
 		Where("payments.created_at >= ? AND payments.created_at <= ?", lastShift.StartedAt, lastShift.EndedAt).
+		Where("sessions.site_id = ?", req.SiteID).
 		Group("sessions.service_type, sessions.with_chemistry, payments.payment_method").
 		Order("sessions.service_type, sessions.with_chemistry, payments.payment_method")
 
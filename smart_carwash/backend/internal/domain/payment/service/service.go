@@ -10,6 +10,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -27,6 +28,16 @@ type SessionExtensionUpdater interface {
 	UpdateSessionExtension(ctx context.Context, sessionID uuid.UUID, extensionTimeMinutes int) error
 }
 
+// SessionQueueJumpUpdater интерфейс для применения оплаченного пропуска очереди к сессии
+// Повторный вызов с тем же платежом не является ошибкой. Ошибка ErrQueueJumpNotApplicable
+// означает, что пропуск применить нельзя и доплату нужно вернуть
+type SessionQueueJumpUpdater interface {
+	ApplyQueueJump(ctx context.Context, sessionID uuid.UUID, paymentID uuid.UUID) error
+}
+
+// ErrQueueJumpNotApplicable пропуск очереди нельзя применить к сессии (сессия ушла из очереди, исчерпан лимит и т.п.)
+var ErrQueueJumpNotApplicable = errors.New("пропуск очереди недоступен")
+
 // TinkoffClient интерфейс для работы с Tinkoff API
 type TinkoffClient interface {
 	CreatePayment(orderID string, amount int, description string, receipt map[string]interface{}) (*TinkoffPaymentResponse, error)
@@ -60,6 +71,7 @@ type Service interface {
 	CalculateExtensionPrice(ctx context.Context, req *models.CalculateExtensionPriceRequest) (*models.CalculateExtensionPriceResponse, error)
 	CreatePayment(ctx context.Context, req *models.CreatePaymentRequest) (*models.CreatePaymentResponse, error)
 	CreateExtensionPayment(ctx context.Context, req *models.CreateExtensionPaymentRequest) (*models.CreateExtensionPaymentResponse, error)
+	CreateQueueJumpPayment(ctx context.Context, req *models.CreateQueueJumpPaymentRequest) (*models.CreateQueueJumpPaymentResponse, error)
 	GetPaymentByID(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error)
 	GetMainPaymentBySessionID(ctx context.Context, sessionID uuid.UUID) (*models.Payment, error)
 	GetLastPaymentBySessionID(ctx context.Context, sessionID uuid.UUID) (*models.Payment, error)
@@ -74,6 +86,7 @@ type Service interface {
 	CreateForCashier(ctx context.Context, sessionID uuid.UUID, amount int) (*models.Payment, error)
 	CashierListPayments(ctx context.Context, req *models.CashierPaymentsRequest) (*models.AdminListPaymentsResponse, error)
 	GetCashierLastShiftStatistics(ctx context.Context, req *models.CashierLastShiftStatisticsRequest) (*models.CashierLastShiftStatisticsResponse, error)
+	SetSessionQueueJumpUpdater(updater SessionQueueJumpUpdater)
 	Shutdown() // Завершение работы сервиса (остановка очереди webhook'ов)
 }
 
@@ -83,6 +96,7 @@ type service struct {
 	settingsRepo            settingsRepo.Repository
 	sessionUpdater          SessionStatusUpdater
 	sessionExtensionUpdater SessionExtensionUpdater
+	sessionQueueJumpUpdater SessionQueueJumpUpdater
 	tinkoffClient           TinkoffClient
 	terminalKey             string
 	secretKey               string
@@ -116,16 +130,22 @@ func NewService(repository repository.Repository, settingsRepo settingsRepo.Repo
 	return s
 }
 
+// SetSessionQueueJumpUpdater устанавливает обработчик оплаченного пропуска очереди
+func (s *service) SetSessionQueueJumpUpdater(updater SessionQueueJumpUpdater) {
+	s.sessionQueueJumpUpdater = updater
+}
+
 // CalculatePrice рассчитывает цену для услуги
 func (s *service) CalculatePrice(ctx context.Context, req *models.CalculatePriceRequest) (*models.CalculatePriceResponse, error) {
 	logger.WithFields(logrus.Fields{
 		"service_type":        req.ServiceType,
 		"rental_time_minutes": req.RentalTimeMinutes,
 		"with_chemistry":      req.WithChemistry,
+		"site_id":             req.SiteID,
 	}).Info("Payment Service - CalculatePrice: начало расчета цены")
 
 	// Получаем базовую цену за минуту
-	basePriceSetting, err := s.settingsRepo.GetServiceSetting(ctx, req.ServiceType, "price_per_minute")
+	basePriceSetting, err := s.settingsRepo.GetSiteServiceSetting(ctx, req.SiteID, req.ServiceType, "price_per_minute")
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"service_type": req.ServiceType,
@@ -156,7 +176,7 @@ func (s *service) CalculatePrice(ctx context.Context, req *models.CalculatePrice
 
 	// Если используется химия, добавляем стоимость химии
 	if req.WithChemistry && req.ChemistryTimeMinutes > 0 {
-		chemistryPriceSetting, err := s.settingsRepo.GetServiceSetting(ctx, req.ServiceType, "chemistry_price_per_minute")
+		chemistryPriceSetting, err := s.settingsRepo.GetSiteServiceSetting(ctx, req.SiteID, req.ServiceType, "chemistry_price_per_minute")
 		if err != nil {
 			return nil, fmt.Errorf("не удалось получить цену химии: %w", err)
 		}
@@ -187,7 +207,7 @@ func (s *service) CalculatePrice(ctx context.Context, req *models.CalculatePrice
 // CalculateExtensionPrice рассчитывает цену для продления сессии
 func (s *service) CalculateExtensionPrice(ctx context.Context, req *models.CalculateExtensionPriceRequest) (*models.CalculateExtensionPriceResponse, error) {
 	// Получаем базовую цену за минуту
-	basePriceSetting, err := s.settingsRepo.GetServiceSetting(ctx, req.ServiceType, "price_per_minute")
+	basePriceSetting, err := s.settingsRepo.GetSiteServiceSetting(ctx, req.SiteID, req.ServiceType, "price_per_minute")
 	if err != nil {
 		return nil, fmt.Errorf("не удалось получить базовую цену: %w", err)
 	}
@@ -214,7 +234,7 @@ func (s *service) CalculateExtensionPrice(ctx context.Context, req *models.Calcu
 
 	// Если используется химия при продлении, добавляем стоимость химии только на докупленное время
 	if req.WithChemistry && req.ExtensionChemistryTimeMinutes > 0 {
-		chemistryPriceSetting, err := s.settingsRepo.GetServiceSetting(ctx, req.ServiceType, "chemistry_price_per_minute")
+		chemistryPriceSetting, err := s.settingsRepo.GetSiteServiceSetting(ctx, req.SiteID, req.ServiceType, "chemistry_price_per_minute")
 		if err != nil {
 			return nil, fmt.Errorf("не удалось получить цену химии: %w", err)
 		}
@@ -378,6 +398,70 @@ func (s *service) CreateExtensionPayment(ctx context.Context, req *models.Create
 	}, nil
 }
 
+// CreateQueueJumpPayment создает платеж за пропуск очереди в Tinkoff и сохраняет в БД
+func (s *service) CreateQueueJumpPayment(ctx context.Context, req *models.CreateQueueJumpPaymentRequest) (*models.CreateQueueJumpPaymentResponse, error) {
+	// Проверяем, есть ли уже pending платеж за пропуск очереди для этой сессии
+	existingPayments, err := s.repository.GetPaymentsBySessionID(ctx, req.SessionID)
+	if err == nil {
+		for _, payment := range existingPayments {
+			if payment.PaymentType != models.PaymentTypeQueueJump {
+				continue
+			}
+			// Пропуск очереди покупается один раз за сессию
+			if payment.Status == models.PaymentStatusSucceeded {
+				return nil, fmt.Errorf("пропуск очереди для этой сессии уже оплачен")
+			}
+			if payment.Status == models.PaymentStatusPending && payment.ExpiresAt != nil && time.Now().Before(*payment.ExpiresAt) {
+				logger.Printf("Найден существующий pending платеж за пропуск очереди, возвращаем его: ID=%s, SessionID=%s",
+					payment.ID, payment.SessionID)
+				return &models.CreateQueueJumpPaymentResponse{
+					Payment: payment,
+				}, nil
+			}
+		}
+	}
+
+	// Создаем уникальный orderID для платежа за пропуск очереди
+	orderID := fmt.Sprintf("jump_%s", generateRandomString(12))
+	description := fmt.Sprintf("Пропуск очереди автомойки (сессия: %s)", req.SessionID.String())
+
+	// Создаем чек для фискализации
+	receipt := s.buildReceipt(req.Amount, req.Email)
+
+	tinkoffResp, err := s.tinkoffClient.CreatePayment(orderID, req.Amount, description, receipt)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания платежа за пропуск очереди в Tinkoff: %w", err)
+	}
+
+	if !tinkoffResp.Success {
+		return nil, fmt.Errorf("ошибка Tinkoff: %s", tinkoffResp.ErrorCode)
+	}
+
+	expiresAt := time.Now().Add(15 * time.Minute) // Платеж действителен 15 минут
+
+	payment := &models.Payment{
+		SessionID:   req.SessionID,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Status:      models.PaymentStatusPending,
+		PaymentType: models.PaymentTypeQueueJump,
+		PaymentURL:  tinkoffResp.PaymentURL,
+		TinkoffID:   tinkoffResp.PaymentId,
+		ExpiresAt:   &expiresAt,
+	}
+
+	if err := s.repository.CreatePayment(ctx, payment); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения платежа за пропуск очереди: %w", err)
+	}
+
+	logger.Printf("Создан платеж за пропуск очереди: ID=%s, SessionID=%s, Amount=%d, TinkoffID=%s",
+		payment.ID, payment.SessionID, payment.Amount, payment.TinkoffID)
+
+	return &models.CreateQueueJumpPaymentResponse{
+		Payment: *payment,
+	}, nil
+}
+
 // GetPaymentStatus получает статус платежа
 func (s *service) GetPaymentStatus(ctx context.Context, req *models.GetPaymentStatusRequest) (*models.GetPaymentStatusResponse, error) {
 	payment, err := s.repository.GetPaymentByID(ctx, req.PaymentID)
@@ -427,18 +511,22 @@ func (s *service) GetPaymentsBySessionID(ctx context.Context, sessionID uuid.UUI
 
 	var mainPayment *models.Payment
 	var extensionPayments []models.Payment
+	var queueJumpPayments []models.Payment
 
 	for _, payment := range payments {
 		if payment.PaymentType == models.PaymentTypeMain {
 			mainPayment = &payment
 		} else if payment.PaymentType == models.PaymentTypeExtension {
 			extensionPayments = append(extensionPayments, payment)
+		} else if payment.PaymentType == models.PaymentTypeQueueJump {
+			queueJumpPayments = append(queueJumpPayments, payment)
 		}
 	}
 
 	return &models.GetPaymentsBySessionResponse{
 		MainPayment:       mainPayment,
 		ExtensionPayments: extensionPayments,
+		QueueJumpPayments: queueJumpPayments,
 	}, nil
 }
 
@@ -453,17 +541,21 @@ func (s *service) GetPaymentsBySessionIDs(ctx context.Context, sessionIDs []uuid
 	for sessionID, payments := range paymentsBySession {
 		var mainPayment *models.Payment
 		var extensionPayments []models.Payment
+		var queueJumpPayments []models.Payment
 		for _, p := range payments {
 			if p.PaymentType == models.PaymentTypeMain {
 				cp := p
 				mainPayment = &cp
 			} else if p.PaymentType == models.PaymentTypeExtension {
 				extensionPayments = append(extensionPayments, p)
+			} else if p.PaymentType == models.PaymentTypeQueueJump {
+				queueJumpPayments = append(queueJumpPayments, p)
 			}
 		}
 		result[sessionID] = &models.GetPaymentsBySessionResponse{
 			MainPayment:       mainPayment,
 			ExtensionPayments: extensionPayments,
+			QueueJumpPayments: queueJumpPayments,
 		}
 	}
 	return result, nil
@@ -492,6 +584,8 @@ func (s *service) processWebhook(ctx context.Context, req *models.WebhookRequest
 		return fmt.Errorf("платеж не найден: %w", err)
 	}
 
+	previousStatus := payment.Status
+
 	// Обрабатываем разные статусы
 	switch req.Status {
 	// Успешные платежи
@@ -561,6 +655,12 @@ func (s *service) processWebhook(ctx context.Context, req *models.WebhookRequest
 				logger.Printf("Ошибка обновления времени продления сессии: %v", err)
 			}
 		}
+
+		// Если платеж только что стал успешным и это доплата за пропуск очереди, поднимаем сессию в очереди
+		// Повторные уведомления (AUTHORIZED, затем CONFIRMED, повторы Tinkoff, возвраты) пропуск не применяют
+		if previousStatus != models.PaymentStatusSucceeded && payment.Status == models.PaymentStatusSucceeded && payment.PaymentType == models.PaymentTypeQueueJump {
+			s.applyQueueJump(ctx, payment)
+		}
 	}
 
 	return nil
@@ -612,6 +712,35 @@ func (s *service) updateSessionExtension(ctx context.Context, payment *models.Pa
 	return nil
 }
 
+// applyQueueJump применяет оплаченный пропуск очереди к сессии
+// Если пропуск применить нельзя (сессия ушла из очереди, исчерпан лимит в час), доплата сразу возвращается.
+// При других ошибках доплата не возвращается: платеж нужно проверить вручную
+func (s *service) applyQueueJump(ctx context.Context, payment *models.Payment) {
+	if s.sessionQueueJumpUpdater == nil {
+		logger.Printf("Обработчик пропуска очереди не установлен, платеж %s не применен", payment.ID)
+		return
+	}
+
+	err := s.sessionQueueJumpUpdater.ApplyQueueJump(ctx, payment.SessionID, payment.ID)
+	if err == nil {
+		logger.Printf("Пропуск очереди применен к сессии %s", payment.SessionID)
+		return
+	}
+
+	if !errors.Is(err, ErrQueueJumpNotApplicable) {
+		logger.Printf("Ошибка применения пропуска очереди к сессии %s, доплата не возвращена, требуется проверка: PaymentID=%s, error=%v", payment.SessionID, payment.ID, err)
+		return
+	}
+
+	logger.Printf("Пропуск очереди не применен к сессии %s, возвращаем доплату: %v", payment.SessionID, err)
+	if _, refundErr := s.RefundPayment(ctx, &models.RefundPaymentRequest{
+		PaymentID: payment.ID,
+		Amount:    payment.Amount - payment.RefundedAmount,
+	}); refundErr != nil {
+		logger.Printf("Ошибка возврата доплаты за пропуск очереди: PaymentID=%s, error=%v", payment.ID, refundErr)
+	}
+}
+
 // ListPayments получает список платежей для админки
 func (s *service) ListPayments(ctx context.Context, req *models.AdminListPaymentsRequest) (*models.AdminListPaymentsResponse, error) {
 	payments, total, err := s.repository.ListPayments(ctx, req)
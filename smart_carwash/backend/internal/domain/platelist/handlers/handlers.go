@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"carwash_backend/internal/domain/platelist/models"
+	"carwash_backend/internal/domain/platelist/service"
+	"carwash_backend/internal/logger"
+	"carwash_backend/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler структура для обработчиков HTTP запросов списков госномеров
+type Handler struct {
+	service service.Service
+}
+
+// NewHandler создает новый экземпляр Handler
+func NewHandler(service service.Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для списков госномеров
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup, adminMiddleware gin.HandlerFunc) {
+	adminRoutes := router.Group("/admin/plate-lists", adminMiddleware)
+	{
+		adminRoutes.GET("", h.adminListEntries)
+		adminRoutes.POST("", h.adminCreateEntry)
+		adminRoutes.PUT("", h.adminUpdateEntry)
+		adminRoutes.DELETE("", h.adminDeleteEntry)
+		adminRoutes.GET("/blocks", h.adminListBlocks)
+	}
+}
+
+// adminListEntries получает записи списков (админка)
+func (h *Handler) adminListEntries(c *gin.Context) {
+	var req models.AdminListEntriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.WithContext(c).Errorf("adminListEntries: ошибка парсинга параметров: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.AdminListEntries(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminListEntries: ошибка получения списков: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// adminCreateEntry добавляет номер в список (админка)
+func (h *Handler) adminCreateEntry(c *gin.Context) {
+	var req models.AdminCreateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("adminCreateEntry: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var adminID *uuid.UUID
+	if value, exists := c.Get("user_id"); exists {
+		if id, ok := value.(uuid.UUID); ok {
+			adminID = &id
+		}
+	}
+
+	c.Set("meta", gin.H{
+		"car_number": req.CarNumber,
+		"list_type":  req.ListType,
+		"reason":     req.Reason,
+		"admin_id":   adminID,
+	})
+
+	entry, err := h.service.AdminCreateEntry(c.Request.Context(), &req, adminID)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminCreateEntry: ошибка добавления номера в список: %v", err)
+		var plateErr *utils.LicensePlateValidationError
+		if errors.As(err, &plateErr) {
+			c.JSON(http.StatusBadRequest, plateErr)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// adminUpdateEntry изменяет запись списка (админка)
+func (h *Handler) adminUpdateEntry(c *gin.Context) {
+	var req models.AdminUpdateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("adminUpdateEntry: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"entry_id": req.ID,
+	})
+
+	entry, err := h.service.AdminUpdateEntry(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminUpdateEntry: ошибка обновления записи списка: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// adminDeleteEntry удаляет запись списка (админка)
+func (h *Handler) adminDeleteEntry(c *gin.Context) {
+	var req models.AdminDeleteEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("adminDeleteEntry: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"entry_id": req.ID,
+	})
+
+	resp, err := h.service.AdminDeleteEntry(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminDeleteEntry: ошибка удаления записи списка: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// adminListBlocks получает журнал блокировок (админка)
+func (h *Handler) adminListBlocks(c *gin.Context) {
+	var req models.AdminListBlocksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.WithContext(c).Errorf("adminListBlocks: ошибка парсинга параметров: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.AdminListBlocks(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminListBlocks: ошибка получения журнала блокировок: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
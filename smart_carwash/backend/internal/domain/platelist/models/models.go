@@ -0,0 +1,163 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Типы списков госномеров
+const (
+	ListTypeBlacklist  = "blacklist"  // Черный список (мошенничество, неоплаченный ущерб)
+	ListTypeVIP        = "vip"        // VIP список
+	ListTypeFleet      = "fleet"      // Корпоративный автопарк (приоритетный класс очереди)
+	ListTypeSubscriber = "subscriber" // Абонемент (приоритетный класс очереди)
+)
+
+// Привилегии VIP номеров
+const (
+	VIPPrivilegePriority = "priority"  // Приоритет в очереди
+	VIPPrivilegeFreePass = "free_pass" // Бесплатная мойка (без оплаты) и приоритет в очереди
+)
+
+// Источники проверки номера
+const (
+	SourceApp     = "app"     // Создание сессии через приложение
+	SourceCashier = "cashier" // Создание сессии через 1С/кассира
+	SourceANPR    = "anpr"    // Распознавание номера камерой
+)
+
+// PlateListEntry запись в списке госномеров
+type PlateListEntry struct {
+	ID               uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CarNumber        string         `json:"car_number" gorm:"not null;index"`                 // Нормализованный госномер
+	CarNumberCountry string         `json:"car_number_country" gorm:"not null;default:'RUS'"` // Страна госномера
+	ListType         string         `json:"list_type" gorm:"not null;index"`                  // blacklist, vip, fleet или subscriber
+	VIPPrivilege     *string        `json:"vip_privilege,omitempty"`                          // Привилегия VIP номера
+	Reason           string         `json:"reason" gorm:"not null"`                           // Причина внесения в список
+	ExpiresAt        *time.Time     `json:"expires_at,omitempty"`                             // Срок действия (nil - бессрочно)
+	CreatedBy        *uuid.UUID     `json:"created_by,omitempty" gorm:"type:uuid"`            // Администратор, добавивший запись
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (PlateListEntry) TableName() string {
+	return "plate_list_entries"
+}
+
+// IsActive проверяет, действует ли запись на указанный момент
+func (e *PlateListEntry) IsActive(now time.Time) bool {
+	return e.ExpiresAt == nil || now.Before(*e.ExpiresAt)
+}
+
+// IsFreePass проверяет, дает ли запись право на бесплатную мойку
+func (e *PlateListEntry) IsFreePass() bool {
+	return e.ListType == ListTypeVIP && e.VIPPrivilege != nil && *e.VIPPrivilege == VIPPrivilegeFreePass
+}
+
+// PrivilegeRank возвращает старшинство привилегированного списка (чем больше, тем выше приоритет)
+// Для черного списка и неизвестных типов возвращает 0
+func (e *PlateListEntry) PrivilegeRank() int {
+	switch e.ListType {
+	case ListTypeVIP:
+		return 3
+	case ListTypeFleet:
+		return 2
+	case ListTypeSubscriber:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// PlateListBlock запись о блокировке номера из черного списка
+type PlateListBlock struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	EntryID   uuid.UUID  `json:"entry_id" gorm:"type:uuid;not null"`
+	CarNumber string     `json:"car_number" gorm:"not null;index"`
+	Source    string     `json:"source" gorm:"not null;index"` // app, cashier или anpr
+	UserID    *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid"`
+	Reason    string     `json:"reason" gorm:"not null"` // Причина из записи черного списка на момент блокировки
+	CreatedAt time.Time  `json:"created_at" gorm:"index"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (PlateListBlock) TableName() string {
+	return "plate_list_blocks"
+}
+
+// PlateBlockedError ошибка создания сессии для номера из черного списка
+type PlateBlockedError struct {
+	CarNumber string     `json:"car_number"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Error реализует интерфейс error
+func (e *PlateBlockedError) Error() string {
+	return fmt.Sprintf("автомобиль с номером '%s' заблокирован: %s", e.CarNumber, e.Reason)
+}
+
+// AdminListEntriesRequest запрос на получение записей списков (админка)
+type AdminListEntriesRequest struct {
+	ListType   string `json:"list_type" form:"list_type"`     // Фильтр по типу списка
+	CarNumber  string `json:"car_number" form:"car_number"`   // Фильтр по госномеру
+	ActiveOnly bool   `json:"active_only" form:"active_only"` // Только действующие записи
+	Limit      int    `json:"limit" form:"limit"`
+	Offset     int    `json:"offset" form:"offset"`
+}
+
+// AdminListEntriesResponse ответ на получение записей списков (админка)
+type AdminListEntriesResponse struct {
+	Entries []PlateListEntry `json:"entries"`
+	Total   int              `json:"total"`
+}
+
+// AdminCreateEntryRequest запрос на добавление номера в список (админка)
+type AdminCreateEntryRequest struct {
+	CarNumber        string     `json:"car_number" binding:"required"`
+	CarNumberCountry string     `json:"car_number_country"`
+	ListType         string     `json:"list_type" binding:"required,oneof=blacklist vip fleet subscriber"`
+	VIPPrivilege     *string    `json:"vip_privilege,omitempty"` // Обязательно для vip: priority или free_pass
+	Reason           string     `json:"reason" binding:"required"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+}
+
+// AdminUpdateEntryRequest запрос на изменение записи списка (админка)
+type AdminUpdateEntryRequest struct {
+	ID             uuid.UUID  `json:"id" binding:"required"`
+	VIPPrivilege   *string    `json:"vip_privilege,omitempty"`
+	Reason         *string    `json:"reason,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	ClearExpiresAt bool       `json:"clear_expires_at"` // Сделать запись бессрочной
+}
+
+// AdminDeleteEntryRequest запрос на удаление записи списка (админка)
+type AdminDeleteEntryRequest struct {
+	ID uuid.UUID `json:"id" binding:"required"`
+}
+
+// AdminDeleteEntryResponse ответ на удаление записи списка (админка)
+type AdminDeleteEntryResponse struct {
+	Success bool `json:"success"`
+}
+
+// AdminListBlocksRequest запрос на получение журнала блокировок (админка)
+type AdminListBlocksRequest struct {
+	CarNumber string     `json:"car_number" form:"car_number"`
+	Source    string     `json:"source" form:"source"`
+	DateFrom  *time.Time `json:"date_from" form:"date_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	DateTo    *time.Time `json:"date_to" form:"date_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit     int        `json:"limit" form:"limit"`
+	Offset    int        `json:"offset" form:"offset"`
+}
+
+// AdminListBlocksResponse ответ на получение журнала блокировок (админка)
+type AdminListBlocksResponse struct {
+	Blocks []PlateListBlock `json:"blocks"`
+	Total  int              `json:"total"`
+}
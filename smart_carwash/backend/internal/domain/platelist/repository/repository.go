@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"carwash_backend/internal/domain/platelist/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository интерфейс для работы со списками госномеров в базе данных
+type Repository interface {
+	CreateEntry(ctx context.Context, entry *models.PlateListEntry) error
+	UpdateEntry(ctx context.Context, entry *models.PlateListEntry) error
+	DeleteEntry(ctx context.Context, id uuid.UUID) error
+	GetEntryByID(ctx context.Context, id uuid.UUID) (*models.PlateListEntry, error)
+	GetEntryByCarNumber(ctx context.Context, carNumber string, listType string) (*models.PlateListEntry, error)
+	GetActiveEntries(ctx context.Context, carNumber string, now time.Time) ([]models.PlateListEntry, error)
+	ListEntries(ctx context.Context, req *models.AdminListEntriesRequest, now time.Time) ([]models.PlateListEntry, int, error)
+	CreateBlock(ctx context.Context, block *models.PlateListBlock) error
+	ListBlocks(ctx context.Context, req *models.AdminListBlocksRequest) ([]models.PlateListBlock, int, error)
+}
+
+// PostgresRepository реализация Repository для PostgreSQL
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository создает новый экземпляр PostgresRepository
+func NewPostgresRepository(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{
+		db: db,
+	}
+}
+
+// CreateEntry добавляет номер в список
+func (r *PostgresRepository) CreateEntry(ctx context.Context, entry *models.PlateListEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// UpdateEntry обновляет запись списка
+func (r *PostgresRepository) UpdateEntry(ctx context.Context, entry *models.PlateListEntry) error {
+	return r.db.WithContext(ctx).Save(entry).Error
+}
+
+// DeleteEntry удаляет запись списка (мягкое удаление)
+func (r *PostgresRepository) DeleteEntry(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.PlateListEntry{}, "id = ?", id).Error
+}
+
+// GetEntryByID получает запись списка по ID
+func (r *PostgresRepository) GetEntryByID(ctx context.Context, id uuid.UUID) (*models.PlateListEntry, error) {
+	var entry models.PlateListEntry
+	err := r.db.WithContext(ctx).First(&entry, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetEntryByCarNumber получает запись списка по номеру (включая истекшие)
+func (r *PostgresRepository) GetEntryByCarNumber(ctx context.Context, carNumber string, listType string) (*models.PlateListEntry, error) {
+	var entry models.PlateListEntry
+	err := r.db.WithContext(ctx).
+		Where("car_number = ? AND list_type = ?", carNumber, listType).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetActiveEntries получает действующие записи всех списков для номера
+func (r *PostgresRepository) GetActiveEntries(ctx context.Context, carNumber string, now time.Time) ([]models.PlateListEntry, error) {
+	var entries []models.PlateListEntry
+	err := r.db.WithContext(ctx).
+		Where("car_number = ?", carNumber).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Find(&entries).Error
+	return entries, err
+}
+
+// ListEntries получает записи списков с фильтрами и пагинацией
+func (r *PostgresRepository) ListEntries(ctx context.Context, req *models.AdminListEntriesRequest, now time.Time) ([]models.PlateListEntry, int, error) {
+	var entries []models.PlateListEntry
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.PlateListEntry{})
+	if req.ListType != "" {
+		query = query.Where("list_type = ?", req.ListType)
+	}
+	if req.CarNumber != "" {
+		query = query.Where("car_number LIKE ?", "%"+req.CarNumber+"%")
+	}
+	if req.ActiveOnly {
+		query = query.Where("expires_at IS NULL OR expires_at > ?", now)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := req.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, int(total), nil
+}
+
+// CreateBlock записывает факт блокировки номера
+func (r *PostgresRepository) CreateBlock(ctx context.Context, block *models.PlateListBlock) error {
+	if block.CreatedAt.IsZero() {
+		block.CreatedAt = time.Now()
+	}
+	return r.db.WithContext(ctx).Create(block).Error
+}
+
+// ListBlocks получает журнал блокировок с фильтрами и пагинацией
+func (r *PostgresRepository) ListBlocks(ctx context.Context, req *models.AdminListBlocksRequest) ([]models.PlateListBlock, int, error) {
+	var blocks []models.PlateListBlock
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.PlateListBlock{})
+	if req.CarNumber != "" {
+		query = query.Where("car_number LIKE ?", "%"+req.CarNumber+"%")
+	}
+	if req.Source != "" {
+		query = query.Where("source = ?", req.Source)
+	}
+	if req.DateFrom != nil {
+		query = query.Where("created_at >= ?", *req.DateFrom)
+	}
+	if req.DateTo != nil {
+		query = query.Where("created_at <= ?", *req.DateTo)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := req.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&blocks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return blocks, int(total), nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"carwash_backend/internal/domain/platelist/models"
+	"carwash_backend/internal/domain/platelist/repository"
+	"carwash_backend/internal/logger"
+	"carwash_backend/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Service интерфейс для бизнес-логики списков госномеров
+type Service interface {
+	CheckCarNumber(ctx context.Context, carNumber string, source string, userID *uuid.UUID) (*models.PlateListEntry, error)
+	AdminListEntries(ctx context.Context, req *models.AdminListEntriesRequest) (*models.AdminListEntriesResponse, error)
+	AdminCreateEntry(ctx context.Context, req *models.AdminCreateEntryRequest, adminID *uuid.UUID) (*models.PlateListEntry, error)
+	AdminUpdateEntry(ctx context.Context, req *models.AdminUpdateEntryRequest) (*models.PlateListEntry, error)
+	AdminDeleteEntry(ctx context.Context, req *models.AdminDeleteEntryRequest) (*models.AdminDeleteEntryResponse, error)
+	AdminListBlocks(ctx context.Context, req *models.AdminListBlocksRequest) (*models.AdminListBlocksResponse, error)
+}
+
+// ServiceImpl реализация Service
+type ServiceImpl struct {
+	repo repository.Repository
+}
+
+// NewService создает новый экземпляр Service
+func NewService(repo repository.Repository) *ServiceImpl {
+	return &ServiceImpl{
+		repo: repo,
+	}
+}
+
+// CheckCarNumber проверяет госномер по спискам
+// Если номер в черном списке - записывает блокировку и возвращает *models.PlateBlockedError
+// Если номер в привилегированных списках (vip, fleet, subscriber) - возвращает запись с наивысшим приоритетом, иначе nil
+func (s *ServiceImpl) CheckCarNumber(ctx context.Context, carNumber string, source string, userID *uuid.UUID) (*models.PlateListEntry, error) {
+	if carNumber == "" {
+		return nil, nil
+	}
+
+	entries, err := s.repo.GetActiveEntries(ctx, carNumber, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки списков госномеров: %w", err)
+	}
+
+	var privilegedEntry *models.PlateListEntry
+	for i := range entries {
+		entry := &entries[i]
+		switch entry.ListType {
+		case models.ListTypeBlacklist:
+			block := &models.PlateListBlock{
+				EntryID:   entry.ID,
+				CarNumber: carNumber,
+				Source:    source,
+				UserID:    userID,
+				Reason:    entry.Reason,
+			}
+			if err := s.repo.CreateBlock(ctx, block); err != nil {
+				logger.Printf("PlateList - CheckCarNumber: ошибка записи блокировки, car_number: %s, source: %s, error: %v", carNumber, source, err)
+			}
+			logger.Printf("PlateList - CheckCarNumber: номер в черном списке, car_number: %s, source: %s, reason: %s", carNumber, source, entry.Reason)
+			return nil, &models.PlateBlockedError{
+				CarNumber: carNumber,
+				Reason:    entry.Reason,
+				ExpiresAt: entry.ExpiresAt,
+			}
+		case models.ListTypeVIP, models.ListTypeFleet, models.ListTypeSubscriber:
+			if privilegedEntry == nil || entry.PrivilegeRank() > privilegedEntry.PrivilegeRank() {
+				privilegedEntry = entry
+			}
+		}
+	}
+
+	if privilegedEntry != nil {
+		logger.Printf("PlateList - CheckCarNumber: привилегированный номер, car_number: %s, source: %s, list_type: %s, free_pass: %t",
+			carNumber, source, privilegedEntry.ListType, privilegedEntry.IsFreePass())
+	}
+
+	return privilegedEntry, nil
+}
+
+// AdminListEntries получает записи списков (админка)
+func (s *ServiceImpl) AdminListEntries(ctx context.Context, req *models.AdminListEntriesRequest) (*models.AdminListEntriesResponse, error) {
+	if req.CarNumber != "" {
+		req.CarNumber = utils.NormalizeLicensePlateForSearch(req.CarNumber)
+	}
+
+	entries, total, err := s.repo.ListEntries(ctx, req, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списков госномеров: %w", err)
+	}
+
+	return &models.AdminListEntriesResponse{
+		Entries: entries,
+		Total:   total,
+	}, nil
+}
+
+// AdminCreateEntry добавляет номер в список (админка)
+func (s *ServiceImpl) AdminCreateEntry(ctx context.Context, req *models.AdminCreateEntryRequest, adminID *uuid.UUID) (*models.PlateListEntry, error) {
+	country := utils.NormalizeCarNumberCountry(req.CarNumberCountry)
+	carNumber, err := utils.ValidateLicensePlate(req.CarNumber, country)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateVIPPrivilege(req.ListType, req.VIPPrivilege); err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("срок действия должен быть в будущем")
+	}
+
+	// Проверяем, что номер еще не в этом списке
+	existing, err := s.repo.GetEntryByCarNumber(ctx, carNumber, req.ListType)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("ошибка проверки списка: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("номер '%s' уже есть в списке %s", carNumber, req.ListType)
+	}
+
+	entry := &models.PlateListEntry{
+		CarNumber:        carNumber,
+		CarNumberCountry: country,
+		ListType:         req.ListType,
+		VIPPrivilege:     req.VIPPrivilege,
+		Reason:           req.Reason,
+		ExpiresAt:        req.ExpiresAt,
+		CreatedBy:        adminID,
+	}
+
+	if err := s.repo.CreateEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("ошибка добавления номера в список: %w", err)
+	}
+
+	logger.Printf("PlateList - AdminCreateEntry: номер %s добавлен в список %s, reason: %s", carNumber, req.ListType, req.Reason)
+
+	return entry, nil
+}
+
+// AdminUpdateEntry изменяет запись списка (админка)
+func (s *ServiceImpl) AdminUpdateEntry(ctx context.Context, req *models.AdminUpdateEntryRequest) (*models.PlateListEntry, error) {
+	entry, err := s.repo.GetEntryByID(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("запись списка не найдена: %w", err)
+	}
+
+	if req.VIPPrivilege != nil {
+		if err := validateVIPPrivilege(entry.ListType, req.VIPPrivilege); err != nil {
+			return nil, err
+		}
+		entry.VIPPrivilege = req.VIPPrivilege
+	}
+	if req.Reason != nil {
+		if *req.Reason == "" {
+			return nil, fmt.Errorf("причина не может быть пустой")
+		}
+		entry.Reason = *req.Reason
+	}
+	if req.ClearExpiresAt {
+		entry.ExpiresAt = nil
+	} else if req.ExpiresAt != nil {
+		entry.ExpiresAt = req.ExpiresAt
+	}
+
+	if err := s.repo.UpdateEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("ошибка обновления записи списка: %w", err)
+	}
+
+	return entry, nil
+}
+
+// AdminDeleteEntry удаляет запись списка (админка)
+func (s *ServiceImpl) AdminDeleteEntry(ctx context.Context, req *models.AdminDeleteEntryRequest) (*models.AdminDeleteEntryResponse, error) {
+	if _, err := s.repo.GetEntryByID(ctx, req.ID); err != nil {
+		return nil, fmt.Errorf("запись списка не найдена: %w", err)
+	}
+
+	if err := s.repo.DeleteEntry(ctx, req.ID); err != nil {
+		return nil, fmt.Errorf("ошибка удаления записи списка: %w", err)
+	}
+
+	return &models.AdminDeleteEntryResponse{Success: true}, nil
+}
+
+// AdminListBlocks получает журнал блокировок (админка)
+func (s *ServiceImpl) AdminListBlocks(ctx context.Context, req *models.AdminListBlocksRequest) (*models.AdminListBlocksResponse, error) {
+	if req.CarNumber != "" {
+		req.CarNumber = utils.NormalizeLicensePlateForSearch(req.CarNumber)
+	}
+
+	blocks, total, err := s.repo.ListBlocks(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения журнала блокировок: %w", err)
+	}
+
+	return &models.AdminListBlocksResponse{
+		Blocks: blocks,
+		Total:  total,
+	}, nil
+}
+
+// validateVIPPrivilege проверяет привилегию в зависимости от типа списка
+func validateVIPPrivilege(listType string, privilege *string) error {
+	if listType != models.ListTypeVIP {
+		if privilege != nil {
+			return fmt.Errorf("привилегия указывается только для VIP списка")
+		}
+		return nil
+	}
+
+	if privilege == nil {
+		return fmt.Errorf("для VIP списка необходимо указать привилегию: %s или %s", models.VIPPrivilegePriority, models.VIPPrivilegeFreePass)
+	}
+	switch *privilege {
+	case models.VIPPrivilegePriority, models.VIPPrivilegeFreePass:
+		return nil
+	default:
+		return fmt.Errorf("неизвестная привилегия VIP: %s", *privilege)
+	}
+}
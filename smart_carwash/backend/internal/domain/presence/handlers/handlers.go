@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"carwash_backend/internal/domain/presence/models"
+	"carwash_backend/internal/domain/presence/service"
+	"carwash_backend/internal/logger"
+)
+
+// Handler представляет HTTP обработчики датчиков присутствия
+type Handler struct {
+	presenceService service.Service
+}
+
+// NewHandler создает новый экземпляр обработчика
+func NewHandler(presenceService service.Service) *Handler {
+	return &Handler{
+		presenceService: presenceService,
+	}
+}
+
+// SensorEvents принимает изменения датчиков присутствия от modbus сервера
+// POST /api/v1/modbus/sensor-events
+// При ошибке modbus сервер повторит доставку: повторно присланные изменения не применяются дважды
+func (h *Handler) SensorEvents(c *gin.Context) {
+	var req models.SensorEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	processed, err := h.presenceService.HandleSensorEvents(c.Request.Context(), req.Events)
+	if err != nil {
+		logger.Printf("Ошибка обработки событий датчиков присутствия - events: %d, error: %v", len(req.Events), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SensorEventsResponse{
+		Success:   true,
+		Processed: processed,
+	})
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"carwash_backend/internal/domain/presence/middleware"
+)
+
+// SetupRoutes настраивает маршруты датчиков присутствия
+func SetupRoutes(router *gin.RouterGroup, handler *Handler, eventsToken string) {
+	// События modbus сервера (Bearer токен MODBUS_EVENTS_TOKEN)
+	router.POST("/modbus/sensor-events", middleware.ModbusEventsAuthMiddleware(eventsToken), handler.SensorEvents)
+}
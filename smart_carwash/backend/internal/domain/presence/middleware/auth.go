@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModbusEventsAuthMiddleware создает middleware для аутентификации событий modbus сервера
+// Токен совпадает с BACKEND_EVENTS_TOKEN modbus сервера
+func ModbusEventsAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Проверяем, что токен настроен
+		if token == "" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "MODBUS_EVENTS_TOKEN не настроен"})
+			c.Abort()
+			return
+		}
+
+		// Проверяем формат Bearer token
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Неверный формат заголовка Authorization. Ожидается: Bearer <token>"})
+			c.Abort()
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(parts[1]), []byte(token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Неверный токен"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Действия, выполненные по изменению датчика присутствия
+const (
+	ActionAutoStart   = "auto_start"   // Назначенная сессия запущена по въезду машины
+	ActionCarReturned = "car_returned" // Машина вернулась в бокс активной сессии, пометка снята
+	ActionBoxReleased = "box_released" // Машина уехала после сессии, кулдаун бокса снят
+)
+
+// SensorEvent изменение датчика присутствия от modbus сервера
+type SensorEvent struct {
+	BoxID     uuid.UUID `json:"box_id" binding:"required"`
+	Device    string    `json:"device"`
+	Register  string    `json:"register" binding:"required"`
+	Value     bool      `json:"value"` // true - машина в боксе
+	ChangedAt time.Time `json:"changed_at" binding:"required"`
+}
+
+// SensorEventsRequest пакет изменений датчиков присутствия
+type SensorEventsRequest struct {
+	Events []SensorEvent `json:"events" binding:"max=1000,dive"`
+}
+
+// SensorEventsResponse ответ на пакет изменений датчиков
+type SensorEventsResponse struct {
+	Success   bool `json:"success"`
+	Processed int  `json:"processed"` // Сколько изменений изменили состояние бокса; повторы не учитываются
+}
+
+// NoCarSession активная сессия, в боксе которой нет машины
+type NoCarSession struct {
+	SessionID   uuid.UUID
+	UserID      uuid.UUID
+	SiteID      uuid.UUID
+	BoxID       uuid.UUID
+	BoxNumber   int
+	AbsentSince time.Time // С какого момента нет машины: выезд из бокса или старт сессии, если машины не было
+}
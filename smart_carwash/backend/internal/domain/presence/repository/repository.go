@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"carwash_backend/internal/domain/presence/models"
+	sessionModels "carwash_backend/internal/domain/session/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
+)
+
+// Repository интерфейс для хранения состояния датчиков присутствия
+type Repository interface {
+	GetWashBoxByID(ctx context.Context, id uuid.UUID) (*washboxModels.WashBox, error)
+	UpdateCarPresence(ctx context.Context, boxID uuid.UUID, register string, present bool, changedAt time.Time) (bool, error)
+	ClearNoCar(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	GetNoCarSessions(ctx context.Context, absentBefore time.Time) ([]models.NoCarSession, error)
+	MarkNoCar(ctx context.Context, sessionID uuid.UUID, since time.Time) (bool, error)
+}
+
+// PostgresRepository реализация Repository для PostgreSQL
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository создает новый экземпляр PostgresRepository
+func NewPostgresRepository(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{
+		db: db,
+	}
+}
+
+// GetWashBoxByID получает бокс по ID
+func (r *PostgresRepository) GetWashBoxByID(ctx context.Context, id uuid.UUID) (*washboxModels.WashBox, error) {
+	var box washboxModels.WashBox
+	if err := r.db.WithContext(ctx).First(&box, id).Error; err != nil {
+		return nil, err
+	}
+	return &box, nil
+}
+
+// UpdateCarPresence сохраняет состояние датчика бокса
+// Возвращает false, если состояние не изменилось, изменение старше сохраненного или датчик
+// бокса уже другой: так повторная доставка и синхронизация с modbus сервером безопасны
+func (r *PostgresRepository) UpdateCarPresence(ctx context.Context, boxID uuid.UUID, register string, present bool, changedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&washboxModels.WashBox{}).
+		Where("id = ? AND presence_sensor_register = ?", boxID, register).
+		Where("car_present IS DISTINCT FROM ?", present).
+		Where("car_present_changed_at IS NULL OR car_present_changed_at <= ?", changedAt).
+		Updates(map[string]interface{}{
+			"car_present":            present,
+			"car_present_changed_at": changedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClearNoCar снимает пометку об отсутствии машины; false - сессия не была помечена
+func (r *PostgresRepository) ClearNoCar(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&sessionModels.Session{}).
+		Where("id = ? AND no_car_since IS NOT NULL", sessionID).
+		Update("no_car_since", nil)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetNoCarSessions получает непомеченные активные сессии, в боксе которых нет машины с absentBefore или раньше
+func (r *PostgresRepository) GetNoCarSessions(ctx context.Context, absentBefore time.Time) ([]models.NoCarSession, error) {
+	var sessions []models.NoCarSession
+	err := r.db.WithContext(ctx).Table("sessions").
+		Select("sessions.id AS session_id, sessions.user_id, sessions.site_id, sessions.box_id, wash_boxes.number AS box_number, "+
+			"GREATEST(sessions.status_updated_at, wash_boxes.car_present_changed_at) AS absent_since").
+		Joins("JOIN wash_boxes ON wash_boxes.id = sessions.box_id AND wash_boxes.deleted_at IS NULL").
+		Where("sessions.status = ? AND sessions.no_car_since IS NULL AND sessions.deleted_at IS NULL", sessionModels.SessionStatusActive).
+		Where("wash_boxes.car_present = FALSE").
+		Where("GREATEST(sessions.status_updated_at, wash_boxes.car_present_changed_at) <= ?", absentBefore).
+		Scan(&sessions).Error
+	return sessions, err
+}
+
+// MarkNoCar помечает активную сессию, в боксе которой нет машины; false - сессия уже помечена или не активна
+func (r *PostgresRepository) MarkNoCar(ctx context.Context, sessionID uuid.UUID, since time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&sessionModels.Session{}).
+		Where("id = ? AND status = ? AND no_car_since IS NULL", sessionID, sessionModels.SessionStatusActive).
+		Update("no_car_since", since)
+	return result.RowsAffected > 0, result.Error
+}
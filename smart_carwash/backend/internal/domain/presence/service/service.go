@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"carwash_backend/internal/config"
+	modbusModels "carwash_backend/internal/domain/modbus/models"
+	"carwash_backend/internal/domain/presence/models"
+	"carwash_backend/internal/domain/presence/repository"
+	realtimeModels "carwash_backend/internal/domain/realtime/models"
+	realtimeService "carwash_backend/internal/domain/realtime/service"
+	sessionModels "carwash_backend/internal/domain/session/models"
+	"carwash_backend/internal/logger"
+)
+
+// Service представляет сервис датчиков присутствия автомобиля в боксах
+type Service interface {
+	HandleSensorEvents(ctx context.Context, events []models.SensorEvent) (int, error)
+	SyncSensors(ctx context.Context) error
+	CheckNoCarSessions(ctx context.Context) error
+}
+
+// SessionService интерфейс для работы с сессиями
+type SessionService interface {
+	GetActiveSessionByBoxID(ctx context.Context, boxID uuid.UUID) (*sessionModels.Session, error)
+	StartSession(ctx context.Context, req *sessionModels.StartSessionRequest) (*sessionModels.Session, error)
+}
+
+// WashboxService интерфейс для работы с боксами
+type WashboxService interface {
+	ClearCooldown(ctx context.Context, boxID uuid.UUID) error
+}
+
+// SensorSource интерфейс для синхронизации датчиков с modbus сервером
+type SensorSource interface {
+	SyncPresenceSensors(ctx context.Context) ([]modbusModels.SensorState, error)
+}
+
+// ServiceImpl реализует интерфейс Service
+type ServiceImpl struct {
+	repo           repository.Repository
+	sessionService SessionService
+	washboxService WashboxService
+	sensors        SensorSource
+	config         *config.Config
+	eventPublisher realtimeService.Publisher
+}
+
+// NewService создает новый экземпляр сервиса
+func NewService(repo repository.Repository, sessionService SessionService, washboxService WashboxService, sensors SensorSource, cfg *config.Config) *ServiceImpl {
+	return &ServiceImpl{
+		repo:           repo,
+		sessionService: sessionService,
+		washboxService: washboxService,
+		sensors:        sensors,
+		config:         cfg,
+	}
+}
+
+// SetEventPublisher устанавливает отправку изменений датчиков в поток событий
+func (s *ServiceImpl) SetEventPublisher(publisher realtimeService.Publisher) {
+	s.eventPublisher = publisher
+}
+
+// HandleSensorEvents обрабатывает изменения датчиков, присланные modbus сервером
+// Возвращает число изменений, которые изменили состояние бокса
+func (s *ServiceImpl) HandleSensorEvents(ctx context.Context, events []models.SensorEvent) (int, error) {
+	processed := 0
+	for _, event := range events {
+		changed, err := s.handleSensorState(ctx, event.BoxID, event.Register, event.Value, event.ChangedAt)
+		if err != nil {
+			return processed, err
+		}
+		if changed {
+			processed++
+		}
+	}
+	return processed, nil
+}
+
+// SyncSensors передает modbus серверу датчики боксов и применяет прочитанное им состояние
+// Так изменения, которые не удалось доставить событием, применяются не позже следующей синхронизации
+func (s *ServiceImpl) SyncSensors(ctx context.Context) error {
+	states, err := s.sensors.SyncPresenceSensors(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		if state.Value == nil || state.ChangedAt == nil {
+			continue
+		}
+		if _, err := s.handleSensorState(ctx, state.BoxID, state.Register, *state.Value, *state.ChangedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckNoCarSessions помечает активные сессии, в боксе которых нет машины дольше PRESENCE_NO_CAR_MINUTES
+func (s *ServiceImpl) CheckNoCarSessions(ctx context.Context) error {
+	if s.config.PresenceNoCarMinutes <= 0 {
+		return nil
+	}
+
+	absentBefore := time.Now().Add(-time.Duration(s.config.PresenceNoCarMinutes) * time.Minute)
+	sessions, err := s.repo.GetNoCarSessions(ctx, absentBefore)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		marked, err := s.repo.MarkNoCar(ctx, session.SessionID, session.AbsentSince)
+		if err != nil {
+			return err
+		}
+		if !marked {
+			continue
+		}
+
+		logger.WithFields(logrus.Fields{
+			"service":      "presence",
+			"method":       "CheckNoCarSessions",
+			"session_id":   session.SessionID,
+			"box_id":       session.BoxID,
+			"box_number":   session.BoxNumber,
+			"no_car_since": session.AbsentSince,
+		}).Warn("В боксе активной сессии нет машины")
+
+		if s.eventPublisher != nil {
+			s.eventPublisher.Publish(realtimeModels.Event{
+				Type:     realtimeModels.EventTypeSessionNoCar,
+				Channels: []string{realtimeModels.ChannelCashier, realtimeModels.ChannelAdmin},
+				SiteID:   session.SiteID,
+				Data: realtimeModels.SessionNoCarEvent{
+					SessionID:  session.SessionID,
+					BoxID:      session.BoxID,
+					BoxNumber:  session.BoxNumber,
+					NoCarSince: session.AbsentSince,
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// handleSensorState сохраняет состояние датчика бокса и реагирует на его изменение:
+// въезд машины запускает назначенную сессию или снимает пометку активной сессии,
+// выезд после завершения сессии снимает кулдаун бокса. Возвращает false, если состояние не изменилось
+func (s *ServiceImpl) handleSensorState(ctx context.Context, boxID uuid.UUID, register string, present bool, changedAt time.Time) (bool, error) {
+	changed, err := s.repo.UpdateCarPresence(ctx, boxID, register, present, changedAt)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	box, err := s.repo.GetWashBoxByID(ctx, boxID)
+	if err != nil {
+		return true, err
+	}
+
+	session, err := s.sessionService.GetActiveSessionByBoxID(ctx, boxID)
+	if err != nil {
+		return true, err
+	}
+
+	log := logger.WithFields(logrus.Fields{
+		"service":    "presence",
+		"method":     "handleSensorState",
+		"box_id":     boxID,
+		"box_number": box.Number,
+		"present":    present,
+		"changed_at": changedAt,
+	})
+	log.Info("Изменение датчика присутствия")
+
+	action := ""
+	switch {
+	case present && session != nil && session.Status == sessionModels.SessionStatusAssigned && s.config.PresenceAutoStart:
+		if _, err := s.sessionService.StartSession(ctx, &sessionModels.StartSessionRequest{SessionID: session.ID}); err != nil {
+			log.WithField("session_id", session.ID).Errorf("Ошибка автозапуска сессии по въезду машины: %v", err)
+		} else {
+			action = models.ActionAutoStart
+			log.WithField("session_id", session.ID).Info("Сессия запущена по въезду машины")
+		}
+	case present && session != nil && session.Status == sessionModels.SessionStatusActive:
+		cleared, err := s.repo.ClearNoCar(ctx, session.ID)
+		if err != nil {
+			return true, err
+		}
+		if cleared {
+			action = models.ActionCarReturned
+			log.WithField("session_id", session.ID).Info("Машина вернулась в бокс активной сессии")
+		}
+	case !present && session == nil && box.CooldownUntil != nil && box.Status != "maintenance":
+		// Машина уехала после сессии: кулдаун для повторной аренды больше не нужен
+		if err := s.washboxService.ClearCooldown(ctx, boxID); err != nil {
+			log.Errorf("Ошибка снятия кулдауна бокса после выезда машины: %v", err)
+		} else {
+			action = models.ActionBoxReleased
+			log.Info("Кулдаун бокса снят после выезда машины")
+		}
+	}
+
+	if s.eventPublisher != nil {
+		var sessionID *uuid.UUID
+		if session != nil {
+			sessionID = &session.ID
+		}
+		s.eventPublisher.Publish(realtimeModels.Event{
+			Type:     realtimeModels.EventTypeCarPresence,
+			Channels: []string{realtimeModels.ChannelCashier, realtimeModels.ChannelAdmin},
+			SiteID:   box.SiteID,
+			Data: realtimeModels.CarPresenceEvent{
+				BoxID:     boxID,
+				BoxNumber: box.Number,
+				Present:   present,
+				ChangedAt: changedAt,
+				SessionID: sessionID,
+				Action:    action,
+			},
+		})
+	}
+	return true, nil
+}
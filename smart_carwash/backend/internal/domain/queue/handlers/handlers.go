@@ -5,6 +5,7 @@ import (
 
 	"carwash_backend/internal/domain/queue/models"
 	"carwash_backend/internal/domain/queue/service"
+	"carwash_backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -44,8 +45,15 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup, cashierMiddleware gin.
 // getQueueStatus обработчик для получения статуса очереди и боксов
 // Для мини-приложения не загружаем пользователей (includeUsers=false) для оптимизации
 func (h *Handler) getQueueStatus(c *gin.Context) {
+	// Площадка, выбранная в мини-приложении
+	siteID, err := middleware.RequestSiteID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Получаем статус очереди и боксов без пользователей (для мини-приложения)
-	queueStatus, err := h.service.GetQueueStatus(c.Request.Context(), false)
+	queueStatus, err := h.service.GetQueueStatus(c.Request.Context(), siteID, false)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -56,9 +64,16 @@ func (h *Handler) getQueueStatus(c *gin.Context) {
 
 // adminGetQueueStatus обработчик для получения детального статуса очереди для администратора
 func (h *Handler) adminGetQueueStatus(c *gin.Context) {
+	siteID, err := middleware.RequestSiteID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Получаем детальный статус очереди (всегда включаем детали)
 	req := &models.AdminQueueStatusRequest{
 		IncludeDetails: true,
+		SiteID:         siteID,
 	}
 
 	// Получаем детальный статус очереди
@@ -71,6 +86,7 @@ func (h *Handler) adminGetQueueStatus(c *gin.Context) {
 	// Логируем мета-параметры
 	c.Set("meta", gin.H{
 		"include_details":  true,
+		"site_id":          siteID,
 		"total_queue_size": resp.QueueStatus.TotalQueueSize,
 		"has_any_queue":    resp.QueueStatus.HasAnyQueue,
 	})
@@ -80,8 +96,10 @@ func (h *Handler) adminGetQueueStatus(c *gin.Context) {
 
 // cashierGetQueueStatus обработчик для получения детального статуса очереди для кассира
 func (h *Handler) cashierGetQueueStatus(c *gin.Context) {
+	// Кассир видит очередь только своей площадки
 	req := &models.AdminQueueStatusRequest{
 		IncludeDetails: true,
+		SiteID:         middleware.SiteIDFromContext(c),
 	}
 
 	resp, err := h.service.AdminGetQueueStatus(c.Request.Context(), req)
@@ -92,6 +110,7 @@ func (h *Handler) cashierGetQueueStatus(c *gin.Context) {
 
 	meta := gin.H{
 		"include_details":  true,
+		"site_id":          req.SiteID,
 		"total_queue_size": resp.QueueStatus.TotalQueueSize,
 		"has_any_queue":    resp.QueueStatus.HasAnyQueue,
 	}
@@ -2,6 +2,8 @@ package models
 
 import (
 	washboxModels "carwash_backend/internal/domain/washbox/models"
+
+	"github.com/google/uuid"
 )
 
 // ServiceQueueInfo представляет информацию об очереди для конкретного типа услуги
@@ -14,8 +16,9 @@ type ServiceQueueInfo struct {
 	WaitTimeMinutes *int                    `json:"wait_time_minutes,omitempty"` // Время ожидания в минутах (если есть очередь)
 }
 
-// QueueStatus представляет статус очереди и боксов
+// QueueStatus представляет статус очереди и боксов площадки
 type QueueStatus struct {
+	SiteID         uuid.UUID               `json:"site_id"`
 	AllBoxes       []washboxModels.WashBox `json:"all_boxes"`
 	WashQueue      ServiceQueueInfo        `json:"wash_queue"`
 	AirDryQueue    ServiceQueueInfo        `json:"air_dry_queue"`
@@ -28,7 +31,8 @@ type QueueStatus struct {
 
 // AdminQueueStatusRequest запрос на получение статуса очереди для администратора
 type AdminQueueStatusRequest struct {
-	IncludeDetails bool `json:"include_details"`
+	IncludeDetails bool      `json:"include_details"`
+	SiteID         uuid.UUID `json:"-"` // Площадка (по умолчанию основная)
 }
 
 // AdminQueueStatusResponse ответ на получение статуса очереди для администратора
@@ -53,4 +57,10 @@ type QueueUser struct {
 	Position     int    `json:"position"`
 	WaitingSince string `json:"waiting_since"` // Время добавления в очередь
 	CarNumber    string `json:"car_number"`    // Номер машины
+	// Класс приоритета в очереди (normal, subscriber, fleet, skip_line, vip)
+	PriorityClass string `json:"priority_class"`
+	// Сколько раз клиент отложил назначенный бокс
+	SnoozeCount int `json:"snooze_count"`
+	// Прогноз ожидания назначения бокса в минутах (если удалось рассчитать)
+	ETAMinutes *int `json:"eta_minutes,omitempty"`
 }
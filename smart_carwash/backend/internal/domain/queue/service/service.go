@@ -10,16 +10,18 @@ import (
 	"carwash_backend/internal/domain/queue/models"
 	sessionModels "carwash_backend/internal/domain/session/models"
 	sessionService "carwash_backend/internal/domain/session/service"
+	siteModels "carwash_backend/internal/domain/site/models"
 	userModels "carwash_backend/internal/domain/user/models"
 	userService "carwash_backend/internal/domain/user/service"
 	washboxModels "carwash_backend/internal/domain/washbox/models"
 	washboxService "carwash_backend/internal/domain/washbox/service"
+	"carwash_backend/internal/logger"
 	"carwash_backend/internal/metrics"
 )
 
 // Service интерфейс для бизнес-логики очереди
 type Service interface {
-	GetQueueStatus(ctx context.Context, includeUsers bool) (*models.QueueStatus, error)
+	GetQueueStatus(ctx context.Context, siteID uuid.UUID, includeUsers bool) (*models.QueueStatus, error)
 
 	// Административные методы
 	AdminGetQueueStatus(ctx context.Context, req *models.AdminQueueStatusRequest) (*models.AdminQueueStatusResponse, error)
@@ -43,26 +45,27 @@ func NewService(sessionService sessionService.Service, washboxService washboxSer
 	}
 }
 
-// getServiceQueueInfo получает информацию об очереди для конкретного типа услуги
-func (s *ServiceImpl) getServiceQueueInfo(ctx context.Context, serviceType string, includeUsers bool) (*models.ServiceQueueInfo, error) {
+// getServiceQueueInfo получает информацию об очереди площадки для конкретного типа услуги
+func (s *ServiceImpl) getServiceQueueInfo(ctx context.Context, siteID uuid.UUID, serviceType string, includeUsers bool) (*models.ServiceQueueInfo, error) {
 	// Проверяем контекст перед DB запросами
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	// Получаем боксы для данного типа услуги
+	// Получаем боксы площадки для данного типа услуги
 	boxes, err := s.washboxService.GetWashBoxesByServiceType(ctx, serviceType)
 	if err != nil {
 		return nil, err
 	}
+	boxes = washboxModels.FilterBySite(boxes, siteID)
 
 	// Проверяем контекст после запроса боксов
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	// Получаем сессии со статусом "created"
-	createdSessions, err := s.sessionService.GetSessionsByStatus(ctx, sessionModels.SessionStatusInQueue)
+	// Получаем сессии в очереди в порядке обслуживания (с учетом классов приоритета)
+	queuedSessions, err := s.sessionService.GetOrderedQueue(ctx, siteID, serviceType)
 	if err != nil {
 		return nil, err
 	}
@@ -78,13 +81,11 @@ func (s *ServiceImpl) getServiceQueueInfo(ctx context.Context, serviceType strin
 	var sessionUserIDs []uuid.UUID
 	sessionsByType := make([]sessionModels.Session, 0)
 
-	// Собираем сессии нужного типа и ID пользователей
-	for _, session := range createdSessions {
-		if session.ServiceType == serviceType {
-			queueSize++
-			sessionUserIDs = append(sessionUserIDs, session.UserID)
-			sessionsByType = append(sessionsByType, session)
-		}
+	// Собираем сессии и ID пользователей
+	for _, session := range queuedSessions {
+		queueSize++
+		sessionUserIDs = append(sessionUserIDs, session.UserID)
+		sessionsByType = append(sessionsByType, session)
 	}
 
 	// Загружаем пользователей батчем только если нужно
@@ -110,6 +111,16 @@ func (s *ServiceImpl) getServiceQueueInfo(ctx context.Context, serviceType strin
 
 	// Формируем список пользователей в очереди
 	if includeUsers {
+		// Прогноз ожидания по позициям очереди
+		etaBySession := make(map[uuid.UUID]int)
+		etas, err := s.sessionService.GetQueueETAs(ctx, siteID, serviceType)
+		if err != nil {
+			logger.Printf("getServiceQueueInfo: ошибка расчета прогноза ожидания, service_type: %s, error: %v", serviceType, err)
+		}
+		for _, eta := range etas {
+			etaBySession[eta.SessionID] = eta.ETAMinutes
+		}
+
 		for i, session := range sessionsByType {
 			user, ok := usersMap[session.UserID]
 			if !ok {
@@ -124,14 +135,19 @@ func (s *ServiceImpl) getServiceQueueInfo(ctx context.Context, serviceType strin
 
 			// Добавляем пользователя в очередь
 			queueUser := models.QueueUser{
-				UserID:       session.UserID.String(),
-				Username:     user.Username,
-				FirstName:    user.FirstName,
-				LastName:     user.LastName,
-				ServiceType:  serviceType,
-				Position:     i + 1, // Позиция в очереди
-				WaitingSince: session.CreatedAt.Format("2006-01-02 15:04:05"),
-				CarNumber:    session.CarNumber, // Номер машины из сессии
+				UserID:        session.UserID.String(),
+				Username:      user.Username,
+				FirstName:     user.FirstName,
+				LastName:      user.LastName,
+				ServiceType:   serviceType,
+				Position:      i + 1, // Позиция в очереди
+				WaitingSince:  session.CreatedAt.Format("2006-01-02 15:04:05"),
+				CarNumber:     session.CarNumber, // Номер машины из сессии
+				PriorityClass: session.PriorityClass,
+				SnoozeCount:   session.SnoozeCount,
+			}
+			if eta, ok := etaBySession[session.ID]; ok {
+				queueUser.ETAMinutes = &eta
 			}
 			usersInQueue = append(usersInQueue, queueUser)
 		}
@@ -142,6 +158,7 @@ func (s *ServiceImpl) getServiceQueueInfo(ctx context.Context, serviceType strin
 	if err != nil {
 		return nil, err
 	}
+	freeBoxes = washboxModels.FilterBySite(freeBoxes, siteID)
 
 	// Определяем, есть ли очередь
 	hasQueue := queueSize > len(freeBoxes)
@@ -159,7 +176,7 @@ func (s *ServiceImpl) getServiceQueueInfo(ctx context.Context, serviceType strin
 		now := time.Now()
 
 		for _, session := range activeSessions {
-			if session.ServiceType == serviceType {
+			if session.SiteID == siteID && session.ServiceType == serviceType {
 				// Общее время сессии в минутах
 				totalTimeMinutes := session.RentalTimeMinutes + session.ExtensionTimeMinutes
 
@@ -199,8 +216,8 @@ func (s *ServiceImpl) getServiceQueueInfo(ctx context.Context, serviceType strin
 	}, nil
 }
 
-// GetQueueStatus получает статус очереди и боксов
-func (s *ServiceImpl) GetQueueStatus(ctx context.Context, includeUsers bool) (*models.QueueStatus, error) {
+// GetQueueStatus получает статус очереди и боксов площадки
+func (s *ServiceImpl) GetQueueStatus(ctx context.Context, siteID uuid.UUID, includeUsers bool) (*models.QueueStatus, error) {
 	// Проверяем контекст перед DB запросами
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
@@ -211,6 +228,7 @@ func (s *ServiceImpl) GetQueueStatus(ctx context.Context, includeUsers bool) (*m
 	if err != nil {
 		return nil, err
 	}
+	allBoxes = washboxModels.FilterBySite(allBoxes, siteID)
 
 	// Проверяем контекст после каждого шага
 	if ctx.Err() != nil {
@@ -218,7 +236,7 @@ func (s *ServiceImpl) GetQueueStatus(ctx context.Context, includeUsers bool) (*m
 	}
 
 	// Получаем информацию об очереди для каждого типа услуги
-	washQueueInfo, err := s.getServiceQueueInfo(ctx, washboxModels.ServiceTypeWash, includeUsers)
+	washQueueInfo, err := s.getServiceQueueInfo(ctx, siteID, washboxModels.ServiceTypeWash, includeUsers)
 	if err != nil {
 		return nil, err
 	}
@@ -227,7 +245,7 @@ func (s *ServiceImpl) GetQueueStatus(ctx context.Context, includeUsers bool) (*m
 		return nil, ctx.Err()
 	}
 
-	airDryQueueInfo, err := s.getServiceQueueInfo(ctx, washboxModels.ServiceTypeAirDry, includeUsers)
+	airDryQueueInfo, err := s.getServiceQueueInfo(ctx, siteID, washboxModels.ServiceTypeAirDry, includeUsers)
 	if err != nil {
 		return nil, err
 	}
@@ -236,7 +254,7 @@ func (s *ServiceImpl) GetQueueStatus(ctx context.Context, includeUsers bool) (*m
 		return nil, ctx.Err()
 	}
 
-	vacuumQueueInfo, err := s.getServiceQueueInfo(ctx, washboxModels.ServiceTypeVacuum, includeUsers)
+	vacuumQueueInfo, err := s.getServiceQueueInfo(ctx, siteID, washboxModels.ServiceTypeVacuum, includeUsers)
 	if err != nil {
 		return nil, err
 	}
@@ -247,8 +265,8 @@ func (s *ServiceImpl) GetQueueStatus(ctx context.Context, includeUsers bool) (*m
 	// Определяем, есть ли очередь хотя бы для одного типа услуги
 	hasAnyQueue := washQueueInfo.HasQueue || airDryQueueInfo.HasQueue || vacuumQueueInfo.HasQueue
 
-	// Обновляем метрики очереди
-	if s.metrics != nil {
+	// Обновляем метрики очереди (метрики без разбивки по площадкам, поэтому только для основной)
+	if s.metrics != nil && siteID == siteModels.DefaultSiteID {
 		s.metrics.UpdateQueueSize("wash", float64(washQueueInfo.QueueSize))
 		s.metrics.UpdateQueueSize("air_dry", float64(airDryQueueInfo.QueueSize))
 		s.metrics.UpdateQueueSize("vacuum", float64(vacuumQueueInfo.QueueSize))
@@ -256,6 +274,7 @@ func (s *ServiceImpl) GetQueueStatus(ctx context.Context, includeUsers bool) (*m
 
 	// Формируем ответ
 	return &models.QueueStatus{
+		SiteID:         siteID,
 		AllBoxes:       allBoxes,
 		WashQueue:      *washQueueInfo,
 		AirDryQueue:    *airDryQueueInfo,
@@ -268,7 +287,7 @@ func (s *ServiceImpl) GetQueueStatus(ctx context.Context, includeUsers bool) (*m
 // AdminGetQueueStatus получает детальный статус очереди для администратора
 func (s *ServiceImpl) AdminGetQueueStatus(ctx context.Context, req *models.AdminQueueStatusRequest) (*models.AdminQueueStatusResponse, error) {
 	// Получаем базовый статус очереди (для админки всегда включаем пользователей)
-	queueStatus, err := s.GetQueueStatus(ctx, true)
+	queueStatus, err := s.GetQueueStatus(ctx, req.SiteID, true)
 	if err != nil {
 		return nil, err
 	}
@@ -279,7 +298,7 @@ func (s *ServiceImpl) AdminGetQueueStatus(ctx context.Context, req *models.Admin
 
 	// Если запрошены детали, добавляем их
 	if req.IncludeDetails {
-		details, err := s.getQueueDetails(ctx)
+		details, err := s.getQueueDetails(ctx, req.SiteID)
 		if err != nil {
 			return nil, err
 		}
@@ -289,37 +308,32 @@ func (s *ServiceImpl) AdminGetQueueStatus(ctx context.Context, req *models.Admin
 	return response, nil
 }
 
-// getQueueDetails получает детальную информацию об очереди
-func (s *ServiceImpl) getQueueDetails(ctx context.Context) (*models.QueueDetails, error) {
-	// Получаем все сессии со статусом "created" (в очереди)
-	createdSessions, err := s.sessionService.GetSessionsByStatus(ctx, sessionModels.SessionStatusCreated)
-	if err != nil {
-		return nil, err
-	}
-
-	// Группируем сессии по типу услуги
-	sessionsByType := make(map[string][]sessionModels.Session)
-	for _, session := range createdSessions {
-		sessionsByType[session.ServiceType] = append(sessionsByType[session.ServiceType], session)
-	}
-
+// getQueueDetails получает детальную информацию об очереди площадки
+func (s *ServiceImpl) getQueueDetails(ctx context.Context, siteID uuid.UUID) (*models.QueueDetails, error) {
 	var usersInQueue []models.QueueUser
 	var queueOrder []string
 
-	// Обрабатываем каждый тип услуги
-	for serviceType, sessions := range sessionsByType {
+	// Обрабатываем каждый тип услуги в порядке обслуживания очереди
+	for _, serviceType := range []string{washboxModels.ServiceTypeWash, washboxModels.ServiceTypeAirDry, washboxModels.ServiceTypeVacuum} {
+		sessions, err := s.sessionService.GetOrderedQueue(ctx, siteID, serviceType)
+		if err != nil {
+			return nil, err
+		}
+
 		for i, session := range sessions {
 			// Здесь нужно получить информацию о пользователе
 			// Пока используем базовую информацию
 			user := models.QueueUser{
-				UserID:       session.UserID.String(),
-				Username:     "Пользователь", // Нужно получить из user service
-				FirstName:    "Имя",          // Нужно получить из user service
-				LastName:     "Фамилия",      // Нужно получить из user service
-				ServiceType:  serviceType,
-				Position:     i + 1,
-				WaitingSince: session.CreatedAt.Format("2006-01-02 15:04:05"),
-				CarNumber:    session.CarNumber, // Номер машины из сессии
+				UserID:        session.UserID.String(),
+				Username:      "Пользователь", // Нужно получить из user service
+				FirstName:     "Имя",          // Нужно получить из user service
+				LastName:      "Фамилия",      // Нужно получить из user service
+				ServiceType:   serviceType,
+				Position:      i + 1,
+				WaitingSince:  session.CreatedAt.Format("2006-01-02 15:04:05"),
+				CarNumber:     session.CarNumber, // Номер машины из сессии
+				PriorityClass: session.PriorityClass,
+				SnoozeCount:   session.SnoozeCount,
 			}
 			usersInQueue = append(usersInQueue, user)
 			queueOrder = append(queueOrder, session.UserID.String())
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"carwash_backend/internal/domain/realtime/models"
+	"carwash_backend/internal/domain/realtime/service"
+	"carwash_backend/internal/logger"
+	"carwash_backend/internal/middleware"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// heartbeatInterval период отправки пустого комментария, чтобы прокси не закрывали соединение
+	heartbeatInterval = 20 * time.Second
+	// writeTimeout время на запись одного события клиенту
+	writeTimeout = 10 * time.Second
+	// retryMillis через сколько клиент должен переподключиться после обрыва
+	retryMillis = 3000
+)
+
+// Handler структура для обработчиков потока событий
+type Handler struct {
+	service service.Service
+}
+
+// NewHandler создает новый экземпляр Handler
+func NewHandler(service service.Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты потока событий (Server-Sent Events)
+// Маршруты нельзя регистрировать в группе с таймаутом запроса - соединение держится открытым
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup, userMiddleware gin.HandlerFunc, cashierMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+	realtimeRoutes := router.Group("/realtime")
+	{
+		// Пользователь получает только события своих сессий, ID берется из подписанных данных Telegram
+		realtimeRoutes.GET("/user", userMiddleware, h.streamUser)
+		// EventSource в браузере не умеет передавать заголовки, поэтому токен можно передать в query
+		realtimeRoutes.GET("/cashier", tokenFromQuery(), cashierMiddleware, h.streamCashier)
+		realtimeRoutes.GET("/admin", tokenFromQuery(), adminMiddleware, h.streamAdmin)
+	}
+}
+
+// tokenFromQuery переносит токен из параметра token в заголовок Authorization
+func tokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next()
+	}
+}
+
+// streamUser поток событий сессий пользователя
+func (h *Handler) streamUser(c *gin.Context) {
+	value, exists := c.Get(middleware.TelegramUserIDContextKey)
+	userID, ok := value.(uuid.UUID)
+	if !exists || !ok || userID == uuid.Nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		return
+	}
+
+	h.stream(c, models.ChannelUser, &userID, uuid.Nil)
+}
+
+// streamCashier поток событий для кассира по площадке из его токена
+func (h *Handler) streamCashier(c *gin.Context) {
+	h.stream(c, models.ChannelCashier, nil, middleware.SiteIDFromContext(c))
+}
+
+// streamAdmin поток событий для администратора
+func (h *Handler) streamAdmin(c *gin.Context) {
+	h.stream(c, models.ChannelAdmin, nil, uuid.Nil)
+}
+
+// stream отправляет события канала клиенту до закрытия соединения
+func (h *Handler) stream(c *gin.Context, channel string, userID *uuid.UUID, siteID uuid.UUID) {
+	// Клиент передает ID последнего полученного события при переподключении
+	var lastEventID int64
+	if value := c.GetHeader("Last-Event-ID"); value != "" {
+		lastEventID, _ = strconv.ParseInt(value, 10, 64)
+	}
+
+	sub, missed := h.service.Subscribe(channel, userID, siteID, lastEventID)
+	defer h.service.Unsubscribe(sub)
+
+	logger.WithContext(c).Infof("Realtime: подписка на канал %s, last_event_id: %d, пропущено событий: %d", channel, lastEventID, len(missed))
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Отключаем буферизацию в nginx
+	c.Status(http.StatusOK)
+
+	// Таймаут записи сервера рассчитан на обычные запросы, для потока продлеваем его перед каждой записью
+	rc := http.NewResponseController(c.Writer)
+	write := func(payload string) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := fmt.Fprint(c.Writer, payload); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+
+	if !write(fmt.Sprintf("retry: %d\n\n", retryMillis)) {
+		return
+	}
+	for _, event := range missed {
+		if !write(formatEvent(event)) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if !write(formatEvent(event)) {
+				return
+			}
+		case <-heartbeat.C:
+			if !write(": ping\n\n") {
+				return
+			}
+		}
+	}
+}
+
+// formatEvent форматирует событие в формате Server-Sent Events
+func formatEvent(event models.Event) string {
+	data, err := json.Marshal(event)
+	if err != nil {
+		data = []byte("{}")
+	}
+	return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Каналы потока событий
+const (
+	ChannelUser    = "user"    // События сессий конкретного пользователя
+	ChannelCashier = "cashier" // События для кассира
+	ChannelAdmin   = "admin"   // Все события для администратора
+)
+
+// Типы событий
+const (
+	EventTypeBoxStatus     = "box_status"     // Изменение статуса бокса
+	EventTypeCoilState     = "coil_state"     // Изменение состояния катушки (свет, химия)
+	EventTypeSessionStatus = "session_status" // Изменение статуса сессии
+	EventTypeQueuePosition = "queue_position" // Изменение позиции сессии в очереди
+	EventTypeQueueUpdate   = "queue_update"   // Изменение очереди по типу услуги
+	EventTypeModbusAlert   = "modbus_alert"   // Команда Modbus не выполнена в срок
+	EventTypeCarPresence   = "car_presence"   // Изменение датчика присутствия автомобиля в боксе
+	EventTypeSessionNoCar  = "session_no_car" // В боксе активной сессии долго нет машины
+)
+
+// Event событие для отправки подписчикам
+type Event struct {
+	ID        int64       `json:"id"`
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+	Channels  []string    `json:"-"` // Каналы, в которые отправляется событие
+	UserID    *uuid.UUID  `json:"-"` // Владелец события для канала пользователя
+	SiteID    uuid.UUID   `json:"-"` // Площадка события: кассир получает события только своей площадки
+}
+
+// BoxStatusEvent данные события изменения статуса бокса
+type BoxStatusEvent struct {
+	BoxID       uuid.UUID `json:"box_id"`
+	BoxNumber   int       `json:"box_number"`
+	ServiceType string    `json:"service_type"`
+	PrevStatus  string    `json:"prev_status"`
+	Status      string    `json:"status"`
+	ActorType   string    `json:"actor_type"`
+}
+
+// CoilStateEvent данные события изменения состояния катушки
+type CoilStateEvent struct {
+	BoxID     uuid.UUID `json:"box_id"`
+	BoxNumber int       `json:"box_number"`
+	Action    string    `json:"action"` // light_on, light_off, chemistry_on, chemistry_off
+	PrevValue *bool     `json:"prev_value,omitempty"`
+	Value     bool      `json:"value"`
+	ActorType string    `json:"actor_type"`
+}
+
+// SessionStatusEvent данные события изменения статуса сессии
+type SessionStatusEvent struct {
+	SessionID   uuid.UUID  `json:"session_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	SiteID      uuid.UUID  `json:"site_id"`
+	ServiceType string     `json:"service_type"`
+	CarNumber   string     `json:"car_number,omitempty"`
+	BoxID       *uuid.UUID `json:"box_id,omitempty"`
+	BoxNumber   *int       `json:"box_number,omitempty"`
+	PrevStatus  string     `json:"prev_status"`
+	Status      string     `json:"status"`
+}
+
+// QueuePositionEvent данные события изменения позиции сессии в очереди
+type QueuePositionEvent struct {
+	SessionID     uuid.UUID `json:"session_id"`
+	SiteID        uuid.UUID `json:"site_id"`
+	ServiceType   string    `json:"service_type"`
+	Position      int       `json:"position"`
+	PrevPosition  *int      `json:"prev_position,omitempty"` // nil - сессия только что встала в очередь
+	QueueSize     int       `json:"queue_size"`
+	PriorityClass string    `json:"priority_class"`
+}
+
+// QueueUpdateEvent данные события изменения очереди площадки по типу услуги
+type QueueUpdateEvent struct {
+	SiteID      uuid.UUID       `json:"site_id"`
+	ServiceType string          `json:"service_type"`
+	QueueSize   int             `json:"queue_size"`
+	Sessions    []QueuedSession `json:"sessions"`
+}
+
+// QueuedSession сессия в очереди в порядке обслуживания
+type QueuedSession struct {
+	SessionID     uuid.UUID `json:"session_id"`
+	Position      int       `json:"position"`
+	CarNumber     string    `json:"car_number,omitempty"`
+	PriorityClass string    `json:"priority_class"`
+	SnoozeCount   int       `json:"snooze_count"` // Сколько раз клиент отложил назначенный бокс
+}
+
+// ModbusAlertEvent данные тревоги о команде Modbus, не выполненной в срок
+type ModbusAlertEvent struct {
+	CommandID uuid.UUID  `json:"command_id"`
+	BoxID     uuid.UUID  `json:"box_id"`
+	BoxNumber int        `json:"box_number"`
+	SessionID *uuid.UUID `json:"session_id,omitempty"`
+	Operation string     `json:"operation"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+	Expired   bool       `json:"expired"` // Команда больше не повторяется
+}
+
+// CarPresenceEvent данные события изменения датчика присутствия автомобиля
+type CarPresenceEvent struct {
+	BoxID     uuid.UUID  `json:"box_id"`
+	BoxNumber int        `json:"box_number"`
+	Present   bool       `json:"present"`
+	ChangedAt time.Time  `json:"changed_at"`
+	SessionID *uuid.UUID `json:"session_id,omitempty"` // Назначенная или активная сессия бокса
+	Action    string     `json:"action,omitempty"`     // auto_start, car_returned, box_released
+}
+
+// SessionNoCarEvent данные события об активной сессии без машины в боксе
+type SessionNoCarEvent struct {
+	SessionID  uuid.UUID `json:"session_id"`
+	BoxID      uuid.UUID `json:"box_id"`
+	BoxNumber  int       `json:"box_number"`
+	NoCarSince time.Time `json:"no_car_since"`
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"carwash_backend/internal/domain/realtime/models"
+	"carwash_backend/internal/logger"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// subscriberBufferSize размер буфера событий одного подписчика
+	subscriberBufferSize = 64
+	// historySize количество последних событий для досылки после переподключения
+	historySize = 256
+)
+
+// Publisher отправляет события подписчикам
+// Используется сервисами, в которых происходят изменения (боксы, сессии, очередь)
+type Publisher interface {
+	Publish(event models.Event)
+}
+
+// Service интерфейс для потока событий в реальном времени
+type Service interface {
+	Publisher
+	Subscribe(channel string, userID *uuid.UUID, siteID uuid.UUID, lastEventID int64) (*Subscription, []models.Event)
+	Unsubscribe(sub *Subscription)
+}
+
+// Subscription подписка на канал событий
+type Subscription struct {
+	Channel string
+	UserID  *uuid.UUID
+	SiteID  uuid.UUID // Площадка кассира; для остальных каналов не используется
+	Events  chan models.Event
+}
+
+// matches проверяет, должна ли подписка получить событие
+func (sub *Subscription) matches(event models.Event) bool {
+	for _, channel := range event.Channels {
+		if channel != sub.Channel {
+			continue
+		}
+		switch channel {
+		case models.ChannelUser:
+			// В канал пользователя попадают только события его сессий
+			return sub.UserID != nil && event.UserID != nil && *sub.UserID == *event.UserID
+		case models.ChannelCashier:
+			// Кассир видит только события своей площадки
+			return event.SiteID == sub.SiteID
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceImpl реализация Service в памяти процесса
+type ServiceImpl struct {
+	mu          sync.RWMutex
+	nextID      int64
+	subscribers map[*Subscription]struct{}
+	history     []models.Event
+}
+
+// NewService создает новый экземпляр Service
+func NewService() *ServiceImpl {
+	return &ServiceImpl{
+		subscribers: make(map[*Subscription]struct{}),
+		history:     make([]models.Event, 0, historySize),
+	}
+}
+
+// Publish отправляет событие всем подходящим подписчикам
+// Медленный подписчик не блокирует отправку: при переполненном буфере событие для него пропускается
+func (s *ServiceImpl) Publish(event models.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	event.ID = s.nextID
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	if len(s.history) == historySize {
+		s.history = append(s.history[:0], s.history[1:]...)
+	}
+	s.history = append(s.history, event)
+
+	for sub := range s.subscribers {
+		if !sub.matches(event) {
+			continue
+		}
+		select {
+		case sub.Events <- event:
+		default:
+			logger.Printf("Realtime - Publish: буфер подписчика переполнен, событие %d (%s) пропущено, channel: %s", event.ID, event.Type, sub.Channel)
+		}
+	}
+}
+
+// Subscribe создает подписку на канал; siteID - площадка подписки кассира
+// Если указан lastEventID, возвращает пропущенные после него события, которые еще хранятся в истории
+func (s *ServiceImpl) Subscribe(channel string, userID *uuid.UUID, siteID uuid.UUID, lastEventID int64) (*Subscription, []models.Event) {
+	sub := &Subscription{
+		Channel: channel,
+		UserID:  userID,
+		SiteID:  siteID,
+		Events:  make(chan models.Event, subscriberBufferSize),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var missed []models.Event
+	if lastEventID > 0 {
+		for _, event := range s.history {
+			if event.ID > lastEventID && sub.matches(event) {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	s.subscribers[sub] = struct{}{}
+
+	return sub, missed
+}
+
+// Unsubscribe удаляет подписку
+func (s *ServiceImpl) Unsubscribe(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.Events)
+	}
+}
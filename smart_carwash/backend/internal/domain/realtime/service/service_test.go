@@ -0,0 +1,77 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"carwash_backend/internal/domain/realtime/models"
+)
+
+// receivedTypes забирает из буфера подписки все отправленные ей события
+func receivedTypes(sub *Subscription) []string {
+	types := []string{}
+	for {
+		select {
+		case event := <-sub.Events:
+			types = append(types, event.Type)
+		default:
+			return types
+		}
+	}
+}
+
+func TestPublishCashierFiltersBySite(t *testing.T) {
+	siteA := uuid.NewSHA1(uuid.Nil, []byte("site-a"))
+	siteB := uuid.NewSHA1(uuid.Nil, []byte("site-b"))
+	userA := uuid.NewSHA1(uuid.Nil, []byte("user-a"))
+	cashierAdmin := []string{models.ChannelCashier, models.ChannelAdmin}
+
+	events := []models.Event{
+		{Type: "box_a", Channels: cashierAdmin, SiteID: siteA},
+		{Type: "box_b", Channels: cashierAdmin, SiteID: siteB},
+		{Type: "session_a", Channels: []string{models.ChannelUser, models.ChannelCashier, models.ChannelAdmin}, UserID: &userA, SiteID: siteA},
+		{Type: "coil_b", Channels: []string{models.ChannelAdmin}, SiteID: siteB},
+		{Type: "no_site", Channels: cashierAdmin},
+	}
+
+	service := NewService()
+	cashierA, _ := service.Subscribe(models.ChannelCashier, nil, siteA, 0)
+	cashierB, _ := service.Subscribe(models.ChannelCashier, nil, siteB, 0)
+	admin, _ := service.Subscribe(models.ChannelAdmin, nil, uuid.Nil, 0)
+	user, _ := service.Subscribe(models.ChannelUser, &userA, uuid.Nil, 0)
+
+	for _, event := range events {
+		service.Publish(event)
+	}
+
+	tests := []struct {
+		name     string
+		sub      *Subscription
+		expected []string
+	}{
+		{name: "Cashier of site A", sub: cashierA, expected: []string{"box_a", "session_a"}},
+		{name: "Cashier of site B", sub: cashierB, expected: []string{"box_b"}},
+		{name: "Admin gets all sites", sub: admin, expected: []string{"box_a", "box_b", "session_a", "coil_b", "no_site"}},
+		{name: "User gets own sessions", sub: user, expected: []string{"session_a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := receivedTypes(tt.sub); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("received events = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	// Досылка после переподключения тоже не отдает события чужой площадки
+	_, missed := service.Subscribe(models.ChannelCashier, nil, siteB, 1)
+	got := []string{}
+	for _, event := range missed {
+		got = append(got, event.Type)
+	}
+	if expected := []string{"box_b"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("missed events of site B = %v, want %v", got, expected)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"carwash_backend/internal/logger"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,10 +12,13 @@ import (
 	"time"
 
 	authService "carwash_backend/internal/domain/auth/service"
+	carwashStatusModels "carwash_backend/internal/domain/carwash_status/models"
 	paymentService "carwash_backend/internal/domain/payment/service"
+	plateListModels "carwash_backend/internal/domain/platelist/models"
 	"carwash_backend/internal/domain/session/middleware"
 	"carwash_backend/internal/domain/session/models"
 	"carwash_backend/internal/domain/session/service"
+	siteService "carwash_backend/internal/domain/site/service"
 	authMiddleware "carwash_backend/internal/middleware"
 	"carwash_backend/internal/utils"
 
@@ -28,15 +32,17 @@ type Handler struct {
 	paymentService paymentService.Service
 	authService    authService.Service
 	apiKey1C       string
+	siteService    siteService.Service
 }
 
 // NewHandler создает новый экземпляр Handler
-func NewHandler(service service.Service, paymentService paymentService.Service, authService authService.Service, apiKey1C string) *Handler {
+func NewHandler(service service.Service, paymentService paymentService.Service, authService authService.Service, apiKey1C string, siteService siteService.Service) *Handler {
 	return &Handler{
 		service:        service,
 		paymentService: paymentService,
 		authService:    authService,
 		apiKey1C:       apiKey1C,
+		siteService:    siteService,
 	}
 }
 
@@ -56,6 +62,8 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 		sessionRoutes.GET("/history", h.getUserSessionHistory)                 // user_id в query параметре
 		sessionRoutes.POST("/cancel", h.cancelSession)                         // session_id и user_id в теле запроса
 		sessionRoutes.POST("/enable-chemistry", h.enableChemistry)             // session_id и user_id в теле запроса
+		sessionRoutes.POST("/snooze", h.snoozeSession)                         // session_id и user_id в теле запроса, отсрочка назначенного бокса
+		sessionRoutes.POST("/queue-jump", h.queueJump)                         // session_id и user_id в теле запроса, платный пропуск очереди
 	}
 
 	// Административные маршруты
@@ -81,9 +89,9 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 	// 1C webhook маршруты
 	oneCRoutes := router.Group("/1c")
 	{
-		oneCRoutes.POST("/payment-callback", middleware.Auth1CMiddleware(h.apiKey1C), h.handle1CPaymentCallback)
-		oneCRoutes.POST("/extend-session", middleware.Auth1CMiddleware(h.apiKey1C), h.handle1CExtendSession)
-		oneCRoutes.GET("/get-session-type", middleware.Auth1CMiddleware(h.apiKey1C), h.handle1CGetSessionType)
+		oneCRoutes.POST("/payment-callback", middleware.Auth1CMiddleware(h.apiKey1C, h.siteService), h.handle1CPaymentCallback)
+		oneCRoutes.POST("/extend-session", middleware.Auth1CMiddleware(h.apiKey1C, h.siteService), h.handle1CExtendSession)
+		oneCRoutes.GET("/get-session-type", middleware.Auth1CMiddleware(h.apiKey1C, h.siteService), h.handle1CGetSessionType)
 	}
 }
 
@@ -101,6 +109,30 @@ func (h *Handler) createSessionWithPayment(c *gin.Context) {
 	// Создаем сессию с платежом
 	response, err := h.service.CreateSessionWithPayment(c.Request.Context(), &req)
 	if err != nil {
+		var plateErr *utils.LicensePlateValidationError
+		if errors.As(err, &plateErr) {
+			logger.WithContext(c).Infof("API - createSessionWithPayment: невалидный госномер, user_id: %s, error: %v", req.UserID.String(), err)
+			c.JSON(http.StatusBadRequest, plateErr)
+			return
+		}
+		var blockedErr *plateListModels.PlateBlockedError
+		if errors.As(err, &blockedErr) {
+			logger.WithContext(c).Infof("API - createSessionWithPayment: госномер в черном списке, user_id: %s, error: %v", req.UserID.String(), err)
+			c.JSON(http.StatusForbidden, gin.H{"error": blockedErr.Error(), "code": "car_number_blacklisted"})
+			return
+		}
+		var closedErr *carwashStatusModels.CarwashClosedError
+		if errors.As(err, &closedErr) {
+			logger.WithContext(c).Infof("API - createSessionWithPayment: площадка не принимает сессии, user_id: %s, error: %v", req.UserID.String(), err)
+			c.JSON(http.StatusConflict, gin.H{"error": closedErr.Error(), "code": "carwash_closed", "next_open_at": closedErr.NextOpenAt})
+			return
+		}
+		var unavailableErr *carwashStatusModels.ServiceTypeUnavailableError
+		if errors.As(err, &unavailableErr) {
+			logger.WithContext(c).Infof("API - createSessionWithPayment: услуга недоступна, user_id: %s, service_type: %s, error: %v", req.UserID.String(), req.ServiceType, err)
+			c.JSON(http.StatusConflict, gin.H{"error": unavailableErr.Error(), "code": unavailableErr.Code, "service_type": unavailableErr.ServiceType})
+			return
+		}
 		logger.WithContext(c).Errorf("API Error - createSessionWithPayment: ошибка создания сессии с платежом, user_id: %s, service_type: %s, error: %v", req.UserID.String(), req.ServiceType, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -361,6 +393,60 @@ func (h *Handler) cancelSession(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// snoozeSession обработчик для отсрочки назначенного бокса
+func (h *Handler) snoozeSession(c *gin.Context) {
+	var req models.SnoozeSessionRequest
+
+	// Парсим JSON из тела запроса
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Логируем мета-параметры
+	c.Set("meta", gin.H{
+		"session_id": req.SessionID,
+		"user_id":    req.UserID,
+	})
+
+	response, err := h.service.SnoozeSession(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("Ошибка отсрочки бокса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.WithContext(c).Infof("Бокс отложен: SessionID=%s, UserID=%s, position=%d", req.SessionID, req.UserID, response.Position)
+	c.JSON(http.StatusOK, response)
+}
+
+// queueJump обработчик для создания платежа за пропуск очереди
+func (h *Handler) queueJump(c *gin.Context) {
+	var req models.QueueJumpRequest
+
+	// Парсим JSON из тела запроса
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Логируем мета-параметры
+	c.Set("meta", gin.H{
+		"session_id": req.SessionID,
+		"user_id":    req.UserID,
+	})
+
+	response, err := h.service.CreateQueueJumpPayment(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("Ошибка создания платежа за пропуск очереди: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.WithContext(c).Infof("Создан платеж за пропуск очереди: SessionID=%s, PaymentID=%s, position=%d", req.SessionID, response.Payment.ID, response.Position)
+	c.JSON(http.StatusOK, response)
+}
+
 // getUserSessionHistory обработчик для получения истории сессий пользователя
 func (h *Handler) getUserSessionHistory(c *gin.Context) {
 	// Получаем user_id из query параметра
@@ -449,6 +535,14 @@ func (h *Handler) adminListSessions(c *gin.Context) {
 		req.ServiceType = &serviceType
 	}
 
+	// Площадка
+	siteID, err := authMiddleware.SiteIDFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.SiteID = siteID
+
 	// Дата от (поддерживаем ISO 8601 с timezone)
 	if dateFromStr := c.Query("date_from"); dateFromStr != "" {
 		dateFrom, err := time.Parse(time.RFC3339, dateFromStr)
@@ -575,13 +669,28 @@ func (h *Handler) handle1CPaymentCallback(c *gin.Context) {
 		return
 	}
 
+	// Площадка определяется ключом 1C
+	req.SiteID = authMiddleware.SiteIDFromContext(c)
+
 	// Логируем входящий запрос
-	logger.WithContext(c).Infof("Received 1C payment callback: ServiceType=%s, WithChemistry=%t, Amount=%d, RentalTimeMinutes=%d, CarNumber=%s, PaymentTime=%s",
-		req.ServiceType, req.WithChemistry, req.Amount, req.RentalTimeMinutes, req.CarNumber, req.PaymentTime.Format(time.RFC3339))
+	logger.WithContext(c).Infof("Received 1C payment callback: ServiceType=%s, WithChemistry=%t, Amount=%d, RentalTimeMinutes=%d, CarNumber=%s, PaymentTime=%s, SiteID=%s",
+		req.ServiceType, req.WithChemistry, req.Amount, req.RentalTimeMinutes, req.CarNumber, req.PaymentTime.Format(time.RFC3339), req.SiteID)
 
 	// Создаем сессию через кассира
 	session, err := h.service.CreateFromCashier(c.Request.Context(), &req)
 	if err != nil {
+		var plateErr *utils.LicensePlateValidationError
+		if errors.As(err, &plateErr) {
+			logger.WithContext(c).Infof("Invalid car number in 1C payment callback: %v", err)
+			c.JSON(http.StatusBadRequest, plateErr)
+			return
+		}
+		var blockedErr *plateListModels.PlateBlockedError
+		if errors.As(err, &blockedErr) {
+			logger.WithContext(c).Infof("Blacklisted car number in 1C payment callback: %v", err)
+			c.JSON(http.StatusForbidden, gin.H{"error": blockedErr.Error(), "code": "car_number_blacklisted"})
+			return
+		}
 		logger.WithContext(c).Errorf("Error creating session from cashier: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -722,13 +831,17 @@ func (h *Handler) handle1CGetSessionType(c *gin.Context) {
 		"car_number": normalizedCarNumber,
 	})
 
+	// Долг за простой показываем кассиру независимо от наличия активной сессии
+	debtAmount := h.service.GetPendingDebtAmount(c.Request.Context(), normalizedCarNumber)
+
 	// Получаем активную сессию по номеру машины
 	session, err := h.service.GetActiveSessionByCarNumber(c.Request.Context(), normalizedCarNumber)
 	if err != nil {
 		logger.WithContext(c).Errorf("Error getting active session by car number: %v, car_number: %s", err, normalizedCarNumber)
 		c.JSON(http.StatusNotFound, models.GetSessionType1CResponse{
-			Success: false,
-			Message: "Нет активных сессий по номеру машины",
+			Success:    false,
+			Message:    "Нет активных сессий по номеру машины",
+			DebtAmount: debtAmount,
 		})
 		return
 	}
@@ -759,6 +872,7 @@ func (h *Handler) handle1CGetSessionType(c *gin.Context) {
 		Success:     true,
 		ServiceType: session.ServiceType,
 		Message:     "Тип сессии успешно получен",
+		DebtAmount:  debtAmount,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -784,6 +898,7 @@ func (h *Handler) cashierGetActiveSessions(c *gin.Context) {
 
 	// Создаем запрос
 	req := &models.CashierActiveSessionsRequest{
+		SiteID: authMiddleware.SiteIDFromContext(c),
 		Limit:  limit,
 		Offset: offset,
 	}
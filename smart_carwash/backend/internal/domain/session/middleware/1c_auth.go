@@ -1,17 +1,23 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
+	siteModels "carwash_backend/internal/domain/site/models"
+	siteService "carwash_backend/internal/domain/site/service"
+	authMiddleware "carwash_backend/internal/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
 // Auth1CMiddleware создает middleware для аутентификации 1C webhook
-func Auth1CMiddleware(apiKey string) gin.HandlerFunc {
+// Общий ключ API_KEY_1C относится к площадке по умолчанию, ключи площадок определяют свою площадку
+func Auth1CMiddleware(apiKey string, sites siteService.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Проверяем, что API ключ настроен
-		if apiKey == "" {
+		if apiKey == "" && sites == nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "API_KEY_1C не настроен"})
 			c.Abort()
 			return
@@ -35,13 +41,31 @@ func Auth1CMiddleware(apiKey string) gin.HandlerFunc {
 
 		// Проверяем API ключ
 		token := parts[1]
-		if token != apiKey {
+		if apiKey != "" && token == apiKey {
+			c.Set(authMiddleware.SiteIDContextKey, siteModels.DefaultSiteID)
+			c.Next()
+			return
+		}
+
+		if sites == nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Неверный API ключ"})
 			c.Abort()
 			return
 		}
 
-		// Аутентификация успешна, продолжаем
+		site, err := sites.AuthenticateBy1CKey(c.Request.Context(), token)
+		if err != nil {
+			if errors.Is(err, siteService.ErrInvalidCredentials) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Неверный API ключ"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			c.Abort()
+			return
+		}
+
+		// Аутентификация успешна, запоминаем площадку и продолжаем
+		c.Set(authMiddleware.SiteIDContextKey, site.ID)
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
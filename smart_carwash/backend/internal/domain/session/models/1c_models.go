@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // CashierPaymentRequest представляет запрос от 1C для платежа через кассира
@@ -14,6 +16,8 @@ type CashierPaymentRequest struct {
 	RentalTimeMinutes    int       `json:"rental_time_minutes" binding:"required,min=1"`
 	CarNumber            string    `json:"car_number"`         // Опциональный номер машины
 	CarNumberCountry     string    `json:"car_number_country"` // Опциональная страна гос номера
+	DebtAmount           int       `json:"debt_amount"`        // Часть суммы в копейках, принятая в счет долга за простой
+	SiteID               uuid.UUID `json:"-"`                  // Площадка кассы, определяется по ключу 1C
 }
 
 // CashierPaymentResponse представляет ответ на запрос от 1C
@@ -49,4 +53,5 @@ type GetSessionType1CResponse struct {
 	Success     bool   `json:"success"`
 	ServiceType string `json:"service_type,omitempty"` // Тип услуги: wash, air_dry, vacuum
 	Message     string `json:"message,omitempty"`
+	DebtAmount  int    `json:"debt_amount"` // Неоплаченный долг за простой в копейках
 }
@@ -18,10 +18,20 @@ const (
 	SessionStatusCanceled      = "canceled"       // Отменена
 )
 
+// Классы приоритета сессии в очереди
+const (
+	PriorityClassNormal     = "normal"     // Обычный клиент
+	PriorityClassSubscriber = "subscriber" // Абонемент
+	PriorityClassFleet      = "fleet"      // Корпоративный автопарк
+	PriorityClassSkipLine   = "skip_line"  // Платный проезд без очереди
+	PriorityClassVIP        = "vip"        // VIP номер
+)
+
 // Session представляет сессию мойки
 type Session struct {
 	ID                                     uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	UserID                                 uuid.UUID      `json:"user_id" gorm:"index;type:uuid"`
+	SiteID                                 uuid.UUID      `json:"site_id" gorm:"index;type:uuid"` // Площадка, на которой обслуживается сессия
 	BoxID                                  *uuid.UUID     `json:"box_id,omitempty" gorm:"index;type:uuid"`
 	BoxNumber                              *int           `json:"box_number,omitempty" gorm:"-"` // Виртуальное поле, не хранится в БД
 	Status                                 string         `json:"status" gorm:"default:created;index"`
@@ -33,6 +43,7 @@ type Session struct {
 	ChemistryEndedAt                       *time.Time     `json:"chemistry_ended_at,omitempty"`                                // Когда была выключена химия
 	CarNumber                              string         `json:"car_number"`                                                  // Номер машины в сессии
 	CarNumberCountry                       string         `json:"car_number_country" gorm:"default:'RUS'"`                     // Страна гос номера
+	CarNumberFormatted                     string         `json:"car_number_formatted,omitempty" gorm:"-"`                     // Номер для отображения по формату страны (виртуальное поле)
 	Email                                  string         `json:"email"`                                                       // Email для чека
 	RentalTimeMinutes                      int            `json:"rental_time_minutes" gorm:"default:5"`                        // Время мойки в минутах
 	ExtensionTimeMinutes                   int            `json:"extension_time_minutes" gorm:"default:0"`                     // Время продления в минутах
@@ -45,6 +56,22 @@ type Session struct {
 	IdempotencyKey                         string         `json:"idempotency_key,omitempty" gorm:"index"`
 	IsExpiringNotificationSent             bool           `json:"is_expiring_notification_sent" gorm:"default:false"`
 	IsCompletingNotificationSent           bool           `json:"is_completing_notification_sent" gorm:"default:false"`
+	IsQueuePositionNotificationSent        bool           `json:"is_queue_position_notification_sent" gorm:"default:false"`
+	IsQueueWaitNotificationSent            bool           `json:"is_queue_wait_notification_sent" gorm:"default:false"`
+	QueueJumpPaymentID                     *uuid.UUID     `json:"queue_jump_payment_id,omitempty" gorm:"type:uuid"`
+	QueueJumpedAt                          *time.Time     `json:"queue_jumped_at,omitempty"`
+	QueueJumpExpectedAt                    *time.Time     `json:"queue_jump_expected_at,omitempty"`
+	IsQueueJumpSettled                     bool           `json:"is_queue_jump_settled" gorm:"default:false"`
+	IsVIP                                  bool           `json:"is_vip" gorm:"default:false"`          // Госномер из VIP списка (приоритет в очереди)
+	IsFreePass                             bool           `json:"is_free_pass" gorm:"-"`                // VIP бесплатный проезд (виртуальное поле)
+	PriorityClass                          string         `json:"priority_class" gorm:"default:normal"` // Класс приоритета в очереди
+	DebtAmount                             int            `json:"debt_amount" gorm:"default:0"`         // Долг за простой в копейках, оплаченный вместе с сессией
+	SnoozeCount                            int            `json:"snooze_count" gorm:"default:0"`        // Сколько раз клиент отложил назначенный бокс
+	QueueEnteredAt                         *time.Time     `json:"queue_entered_at,omitempty"`           // С какого момента считается ожидание в очереди после отсрочки
+	AssignedAt                             *time.Time     `json:"assigned_at,omitempty"`                // Когда был назначен бокс
+	StartedAt                              *time.Time     `json:"started_at,omitempty"`                 // Когда клиент приступил к мойке
+	CompletedAt                            *time.Time     `json:"completed_at,omitempty"`               // Когда сессия была завершена
+	NoCarSince                             *time.Time     `json:"no_car_since,omitempty"`               // С какого момента в боксе активной сессии нет машины по датчику
 	CreatedAt                              time.Time      `json:"created_at"`
 	UpdatedAt                              time.Time      `json:"updated_at"`
 	StatusUpdatedAt                        time.Time      `json:"status_updated_at"`                   // Время последнего обновления статуса
@@ -65,30 +92,58 @@ type ReassignSessionResponse struct {
 	Session Session `json:"session"`
 }
 
+// SnoozeSessionRequest запрос на отсрочку назначенного бокса
+type SnoozeSessionRequest struct {
+	SessionID uuid.UUID `json:"session_id" binding:"required"`
+	UserID    uuid.UUID `json:"user_id" binding:"required"`
+}
+
+// SnoozeSessionResponse ответ на отсрочку назначенного бокса
+type SnoozeSessionResponse struct {
+	Session     Session `json:"session"`
+	Position    int     `json:"position"`     // Новая позиция в очереди по типу услуги
+	SnoozesLeft int     `json:"snoozes_left"` // Сколько раз еще можно отложить бокс
+}
+
+// QueueJumpRequest запрос на платный пропуск очереди
+type QueueJumpRequest struct {
+	SessionID uuid.UUID `json:"session_id" binding:"required"`
+	UserID    uuid.UUID `json:"user_id" binding:"required"`
+}
+
+// QueueJumpResponse ответ на создание платежа за пропуск очереди
+type QueueJumpResponse struct {
+	Session  *Session `json:"session"`
+	Payment  *Payment `json:"payment"`
+	Position int      `json:"position"` // Текущая позиция в очереди по типу услуги
+}
+
 // CreateSessionRequest представляет запрос на создание сессии
 type CreateSessionRequest struct {
-	UserID               uuid.UUID `json:"user_id" binding:"required"`
-	ServiceType          string    `json:"service_type" binding:"required"`
-	WithChemistry        bool      `json:"with_chemistry"`
-	ChemistryTimeMinutes int       `json:"chemistry_time_minutes"` // Выбранное время химии в минутах
-	CarNumber            string    `json:"car_number" binding:"required"`
-	CarNumberCountry     string    `json:"car_number_country"` // Страна гос номера
-	Email                string    `json:"email"`              // Email для чека
-	RentalTimeMinutes    int       `json:"rental_time_minutes" binding:"required"`
-	IdempotencyKey       string    `json:"idempotency_key" binding:"required"`
+	UserID               uuid.UUID  `json:"user_id" binding:"required"`
+	ServiceType          string     `json:"service_type" binding:"required"`
+	WithChemistry        bool       `json:"with_chemistry"`
+	ChemistryTimeMinutes int        `json:"chemistry_time_minutes"` // Выбранное время химии в минутах
+	CarNumber            string     `json:"car_number" binding:"required"`
+	CarNumberCountry     string     `json:"car_number_country"` // Страна гос номера
+	Email                string     `json:"email"`              // Email для чека
+	RentalTimeMinutes    int        `json:"rental_time_minutes" binding:"required"`
+	IdempotencyKey       string     `json:"idempotency_key" binding:"required"`
+	SiteID               *uuid.UUID `json:"site_id"` // Выбранная площадка (по умолчанию основная)
 }
 
 // CreateSessionWithPaymentRequest представляет запрос на создание сессии с платежом
 type CreateSessionWithPaymentRequest struct {
-	UserID               uuid.UUID `json:"user_id" binding:"required"`
-	ServiceType          string    `json:"service_type" binding:"required"`
-	WithChemistry        bool      `json:"with_chemistry"`
-	ChemistryTimeMinutes int       `json:"chemistry_time_minutes"` // Выбранное время химии в минутах
-	CarNumber            string    `json:"car_number" binding:"required"`
-	CarNumberCountry     string    `json:"car_number_country"` // Страна гос номера
-	Email                string    `json:"email"`              // Email для чека
-	RentalTimeMinutes    int       `json:"rental_time_minutes" binding:"required"`
-	IdempotencyKey       string    `json:"idempotency_key" binding:"required"`
+	UserID               uuid.UUID  `json:"user_id" binding:"required"`
+	ServiceType          string     `json:"service_type" binding:"required"`
+	WithChemistry        bool       `json:"with_chemistry"`
+	ChemistryTimeMinutes int        `json:"chemistry_time_minutes"` // Выбранное время химии в минутах
+	CarNumber            string     `json:"car_number" binding:"required"`
+	CarNumberCountry     string     `json:"car_number_country"` // Страна гос номера
+	Email                string     `json:"email"`              // Email для чека
+	RentalTimeMinutes    int        `json:"rental_time_minutes" binding:"required"`
+	IdempotencyKey       string     `json:"idempotency_key" binding:"required"`
+	SiteID               *uuid.UUID `json:"site_id"` // Выбранная площадка (по умолчанию основная)
 }
 
 // CreateSessionWithPaymentResponse представляет ответ на создание сессии с платежом
@@ -121,8 +176,25 @@ type GetUserSessionRequest struct {
 
 // GetUserSessionResponse представляет ответ на получение сессии пользователя
 type GetUserSessionResponse struct {
-	Session *Session `json:"session"`
-	Payment *Payment `json:"payment,omitempty"`
+	Session  *Session  `json:"session"`
+	Payment  *Payment  `json:"payment,omitempty"`
+	QueueETA *QueueETA `json:"queue_eta,omitempty"` // Прогноз ожидания, если сессия в очереди
+}
+
+// QueueETA прогноз времени ожидания назначения бокса для сессии в очереди
+type QueueETA struct {
+	SessionID        uuid.UUID `json:"session_id"`
+	Position         int       `json:"position"`           // Позиция в очереди по типу услуги
+	ETAMinutes       int       `json:"eta_minutes"`        // Ожидаемое время до назначения бокса в минутах
+	EstimatedStartAt time.Time `json:"estimated_start_at"` // Ожидаемое время назначения бокса
+}
+
+// SessionDurationStat статистика фактической длительности завершенных сессий
+type SessionDurationStat struct {
+	RentalTimeMinutes    int     `json:"rental_time_minutes"`
+	AvgDurationMinutes   float64 `json:"avg_duration_minutes"`    // Средняя длительность от старта до завершения
+	AvgStartDelayMinutes float64 `json:"avg_start_delay_minutes"` // Среднее время от назначения бокса до старта
+	SessionsCount        int     `json:"sessions_count"`
 }
 
 // CheckActiveSessionRequest представляет запрос на проверку активной сессии
@@ -203,6 +275,7 @@ type GetSessionPaymentsRequest struct {
 type GetSessionPaymentsResponse struct {
 	MainPayment       *Payment  `json:"main_payment,omitempty"`
 	ExtensionPayments []Payment `json:"extension_payments,omitempty"`
+	QueueJumpPayments []Payment `json:"queue_jump_payments,omitempty"`
 }
 
 // CancelSessionRequest представляет запрос на отмену сессии
@@ -237,6 +310,7 @@ type AdminListSessionsRequest struct {
 	BoxNumber   *int       `json:"box_number"`
 	Status      *string    `json:"status" binding:"omitempty,oneof=created in_queue payment_failed assigned active complete canceled"`
 	ServiceType *string    `json:"service_type" binding:"omitempty,oneof=wash air_dry vacuum"`
+	SiteID      *uuid.UUID `json:"site_id"`
 	DateFrom    *time.Time `json:"date_from"`
 	DateTo      *time.Time `json:"date_to"`
 	Limit       *int       `json:"limit"`
@@ -280,8 +354,9 @@ type CashierSessionsRequest struct {
 
 // CashierActiveSessionsRequest представляет запрос на получение активных сессий кассира
 type CashierActiveSessionsRequest struct {
-	Limit  int `json:"limit"`
-	Offset int `json:"offset"`
+	SiteID uuid.UUID `json:"-"` // Площадка кассира
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
 }
 
 // CashierActiveSessionsResponse представляет ответ на получение активных сессий кассира
@@ -22,10 +22,11 @@ type Repository interface {
 	UpdateSessionFields(ctx context.Context, sessionID uuid.UUID, fields map[string]interface{}) error
 	GetSessionsByStatus(ctx context.Context, status string) ([]models.Session, error)
 	CountSessionsByStatus(ctx context.Context, status string) (int, error)
+	CountQueuedSessions(ctx context.Context, siteID uuid.UUID, serviceType string) (int, error)
 	GetUserSessionHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]models.Session, error)
 
 	// Административные методы
-	GetSessionsWithFilters(ctx context.Context, userID *uuid.UUID, boxID *uuid.UUID, boxNumber *int, status *string, serviceType *string, dateFrom *time.Time, dateTo *time.Time, limit int, offset int) ([]models.Session, int, error)
+	GetSessionsWithFilters(ctx context.Context, userID *uuid.UUID, boxID *uuid.UUID, boxNumber *int, status *string, serviceType *string, siteID *uuid.UUID, dateFrom *time.Time, dateTo *time.Time, limit int, offset int) ([]models.Session, int, error)
 
 	// Метод для проверки завершенных сессий между уборками
 	GetCompletedSessionsBetween(ctx context.Context, boxID uuid.UUID, dateFrom, dateTo time.Time) (int, error)
@@ -34,6 +35,17 @@ type Repository interface {
 		CreatedAt time.Time
 	}, error)
 
+	// Статистика длительности завершенных сессий для прогноза очереди
+	GetSessionDurationStats(ctx context.Context, serviceType string, since time.Time) ([]models.SessionDurationStat, error)
+
+	// Статистика использования боксов для стратегий выбора бокса
+	GetBusyMinutesSinceForBoxes(ctx context.Context, boxIDs []uuid.UUID, since time.Time) (map[uuid.UUID]float64, error)
+	GetLastAssignedAtForBoxes(ctx context.Context, boxIDs []uuid.UUID) (map[uuid.UUID]time.Time, error)
+
+	// Платный пропуск очереди
+	CountQueueJumpsSince(ctx context.Context, siteID uuid.UUID, since time.Time) (int, error)
+	GetUnsettledQueueJumpSessions(ctx context.Context) ([]models.Session, error)
+
 	// Методы с блокировкой для предотвращения дедлоков
 	GetSessionByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Session, error)
 	UpdateSessionInTransaction(ctx context.Context, session *models.Session) error
@@ -198,6 +210,32 @@ func (r *PostgresRepository) CountSessionsByStatus(ctx context.Context, status s
 	return int(count), err
 }
 
+// CountQueuedSessions подсчитывает сессии в очереди площадки на тип услуги
+func (r *PostgresRepository) CountQueuedSessions(ctx context.Context, siteID uuid.UUID, serviceType string) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("site_id = ? AND service_type = ? AND status = ?", siteID, serviceType, models.SessionStatusInQueue).
+		Count(&count).Error
+	return int(count), err
+}
+
+// CountQueueJumpsSince подсчитывает сессии площадки, применившие пропуск очереди начиная с указанного времени
+func (r *PostgresRepository) CountQueueJumpsSince(ctx context.Context, siteID uuid.UUID, since time.Time) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Session{}).Where("site_id = ? AND queue_jumped_at >= ?", siteID, since).Count(&count).Error
+	return int(count), err
+}
+
+// GetUnsettledQueueJumpSessions получает сессии с пропуском очереди, покинувшие очередь, по которым еще не решен возврат доплаты
+func (r *PostgresRepository) GetUnsettledQueueJumpSessions(ctx context.Context) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.WithContext(ctx).
+		Where("queue_jumped_at IS NOT NULL AND is_queue_jump_settled = ? AND status <> ?", false, models.SessionStatusInQueue).
+		Order("queue_jumped_at ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
 // GetUserSessionHistory получает историю сессий пользователя
 func (r *PostgresRepository) GetUserSessionHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]models.Session, error) {
 	var sessions []models.Session
@@ -215,7 +253,7 @@ func (r *PostgresRepository) GetUserSessionHistory(ctx context.Context, userID u
 }
 
 // GetSessionsWithFilters получает сессии с фильтрацией для администратора
-func (r *PostgresRepository) GetSessionsWithFilters(ctx context.Context, userID *uuid.UUID, boxID *uuid.UUID, boxNumber *int, status *string, serviceType *string, dateFrom *time.Time, dateTo *time.Time, limit int, offset int) ([]models.Session, int, error) {
+func (r *PostgresRepository) GetSessionsWithFilters(ctx context.Context, userID *uuid.UUID, boxID *uuid.UUID, boxNumber *int, status *string, serviceType *string, siteID *uuid.UUID, dateFrom *time.Time, dateTo *time.Time, limit int, offset int) ([]models.Session, int, error) {
 	var sessions []models.Session
 
 	// Создаем функцию для применения фильтров к запросу
@@ -238,6 +276,9 @@ func (r *PostgresRepository) GetSessionsWithFilters(ctx context.Context, userID
 		if serviceType != nil {
 			query = query.Where("sessions.service_type = ?", *serviceType)
 		}
+		if siteID != nil {
+			query = query.Where("sessions.site_id = ?", *siteID)
+		}
 		if dateFrom != nil {
 			query = query.Where("sessions.created_at >= ?", *dateFrom)
 		}
@@ -337,6 +378,72 @@ func (r *PostgresRepository) GetCompletedSessionsCreatedAtSinceForBoxes(ctx cont
 	return rows, err
 }
 
+// GetSessionDurationStats получает среднюю фактическую длительность завершенных сессий по времени аренды
+// Учитываются только сессии с заполненным временем старта и завершения
+func (r *PostgresRepository) GetSessionDurationStats(ctx context.Context, serviceType string, since time.Time) ([]models.SessionDurationStat, error) {
+	var stats []models.SessionDurationStat
+	err := r.db.WithContext(ctx).Model(&models.Session{}).
+		Select(`rental_time_minutes,
+			AVG(EXTRACT(EPOCH FROM (completed_at - started_at)) / 60) AS avg_duration_minutes,
+			COALESCE(AVG(EXTRACT(EPOCH FROM (started_at - assigned_at)) / 60), 0) AS avg_start_delay_minutes,
+			COUNT(*) AS sessions_count`).
+		Where("status = ? AND service_type = ? AND completed_at >= ? AND started_at IS NOT NULL AND completed_at > started_at",
+			models.SessionStatusComplete, serviceType, since).
+		Group("rental_time_minutes").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// GetBusyMinutesSinceForBoxes возвращает суммарное время работы боксов в минутах с указанного момента
+// Для активных сессий время считается до текущего момента
+func (r *PostgresRepository) GetBusyMinutesSinceForBoxes(ctx context.Context, boxIDs []uuid.UUID, since time.Time) (map[uuid.UUID]float64, error) {
+	result := make(map[uuid.UUID]float64)
+	if len(boxIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		BoxID       uuid.UUID
+		BusyMinutes float64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Session{}).
+		Select("box_id, SUM(EXTRACT(EPOCH FROM (COALESCE(completed_at, NOW()) - started_at)) / 60) AS busy_minutes").
+		Where("box_id IN ? AND started_at >= ? AND status IN ?", boxIDs, since,
+			[]string{models.SessionStatusActive, models.SessionStatusComplete}).
+		Group("box_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.BoxID] = row.BusyMinutes
+	}
+	return result, nil
+}
+
+// GetLastAssignedAtForBoxes возвращает время последнего назначения сессии на каждый бокс
+func (r *PostgresRepository) GetLastAssignedAtForBoxes(ctx context.Context, boxIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	result := make(map[uuid.UUID]time.Time)
+	if len(boxIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		BoxID          uuid.UUID
+		LastAssignedAt time.Time
+	}
+	err := r.db.WithContext(ctx).Model(&models.Session{}).
+		Select("box_id, MAX(assigned_at) AS last_assigned_at").
+		Where("box_id IN ? AND assigned_at IS NOT NULL", boxIDs).
+		Group("box_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.BoxID] = row.LastAssignedAt
+	}
+	return result, nil
+}
+
 // GetSessionByIDForUpdate получает сессию по ID (без блокировки)
 func (r *PostgresRepository) GetSessionByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Session, error) {
 	var session models.Session
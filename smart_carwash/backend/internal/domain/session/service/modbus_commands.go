@@ -0,0 +1,102 @@
+package service
+
+import (
+	"carwash_backend/internal/logger"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"carwash_backend/internal/domain/session/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
+)
+
+// enqueueChannels ставит включение или выключение каналов бокса с режимом mode в очередь команд Modbus
+// С транзакцией tx команды сохраняются вместе с изменением сессии и не теряются при недоступности
+// Modbus сервера. Каналы без регистра или в ручном режиме сессией не управляются
+func (s *ServiceImpl) enqueueChannels(ctx context.Context, tx *gorm.DB, box *washboxModels.WashBox, sessionID uuid.UUID, mode string, on bool) error {
+	if s.modbusService == nil {
+		return nil
+	}
+	for _, channel := range box.ChannelsByMode(mode) {
+		if err := s.modbusService.EnqueueChannel(ctx, tx, box.ID, &sessionID, channel, on); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueueChemistryChannels ставит в очередь включение или выключение каналов химии бокса
+func (s *ServiceImpl) enqueueChemistryChannels(ctx context.Context, tx *gorm.DB, boxID, sessionID uuid.UUID, on bool) error {
+	if s.modbusService == nil || s.washboxService == nil {
+		return nil
+	}
+	box, err := s.washboxService.GetWashBoxByID(ctx, boxID)
+	if err != nil {
+		logger.Printf("enqueueChemistryChannels: ошибка получения бокса %s: %v", boxID, err)
+		return nil
+	}
+	return s.enqueueChannels(ctx, tx, box, sessionID, washboxModels.ChannelModeChemistry, on)
+}
+
+// enqueueRegisters ставит в очередь запись holding registers бокса (давление, пена, табло обратного отсчета)
+// active - сессия идет и табло показывает remainingSeconds; иначе регистры переводятся в значения простоя
+func (s *ServiceImpl) enqueueRegisters(ctx context.Context, tx *gorm.DB, boxID, sessionID uuid.UUID, remainingSeconds int, active bool) error {
+	if s.modbusService == nil {
+		return nil
+	}
+	return s.modbusService.EnqueueSessionRegisters(ctx, tx, boxID, sessionID, remainingSeconds, active)
+}
+
+// sessionRemainingSeconds оставшееся время активной сессии с учетом продлений
+// Отсчет, как и при автозавершении, идет от StatusUpdatedAt; время аренды по умолчанию - 5 минут
+func sessionRemainingSeconds(session *models.Session, now time.Time) int {
+	rentalTime := session.RentalTimeMinutes
+	if rentalTime <= 0 {
+		rentalTime = 5
+	}
+	totalTime := time.Duration(rentalTime+session.ExtensionTimeMinutes) * time.Minute
+	remaining := int(session.StatusUpdatedAt.Add(totalTime).Sub(now).Seconds())
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// enqueueSessionRegisters после продления ставит в очередь новое оставшееся время активной сессии
+// Используется вне транзакции, ошибка постановки только логируется
+func (s *ServiceImpl) enqueueSessionRegisters(ctx context.Context, session *models.Session) {
+	if session.BoxID == nil || session.Status != models.SessionStatusActive {
+		return
+	}
+	if err := s.enqueueRegisters(ctx, nil, *session.BoxID, session.ID, sessionRemainingSeconds(session, time.Now()), true); err != nil {
+		logger.Printf("enqueueSessionRegisters: ошибка постановки записи регистров - session_id: %s, box_id: %s, error: %v", session.ID, *session.BoxID, err)
+	}
+}
+
+// enqueueBoxOff ставит в очередь выключение каналов сессии (свет и т.п.) и, если она включалась, химии бокса
+// Используется вне транзакции, ошибка постановки только логируется
+func (s *ServiceImpl) enqueueBoxOff(ctx context.Context, boxID, sessionID uuid.UUID, wasChemistryOn bool) {
+	if s.modbusService == nil || s.washboxService == nil {
+		return
+	}
+
+	box, err := s.washboxService.GetWashBoxByID(ctx, boxID)
+	if err != nil {
+		logger.Printf("enqueueBoxOff: ошибка получения бокса %s: %v", boxID, err)
+		return
+	}
+
+	if err := s.enqueueChannels(ctx, nil, box, sessionID, washboxModels.ChannelModeSession, false); err != nil {
+		logger.Printf("enqueueBoxOff: ошибка постановки выключения света - session_id: %s, box_id: %s, error: %v", sessionID, boxID, err)
+	}
+	if wasChemistryOn {
+		if err := s.enqueueChannels(ctx, nil, box, sessionID, washboxModels.ChannelModeChemistry, false); err != nil {
+			logger.Printf("enqueueBoxOff: ошибка постановки выключения химии - session_id: %s, box_id: %s, error: %v", sessionID, boxID, err)
+		}
+	}
+	if err := s.enqueueRegisters(ctx, nil, boxID, sessionID, 0, false); err != nil {
+		logger.Printf("enqueueBoxOff: ошибка постановки записи регистров - session_id: %s, box_id: %s, error: %v", sessionID, boxID, err)
+	}
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"carwash_backend/internal/domain/session/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// etaHistoryDays период, за который берется статистика фактической длительности сессий
+	etaHistoryDays = 30
+	// etaMinSamples минимальное количество сессий, при котором статистике можно доверять
+	etaMinSamples = 5
+)
+
+// durationEstimator оценивает фактическую длительность сессий по истории
+type durationEstimator struct {
+	stats          map[int]models.SessionDurationStat
+	startDelay     time.Duration // Типичное время от назначения бокса до старта
+	sessionTimeout time.Duration // Максимальное время ожидания старта после назначения бокса
+}
+
+// expectedDuration возвращает ожидаемую длительность сессии с учетом досрочных завершений
+// Ожидаемая длительность не превышает оплаченное время
+func (e *durationEstimator) expectedDuration(rentalTimeMinutes, extensionTimeMinutes int) time.Duration {
+	booked := time.Duration(rentalTimeMinutes+extensionTimeMinutes) * time.Minute
+
+	stat, ok := e.stats[rentalTimeMinutes]
+	if !ok || stat.SessionsCount < etaMinSamples || stat.AvgDurationMinutes <= 0 {
+		return booked
+	}
+
+	expected := time.Duration(stat.AvgDurationMinutes*float64(time.Minute)) + time.Duration(extensionTimeMinutes)*time.Minute
+	if expected > booked {
+		return booked
+	}
+	return expected
+}
+
+// boxSlot момент, когда бокс освободится для следующей сессии из очереди
+type boxSlot struct {
+	box         washboxModels.WashBox
+	availableAt time.Time
+}
+
+// GetQueueETAs рассчитывает прогноз ожидания для каждой сессии в очереди площадки по типу услуги
+// Учитывается оставшееся время активных сессий, фактическая длительность сессий по истории,
+// время ожидания старта после назначения бокса, кулдаун после завершения и уборка боксов
+func (s *ServiceImpl) GetQueueETAs(ctx context.Context, siteID uuid.UUID, serviceType string) ([]models.QueueETA, error) {
+	// Порядок очереди совпадает с ProcessQueue
+	sessions, err := s.GetOrderedQueue(ctx, siteID, serviceType)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 || s.washboxService == nil {
+		return []models.QueueETA{}, nil
+	}
+
+	now := time.Now()
+
+	estimator := s.loadDurationEstimator(ctx, serviceType, now)
+	cooldown := s.cooldownDuration(ctx)
+
+	slots, err := s.loadBoxSlots(ctx, siteID, serviceType, estimator, cooldown, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.QueueETA, 0, len(sessions))
+	for i, session := range sessions {
+		// Ищем бокс, который освободится раньше всех и подходит сессии
+		best := -1
+		for j := range slots {
+			if session.WithChemistry && serviceType == washboxModels.ServiceTypeWash && !slots[j].box.ChemistryEnabled {
+				continue
+			}
+			if best == -1 || slots[j].availableAt.Before(slots[best].availableAt) {
+				best = j
+			}
+		}
+
+		// Нет подходящих боксов - прогноз невозможен
+		if best == -1 {
+			continue
+		}
+
+		startAt := slots[best].availableAt
+		result = append(result, models.QueueETA{
+			SessionID:        session.ID,
+			Position:         i + 1,
+			ETAMinutes:       int(math.Ceil(startAt.Sub(now).Minutes())),
+			EstimatedStartAt: startAt,
+		})
+
+		// Бокс занят этой сессией: ожидание старта, мойка и кулдаун
+		slots[best].availableAt = startAt.
+			Add(estimator.startDelay).
+			Add(estimator.expectedDuration(session.RentalTimeMinutes, session.ExtensionTimeMinutes)).
+			Add(cooldown)
+	}
+
+	return result, nil
+}
+
+// GetQueueETA возвращает прогноз ожидания для конкретной сессии в очереди
+func (s *ServiceImpl) GetQueueETA(ctx context.Context, session *models.Session) (*models.QueueETA, error) {
+	if session == nil || session.Status != models.SessionStatusInQueue {
+		return nil, nil
+	}
+
+	etas, err := s.GetQueueETAs(ctx, session.SiteID, session.ServiceType)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range etas {
+		if etas[i].SessionID == session.ID {
+			return &etas[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// loadDurationEstimator загружает статистику фактической длительности сессий
+func (s *ServiceImpl) loadDurationEstimator(ctx context.Context, serviceType string, now time.Time) *durationEstimator {
+	sessionTimeoutMinutes := 3
+	if s.settingsService != nil {
+		if timeout, err := s.settingsService.GetSessionTimeout(ctx); err == nil {
+			sessionTimeoutMinutes = timeout
+		}
+	}
+
+	estimator := &durationEstimator{
+		stats:          make(map[int]models.SessionDurationStat),
+		sessionTimeout: time.Duration(sessionTimeoutMinutes) * time.Minute,
+		startDelay:     time.Duration(sessionTimeoutMinutes) * time.Minute,
+	}
+
+	stats, err := s.repo.GetSessionDurationStats(ctx, serviceType, now.AddDate(0, 0, -etaHistoryDays))
+	if err != nil {
+		// Без статистики считаем по оплаченному времени
+		logger.Printf("GetQueueETAs: ошибка получения статистики длительности сессий, service_type: %s, error: %v", serviceType, err)
+		return estimator
+	}
+
+	// Время до старта усредняем по всем сессиям, оно не зависит от времени аренды
+	var delaySum float64
+	var delayCount int
+	for _, stat := range stats {
+		estimator.stats[stat.RentalTimeMinutes] = stat
+		delaySum += stat.AvgStartDelayMinutes * float64(stat.SessionsCount)
+		delayCount += stat.SessionsCount
+	}
+
+	if delayCount >= etaMinSamples {
+		delay := time.Duration(delaySum / float64(delayCount) * float64(time.Minute))
+		if delay < 0 {
+			delay = 0
+		}
+		// Несостоявшийся старт ограничен временем ожидания - дальше сессия запускается автоматически
+		if delay > estimator.sessionTimeout {
+			delay = estimator.sessionTimeout
+		}
+		estimator.startDelay = delay
+	}
+
+	return estimator
+}
+
+// loadBoxSlots определяет, когда освободится каждый бокс площадки для данного типа услуги
+func (s *ServiceImpl) loadBoxSlots(ctx context.Context, siteID uuid.UUID, serviceType string, estimator *durationEstimator, cooldown time.Duration, now time.Time) ([]boxSlot, error) {
+	boxes, err := s.washboxService.GetWashBoxesByServiceType(ctx, serviceType)
+	if err != nil {
+		return nil, err
+	}
+	boxes = washboxModels.FilterBySite(boxes, siteID)
+
+	activeSessions, err := s.repo.GetSessionsByStatus(ctx, models.SessionStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	assignedSessions, err := s.repo.GetSessionsByStatus(ctx, models.SessionStatusAssigned)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionsByBox := make(map[uuid.UUID]models.Session)
+	for _, sessions := range [][]models.Session{activeSessions, assignedSessions} {
+		for _, session := range sessions {
+			if session.BoxID != nil {
+				sessionsByBox[*session.BoxID] = session
+			}
+		}
+	}
+
+	cleaningTimeoutMinutes := 3
+	if s.settingsService != nil {
+		if timeout, err := s.settingsService.GetCleaningTimeout(ctx); err == nil {
+			cleaningTimeoutMinutes = timeout
+		}
+	}
+
+	slots := make([]boxSlot, 0, len(boxes))
+	for _, box := range boxes {
+		availableAt := now
+
+		switch box.Status {
+		case washboxModels.StatusMaintenance:
+			// Бокс на обслуживании не участвует в очереди
+			continue
+
+		case washboxModels.StatusCleaning:
+			if box.CleaningStartedAt != nil {
+				availableAt = box.CleaningStartedAt.Add(time.Duration(cleaningTimeoutMinutes) * time.Minute)
+			} else {
+				availableAt = now.Add(time.Duration(cleaningTimeoutMinutes) * time.Minute)
+			}
+
+		case washboxModels.StatusReserved:
+			session, ok := sessionsByBox[box.ID]
+			if !ok {
+				availableAt = now.Add(estimator.sessionTimeout)
+				break
+			}
+			assignedAt := session.StatusUpdatedAt
+			if session.AssignedAt != nil {
+				assignedAt = *session.AssignedAt
+			}
+			startAt := assignedAt.Add(estimator.startDelay)
+			if startAt.Before(now) {
+				startAt = now
+			}
+			availableAt = startAt.
+				Add(estimator.expectedDuration(session.RentalTimeMinutes, session.ExtensionTimeMinutes)).
+				Add(cooldown)
+
+		case washboxModels.StatusBusy:
+			session, ok := sessionsByBox[box.ID]
+			if !ok {
+				// Бокс занят без активной сессии - кулдаун после завершения
+				if box.CooldownUntil != nil {
+					availableAt = *box.CooldownUntil
+				}
+				break
+			}
+			startedAt := session.StatusUpdatedAt
+			if session.StartedAt != nil {
+				startedAt = *session.StartedAt
+			}
+			endAt := startedAt.Add(estimator.expectedDuration(session.RentalTimeMinutes, session.ExtensionTimeMinutes))
+			if endAt.Before(now) {
+				// Сессия уже идет дольше типичного - ориентируемся на оплаченное время
+				endAt = startedAt.Add(time.Duration(session.RentalTimeMinutes+session.ExtensionTimeMinutes) * time.Minute)
+				if endAt.Before(now) {
+					endAt = now
+				}
+			}
+			availableAt = endAt.Add(cooldown)
+		}
+
+		if availableAt.Before(now) {
+			availableAt = now
+		}
+
+		slots = append(slots, boxSlot{box: box, availableAt: availableAt})
+	}
+
+	return slots, nil
+}
+
+// cooldownDuration возвращает время блокировки бокса после завершения сессии
+func (s *ServiceImpl) cooldownDuration(ctx context.Context) time.Duration {
+	cooldownMinutes := 5
+	if s.settingsService != nil {
+		if timeout, err := s.settingsService.GetCooldownTimeout(ctx); err == nil {
+			cooldownMinutes = timeout
+		}
+	}
+	return time.Duration(cooldownMinutes) * time.Minute
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	paymentModels "carwash_backend/internal/domain/payment/models"
+	paymentService "carwash_backend/internal/domain/payment/service"
+	"carwash_backend/internal/domain/session/models"
+	settingsModels "carwash_backend/internal/domain/settings/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// getQueueJumpSettings получает настройки платного пропуска очереди
+// При ошибке используются значения по умолчанию
+func (s *ServiceImpl) getQueueJumpSettings(ctx context.Context) *settingsModels.QueueJumpSettings {
+	defaults := &settingsModels.QueueJumpSettings{Price: 10000, MaxPerHour: 3}
+	if s.settingsService == nil {
+		return defaults
+	}
+
+	settings, err := s.settingsService.GetQueueJumpSettings(ctx)
+	if err != nil {
+		logger.Printf("getQueueJumpSettings: ошибка получения настроек пропуска очереди, используем значения по умолчанию: %v", err)
+	}
+	if settings == nil {
+		return defaults
+	}
+
+	return settings
+}
+
+// checkQueueJumpAllowed проверяет, может ли сессия воспользоваться пропуском очереди, и возвращает ее позицию
+func (s *ServiceImpl) checkQueueJumpAllowed(ctx context.Context, session *models.Session, settings *settingsModels.QueueJumpSettings) (int, error) {
+	if settings.Price <= 0 || settings.MaxPerHour <= 0 {
+		return 0, paymentService.ErrQueueJumpNotApplicable
+	}
+
+	if session.Status != models.SessionStatusInQueue {
+		return 0, fmt.Errorf("%w: пропустить очередь можно только в статусе in_queue, текущий статус: '%s'", paymentService.ErrQueueJumpNotApplicable, session.Status)
+	}
+
+	if session.QueueJumpedAt != nil {
+		return 0, fmt.Errorf("%w: пропуск очереди для этой сессии уже применен", paymentService.ErrQueueJumpNotApplicable)
+	}
+
+	// Сессии со старшим классом приоритета пропуск очереди ничего не дает
+	prioritySettings := s.getQueuePrioritySettings(ctx)
+	if prioritySettings.BoostMinutes[session.PriorityClass] >= prioritySettings.BoostMinutes[models.PriorityClassSkipLine] {
+		return 0, fmt.Errorf("%w: сессия уже обслуживается в приоритетном порядке", paymentService.ErrQueueJumpNotApplicable)
+	}
+
+	// Ограничиваем количество пропусков в час на площадке, чтобы не задерживать остальную очередь
+	jumps, err := s.repo.CountQueueJumpsSince(ctx, session.SiteID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return 0, fmt.Errorf("ошибка подсчета пропусков очереди: %w", err)
+	}
+	if jumps >= settings.MaxPerHour {
+		return 0, fmt.Errorf("%w: лимит пропусков очереди за час исчерпан, попробуйте позже", paymentService.ErrQueueJumpNotApplicable)
+	}
+
+	queue, err := s.GetOrderedQueue(ctx, session.SiteID, session.ServiceType)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения очереди: %w", err)
+	}
+	position := 0
+	for i := range queue {
+		if queue[i].ID == session.ID {
+			position = i + 1
+			break
+		}
+	}
+	if position == 0 {
+		return 0, fmt.Errorf("%w: сессия не найдена в очереди", paymentService.ErrQueueJumpNotApplicable)
+	}
+	if position == 1 {
+		return 0, fmt.Errorf("%w: сессия уже первая в очереди", paymentService.ErrQueueJumpNotApplicable)
+	}
+
+	return position, nil
+}
+
+// CreateQueueJumpPayment создает платеж за пропуск очереди
+// Сессия поднимается в очереди только после успешной оплаты (см. ApplyQueueJump)
+func (s *ServiceImpl) CreateQueueJumpPayment(ctx context.Context, req *models.QueueJumpRequest) (*models.QueueJumpResponse, error) {
+	session, err := s.repo.GetSessionByID(ctx, req.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("сессия не найдена: %w", err)
+	}
+
+	if session.UserID != req.UserID {
+		return nil, fmt.Errorf("недостаточно прав для пропуска очереди")
+	}
+
+	settings := s.getQueueJumpSettings(ctx)
+	position, err := s.checkQueueJumpAllowed(ctx, session, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentResp, err := s.paymentService.CreateQueueJumpPayment(ctx, &paymentModels.CreateQueueJumpPaymentRequest{
+		SessionID: session.ID,
+		Amount:    settings.Price,
+		Currency:  "RUB",
+		Email:     session.Email, // Передаем email из сессии
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания платежа за пропуск очереди: %w", err)
+	}
+
+	payment := &models.Payment{
+		ID:             paymentResp.Payment.ID,
+		SessionID:      paymentResp.Payment.SessionID,
+		Amount:         paymentResp.Payment.Amount,
+		RefundedAmount: paymentResp.Payment.RefundedAmount,
+		Currency:       paymentResp.Payment.Currency,
+		Status:         paymentResp.Payment.Status,
+		PaymentType:    paymentResp.Payment.PaymentType,
+		PaymentURL:     paymentResp.Payment.PaymentURL,
+		TinkoffID:      paymentResp.Payment.TinkoffID,
+		ExpiresAt:      paymentResp.Payment.ExpiresAt,
+		RefundedAt:     paymentResp.Payment.RefundedAt,
+		CreatedAt:      paymentResp.Payment.CreatedAt,
+		UpdatedAt:      paymentResp.Payment.UpdatedAt,
+	}
+
+	return &models.QueueJumpResponse{
+		Session:  session,
+		Payment:  payment,
+		Position: position,
+	}, nil
+}
+
+// ApplyQueueJump применяет оплаченный пропуск очереди: сессия получает класс skip_line
+// Запоминается прогноз назначения бокса без пропуска, чтобы после назначения решить, вернуть ли доплату.
+// Повторный вызов с уже примененным платежом ничего не делает. Ошибка ErrQueueJumpNotApplicable
+// означает, что пропуск применить нельзя и доплату нужно вернуть
+func (s *ServiceImpl) ApplyQueueJump(ctx context.Context, sessionID uuid.UUID, paymentID uuid.UUID) error {
+	// Исключаем одновременное назначение боксов, пока меняется порядок очереди
+	s.processQueueMu.Lock()
+	defer s.processQueueMu.Unlock()
+
+	session, err := s.repo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("сессия не найдена: %w", err)
+	}
+
+	// Повторное уведомление об оплате того же платежа
+	if session.QueueJumpPaymentID != nil && *session.QueueJumpPaymentID == paymentID {
+		logger.Printf("ApplyQueueJump: пропуск очереди уже применен этим платежом, SessionID=%s, PaymentID=%s", session.ID, paymentID)
+		return nil
+	}
+
+	if _, err := s.checkQueueJumpAllowed(ctx, session, s.getQueueJumpSettings(ctx)); err != nil {
+		return err
+	}
+
+	// Прогноз считается до смены класса - это время назначения бокса без пропуска
+	eta, err := s.GetQueueETA(ctx, session)
+	if err != nil {
+		return fmt.Errorf("ошибка расчета прогноза ожидания: %w", err)
+	}
+	if eta == nil {
+		return fmt.Errorf("не удалось рассчитать прогноз ожидания для сессии %s", session.ID)
+	}
+
+	now := time.Now()
+	err = s.repo.UpdateSessionFields(ctx, session.ID, map[string]interface{}{
+		"priority_class":         models.PriorityClassSkipLine,
+		"queue_jump_payment_id":  paymentID,
+		"queue_jumped_at":        now,
+		"queue_jump_expected_at": eta.EstimatedStartAt,
+		"updated_at":             now,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка применения пропуска очереди: %w", err)
+	}
+
+	logger.Printf("ApplyQueueJump: пропуск очереди применен, SessionID=%s, PaymentID=%s, position=%d, expected_at=%s",
+		session.ID, paymentID, eta.Position, eta.EstimatedStartAt.Format(time.RFC3339))
+
+	s.publishQueuePositions(ctx)
+
+	return nil
+}
+
+// settleQueueJumps решает судьбу доплаты за пропуск очереди у сессий, покинувших очередь
+// Если бокс назначен раньше прогноза без пропуска, доплата остается, иначе (в том числе при отмене) возвращается
+func (s *ServiceImpl) settleQueueJumps(ctx context.Context) {
+	if s.paymentService == nil {
+		return
+	}
+
+	sessions, err := s.repo.GetUnsettledQueueJumpSessions(ctx)
+	if err != nil {
+		logger.Printf("settleQueueJumps: ошибка получения сессий с пропуском очереди: %v", err)
+		return
+	}
+
+	for i := range sessions {
+		session := sessions[i]
+
+		sooner := session.AssignedAt != nil && session.QueueJumpExpectedAt != nil && session.AssignedAt.Before(*session.QueueJumpExpectedAt)
+		refunded := 0
+
+		if !sooner && session.QueueJumpPaymentID != nil {
+			payment, err := s.paymentService.GetPaymentByID(ctx, *session.QueueJumpPaymentID)
+			if err != nil {
+				logger.Printf("settleQueueJumps: ошибка получения платежа за пропуск очереди, SessionID=%s: %v", session.ID, err)
+				continue
+			}
+
+			// Платеж мог быть уже возвращен вручную из админки
+			if payment.Status == paymentModels.PaymentStatusSucceeded && payment.Amount > payment.RefundedAmount {
+				refunded = payment.Amount - payment.RefundedAmount
+				if _, err := s.paymentService.RefundPayment(ctx, &paymentModels.RefundPaymentRequest{
+					PaymentID: payment.ID,
+					Amount:    refunded,
+				}); err != nil {
+					// Повторим при следующей обработке очереди
+					logger.Printf("settleQueueJumps: ошибка возврата доплаты за пропуск очереди, SessionID=%s, PaymentID=%s: %v", session.ID, payment.ID, err)
+					continue
+				}
+			}
+		}
+
+		if err := s.repo.UpdateSessionFields(ctx, session.ID, map[string]interface{}{
+			"is_queue_jump_settled": true,
+			"updated_at":            time.Now(),
+		}); err != nil {
+			logger.Printf("settleQueueJumps: ошибка обновления сессии %s: %v", session.ID, err)
+			continue
+		}
+
+		logger.Printf("settleQueueJumps: пропуск очереди рассчитан, SessionID=%s, status=%s, sooner=%t, refunded=%d", session.ID, session.Status, sooner, refunded)
+
+		if refunded > 0 && s.telegramBot != nil && s.userService != nil {
+			go func(sessionID uuid.UUID, userID uuid.UUID, serviceType string, amount int) {
+				user, err := s.userService.GetUserByID(context.Background(), userID)
+				if err != nil || user == nil {
+					logger.Printf("settleQueueJumps: не удалось получить пользователя для уведомления, SessionID=%s: %v", sessionID, err)
+					return
+				}
+				if err := s.telegramBot.SendQueueJumpRefundNotification(user.TelegramID, serviceType, amount); err != nil {
+					logger.Printf("settleQueueJumps: ошибка отправки уведомления о возврате доплаты, SessionID=%s: %v", sessionID, err)
+				}
+			}(session.ID, session.UserID, session.ServiceType, refunded)
+		}
+	}
+}
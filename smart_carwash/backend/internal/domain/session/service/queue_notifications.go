@@ -0,0 +1,167 @@
+package service
+
+import (
+	"carwash_backend/internal/domain/session/models"
+	siteModels "carwash_backend/internal/domain/site/models"
+	userModels "carwash_backend/internal/domain/user/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// notifyQueueChanges отправляет уведомления об очереди в Telegram после обработки очереди
+// Уведомления включаются пользователем: позиция в очереди не больше N, прогноз ожидания не больше N минут,
+// опустевшая очередь на услугу. Позиция и ожидание отправляются один раз за сессию
+func (s *ServiceImpl) notifyQueueChanges(ctx context.Context) {
+	if s.userService == nil || s.telegramBot == nil {
+		return
+	}
+
+	sessions, err := s.GetOrderedQueue(ctx, uuid.Nil, "")
+	if err != nil {
+		logger.Printf("notifyQueueChanges: ошибка получения очереди: %v", err)
+		return
+	}
+
+	// Очереди считаются раздельно по площадке и типу услуги, подписка хранит свою площадку
+	queueSizes := make(map[queueKey]int)
+	for _, session := range sessions {
+		queueSizes[queueKey{siteID: session.SiteID, serviceType: session.ServiceType}]++
+	}
+
+	s.notifyQueuePositions(ctx, sessions)
+	s.notifyEmptyQueues(ctx, queueSizes)
+}
+
+// notifyQueuePositions отправляет уведомления о позиции и времени ожидания сессиям в очереди
+func (s *ServiceImpl) notifyQueuePositions(ctx context.Context, sessions []models.Session) {
+	// Настройки нужны только владельцам сессий, которым еще не все уведомления отправлены
+	userIDs := make([]uuid.UUID, 0, len(sessions))
+	for _, session := range sessions {
+		if !session.IsQueuePositionNotificationSent || !session.IsQueueWaitNotificationSent {
+			userIDs = append(userIDs, session.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	users, err := s.userService.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		logger.Printf("notifyQueuePositions: ошибка получения пользователей: %v", err)
+		return
+	}
+
+	// Прогноз ожидания считается только для очередей, где он кому-то нужен
+	etas := make(map[uuid.UUID]int)
+	etaLoaded := make(map[queueKey]bool)
+	positions := make(map[queueKey]int)
+
+	for _, session := range sessions {
+		key := queueKey{siteID: session.SiteID, serviceType: session.ServiceType}
+		positions[key]++
+		position := positions[key]
+
+		user, ok := users[session.UserID]
+		if !ok || user.TelegramID == 0 {
+			continue
+		}
+
+		if !session.IsQueuePositionNotificationSent && user.QueueNotifyPosition != nil && position <= *user.QueueNotifyPosition {
+			if s.markQueueNotificationSent(ctx, session.ID, "is_queue_position_notification_sent") {
+				go func(sessionID uuid.UUID, telegramID int64, serviceType string, position int) {
+					if err := s.telegramBot.SendQueuePositionNotification(telegramID, serviceType, position); err != nil {
+						logger.Printf("notifyQueuePositions: ошибка отправки уведомления о позиции для сессии %s: %v", sessionID, err)
+					}
+				}(session.ID, user.TelegramID, session.ServiceType, position)
+			}
+		}
+
+		if !session.IsQueueWaitNotificationSent && user.QueueNotifyWaitMinutes != nil {
+			if !etaLoaded[key] {
+				etaLoaded[key] = true
+				s.loadQueueETAMinutes(ctx, key, etas)
+			}
+			eta, ok := etas[session.ID]
+			if ok && eta <= *user.QueueNotifyWaitMinutes {
+				if s.markQueueNotificationSent(ctx, session.ID, "is_queue_wait_notification_sent") {
+					go func(sessionID uuid.UUID, telegramID int64, serviceType string, eta int) {
+						if err := s.telegramBot.SendQueueWaitNotification(telegramID, serviceType, eta); err != nil {
+							logger.Printf("notifyQueuePositions: ошибка отправки уведомления об ожидании для сессии %s: %v", sessionID, err)
+						}
+					}(session.ID, user.TelegramID, session.ServiceType, eta)
+				}
+			}
+		}
+	}
+}
+
+// loadQueueETAMinutes добавляет в etas прогноз ожидания сессий очереди площадки по типу услуги
+func (s *ServiceImpl) loadQueueETAMinutes(ctx context.Context, key queueKey, etas map[uuid.UUID]int) {
+	queueETAs, err := s.GetQueueETAs(ctx, key.siteID, key.serviceType)
+	if err != nil {
+		logger.Printf("notifyQueuePositions: ошибка расчета прогноза ожидания, site_id: %s, service_type: %s, error: %v", key.siteID, key.serviceType, err)
+		return
+	}
+	for _, eta := range queueETAs {
+		etas[eta.SessionID] = eta.ETAMinutes
+	}
+}
+
+// markQueueNotificationSent помечает уведомление об очереди отправленным до отправки,
+// чтобы следующая обработка очереди не отправила его повторно
+func (s *ServiceImpl) markQueueNotificationSent(ctx context.Context, sessionID uuid.UUID, flag string) bool {
+	err := s.repo.UpdateSessionFields(ctx, sessionID, map[string]interface{}{
+		flag:         true,
+		"updated_at": time.Now(),
+	})
+	if err != nil {
+		logger.Printf("notifyQueuePositions: ошибка обновления флага %s для сессии %s: %v", flag, sessionID, err)
+		return false
+	}
+	return true
+}
+
+// notifyEmptyQueues однократно уведомляет подписчиков об опустевшей очереди на услугу на их площадке
+// Пользователю с активной сессией уведомление не нужно, подписка просто снимается
+func (s *ServiceImpl) notifyEmptyQueues(ctx context.Context, queueSizes map[queueKey]int) {
+	users, err := s.userService.GetUsersAwaitingEmptyQueue(ctx)
+	if err != nil {
+		logger.Printf("notifyEmptyQueues: ошибка получения подписчиков: %v", err)
+		return
+	}
+
+	for _, user := range users {
+		serviceType := *user.QueueNotifyEmptyServiceType
+		siteID := siteModels.DefaultSiteID
+		if user.QueueNotifyEmptySiteID != nil {
+			siteID = *user.QueueNotifyEmptySiteID
+		}
+		if queueSizes[queueKey{siteID: siteID, serviceType: serviceType}] > 0 {
+			continue
+		}
+
+		if err := s.userService.ClearQueueNotifyEmpty(ctx, user.ID); err != nil {
+			logger.Printf("notifyEmptyQueues: ошибка снятия подписки пользователя %s: %v", user.ID, err)
+			continue
+		}
+
+		if s.hasActiveSession(ctx, user.ID) || user.TelegramID == 0 {
+			continue
+		}
+
+		go func(user userModels.User, serviceType string) {
+			if err := s.telegramBot.SendQueueEmptyNotification(user.TelegramID, serviceType); err != nil {
+				logger.Printf("notifyEmptyQueues: ошибка отправки уведомления пользователю %s: %v", user.ID, err)
+			}
+		}(user, serviceType)
+	}
+}
+
+// hasActiveSession проверяет, есть ли у пользователя незавершенная сессия
+func (s *ServiceImpl) hasActiveSession(ctx context.Context, userID uuid.UUID) bool {
+	session, err := s.repo.GetActiveSessionByUserID(ctx, userID)
+	return err == nil && session != nil
+}
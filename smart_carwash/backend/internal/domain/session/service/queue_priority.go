@@ -0,0 +1,110 @@
+package service
+
+import (
+	plateListModels "carwash_backend/internal/domain/platelist/models"
+	"carwash_backend/internal/domain/session/models"
+	settingsModels "carwash_backend/internal/domain/settings/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// priorityClassForEntry определяет класс приоритета сессии по записи привилегированного списка госномеров
+func priorityClassForEntry(entry *plateListModels.PlateListEntry) string {
+	if entry == nil {
+		return models.PriorityClassNormal
+	}
+
+	switch entry.ListType {
+	case plateListModels.ListTypeVIP:
+		return models.PriorityClassVIP
+	case plateListModels.ListTypeFleet:
+		return models.PriorityClassFleet
+	case plateListModels.ListTypeSubscriber:
+		return models.PriorityClassSubscriber
+	default:
+		return models.PriorityClassNormal
+	}
+}
+
+// queueWaitingSince возвращает момент, от которого считается ожидание сессии в очереди
+// Для отложивших бокс это подобранное при отсрочке время, иначе время создания сессии
+func queueWaitingSince(session models.Session) time.Time {
+	if session.QueueEnteredAt != nil {
+		return *session.QueueEnteredAt
+	}
+	return session.CreatedAt
+}
+
+// isQueueOverdue проверяет, ожидает ли сессия дольше максимального времени
+func isQueueOverdue(session models.Session, settings *settingsModels.QueuePrioritySettings, now time.Time) bool {
+	return settings.MaxWaitMinutes > 0 && now.Sub(queueWaitingSince(session)) >= time.Duration(settings.MaxWaitMinutes)*time.Minute
+}
+
+// queueScore оценка сессии в очереди: минуты ожидания плюс фора класса приоритета
+func queueScore(session models.Session, settings *settingsModels.QueuePrioritySettings, now time.Time) float64 {
+	return now.Sub(queueWaitingSince(session)).Minutes() + float64(settings.BoostMinutes[session.PriorityClass])
+}
+
+// orderQueueSessions упорядочивает сессии очереди со взвешенной справедливостью
+// Сессии, ожидающие дольше максимального времени, обслуживаются первыми в порядке поступления,
+// остальные - по убыванию оценки: минуты ожидания плюс фора класса приоритета
+func orderQueueSessions(sessions []models.Session, settings *settingsModels.QueuePrioritySettings, now time.Time) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		overdueI, overdueJ := isQueueOverdue(sessions[i], settings, now), isQueueOverdue(sessions[j], settings, now)
+		if overdueI != overdueJ {
+			return overdueI
+		}
+		if !overdueI {
+			scoreI, scoreJ := queueScore(sessions[i], settings, now), queueScore(sessions[j], settings, now)
+			if scoreI != scoreJ {
+				return scoreI > scoreJ
+			}
+		}
+		return queueWaitingSince(sessions[i]).Before(queueWaitingSince(sessions[j]))
+	})
+}
+
+// getQueuePrioritySettings получает настройки взвешенной очереди
+// При ошибке используются значения по умолчанию, чтобы не останавливать очередь
+func (s *ServiceImpl) getQueuePrioritySettings(ctx context.Context) *settingsModels.QueuePrioritySettings {
+	if s.settingsService == nil {
+		return &settingsModels.QueuePrioritySettings{BoostMinutes: map[string]int{}}
+	}
+
+	settings, err := s.settingsService.GetQueuePrioritySettings(ctx)
+	if err != nil {
+		logger.Printf("getQueuePrioritySettings: ошибка получения настроек очереди, используем значения по умолчанию: %v", err)
+	}
+	if settings == nil {
+		settings = &settingsModels.QueuePrioritySettings{BoostMinutes: map[string]int{}}
+	}
+
+	return settings
+}
+
+// GetOrderedQueue возвращает сессии в очереди площадки в порядке обслуживания
+// Если siteID пустой, возвращаются сессии всех площадок, если serviceType пустой - всех типов услуг
+func (s *ServiceImpl) GetOrderedQueue(ctx context.Context, siteID uuid.UUID, serviceType string) ([]models.Session, error) {
+	queued, err := s.repo.GetSessionsByStatus(ctx, models.SessionStatusInQueue)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.Session, 0, len(queued))
+	for _, session := range queued {
+		if siteID != uuid.Nil && session.SiteID != siteID {
+			continue
+		}
+		if serviceType == "" || session.ServiceType == serviceType {
+			sessions = append(sessions, session)
+		}
+	}
+
+	orderQueueSessions(sessions, s.getQueuePrioritySettings(ctx), time.Now())
+
+	return sessions, nil
+}
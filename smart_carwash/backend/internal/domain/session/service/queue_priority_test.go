@@ -0,0 +1,200 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"carwash_backend/internal/domain/session/models"
+	settingsModels "carwash_backend/internal/domain/settings/models"
+)
+
+func queueTestSettings(maxWait int) *settingsModels.QueuePrioritySettings {
+	return &settingsModels.QueuePrioritySettings{
+		BoostMinutes: map[string]int{
+			models.PriorityClassNormal:     0,
+			models.PriorityClassSubscriber: 5,
+			models.PriorityClassFleet:      10,
+			models.PriorityClassSkipLine:   30,
+			models.PriorityClassVIP:        60,
+		},
+		MaxWaitMinutes: maxWait,
+	}
+}
+
+func queueTestSession(name, class string, now time.Time, waitMinutes float64) models.Session {
+	return models.Session{
+		ID:            uuid.NewSHA1(uuid.Nil, []byte(name)),
+		PriorityClass: class,
+		CreatedAt:     now.Add(-time.Duration(waitMinutes * float64(time.Minute))),
+	}
+}
+
+func TestQueueScore(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	settings := queueTestSettings(30)
+	snoozedAt := now.Add(-3 * time.Minute)
+
+	tests := []struct {
+		name     string
+		session  models.Session
+		expected float64
+	}{
+		{
+			name:     "Normal without boost",
+			session:  queueTestSession("normal", models.PriorityClassNormal, now, 7),
+			expected: 7,
+		},
+		{
+			name:     "Subscriber boost",
+			session:  queueTestSession("subscriber", models.PriorityClassSubscriber, now, 7),
+			expected: 12,
+		},
+		{
+			name:     "Fleet boost",
+			session:  queueTestSession("fleet", models.PriorityClassFleet, now, 7),
+			expected: 17,
+		},
+		{
+			name:     "Skip line boost",
+			session:  queueTestSession("skip_line", models.PriorityClassSkipLine, now, 7),
+			expected: 37,
+		},
+		{
+			name:     "VIP boost",
+			session:  queueTestSession("vip", models.PriorityClassVIP, now, 7),
+			expected: 67,
+		},
+		{
+			name:     "Unknown class without boost",
+			session:  queueTestSession("unknown", "unknown", now, 7),
+			expected: 7,
+		},
+		{
+			name: "Snoozed session counts from queue entry",
+			session: models.Session{
+				PriorityClass:  models.PriorityClassNormal,
+				CreatedAt:      now.Add(-20 * time.Minute),
+				QueueEnteredAt: &snoozedAt,
+			},
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queueScore(tt.session, settings, now); got != tt.expected {
+				t.Errorf("queueScore() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOrderQueueSessions(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		maxWait  int
+		sessions []models.Session
+		expected []string
+	}{
+		{
+			name:    "FIFO within one class",
+			maxWait: 30,
+			sessions: []models.Session{
+				queueTestSession("second", models.PriorityClassNormal, now, 5),
+				queueTestSession("first", models.PriorityClassNormal, now, 10),
+				queueTestSession("third", models.PriorityClassNormal, now, 1),
+			},
+			expected: []string{"first", "second", "third"},
+		},
+		{
+			name:    "Boost moves priority class ahead",
+			maxWait: 30,
+			sessions: []models.Session{
+				queueTestSession("normal", models.PriorityClassNormal, now, 20),
+				queueTestSession("subscriber", models.PriorityClassSubscriber, now, 16),
+				queueTestSession("fleet", models.PriorityClassFleet, now, 12),
+				queueTestSession("vip", models.PriorityClassVIP, now, 1),
+			},
+			expected: []string{"vip", "fleet", "subscriber", "normal"},
+		},
+		{
+			name:    "Boost smaller than wait difference keeps order",
+			maxWait: 30,
+			sessions: []models.Session{
+				queueTestSession("subscriber", models.PriorityClassSubscriber, now, 2),
+				queueTestSession("normal", models.PriorityClassNormal, now, 10),
+			},
+			expected: []string{"normal", "subscriber"},
+		},
+		{
+			name:    "Equal score falls back to FIFO",
+			maxWait: 30,
+			sessions: []models.Session{
+				queueTestSession("subscriber", models.PriorityClassSubscriber, now, 10),
+				queueTestSession("normal", models.PriorityClassNormal, now, 15),
+			},
+			expected: []string{"normal", "subscriber"},
+		},
+		{
+			name:    "Overdue first in FIFO order",
+			maxWait: 30,
+			sessions: []models.Session{
+				queueTestSession("vip", models.PriorityClassVIP, now, 5),
+				queueTestSession("overdue_late", models.PriorityClassNormal, now, 31),
+				queueTestSession("overdue_early", models.PriorityClassNormal, now, 45),
+				queueTestSession("fleet", models.PriorityClassFleet, now, 25),
+			},
+			expected: []string{"overdue_early", "overdue_late", "vip", "fleet"},
+		},
+		{
+			name:    "Overdue ignores boost",
+			maxWait: 30,
+			sessions: []models.Session{
+				queueTestSession("overdue_vip", models.PriorityClassVIP, now, 30),
+				queueTestSession("overdue_normal", models.PriorityClassNormal, now, 40),
+			},
+			expected: []string{"overdue_normal", "overdue_vip"},
+		},
+		{
+			name:    "Zero max wait disables overdue",
+			maxWait: 0,
+			sessions: []models.Session{
+				queueTestSession("normal", models.PriorityClassNormal, now, 120),
+				queueTestSession("vip", models.PriorityClassVIP, now, 61),
+			},
+			expected: []string{"vip", "normal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := make(map[uuid.UUID]string, len(tt.sessions))
+			for _, session := range tt.sessions {
+				for _, name := range tt.expected {
+					if session.ID == uuid.NewSHA1(uuid.Nil, []byte(name)) {
+						names[session.ID] = name
+					}
+				}
+			}
+
+			orderQueueSessions(tt.sessions, queueTestSettings(tt.maxWait), now)
+
+			if len(tt.sessions) != len(tt.expected) {
+				t.Fatalf("orderQueueSessions() returned %d sessions, want %d", len(tt.sessions), len(tt.expected))
+			}
+			for i, session := range tt.sessions {
+				if names[session.ID] != tt.expected[i] {
+					got := make([]string, 0, len(tt.sessions))
+					for _, s := range tt.sessions {
+						got = append(got, names[s.ID])
+					}
+					t.Fatalf("orderQueueSessions() = %v, want %v", got, tt.expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	realtimeModels "carwash_backend/internal/domain/realtime/models"
+	realtimeService "carwash_backend/internal/domain/realtime/service"
+	"carwash_backend/internal/domain/session/models"
+	"carwash_backend/internal/logger"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// queueKey очередь площадки по типу услуги
+type queueKey struct {
+	siteID      uuid.UUID
+	serviceType string
+}
+
+// queueSlot позиция сессии в очереди на момент последней отправки событий
+type queueSlot struct {
+	queue    queueKey
+	position int
+}
+
+// SetEventPublisher устанавливает отправку изменений сессий и очереди в поток событий
+func (s *ServiceImpl) SetEventPublisher(publisher realtimeService.Publisher) {
+	s.eventPublisher = publisher
+	s.queueSlots = make(map[uuid.UUID]queueSlot)
+}
+
+// publishSessionStatus отправляет событие смены статуса сессии владельцу, кассиру и администратору
+func (s *ServiceImpl) publishSessionStatus(session *models.Session, prevStatus string) {
+	if s.eventPublisher == nil || session == nil || session.Status == prevStatus {
+		return
+	}
+
+	userID := session.UserID
+	s.eventPublisher.Publish(realtimeModels.Event{
+		Type:     realtimeModels.EventTypeSessionStatus,
+		Channels: []string{realtimeModels.ChannelUser, realtimeModels.ChannelCashier, realtimeModels.ChannelAdmin},
+		UserID:   &userID,
+		SiteID:   session.SiteID,
+		Data: realtimeModels.SessionStatusEvent{
+			SessionID:   session.ID,
+			UserID:      session.UserID,
+			SiteID:      session.SiteID,
+			ServiceType: session.ServiceType,
+			CarNumber:   session.CarNumber,
+			BoxID:       session.BoxID,
+			BoxNumber:   session.BoxNumber,
+			PrevStatus:  prevStatus,
+			Status:      session.Status,
+		},
+	})
+}
+
+// publishQueuePositions отправляет изменения позиций в очереди после обработки очереди
+// Пользователь получает свою новую позицию, кассир и администратор - очередь площадки целиком по типу услуги
+// Вызывается под processQueueMu, поэтому queueSlots не требует отдельной блокировки
+func (s *ServiceImpl) publishQueuePositions(ctx context.Context) {
+	if s.eventPublisher == nil {
+		return
+	}
+
+	sessions, err := s.GetOrderedQueue(ctx, uuid.Nil, "")
+	if err != nil {
+		logger.Printf("publishQueuePositions: ошибка получения очереди: %v", err)
+		return
+	}
+
+	slots := make(map[uuid.UUID]queueSlot, len(sessions))
+	queues := make(map[queueKey][]realtimeModels.QueuedSession)
+	changedQueues := make(map[queueKey]bool)
+
+	for _, session := range sessions {
+		key := queueKey{siteID: session.SiteID, serviceType: session.ServiceType}
+		position := len(queues[key]) + 1
+		slots[session.ID] = queueSlot{queue: key, position: position}
+		queues[key] = append(queues[key], realtimeModels.QueuedSession{
+			SessionID:     session.ID,
+			Position:      position,
+			CarNumber:     session.CarNumber,
+			PriorityClass: session.PriorityClass,
+			SnoozeCount:   session.SnoozeCount,
+		})
+	}
+
+	for _, session := range sessions {
+		slot := slots[session.ID]
+		prev, known := s.queueSlots[session.ID]
+		if known && prev == slot {
+			continue
+		}
+		changedQueues[slot.queue] = true
+
+		event := realtimeModels.QueuePositionEvent{
+			SessionID:     session.ID,
+			SiteID:        slot.queue.siteID,
+			ServiceType:   slot.queue.serviceType,
+			Position:      slot.position,
+			QueueSize:     len(queues[slot.queue]),
+			PriorityClass: session.PriorityClass,
+		}
+		if known {
+			prevPosition := prev.position
+			event.PrevPosition = &prevPosition
+		}
+		userID := session.UserID
+		s.eventPublisher.Publish(realtimeModels.Event{
+			Type:     realtimeModels.EventTypeQueuePosition,
+			Channels: []string{realtimeModels.ChannelUser},
+			UserID:   &userID,
+			SiteID:   slot.queue.siteID,
+			Data:     event,
+		})
+	}
+
+	// Сессии, покинувшие очередь, тоже меняют очередь своей площадки и типа услуги
+	for sessionID, prev := range s.queueSlots {
+		if _, ok := slots[sessionID]; !ok {
+			changedQueues[prev.queue] = true
+		}
+	}
+
+	for key := range changedQueues {
+		queued := queues[key]
+		if queued == nil {
+			queued = []realtimeModels.QueuedSession{}
+		}
+		s.publishQueueUpdate(key, queued)
+	}
+
+	s.queueSlots = slots
+}
+
+// publishQueueUpdate отправляет кассиру и администратору очередь площадки по типу услуги
+func (s *ServiceImpl) publishQueueUpdate(key queueKey, queued []realtimeModels.QueuedSession) {
+	s.eventPublisher.Publish(realtimeModels.Event{
+		Type:     realtimeModels.EventTypeQueueUpdate,
+		Channels: []string{realtimeModels.ChannelCashier, realtimeModels.ChannelAdmin},
+		SiteID:   key.siteID,
+		Data: realtimeModels.QueueUpdateEvent{
+			SiteID:      key.siteID,
+			ServiceType: key.serviceType,
+			QueueSize:   len(queued),
+			Sessions:    queued,
+		},
+	})
+}
@@ -2,13 +2,20 @@ package service
 
 import (
 	carwashStatusRepo "carwash_backend/internal/domain/carwash_status/repository"
+	debtModels "carwash_backend/internal/domain/debt/models"
+	debtService "carwash_backend/internal/domain/debt/service"
 	"carwash_backend/internal/domain/modbus"
 	paymentModels "carwash_backend/internal/domain/payment/models"
 	paymentService "carwash_backend/internal/domain/payment/service"
+	plateListModels "carwash_backend/internal/domain/platelist/models"
+	plateListService "carwash_backend/internal/domain/platelist/service"
+	realtimeService "carwash_backend/internal/domain/realtime/service"
 	"carwash_backend/internal/domain/session/models"
 	"carwash_backend/internal/domain/session/repository"
 	settingsModels "carwash_backend/internal/domain/settings/models"
 	settingsService "carwash_backend/internal/domain/settings/service"
+	siteModels "carwash_backend/internal/domain/site/models"
+	siteService "carwash_backend/internal/domain/site/service"
 	"carwash_backend/internal/domain/telegram"
 	userService "carwash_backend/internal/domain/user/service"
 	washboxModels "carwash_backend/internal/domain/washbox/models"
@@ -20,7 +27,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -51,10 +57,15 @@ type Service interface {
 	CheckAndNotifyCompletingSessions(ctx context.Context) error
 	CountSessionsByStatus(ctx context.Context, status string) (int, error)
 	GetSessionsByStatus(ctx context.Context, status string) ([]models.Session, error)
+	NotifyQueueBeforeClosing(ctx context.Context, siteID uuid.UUID, closesAt time.Time) (int, error)
+	CountQueuedSessions(ctx context.Context, siteID uuid.UUID, serviceType string) (int, error)
 	GetUserSessionHistory(ctx context.Context, req *models.GetUserSessionHistoryRequest) ([]models.Session, error)
 	CreateFromCashier(ctx context.Context, req *models.CashierPaymentRequest) (*models.Session, error)
 	ExtendFromCashier(ctx context.Context, req *models.ExtendSession1CRequest) (*models.Session, error)
 	GetActiveSessionByCarNumber(ctx context.Context, carNumber string) (*models.Session, error)
+	GetPendingDebtAmount(ctx context.Context, carNumber string) int
+	GetQueueETAs(ctx context.Context, siteID uuid.UUID, serviceType string) ([]models.QueueETA, error)
+	GetOrderedQueue(ctx context.Context, siteID uuid.UUID, serviceType string) ([]models.Session, error)
 
 	// Административные методы
 	AdminListSessions(ctx context.Context, req *models.AdminListSessionsRequest) (*models.AdminListSessionsResponse, error)
@@ -73,6 +84,13 @@ type Service interface {
 	// Методы для переназначения сессий
 	ReassignSession(ctx context.Context, req *models.ReassignSessionRequest) (*models.ReassignSessionResponse, error)
 
+	// Отсрочка назначенного бокса клиентом
+	SnoozeSession(ctx context.Context, req *models.SnoozeSessionRequest) (*models.SnoozeSessionResponse, error)
+
+	// Платный пропуск очереди
+	CreateQueueJumpPayment(ctx context.Context, req *models.QueueJumpRequest) (*models.QueueJumpResponse, error)
+	ApplyQueueJump(ctx context.Context, sessionID uuid.UUID, paymentID uuid.UUID) error
+
 	// Методы для Dahua интеграции
 	GetActiveSessionByUserID(ctx context.Context, userID uuid.UUID) (*models.Session, error)
 	CompleteSessionWithoutRefund(ctx context.Context, sessionID uuid.UUID) error
@@ -88,11 +106,16 @@ type ServiceImpl struct {
 	modbusService     modbus.ModbusServiceInterface
 	settingsService   settingsService.Service
 	carwashStatusRepo carwashStatusRepo.Repository // Опциональный репозиторий статуса мойки
+	plateListService  plateListService.Service     // Опциональный сервис черного и VIP списков госномеров
+	debtService       debtService.Service          // Опциональный сервис долгов за простой
+	siteService       siteService.Service          // Опциональный сервис площадок
 	cashierUserID     string
 	metrics           *metrics.Metrics
 	db                *gorm.DB
 	processQueueMu    sync.Mutex // Мьютекс для исключения одновременного запуска ProcessQueue
 	washboxLogSvc     washboxlogService.Service
+	eventPublisher    realtimeService.Publisher // Опциональная отправка изменений в поток событий
+	queueSlots        map[uuid.UUID]queueSlot   // Позиции в очереди при последней отправке событий
 }
 
 // NewService создает новый экземпляр Service
@@ -118,13 +141,106 @@ func (s *ServiceImpl) SetCarwashStatusRepo(carwashStatusRepo carwashStatusRepo.R
 	s.carwashStatusRepo = carwashStatusRepo
 }
 
+// SetPlateListService устанавливает сервис списков госномеров
+func (s *ServiceImpl) SetPlateListService(plateListService plateListService.Service) {
+	s.plateListService = plateListService
+}
+
+// SetDebtService устанавливает сервис долгов за простой
+func (s *ServiceImpl) SetDebtService(debtService debtService.Service) {
+	s.debtService = debtService
+}
+
+// SetSiteService устанавливает сервис площадок
+func (s *ServiceImpl) SetSiteService(siteService siteService.Service) {
+	s.siteService = siteService
+}
+
+// resolveSiteID определяет площадку новой сессии
+// Без выбранной площадки сессия создается на площадке по умолчанию
+func (s *ServiceImpl) resolveSiteID(ctx context.Context, siteID *uuid.UUID) (uuid.UUID, error) {
+	if s.siteService != nil {
+		return s.siteService.ResolveSiteID(ctx, siteID)
+	}
+	if siteID == nil || *siteID == uuid.Nil {
+		return siteModels.DefaultSiteID, nil
+	}
+	return *siteID, nil
+}
+
+// GetPendingDebtAmount возвращает сумму неоплаченных долгов за простой по госномеру в копейках
+// Ошибки получения долгов не блокируют работу, в этом случае возвращается 0
+func (s *ServiceImpl) GetPendingDebtAmount(ctx context.Context, carNumber string) int {
+	if s.debtService == nil || carNumber == "" {
+		return 0
+	}
+
+	debts, err := s.debtService.GetPendingDebts(ctx, carNumber)
+	if err != nil {
+		logger.Printf("Service - GetPendingDebtAmount: ошибка получения долгов, car_number: %s, error: %v", carNumber, err)
+		return 0
+	}
+
+	return debts.TotalAmount
+}
+
+// checkPlateLists проверяет госномер по черному и привилегированным спискам
+// Возвращает *plateListModels.PlateBlockedError для номеров из черного списка
+// Ошибки доступа к спискам не блокируют создание сессии
+func (s *ServiceImpl) checkPlateLists(ctx context.Context, carNumber string, source string, userID *uuid.UUID) (*plateListModels.PlateListEntry, error) {
+	if s.plateListService == nil || carNumber == "" {
+		return nil, nil
+	}
+
+	vipEntry, err := s.plateListService.CheckCarNumber(ctx, carNumber, source, userID)
+	if err != nil {
+		var blockedErr *plateListModels.PlateBlockedError
+		if errors.As(err, &blockedErr) {
+			return nil, err
+		}
+		logger.Printf("Service - checkPlateLists: ошибка проверки списков госномеров, car_number: %s, error: %v", carNumber, err)
+		return nil, nil
+	}
+
+	return vipEntry, nil
+}
+
 // CreateSession создает новую сессию
 func (s *ServiceImpl) CreateSession(ctx context.Context, req *models.CreateSessionRequest) (*models.Session, error) {
 	logger.Printf("Service - CreateSession: начало создания сессии, user_id: %s, service_type: %s, with_chemistry: %t", req.UserID.String(), req.ServiceType, req.WithChemistry)
 
-	// Нормализация госномера (без валидации)
-	normalizedCarNumber := utils.NormalizeLicensePlate(req.CarNumber)
-	logger.Printf("Service - CreateSession: госномер нормализован '%s' -> '%s', user_id: %s", req.CarNumber, normalizedCarNumber, req.UserID.String())
+	// Валидация и нормализация госномера по правилам страны (если указан)
+	var normalizedCarNumber string
+	carNumberCountry := utils.NormalizeCarNumberCountry(req.CarNumberCountry)
+	if req.CarNumber != "" {
+		var err error
+		normalizedCarNumber, err = utils.ValidateLicensePlate(req.CarNumber, carNumberCountry)
+		if err != nil {
+			logger.Printf("Service - CreateSession: невалидный госномер '%s' (страна %s), user_id: %s, error: %v", req.CarNumber, carNumberCountry, req.UserID.String(), err)
+			return nil, err
+		}
+		logger.Printf("Service - CreateSession: госномер нормализован '%s' -> '%s', user_id: %s", req.CarNumber, normalizedCarNumber, req.UserID.String())
+	}
+
+	// Проверяем выбранную площадку
+	siteID, err := s.resolveSiteID(ctx, req.SiteID)
+	if err != nil {
+		logger.Printf("Service - CreateSession: площадка недоступна, site_id: %v, user_id: %s, error: %v", req.SiteID, req.UserID.String(), err)
+		return nil, err
+	}
+
+	// Проверяем, что площадка принимает новые сессии (статус мойки, рабочие часы и статус услуги)
+	if err := s.checkAcceptingSessions(ctx, siteID, req.ServiceType, time.Now()); err != nil {
+		logger.Printf("Service - CreateSession: площадка не принимает сессии, site_id: %s, user_id: %s, error: %v", siteID, req.UserID.String(), err)
+		return nil, err
+	}
+
+	// Проверяем госномер по черному и VIP спискам
+	vipEntry, err := s.checkPlateLists(ctx, normalizedCarNumber, plateListModels.SourceApp, &req.UserID)
+	if err != nil {
+		logger.Printf("Service - CreateSession: госномер '%s' в черном списке, user_id: %s", normalizedCarNumber, req.UserID.String())
+		return nil, err
+	}
 
 	// Проверяем, нет ли уже активной сессии с этим номером машины (если указан)
 	if normalizedCarNumber != "" {
@@ -223,16 +339,20 @@ func (s *ServiceImpl) CreateSession(ctx context.Context, req *models.CreateSessi
 	now := time.Now()
 	session := &models.Session{
 		UserID:               req.UserID,
+		SiteID:               siteID,
 		Status:               models.SessionStatusCreated,
 		ServiceType:          req.ServiceType,
 		WithChemistry:        req.WithChemistry,
 		ChemistryTimeMinutes: req.ChemistryTimeMinutes, // Сохраняем выбранное время химии
 		CarNumber:            normalizedCarNumber,      // Используем нормализованный госномер
-		CarNumberCountry:     req.CarNumberCountry,     // Сохраняем страну гос номера
+		CarNumberCountry:     carNumberCountry,         // Сохраняем страну гос номера
 		Email:                req.Email,                // Сохраняем email для чека
 		RentalTimeMinutes:    req.RentalTimeMinutes,
 		IdempotencyKey:       req.IdempotencyKey,
 		StatusUpdatedAt:      now, // Инициализируем время изменения статуса
+		IsVIP:                vipEntry != nil && vipEntry.ListType == plateListModels.ListTypeVIP,
+		IsFreePass:           vipEntry != nil && vipEntry.IsFreePass(),
+		PriorityClass:        priorityClassForEntry(vipEntry),
 	}
 
 	// Сохраняем сессию в базе данных
@@ -276,20 +396,47 @@ func (s *ServiceImpl) CreateSessionWithPayment(ctx context.Context, req *models.
 		WithChemistry:        req.WithChemistry,
 		ChemistryTimeMinutes: req.ChemistryTimeMinutes,
 		CarNumber:            req.CarNumber,
+		CarNumberCountry:     req.CarNumberCountry,
 		RentalTimeMinutes:    req.RentalTimeMinutes,
 		IdempotencyKey:       req.IdempotencyKey,
+		SiteID:               req.SiteID,
 	})
 	if err != nil {
 		logger.Printf("Service - CreateSessionWithPayment: ошибка создания сессии, user_id: %s, error: %v", req.UserID.String(), err)
 		return nil, fmt.Errorf("ошибка создания сессии: %w", err)
 	}
 
+	// VIP номер с бесплатным проездом - ставим в очередь без оплаты
+	if session.IsFreePass && session.Status == models.SessionStatusCreated {
+		if err := s.UpdateSessionStatus(ctx, session.ID, models.SessionStatusInQueue); err != nil {
+			return nil, fmt.Errorf("ошибка постановки бесплатной сессии в очередь: %w", err)
+		}
+		session.Status = models.SessionStatusInQueue
+		logger.Printf("Service - CreateSessionWithPayment: VIP сессия поставлена в очередь без оплаты, session_id: %s, car_number: %s", session.ID.String(), session.CarNumber)
+
+		return &models.CreateSessionWithPaymentResponse{
+			Session: *session,
+		}, nil
+	}
+
+	// Долг за простой после прошлой сессии оплачивается вместе с новой сессией
+	if session.Status == models.SessionStatusCreated && session.DebtAmount == 0 {
+		if debtAmount := s.GetPendingDebtAmount(ctx, session.CarNumber); debtAmount > 0 {
+			if err := s.repo.UpdateSessionFields(ctx, session.ID, map[string]interface{}{"debt_amount": debtAmount}); err != nil {
+				return nil, fmt.Errorf("ошибка сохранения долга в сессии: %w", err)
+			}
+			session.DebtAmount = debtAmount
+			logger.Printf("Service - CreateSessionWithPayment: к оплате добавлен долг за простой, session_id: %s, car_number: %s, debt_amount: %d", session.ID.String(), session.CarNumber, debtAmount)
+		}
+	}
+
 	// 2. Рассчитываем цену через Payment Service
 	priceResp, err := s.paymentService.CalculatePrice(ctx, &paymentModels.CalculatePriceRequest{
 		ServiceType:          req.ServiceType,
 		WithChemistry:        req.WithChemistry,
 		ChemistryTimeMinutes: req.ChemistryTimeMinutes,
 		RentalTimeMinutes:    req.RentalTimeMinutes,
+		SiteID:               &session.SiteID,
 	})
 	if err != nil {
 		logger.Printf("Service - CreateSessionWithPayment: ошибка расчета цены, session_id: %s, error: %v", session.ID.String(), err)
@@ -301,7 +448,7 @@ func (s *ServiceImpl) CreateSessionWithPayment(ctx context.Context, req *models.
 	// 3. Создаем платеж через Payment Service
 	paymentResp, err := s.paymentService.CreatePayment(ctx, &paymentModels.CreatePaymentRequest{
 		SessionID: session.ID,
-		Amount:    priceResp.Price,
+		Amount:    priceResp.Price + session.DebtAmount,
 		Currency:  priceResp.Currency,
 		Email:     session.Email, // Передаем email из сессии
 	})
@@ -416,11 +563,19 @@ func (s *ServiceImpl) GetUserSession(ctx context.Context, req *models.GetUserSes
 			sessionTimeout = 3
 		}
 		session.SessionTimeoutMinutes = sessionTimeout
+		session.CarNumberFormatted = utils.FormatLicensePlate(session.CarNumber, session.CarNumberCountry)
+	}
+
+	// Прогноз ожидания для сессии в очереди
+	queueETA, err := s.GetQueueETA(ctx, session)
+	if err != nil {
+		logger.Printf("GetUserSession: ошибка расчета прогноза ожидания, user_id: %s, error: %v", req.UserID, err)
 	}
 
 	return &models.GetUserSessionResponse{
-		Session: session,
-		Payment: payment,
+		Session:  session,
+		Payment:  payment,
+		QueueETA: queueETA,
 	}, nil
 }
 
@@ -462,6 +617,7 @@ func (s *ServiceImpl) GetUserSessionForPayment(ctx context.Context, req *models.
 			sessionTimeout = 3
 		}
 		session.SessionTimeoutMinutes = sessionTimeout
+		session.CarNumberFormatted = utils.FormatLicensePlate(session.CarNumber, session.CarNumberCountry)
 	}
 
 	return &models.GetUserSessionResponse{
@@ -508,6 +664,7 @@ func (s *ServiceImpl) GetSession(ctx context.Context, req *models.GetSessionRequ
 			sessionTimeout = 3
 		}
 		session.SessionTimeoutMinutes = sessionTimeout
+		session.CarNumberFormatted = utils.FormatLicensePlate(session.CarNumber, session.CarNumberCountry)
 	}
 
 	return &models.GetSessionResponse{
@@ -539,7 +696,9 @@ func (s *ServiceImpl) StartSession(ctx context.Context, req *models.StartSession
 	// Если сервис боксов не инициализирован, просто обновляем статус сессии
 	if s.washboxService == nil {
 		// Обновляем статус сессии на active
+		startedAt := time.Now()
 		session.Status = models.SessionStatusActive
+		session.StartedAt = &startedAt
 		err = s.repo.UpdateSession(ctx, session)
 		if err != nil {
 			logger.Printf("StartSessionError: ошибка обновления статуса сессии %s", session.ID)
@@ -548,8 +707,6 @@ func (s *ServiceImpl) StartSession(ctx context.Context, req *models.StartSession
 		return session, nil
 	}
 
-	var box *washboxModels.WashBox
-
 	// Используем транзакцию для атомарного обновления бокса и сессии
 	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Получаем бокс для обновления
@@ -600,19 +757,28 @@ func (s *ServiceImpl) StartSession(ctx context.Context, req *models.StartSession
 		}
 
 		// Обновляем статус сессии на active, время обновления статуса и сбрасываем флаг уведомления
+		startedAt := time.Now()
 		lockedSession.Status = models.SessionStatusActive
-		lockedSession.StatusUpdatedAt = time.Now()
+		lockedSession.StatusUpdatedAt = startedAt
+		lockedSession.StartedAt = &startedAt
 		lockedSession.IsExpiringNotificationSent = false
 
 		if err := tx.Save(&lockedSession).Error; err != nil {
 			return fmt.Errorf("ошибка обновления сессии: %w", err)
 		}
 
+		// Включение света (каналов сессии) и значения регистров сессии ставятся в очередь команд Modbus в этой же транзакции
+		if err := s.enqueueChannels(ctx, tx, &lockedBox, lockedSession.ID, washboxModels.ChannelModeSession, true); err != nil {
+			return err
+		}
+		if err := s.enqueueRegisters(ctx, tx, lockedBox.ID, lockedSession.ID, sessionRemainingSeconds(&lockedSession, startedAt), true); err != nil {
+			return err
+		}
+
 		// Обновляем локальную копию
 		session.Status = lockedSession.Status
 		session.StatusUpdatedAt = lockedSession.StatusUpdatedAt
 		session.IsExpiringNotificationSent = lockedSession.IsExpiringNotificationSent
-		box = &lockedBox
 
 		return nil
 	})
@@ -622,30 +788,7 @@ func (s *ServiceImpl) StartSession(ctx context.Context, req *models.StartSession
 		return nil, err
 	}
 
-	// Получаем полную информацию о боксе после транзакции (для Modbus операций)
-	if box == nil {
-		box, err = s.washboxService.GetWashBoxByID(ctx, *session.BoxID)
-		if err != nil {
-			logger.Printf("StartSessionError: ошибка получения информации о боксе %s", *session.BoxID)
-		}
-	}
-
-	// Включаем свет в боксе через Modbus
-	if s.modbusService != nil {
-		// Используем уже полученную информацию о боксе
-		if box.LightCoilRegister != nil {
-			err = s.modbusService.WriteLightCoil(ctx, *session.BoxID, *box.LightCoilRegister, true)
-			if err != nil {
-				// Логируем ошибку (HandleModbusError теперь только логирует, не продлевает время)
-				s.modbusService.HandleModbusError(*session.BoxID, "light_on", session.ID, err)
-				logger.Printf("StartSessionError: ошибка включения света в боксе %s: %v", *session.BoxID, err)
-			}
-		} else {
-			logger.Printf("StartSessionError: не найден регистр света для бокса %s", *session.BoxID)
-		}
-	} else {
-		logger.Printf("StartSessionError: ModbusService не инициализирован, не включаем свет в боксе %s", *session.BoxID)
-	}
+	s.publishSessionStatus(session, models.SessionStatusAssigned)
 
 	// Получаем основной платеж сессии
 	paymentResp, err := s.paymentService.GetMainPaymentBySessionID(ctx, session.ID)
@@ -691,7 +834,9 @@ func (s *ServiceImpl) CompleteSession(ctx context.Context, req *models.CompleteS
 	// Если сервис боксов не инициализирован, просто обновляем статус сессии
 	if s.washboxService == nil {
 		// Обновляем статус сессии на complete
+		completedAt := time.Now()
 		session.Status = models.SessionStatusComplete
+		session.CompletedAt = &completedAt
 		err = s.repo.UpdateSession(ctx, session)
 		if err != nil {
 			return nil, err
@@ -736,17 +881,39 @@ func (s *ServiceImpl) CompleteSession(ctx context.Context, req *models.CompleteS
 		}
 
 		// Обновляем статус сессии на complete, время обновления статуса и сбрасываем флаг уведомления
+		completedAt := time.Now()
 		lockedSession.Status = models.SessionStatusComplete
-		lockedSession.StatusUpdatedAt = time.Now()
+		lockedSession.StatusUpdatedAt = completedAt
+		lockedSession.CompletedAt = &completedAt
 		lockedSession.IsCompletingNotificationSent = false
 
+		// Химия, которая еще работает, выключается вместе с завершением сессии
+		chemistryOn := lockedSession.ChemistryStartedAt != nil && lockedSession.ChemistryEndedAt == nil
+		if chemistryOn {
+			lockedSession.ChemistryEndedAt = &completedAt
+		}
+
 		if err := tx.Save(&lockedSession).Error; err != nil {
 			return fmt.Errorf("ошибка обновления сессии: %w", err)
 		}
 
+		// Выключение химии и света ставится в очередь команд Modbus в этой же транзакции
+		if chemistryOn {
+			if err := s.enqueueChannels(ctx, tx, &box, lockedSession.ID, washboxModels.ChannelModeChemistry, false); err != nil {
+				return err
+			}
+		}
+		if err := s.enqueueChannels(ctx, tx, &box, lockedSession.ID, washboxModels.ChannelModeSession, false); err != nil {
+			return err
+		}
+		if err := s.enqueueRegisters(ctx, tx, box.ID, lockedSession.ID, 0, false); err != nil {
+			return err
+		}
+
 		// Обновляем локальную копию
 		session.Status = lockedSession.Status
 		session.StatusUpdatedAt = lockedSession.StatusUpdatedAt
+		session.ChemistryEndedAt = lockedSession.ChemistryEndedAt
 		session.IsCompletingNotificationSent = lockedSession.IsCompletingNotificationSent
 
 		return nil
@@ -757,6 +924,8 @@ func (s *ServiceImpl) CompleteSession(ctx context.Context, req *models.CompleteS
 		return nil, err
 	}
 
+	s.publishSessionStatus(session, models.SessionStatusActive)
+
 	// Рассчитываем использованное время сессии в секундах
 	usedTimeSeconds := 0
 	if session.StatusUpdatedAt.Unix() > 0 {
@@ -765,48 +934,6 @@ func (s *ServiceImpl) CompleteSession(ctx context.Context, req *models.CompleteS
 		usedTimeSeconds = int(now.Sub(startTime).Seconds())
 	}
 
-	// Если химия была включена, но еще не выключена - выключаем ее
-	if session.ChemistryStartedAt != nil && session.ChemistryEndedAt == nil {
-		now := time.Now()
-
-		// Выключаем химию через Modbus
-		if s.modbusService != nil {
-			// Используем уже полученную информацию о боксе
-			if box.ChemistryCoilRegister != nil {
-				err = s.modbusService.WriteChemistryCoil(ctx, *session.BoxID, *box.ChemistryCoilRegister, false)
-				if err != nil {
-					logger.Printf("CompleteSession: ошибка выключения химии в боксе %s: %v", *session.BoxID, err)
-				} else {
-					logger.Printf("CompleteSession: химия выключена в боксе %s, SessionID=%s", *session.BoxID, session.ID)
-				}
-			} else {
-				logger.Printf("CompleteSession: не найден регистр химии для бокса %s", *session.BoxID)
-			}
-		}
-
-		// Обновляем только поле chemistry_ended_at
-		err = s.repo.UpdateSessionFields(ctx, session.ID, map[string]interface{}{
-			"chemistry_ended_at": now,
-			"updated_at":         now,
-		})
-		if err != nil {
-			logger.Printf("CompleteSession: ошибка обновления времени выключения химии: %v", err)
-		}
-	}
-
-	// Выключаем свет в боксе через Modbus
-	if s.modbusService != nil {
-		// Используем уже полученную информацию о боксе
-		if box.LightCoilRegister != nil {
-			err = s.modbusService.WriteLightCoil(ctx, *session.BoxID, *box.LightCoilRegister, false)
-			if err != nil {
-				logger.Printf("Ошибка выключения света в боксе %s: %v", *session.BoxID, err)
-			}
-		} else {
-			logger.Printf("CompleteSession: не найден регистр света для бокса %s", *session.BoxID)
-		}
-	}
-
 	logger.Printf("Завершение сессии: SessionID=%s, RentalTime=%dmin, ExtensionTime=%dmin, UsedTime=%ds",
 		session.ID, session.RentalTimeMinutes, session.ExtensionTimeMinutes, usedTimeSeconds)
 
@@ -883,7 +1010,9 @@ func (s *ServiceImpl) CompleteSessionWithoutRefund(ctx context.Context, sessionI
 	// Если сервис боксов не инициализирован, просто обновляем статус сессии
 	if s.washboxService == nil {
 		// Обновляем статус сессии на complete
+		completedAt := time.Now()
 		session.Status = models.SessionStatusComplete
+		session.CompletedAt = &completedAt
 		err = s.repo.UpdateSession(ctx, session)
 		if err != nil {
 			return err
@@ -928,17 +1057,39 @@ func (s *ServiceImpl) CompleteSessionWithoutRefund(ctx context.Context, sessionI
 		}
 
 		// Обновляем статус сессии на complete, время обновления статуса и сбрасываем флаг уведомления
+		completedAt := time.Now()
 		lockedSession.Status = models.SessionStatusComplete
-		lockedSession.StatusUpdatedAt = time.Now()
+		lockedSession.StatusUpdatedAt = completedAt
+		lockedSession.CompletedAt = &completedAt
 		lockedSession.IsCompletingNotificationSent = false
 
+		// Химия, которая еще работает, выключается вместе с завершением сессии
+		chemistryOn := lockedSession.ChemistryStartedAt != nil && lockedSession.ChemistryEndedAt == nil
+		if chemistryOn {
+			lockedSession.ChemistryEndedAt = &completedAt
+		}
+
 		if err := tx.Save(&lockedSession).Error; err != nil {
 			return fmt.Errorf("ошибка обновления сессии: %w", err)
 		}
 
+		// Выключение химии и света ставится в очередь команд Modbus в этой же транзакции
+		if chemistryOn {
+			if err := s.enqueueChannels(ctx, tx, &box, lockedSession.ID, washboxModels.ChannelModeChemistry, false); err != nil {
+				return err
+			}
+		}
+		if err := s.enqueueChannels(ctx, tx, &box, lockedSession.ID, washboxModels.ChannelModeSession, false); err != nil {
+			return err
+		}
+		if err := s.enqueueRegisters(ctx, tx, box.ID, lockedSession.ID, 0, false); err != nil {
+			return err
+		}
+
 		// Обновляем локальную копию
 		session.Status = lockedSession.Status
 		session.StatusUpdatedAt = lockedSession.StatusUpdatedAt
+		session.ChemistryEndedAt = lockedSession.ChemistryEndedAt
 		session.IsCompletingNotificationSent = lockedSession.IsCompletingNotificationSent
 
 		return nil
@@ -949,49 +1100,7 @@ func (s *ServiceImpl) CompleteSessionWithoutRefund(ctx context.Context, sessionI
 		return err
 	}
 
-	// Если химия была включена, но еще не выключена - выключаем ее
-	if session.ChemistryStartedAt != nil && session.ChemistryEndedAt == nil {
-		now := time.Now()
-
-		// Выключаем химию через Modbus
-		if s.modbusService != nil {
-			// Используем уже полученную информацию о боксе
-			if box.ChemistryCoilRegister != nil {
-				err = s.modbusService.WriteChemistryCoil(ctx, *session.BoxID, *box.ChemistryCoilRegister, false)
-				if err != nil {
-					logger.Printf("CompleteSessionWithoutRefund: ошибка выключения химии в боксе %s: %v", *session.BoxID, err)
-				} else {
-					logger.Printf("CompleteSessionWithoutRefund: химия выключена в боксе %s, SessionID=%s", *session.BoxID, session.ID)
-				}
-			} else {
-				logger.Printf("CompleteSessionWithoutRefund: не найден регистр химии для бокса %s", *session.BoxID)
-			}
-		}
-
-		// Обновляем только поле chemistry_ended_at
-		err = s.repo.UpdateSessionFields(context.Background(), session.ID, map[string]interface{}{
-			"chemistry_ended_at": now,
-			"updated_at":         now,
-		})
-		if err != nil {
-			logger.Printf("CompleteSessionWithoutRefund: ошибка обновления времени выключения химии: %v", err)
-		}
-	}
-
-	// Выключаем свет в боксе через Modbus
-	if s.modbusService != nil {
-		// Используем уже полученную информацию о боксе
-		if box.LightCoilRegister != nil {
-			err = s.modbusService.WriteLightCoil(ctx, *session.BoxID, *box.LightCoilRegister, false)
-			if err != nil {
-				logger.Printf("CompleteSessionWithoutRefund: ошибка выключения света в боксе %s: %v", *session.BoxID, err)
-			} else {
-				logger.Printf("CompleteSessionWithoutRefund: свет выключен в боксе %s, SessionID=%s", *session.BoxID, session.ID)
-			}
-		} else {
-			logger.Printf("CompleteSessionWithoutRefund: не найден регистр света для бокса %s", *session.BoxID)
-		}
-	}
+	s.publishSessionStatus(session, models.SessionStatusActive)
 
 	// Записываем метрику завершения сессии
 	if s.metrics != nil {
@@ -1068,6 +1177,7 @@ func (s *ServiceImpl) ExtendSessionWithPayment(ctx context.Context, req *models.
 		ExtensionTimeMinutes:          req.ExtensionTimeMinutes,
 		WithChemistry:                 session.WithChemistry || req.ExtensionChemistryTimeMinutes > 0,
 		ExtensionChemistryTimeMinutes: req.ExtensionChemistryTimeMinutes,
+		SiteID:                        &session.SiteID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("ошибка расчета цены продления: %w", err)
@@ -1165,6 +1275,25 @@ func (s *ServiceImpl) GetSessionPayments(ctx context.Context, req *models.GetSes
 		})
 	}
 
+	// Маппим платежи за пропуск очереди
+	for _, jumpPayment := range paymentsResp.QueueJumpPayments {
+		response.QueueJumpPayments = append(response.QueueJumpPayments, models.Payment{
+			ID:             jumpPayment.ID,
+			SessionID:      jumpPayment.SessionID,
+			Amount:         jumpPayment.Amount,
+			RefundedAmount: jumpPayment.RefundedAmount,
+			Currency:       jumpPayment.Currency,
+			Status:         jumpPayment.Status,
+			PaymentType:    jumpPayment.PaymentType,
+			PaymentURL:     jumpPayment.PaymentURL,
+			TinkoffID:      jumpPayment.TinkoffID,
+			ExpiresAt:      jumpPayment.ExpiresAt,
+			RefundedAt:     jumpPayment.RefundedAt,
+			CreatedAt:      jumpPayment.CreatedAt,
+			UpdatedAt:      jumpPayment.UpdatedAt,
+		})
+	}
+
 	return response, nil
 }
 
@@ -1213,7 +1342,7 @@ func (s *ServiceImpl) CancelSession(ctx context.Context, req *models.CancelSessi
 			// Выполняем возврат денег через payment service
 			refundReq := &paymentModels.RefundPaymentRequest{
 				PaymentID: paymentResp.ID,
-				Amount:    paymentResp.Amount, // Возвращаем полную сумму
+				Amount:    paymentResp.Amount - session.DebtAmount, // Возвращаем полную сумму за вычетом оплаченного долга за простой
 			}
 
 			refundResp, err := s.paymentService.RefundPayment(ctx, refundReq)
@@ -1249,6 +1378,7 @@ func (s *ServiceImpl) CancelSession(ctx context.Context, req *models.CancelSessi
 	}
 
 	// Обновляем статус сессии на canceled
+	prevStatus := session.Status
 	session.Status = models.SessionStatusCanceled
 	session.StatusUpdatedAt = time.Now()
 	err = s.repo.UpdateSession(ctx, session)
@@ -1256,6 +1386,8 @@ func (s *ServiceImpl) CancelSession(ctx context.Context, req *models.CancelSessi
 		return nil, fmt.Errorf("ошибка обновления статуса сессии: %w", err)
 	}
 
+	s.publishSessionStatus(session, prevStatus)
+
 	if session.BoxID == nil {
 		// Обновляем сессию в ответе
 		response.Session = *session
@@ -1266,34 +1398,9 @@ func (s *ServiceImpl) CancelSession(ctx context.Context, req *models.CancelSessi
 	// Обновляем статус бокса на free
 	s.washboxService.UpdateWashBoxStatus(ctx, *session.BoxID, washboxModels.StatusFree)
 
-	// Выключаем все койлы через Modbus при отмене сессии
-	if s.modbusService != nil {
-		logger.Printf("Выключение всех койлов для отмененной сессии - session_id: %s, box_id: %s", session.ID, *session.BoxID)
-
-		// Выключаем свет
-		lightRegister := s.getLightRegisterForBox(ctx, *session.BoxID)
-		if lightRegister != "" {
-			if err := s.modbusService.WriteLightCoil(ctx, *session.BoxID, lightRegister, false); err != nil {
-				logger.Printf("Ошибка выключения света при отмене сессии - session_id: %s, box_id: %s, error: %v",
-					session.ID, *session.BoxID, err)
-			}
-		} else {
-			logger.Printf("CancelSession: не найден регистр света для бокса %s", *session.BoxID)
-		}
-
-		// Выключаем химию (если была включена)
-		if session.WasChemistryOn {
-			chemistryRegister := s.getChemistryRegisterForBox(ctx, *session.BoxID)
-			if chemistryRegister != "" {
-				if err := s.modbusService.WriteChemistryCoil(ctx, *session.BoxID, chemistryRegister, false); err != nil {
-					logger.Printf("Ошибка выключения химии при отмене сессии - session_id: %s, box_id: %s, error: %v",
-						session.ID, *session.BoxID, err)
-				}
-			} else {
-				logger.Printf("CancelSession: не найден регистр химии для бокса %s", *session.BoxID)
-			}
-		}
-	}
+	// Выключение всех койлов ставится в очередь команд Modbus
+	logger.Printf("Выключение всех койлов для отмененной сессии - session_id: %s, box_id: %s", session.ID, *session.BoxID)
+	s.enqueueBoxOff(ctx, *session.BoxID, session.ID, session.WasChemistryOn)
 
 	// Отправляем уведомление о возврате денег при отмене сессии асинхронно
 	if s.telegramBot != nil {
@@ -1367,13 +1474,16 @@ func (s *ServiceImpl) CheckAndCompleteExpiredSessions(ctx context.Context) error
 			// ИСПРАВЛЕНИЕ: Сначала завершаем сессию, потом освобождаем бокс
 			// Это предотвращает race condition где бокс становится 'free' при активной сессии
 			// Обновляем статус сессии на complete, время обновления статуса и сбрасываем флаг уведомления
+			completedAt := time.Now()
 			session.Status = models.SessionStatusComplete
-			session.StatusUpdatedAt = time.Now()         // Обновляем время изменения статуса
+			session.StatusUpdatedAt = completedAt // Обновляем время изменения статуса
+			session.CompletedAt = &completedAt
 			session.IsCompletingNotificationSent = false // Сбрасываем флаг, чтобы уведомление могло быть отправлено снова
 			err = s.repo.UpdateSession(ctx, &session)
 			if err != nil {
 				return err
 			}
+			s.publishSessionStatus(&session, models.SessionStatusActive)
 			if session.BoxID != nil && s.washboxService != nil {
 				// Исключаем сессии кассира из кулдауна
 				if s.cashierUserID != "" {
@@ -1418,33 +1528,10 @@ func (s *ServiceImpl) CheckAndCompleteExpiredSessions(ctx context.Context) error
 					}
 				}
 			}
-			// Выключаем все койлы через Modbus при истечении сессии
-			if session.BoxID != nil && s.modbusService != nil {
+			// Выключение всех койлов ставится в очередь команд Modbus
+			if session.BoxID != nil {
 				logger.Printf("Выключение всех койлов для истекшей сессии - session_id: %s, box_id: %s", session.ID, *session.BoxID)
-
-				// Выключаем свет
-				lightRegister := s.getLightRegisterForBox(ctx, *session.BoxID)
-				if lightRegister != "" {
-					if err := s.modbusService.WriteLightCoil(ctx, *session.BoxID, lightRegister, false); err != nil {
-						logger.Printf("Ошибка выключения света при истечении сессии - session_id: %s, box_id: %s, error: %v",
-							session.ID, *session.BoxID, err)
-					}
-				} else {
-					logger.Printf("ExpireSession: не найден регистр света для бокса %s", *session.BoxID)
-				}
-
-				// Выключаем химию (если была включена)
-				if session.WasChemistryOn {
-					chemistryRegister := s.getChemistryRegisterForBox(ctx, *session.BoxID)
-					if chemistryRegister != "" {
-						if err := s.modbusService.WriteChemistryCoil(ctx, *session.BoxID, chemistryRegister, false); err != nil {
-							logger.Printf("Ошибка выключения химии при истечении сессии - session_id: %s, box_id: %s, error: %v",
-								session.ID, *session.BoxID, err)
-						}
-					} else {
-						logger.Printf("ExpireSession: не найден регистр химии для бокса %s", *session.BoxID)
-					}
-				}
+				s.enqueueBoxOff(ctx, *session.BoxID, session.ID, session.WasChemistryOn)
 			}
 
 			// Отправляем уведомление о завершении сессии с информацией о кулдауне
@@ -1501,6 +1588,15 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 	s.processQueueMu.Lock()
 	defer s.processQueueMu.Unlock()
 
+	// После обработки отправляем изменения позиций в очереди (выполняется до снятия блокировки)
+	defer s.publishQueuePositions(ctx)
+
+	// После обработки отправляем включенные пользователями уведомления об очереди в Telegram
+	defer s.notifyQueueChanges(ctx)
+
+	// После обработки возвращаем доплату за пропуск очереди, если бокс не был назначен раньше
+	defer s.settleQueueJumps(ctx)
+
 	// Если сервис боксов не инициализирован, выходим
 	if s.washboxService == nil {
 		return nil
@@ -1538,11 +1634,14 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 		return nil
 	}
 
+	// Порядок обслуживания по классам приоритета со взвешенной справедливостью
+	orderQueueSessions(sessions, s.getQueuePrioritySettings(ctx), time.Now())
+
 	// Вспомогательные функции для фильтрации по снимку боксов
 	now := time.Now()
 
-	isInCooldownForUser := func(box washboxModels.WashBox, userID uuid.UUID, serviceType string) bool {
-		if box.ServiceType != serviceType {
+	isInCooldownForUser := func(box washboxModels.WashBox, userID uuid.UUID, siteID uuid.UUID, serviceType string) bool {
+		if box.SiteID != siteID || box.ServiceType != serviceType {
 			return false
 		}
 		if box.LastCompletedSessionUserID == nil || box.CooldownUntil == nil {
@@ -1554,8 +1653,8 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 		return now.Before(*box.CooldownUntil)
 	}
 
-	isInCooldownForCar := func(box washboxModels.WashBox, carNumber string, serviceType string) bool {
-		if box.ServiceType != serviceType {
+	isInCooldownForCar := func(box washboxModels.WashBox, carNumber string, siteID uuid.UUID, serviceType string) bool {
+		if box.SiteID != siteID || box.ServiceType != serviceType {
 			return false
 		}
 		if box.LastCompletedSessionCarNumber == nil || box.CooldownUntil == nil {
@@ -1577,11 +1676,11 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 		}
 	}
 
-	// Фильтрация доступных боксов по снимку
-	filterAvailable := func(serviceType string, withChemistry bool) []washboxModels.WashBox {
+	// Фильтрация доступных боксов площадки по снимку
+	filterAvailable := func(siteID uuid.UUID, serviceType string, withChemistry bool) []washboxModels.WashBox {
 		result := make([]washboxModels.WashBox, 0, len(allBoxes))
 		for _, b := range allBoxes {
-			if b.ServiceType != serviceType {
+			if b.SiteID != siteID || b.ServiceType != serviceType {
 				continue
 			}
 			if withChemistry && serviceType == "wash" && !b.ChemistryEnabled {
@@ -1603,6 +1702,7 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 
 		// Получаем доступные боксы по снимку, учитывая приоритет кулдауна
 		var availableBoxes []washboxModels.WashBox
+		cooldownMatch := false
 
 		// Проверяем, является ли это кассирской сессией
 		isCashierSession := false
@@ -1616,7 +1716,7 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 		if isCashierSession && session.CarNumber != "" {
 			// Сначала пробуем боксы в кулдауне для этого госномера
 			for _, b := range allBoxes {
-				if isInCooldownForCar(b, session.CarNumber, session.ServiceType) {
+				if isInCooldownForCar(b, session.CarNumber, session.SiteID, session.ServiceType) {
 					if session.ServiceType == "wash" && session.WithChemistry && !b.ChemistryEnabled {
 						continue
 					}
@@ -1624,13 +1724,14 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 				}
 			}
 			// Если нет боксов из кулдауна — берём свободные подходящие
+			cooldownMatch = len(availableBoxes) > 0
 			if len(availableBoxes) == 0 {
-				availableBoxes = filterAvailable(session.ServiceType, session.WithChemistry)
+				availableBoxes = filterAvailable(session.SiteID, session.ServiceType, session.WithChemistry)
 			}
 		} else {
 			// Обычная пользовательская сессия: пробуем кулдаун по user_id
 			for _, b := range allBoxes {
-				if isInCooldownForUser(b, session.UserID, session.ServiceType) {
+				if isInCooldownForUser(b, session.UserID, session.SiteID, session.ServiceType) {
 					if session.ServiceType == "wash" && session.WithChemistry && !b.ChemistryEnabled {
 						continue
 					}
@@ -1638,8 +1739,9 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 				}
 			}
 			// Если нет — свободные подходящие
+			cooldownMatch = len(availableBoxes) > 0
 			if len(availableBoxes) == 0 {
-				availableBoxes = filterAvailable(session.ServiceType, session.WithChemistry)
+				availableBoxes = filterAvailable(session.SiteID, session.ServiceType, session.WithChemistry)
 			}
 		}
 
@@ -1648,13 +1750,18 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 			continue
 		}
 
-		// Сортируем доступные боксы по приоритету (A -> Z)
-		sort.Slice(availableBoxes, func(i, j int) bool {
-			return availableBoxes[i].Priority < availableBoxes[j].Priority
+		// Выбираем бокс по стратегии, настроенной для типа услуги
+		selectedBox, assignmentLog, err := s.washboxService.SelectBoxForSession(ctx, &washboxModels.SelectBoxRequest{
+			SessionID:     session.ID,
+			ServiceType:   session.ServiceType,
+			Candidates:    availableBoxes,
+			CooldownMatch: cooldownMatch,
 		})
-
-		// Берем первый доступный бокс (с наивысшим приоритетом)
-		box := availableBoxes[0]
+		if err != nil || selectedBox == nil {
+			logger.Printf("ProcessQueue: ошибка выбора бокса для сессии %s: %v", session.ID, err)
+			continue
+		}
+		box := *selectedBox
 
 		// Локально помечаем бокс зарезервированным, чтобы не назначить повторно в этом проходе
 		markBoxReservedLocal(box.ID)
@@ -1695,6 +1802,11 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 				}
 			}
 
+			// Бокс другой площадки не может обслужить сессию
+			if lockedBox.SiteID != session.SiteID {
+				boxIsAvailable = false
+			}
+
 			if !boxIsAvailable {
 				return fmt.Errorf("бокс %s недоступен, статус: %s", lockedBox.ID, lockedBox.Status)
 			}
@@ -1729,8 +1841,10 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 			// Обновляем сессию - назначаем бокс, меняем статус и обновляем время изменения статуса
 			lockedSession.BoxID = &box.ID
 			lockedSession.BoxNumber = &box.Number
+			assignedAt := time.Now()
 			lockedSession.Status = models.SessionStatusAssigned
-			lockedSession.StatusUpdatedAt = time.Now()
+			lockedSession.StatusUpdatedAt = assignedAt
+			lockedSession.AssignedAt = &assignedAt
 
 			if err := tx.Save(&lockedSession).Error; err != nil {
 				return fmt.Errorf("ошибка обновления сессии: %w", err)
@@ -1750,10 +1864,20 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 			continue // Переходим к следующей сессии вместо остановки всей очереди
 		}
 
+		// Журнал выбора бокса пишем только после фиксации назначения
+		// Ошибка записи журнала не должна мешать назначению бокса
+		if assignmentLog != nil {
+			if err := s.washboxService.CreateAssignmentLog(ctx, assignmentLog); err != nil {
+				logger.Printf("ProcessQueue: ошибка записи журнала выбора бокса для сессии %s: %v", session.ID, err)
+			}
+		}
+
+		s.publishSessionStatus(&session, models.SessionStatusInQueue)
+
 		// Проверяем, был ли бокс в кулдауне для этого пользователя или госномера по локальному снимку
 		// Если да, то сразу запускаем сессию, пропуская статус assigned (асинхронно)
 		if isCashierSession && session.CarNumber != "" {
-			if isInCooldownForCar(box, session.CarNumber, session.ServiceType) {
+			if isInCooldownForCar(box, session.CarNumber, session.SiteID, session.ServiceType) {
 				logger.Printf("ProcessQueue: бокс %s был в кулдауне для госномера %s, запускаем сессию %s автоматически", box.ID, session.CarNumber, session.ID)
 				// Запускаем асинхронно, не ждем завершения
 				go func(sessionID uuid.UUID) {
@@ -1766,7 +1890,7 @@ func (s *ServiceImpl) ProcessQueue(ctx context.Context) error {
 				}(session.ID)
 			}
 		} else {
-			if isInCooldownForUser(box, session.UserID, session.ServiceType) {
+			if isInCooldownForUser(box, session.UserID, session.SiteID, session.ServiceType) {
 				logger.Printf("ProcessQueue: бокс %s был в кулдауне для пользователя %s, запускаем сессию %s автоматически", box.ID, session.UserID, session.ID)
 				// Запускаем асинхронно, не ждем завершения
 				go func(sessionID uuid.UUID) {
@@ -2133,6 +2257,7 @@ func (s *ServiceImpl) GetUserSessionHistory(ctx context.Context, req *models.Get
 		}
 
 		sessions[i].SessionTimeoutMinutes = sessionTimeout
+		sessions[i].CarNumberFormatted = utils.FormatLicensePlate(sessions[i].CarNumber, sessions[i].CarNumberCountry)
 
 		// Заполняем cooldown_minutes только для завершенных сессий с реальным активным кулдауном
 		if sessions[i].Status == models.SessionStatusComplete && sessions[i].BoxID != nil {
@@ -2195,12 +2320,28 @@ func (s *ServiceImpl) CreateFromCashier(ctx context.Context, req *models.Cashier
 		return nil, fmt.Errorf("неверный формат CASHIER_USER_ID: %v", err)
 	}
 
-	// Нормализация госномера (если указан)
+	// Валидация и нормализация госномера по правилам страны (если указан)
 	var normalizedCarNumber string
+	var isVIP bool
+	priorityClass := models.PriorityClassNormal
+	carNumberCountry := utils.NormalizeCarNumberCountry(req.CarNumberCountry)
 	if req.CarNumber != "" {
-		normalizedCarNumber = utils.NormalizeLicensePlate(req.CarNumber)
+		normalizedCarNumber, err = utils.ValidateLicensePlate(req.CarNumber, carNumberCountry)
+		if err != nil {
+			logger.Printf("Service - CreateFromCashier: невалидный госномер '%s' (страна %s): %v", req.CarNumber, carNumberCountry, err)
+			return nil, err
+		}
 		logger.Printf("Service - CreateFromCashier: госномер нормализован '%s' -> '%s'", req.CarNumber, normalizedCarNumber)
 
+		// Проверяем госномер по черному и VIP спискам
+		vipEntry, err := s.checkPlateLists(ctx, normalizedCarNumber, plateListModels.SourceCashier, nil)
+		if err != nil {
+			logger.Printf("Service - CreateFromCashier: госномер '%s' в черном списке", normalizedCarNumber)
+			return nil, err
+		}
+		isVIP = vipEntry != nil && vipEntry.ListType == plateListModels.ListTypeVIP
+		priorityClass = priorityClassForEntry(vipEntry)
+
 		// Проверяем, нет ли уже активной сессии с этим номером машины
 		existingSession, err := s.repo.GetActiveSessionByCarNumber(ctx, normalizedCarNumber)
 		if err == nil && existingSession != nil {
@@ -2233,22 +2374,47 @@ func (s *ServiceImpl) CreateFromCashier(ctx context.Context, req *models.Cashier
 		}
 	}
 
+	// Сессия кассы обслуживается на площадке, определенной по ключу 1C
+	siteID := req.SiteID
+	if siteID == uuid.Nil {
+		siteID = siteModels.DefaultSiteID
+	}
+
 	// Создаем новую сессию
 	now := time.Now()
 	session := &models.Session{
 		UserID:               cashierUserID,
+		SiteID:               siteID,
 		Status:               models.SessionStatusInQueue, // Статус "в очереди" как указано в требованиях
 		ServiceType:          req.ServiceType,
 		WithChemistry:        req.WithChemistry,
 		ChemistryTimeMinutes: req.ChemistryTimeMinutes, // Сохраняем выбранное время химии
 		CarNumber:            normalizedCarNumber,      // Используем нормализованный госномер
-		CarNumberCountry:     req.CarNumberCountry,     // Сохраняем страну гос номера
+		CarNumberCountry:     carNumberCountry,         // Сохраняем страну гос номера
 		RentalTimeMinutes:    req.RentalTimeMinutes,
 		StatusUpdatedAt:      now,
+		IsVIP:                isVIP,
+		PriorityClass:        priorityClass,
 	}
 
-	// Сохраняем сессию в базе данных
-	err = s.repo.CreateSession(ctx, session)
+	// Сохраняем сессию в базе данных. Если кассир принял оплату долга за простой вместе с сессией,
+	// долг погашается в одной транзакции с созданием сессии: при ошибке погашения сессия не создается,
+	// 1C получает ошибку и может повторить запрос
+	if req.DebtAmount > 0 && normalizedCarNumber != "" && s.debtService != nil {
+		err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(session).Error; err != nil {
+				return err
+			}
+			settled, err := s.debtService.SettleFromCashier(ctx, tx, normalizedCarNumber, req.DebtAmount, &session.ID)
+			if err != nil {
+				return fmt.Errorf("ошибка погашения долга за простой: %w", err)
+			}
+			session.DebtAmount = settled
+			return tx.Model(&models.Session{}).Where("id = ?", session.ID).Update("debt_amount", settled).Error
+		})
+	} else {
+		err = s.repo.CreateSession(ctx, session)
+	}
 	if err != nil {
 		// Проверяем, не является ли это ошибкой нарушения уникального индекса
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
@@ -2266,6 +2432,9 @@ func (s *ServiceImpl) CreateFromCashier(ctx context.Context, req *models.Cashier
 		return nil, err
 	}
 
+	// Сессия кассира сразу создается в очереди
+	s.publishSessionStatus(session, "")
+
 	return session, nil
 }
 
@@ -2289,6 +2458,7 @@ func (s *ServiceImpl) AdminListSessions(ctx context.Context, req *models.AdminLi
 		req.BoxNumber,
 		req.Status,
 		req.ServiceType,
+		req.SiteID,
 		req.DateFrom,
 		req.DateTo,
 		limit,
@@ -2306,6 +2476,7 @@ func (s *ServiceImpl) AdminListSessions(ctx context.Context, req *models.AdminLi
 	}
 	for i := range sessions {
 		sessions[i].SessionTimeoutMinutes = sessionTimeout
+		sessions[i].CarNumberFormatted = utils.FormatLicensePlate(sessions[i].CarNumber, sessions[i].CarNumberCountry)
 	}
 
 	return &models.AdminListSessionsResponse{
@@ -2331,6 +2502,7 @@ func (s *ServiceImpl) AdminGetSession(ctx context.Context, req *models.AdminGetS
 			sessionTimeout = 3
 		}
 		session.SessionTimeoutMinutes = sessionTimeout
+		session.CarNumberFormatted = utils.FormatLicensePlate(session.CarNumber, session.CarNumberCountry)
 	}
 
 	return &models.AdminGetSessionResponse{
@@ -2352,6 +2524,7 @@ func (s *ServiceImpl) UpdateSessionStatus(ctx context.Context, sessionID uuid.UU
 	}
 
 	// Обновляем статус и время обновления
+	prevStatus := session.Status
 	session.Status = status
 	session.StatusUpdatedAt = time.Now()
 
@@ -2361,6 +2534,18 @@ func (s *ServiceImpl) UpdateSessionStatus(ctx context.Context, sessionID uuid.UU
 		return fmt.Errorf("ошибка обновления статуса сессии: %w", err)
 	}
 
+	s.publishSessionStatus(session, prevStatus)
+
+	// Сессия оплачена - погашаем долг за простой, включенный в платеж
+	if status == models.SessionStatusInQueue && session.DebtAmount > 0 && s.debtService != nil {
+		settled, err := s.debtService.SettleDebts(ctx, session.CarNumber, &session.CreatedAt, debtModels.SettledViaApp, &session.ID)
+		if err != nil {
+			logger.Printf("UpdateSessionStatus: ошибка погашения долга за простой, session_id: %s, car_number: %s, error: %v", session.ID, session.CarNumber, err)
+		} else if settled != session.DebtAmount {
+			logger.Printf("UpdateSessionStatus: погашенный долг %d не совпадает с оплаченным %d, session_id: %s", settled, session.DebtAmount, session.ID)
+		}
+	}
+
 	return nil
 }
 
@@ -2424,6 +2609,9 @@ func (s *ServiceImpl) UpdateSessionExtension(ctx context.Context, sessionID uuid
 		return fmt.Errorf("ошибка обновления времени продления сессии: %w", err)
 	}
 
+	// Табло обратного отсчета показывает время с учетом продления
+	s.enqueueSessionRegisters(ctx, session)
+
 	return nil
 }
 
@@ -2453,7 +2641,7 @@ func (s *ServiceImpl) CashierGetActiveSessions(ctx context.Context, req *models.
 	// Получаем активные сессии кассира (не завершенные)
 	// Активные статусы: created, in_queue, assigned, active
 	// Терминальные статусы: complete, canceled, expired, payment_failed
-	sessions, total, err := s.repo.GetSessionsWithFilters(ctx, &cashierUserID, nil, nil, nil, nil, nil, nil, limit, offset)
+	sessions, total, err := s.repo.GetSessionsWithFilters(ctx, &cashierUserID, nil, nil, nil, nil, &req.SiteID, nil, nil, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -2487,6 +2675,7 @@ func (s *ServiceImpl) CashierGetActiveSessions(ctx context.Context, req *models.
 	// Загружаем платежи для каждой сессии
 	for i := range activeSessions {
 		activeSessions[i].SessionTimeoutMinutes = sessionTimeout
+		activeSessions[i].CarNumberFormatted = utils.FormatLicensePlate(activeSessions[i].CarNumber, activeSessions[i].CarNumberCountry)
 	}
 
 	return &models.CashierActiveSessionsResponse{
@@ -2667,14 +2856,22 @@ func (s *ServiceImpl) EnableChemistry(ctx context.Context, req *models.EnableChe
 	// ВАЖНО: Обновляем только нужные поля, не трогаем StatusUpdatedAt, RentalTimeMinutes, ExtensionTimeMinutes!
 	logger.Printf("EnableChemistry: обновление химии - SessionID=%s", session.ID)
 
-	// Используем Updates для обновления только нужных полей
-	err = s.repo.UpdateSessionFields(ctx, session.ID, map[string]interface{}{
-		"was_chemistry_on":     true,
-		"chemistry_started_at": now,
-		"updated_at":           now,
+	// Отметка о включении и команда Modbus сохраняются в одной транзакции
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Session{}).Where("id = ?", session.ID).Updates(map[string]interface{}{
+			"was_chemistry_on":     true,
+			"chemistry_started_at": now,
+			"updated_at":           now,
+		}).Error; err != nil {
+			return fmt.Errorf("ошибка при обновлении сессии: %w", err)
+		}
+		if session.BoxID == nil {
+			return nil
+		}
+		return s.enqueueChemistryChannels(ctx, tx, *session.BoxID, session.ID, true)
 	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при обновлении сессии: %w", err)
+		return nil, err
 	}
 
 	// Перечитываем обновленную сессию
@@ -2683,22 +2880,6 @@ func (s *ServiceImpl) EnableChemistry(ctx context.Context, req *models.EnableChe
 		return nil, fmt.Errorf("ошибка получения обновленной сессии: %w", err)
 	}
 
-	// Включаем химию в боксе через Modbus
-	if s.modbusService != nil && session.BoxID != nil {
-		// Получаем регистр химии для бокса
-		chemistryRegister := s.getChemistryRegisterForBox(ctx, *session.BoxID)
-		if chemistryRegister != "" {
-			err = s.modbusService.WriteChemistryCoil(ctx, *session.BoxID, chemistryRegister, true)
-			if err != nil {
-				// Логируем ошибку (HandleModbusError теперь только логирует, не продлевает время)
-				s.modbusService.HandleModbusError(*session.BoxID, "chemistry_on", session.ID, err)
-				logger.Printf("Ошибка включения химии в боксе %s: %v", *session.BoxID, err)
-			}
-		} else {
-			logger.Printf("EnableChemistry: не найден регистр химии для бокса %s", *session.BoxID)
-		}
-	}
-
 	logger.Printf("Химия включена: SessionID=%s, ChemistryTimeMinutes=%d", session.ID, session.ChemistryTimeMinutes)
 
 	// Запускаем автоматическое выключение химии через указанное время
@@ -2730,32 +2911,24 @@ func (s *ServiceImpl) AutoDisableChemistry(sessionID uuid.UUID, chemistryTimeMin
 
 		// Проверяем что химия все еще активна (была включена, но не выключена)
 		if session.ChemistryStartedAt != nil && session.ChemistryEndedAt == nil {
-			// Выключаем химию через Modbus
-			if s.modbusService != nil && session.BoxID != nil {
-				chemistryRegister := s.getChemistryRegisterForBox(opCtx, *session.BoxID)
-				if chemistryRegister != "" {
-					// Отдельный контекст для Modbus операции
-					modbusCtx, modbusCancel := context.WithTimeout(context.Background(), 10*time.Second)
-					defer modbusCancel()
-					if err := s.modbusService.WriteChemistryCoil(modbusCtx, *session.BoxID, chemistryRegister, false); err != nil {
-						logger.Printf("AutoDisableChemistry: ошибка автовыключения химии через Modbus: %v, SessionID=%s, BoxID=%s", err, sessionID, *session.BoxID)
-					} else {
-						logger.Printf("AutoDisableChemistry: химия успешно выключена через Modbus, SessionID=%s, BoxID=%s", sessionID, *session.BoxID)
-					}
-				} else {
-					logger.Printf("AutoDisableChemistry: не найден регистр химии для бокса %s", *session.BoxID)
-				}
-			}
-
-			// Обновляем время выключения химии
 			now := time.Now()
 			logger.Printf("AutoDisableChemistry: выключение химии - SessionID=%s", sessionID)
 
-			if err := s.repo.UpdateSessionFields(opCtx, sessionID, map[string]interface{}{
-				"chemistry_ended_at": now,
-				"updated_at":         now,
-			}); err != nil {
-				logger.Printf("AutoDisableChemistry: ошибка обновления сессии: %v, SessionID=%s", err, sessionID)
+			// Время выключения и команда Modbus сохраняются в одной транзакции
+			err := s.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+				if err := tx.Model(&models.Session{}).Where("id = ?", sessionID).Updates(map[string]interface{}{
+					"chemistry_ended_at": now,
+					"updated_at":         now,
+				}).Error; err != nil {
+					return err
+				}
+				if session.BoxID == nil {
+					return nil
+				}
+				return s.enqueueChemistryChannels(opCtx, tx, *session.BoxID, sessionID, false)
+			})
+			if err != nil {
+				logger.Printf("AutoDisableChemistry: ошибка выключения химии: %v, SessionID=%s", err, sessionID)
 			} else {
 				logger.Printf("AutoDisableChemistry: химия автоматически выключена, SessionID=%s", sessionID)
 			}
@@ -2806,38 +2979,15 @@ func (s *ServiceImpl) ReassignSession(ctx context.Context, req *models.ReassignS
 		}
 	}
 
-	// Выключаем свет и химию через Modbus
-	if s.modbusService != nil {
-		logger.Printf("ReassignSession: выключение оборудования, SessionID=%s, BoxID=%s", req.SessionID, *session.BoxID)
-
-		// Выключаем свет
-		lightRegister := s.getLightRegisterForBox(ctx, *session.BoxID)
-		if lightRegister != "" {
-			if err := s.modbusService.WriteLightCoil(ctx, *session.BoxID, lightRegister, false); err != nil {
-				logger.Printf("ReassignSession: ошибка выключения света, SessionID=%s, BoxID=%s, error=%v", req.SessionID, *session.BoxID, err)
-				// Не прерываем выполнение, логируем ошибку
-			}
-		} else {
-			logger.Printf("ReassignSession: не найден регистр света для бокса %s", *session.BoxID)
-		}
-
-		// Выключаем химию (если была включена)
-		if session.WasChemistryOn {
-			chemistryRegister := s.getChemistryRegisterForBox(ctx, *session.BoxID)
-			if chemistryRegister != "" {
-				if err := s.modbusService.WriteChemistryCoil(ctx, *session.BoxID, chemistryRegister, false); err != nil {
-					logger.Printf("ReassignSession: ошибка выключения химии, SessionID=%s, BoxID=%s, error=%v", req.SessionID, *session.BoxID, err)
-					// Не прерываем выполнение, логируем ошибку
-				}
-			} else {
-				logger.Printf("ReassignSession: не найден регистр химии для бокса %s", *session.BoxID)
-			}
-		}
-	}
+	// Выключение света и химии ставится в очередь команд Modbus
+	logger.Printf("ReassignSession: выключение оборудования, SessionID=%s, BoxID=%s", req.SessionID, *session.BoxID)
+	s.enqueueBoxOff(ctx, *session.BoxID, session.ID, session.WasChemistryOn)
 
-	// Обнуляем связь с боксом
+	// Обнуляем связь с боксом и время назначения/старта
 	session.BoxID = nil
 	session.BoxNumber = nil
+	session.AssignedAt = nil
+	session.StartedAt = nil
 
 	// Сбрасываем флаги химии - будто химия не была использована вообще
 	session.WasChemistryOn = false
@@ -2845,6 +2995,7 @@ func (s *ServiceImpl) ReassignSession(ctx context.Context, req *models.ReassignS
 	session.ChemistryEndedAt = nil
 
 	// Возвращаем сессию в очередь
+	prevStatus := session.Status
 	session.Status = models.SessionStatusInQueue
 	session.StatusUpdatedAt = time.Now() // Сбрасываем таймер
 
@@ -2855,6 +3006,8 @@ func (s *ServiceImpl) ReassignSession(ctx context.Context, req *models.ReassignS
 		return nil, fmt.Errorf("не удалось обновить сессию: %w", err)
 	}
 
+	s.publishSessionStatus(session, prevStatus)
+
 	logger.Printf("ReassignSession: сессия возвращена в очередь, SessionID=%s", req.SessionID)
 
 	return &models.ReassignSessionResponse{
@@ -2954,10 +3107,10 @@ func (s *ServiceImpl) CheckAndAutoEnableChemistry(ctx context.Context) error {
 				// Отправляем уведомление через Telegram
 				err = s.telegramBot.SendSessionNotification(user.TelegramID, telegram.NotificationTypeChemistryAutoEnabled, nil)
 				if err != nil {
-					logger.Printf("CheckAndAutoEnableChemistry: ошибка отправки уведомления - UserID=%s, TelegramID=%s, error=%v",
+					logger.Printf("CheckAndAutoEnableChemistry: ошибка отправки уведомления - UserID=%s, TelegramID=%d, error=%v",
 						user.ID, user.TelegramID, err)
 				} else {
-					logger.Printf("CheckAndAutoEnableChemistry: уведомление отправлено - UserID=%s, TelegramID=%s",
+					logger.Printf("CheckAndAutoEnableChemistry: уведомление отправлено - UserID=%s, TelegramID=%d",
 						user.ID, user.TelegramID)
 				}
 			}(session.ID, session.UserID)
@@ -2986,25 +3139,6 @@ func (s *ServiceImpl) getLightRegisterForBox(ctx context.Context, boxID uuid.UUI
 	return ""
 }
 
-// getChemistryRegisterForBox получает регистр химии для бокса
-func (s *ServiceImpl) getChemistryRegisterForBox(ctx context.Context, boxID uuid.UUID) string {
-	if s.washboxService == nil {
-		return ""
-	}
-
-	box, err := s.washboxService.GetWashBoxByID(ctx, boxID)
-	if err != nil {
-		logger.Printf("getChemistryRegisterForBox: ошибка получения бокса %s: %v", boxID, err)
-		return ""
-	}
-
-	if box.ChemistryCoilRegister != nil {
-		return *box.ChemistryCoilRegister
-	}
-
-	return ""
-}
-
 // GetActiveSessionByCarNumber получает активную сессию по номеру автомобиля
 func (s *ServiceImpl) GetActiveSessionByCarNumber(ctx context.Context, carNumber string) (*models.Session, error) {
 	logger.Printf("Service - GetActiveSessionByCarNumber: поиск активной сессии по номеру %s", carNumber)
@@ -3139,6 +3273,9 @@ func (s *ServiceImpl) ExtendFromCashier(ctx context.Context, req *models.ExtendS
 		return nil, fmt.Errorf("ошибка обновления сессии: %w", err)
 	}
 
+	// Табло обратного отсчета показывает время с учетом продления
+	s.enqueueSessionRegisters(ctx, session)
+
 	logger.Printf("Service - ExtendFromCashier: сессия успешно продлена, session_id: %s, extension_time_minutes: %d, extension_chemistry_time_minutes: %d",
 		session.ID.String(), session.ExtensionTimeMinutes, session.ExtensionChemistryTimeMinutes)
 
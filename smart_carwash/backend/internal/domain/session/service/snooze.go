@@ -0,0 +1,213 @@
+package service
+
+import (
+	"carwash_backend/internal/domain/session/models"
+	settingsModels "carwash_backend/internal/domain/settings/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// getQueueSnoozeSettings получает настройки отсрочки назначенного бокса
+// При ошибке используются значения по умолчанию
+func (s *ServiceImpl) getQueueSnoozeSettings(ctx context.Context) *settingsModels.QueueSnoozeSettings {
+	defaults := &settingsModels.QueueSnoozeSettings{MaxSnoozes: 2, PositionPenalty: 2}
+	if s.settingsService == nil {
+		return defaults
+	}
+
+	settings, err := s.settingsService.GetQueueSnoozeSettings(ctx)
+	if err != nil {
+		logger.Printf("getQueueSnoozeSettings: ошибка получения настроек отсрочки, используем значения по умолчанию: %v", err)
+	}
+	if settings == nil {
+		return defaults
+	}
+
+	return settings
+}
+
+// snoozedQueueEnteredAt подбирает время начала ожидания для отложившей бокс сессии так,
+// чтобы перед ней в очереди оказались ровно penalty сессий (или вся очередь, если она короче)
+// Оценки всех сессий растут со временем одинаково, поэтому подобранное место сохраняется
+// и дальше сдвигается к началу очереди по мере обслуживания впереди стоящих
+func snoozedQueueEnteredAt(session models.Session, queue []models.Session, penalty int, settings *settingsModels.QueuePrioritySettings, now time.Time) time.Time {
+	if len(queue) == 0 {
+		return now
+	}
+
+	boost := float64(settings.BoostMinutes[session.PriorityClass])
+	maxWait := float64(settings.MaxWaitMinutes)
+
+	// enteredAtForScore переводит оценку в время начала ожидания, не допуская попадания в просроченные,
+	// иначе сессия обошла бы всех, кто ждет меньше максимального времени
+	enteredAtForScore := func(score float64) time.Time {
+		wait := score - boost
+		if maxWait > 0 && wait >= maxWait {
+			wait = maxWait - 0.5
+		}
+		return now.Add(-time.Duration(wait * float64(time.Minute)))
+	}
+
+	if penalty <= 0 {
+		first := queue[0]
+		if isQueueOverdue(first, settings, now) {
+			return queueWaitingSince(first).Add(-time.Second)
+		}
+		return enteredAtForScore(queueScore(first, settings, now) + 1)
+	}
+
+	if penalty > len(queue) {
+		penalty = len(queue)
+	}
+	ahead := queue[penalty-1]
+
+	// Впереди стоящая сессия просрочена - просроченные обслуживаются по времени начала ожидания
+	if isQueueOverdue(ahead, settings, now) {
+		return queueWaitingSince(ahead).Add(time.Millisecond)
+	}
+
+	// Встаем между впереди стоящей и следующей за ней сессией
+	score := queueScore(ahead, settings, now) - 0.5
+	if penalty < len(queue) {
+		behind := queue[penalty]
+		score = (queueScore(ahead, settings, now) + queueScore(behind, settings, now)) / 2
+	}
+
+	return enteredAtForScore(score)
+}
+
+// SnoozeSession откладывает назначенный бокс: бокс освобождается для следующего клиента,
+// а сессия возвращается в очередь близко к началу со штрафом в несколько позиций
+func (s *ServiceImpl) SnoozeSession(ctx context.Context, req *models.SnoozeSessionRequest) (*models.SnoozeSessionResponse, error) {
+	logger.Printf("SnoozeSession: начало отсрочки бокса, SessionID=%s, UserID=%s", req.SessionID, req.UserID)
+
+	// Исключаем одновременное назначение боксов, пока сессия возвращается в очередь
+	s.processQueueMu.Lock()
+	defer s.processQueueMu.Unlock()
+
+	session, err := s.repo.GetSessionByID(ctx, req.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("сессия не найдена: %w", err)
+	}
+
+	if session.UserID != req.UserID {
+		return nil, fmt.Errorf("недостаточно прав для отсрочки бокса")
+	}
+
+	if session.Status != models.SessionStatusAssigned || session.BoxID == nil {
+		return nil, fmt.Errorf("отложить можно только назначенный бокс, текущий статус: '%s'", session.Status)
+	}
+
+	snoozeSettings := s.getQueueSnoozeSettings(ctx)
+	if session.SnoozeCount >= snoozeSettings.MaxSnoozes {
+		return nil, fmt.Errorf("бокс уже отложен максимальное количество раз (%d)", snoozeSettings.MaxSnoozes)
+	}
+
+	// Подбираем место в очереди до изменения сессии, сама сессия в очереди еще не числится
+	queue, err := s.GetOrderedQueue(ctx, session.SiteID, session.ServiceType)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения очереди: %w", err)
+	}
+	now := time.Now()
+	enteredAt := snoozedQueueEnteredAt(*session, queue, snoozeSettings.PositionPenalty, s.getQueuePrioritySettings(ctx), now)
+
+	boxID := *session.BoxID
+	var prevBoxStatus string
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Блокируем строку сессии, чтобы параллельный старт или повторная отсрочка дождались транзакции
+		var lockedSession models.Session
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&lockedSession, session.ID).Error; err != nil {
+			return fmt.Errorf("ошибка получения сессии: %w", err)
+		}
+
+		// Сессия могла быть запущена, пока выполнялся запрос
+		if lockedSession.Status != models.SessionStatusAssigned || lockedSession.BoxID == nil || *lockedSession.BoxID != boxID {
+			return fmt.Errorf("сессия %s уже не ожидает старта в боксе, текущий статус: %s", lockedSession.ID, lockedSession.Status)
+		}
+
+		var lockedBox washboxModels.WashBox
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&lockedBox, boxID).Error; err != nil {
+			return fmt.Errorf("ошибка получения бокса: %w", err)
+		}
+		prevBoxStatus = lockedBox.Status
+
+		// Освобождаем бокс для следующего клиента очереди
+		if err := tx.Model(&washboxModels.WashBox{}).
+			Where("id = ?", boxID).
+			Update("status", washboxModels.StatusFree).Error; err != nil {
+			return fmt.Errorf("ошибка освобождения бокса: %w", err)
+		}
+
+		// Возвращаем сессию в очередь, сбрасывая назначение и уведомление об истечении ожидания старта
+		lockedSession.BoxID = nil
+		lockedSession.AssignedAt = nil
+		lockedSession.Status = models.SessionStatusInQueue
+		lockedSession.StatusUpdatedAt = now
+		lockedSession.IsExpiringNotificationSent = false
+		lockedSession.SnoozeCount++
+		lockedSession.QueueEnteredAt = &enteredAt
+
+		if err := tx.Save(&lockedSession).Error; err != nil {
+			return fmt.Errorf("ошибка обновления сессии: %w", err)
+		}
+
+		*session = lockedSession
+		return nil
+	})
+	if err != nil {
+		logger.Printf("SnoozeSession: ошибка отсрочки бокса, SessionID=%s, error=%v", req.SessionID, err)
+		return nil, err
+	}
+
+	if s.washboxLogSvc != nil && prevBoxStatus != washboxModels.StatusFree {
+		_ = s.washboxLogSvc.RecordStatusChange(ctx, boxID, prevBoxStatus, washboxModels.StatusFree, nil)
+	}
+
+	s.publishSessionStatus(session, models.SessionStatusAssigned)
+
+	// Определяем новую позицию в очереди
+	position := 0
+	if ordered, err := s.GetOrderedQueue(ctx, session.SiteID, session.ServiceType); err == nil {
+		for i := range ordered {
+			if ordered[i].ID == session.ID {
+				position = i + 1
+				break
+			}
+		}
+	}
+
+	snoozesLeft := snoozeSettings.MaxSnoozes - session.SnoozeCount
+	if snoozesLeft < 0 {
+		snoozesLeft = 0
+	}
+
+	logger.Printf("SnoozeSession: бокс отложен, SessionID=%s, BoxID=%s, position=%d, snooze_count=%d", session.ID, boxID, position, session.SnoozeCount)
+
+	// Отправляем уведомление об отсрочке асинхронно
+	if s.telegramBot != nil && s.userService != nil {
+		go func(sessionID uuid.UUID, userID uuid.UUID, serviceType string, position int, snoozesLeft int) {
+			user, err := s.userService.GetUserByID(context.Background(), userID)
+			if err != nil || user == nil {
+				logger.Printf("SnoozeSession: не удалось получить пользователя для уведомления, SessionID=%s: %v", sessionID, err)
+				return
+			}
+			if err := s.telegramBot.SendSessionSnoozedNotification(user.TelegramID, serviceType, position, snoozesLeft); err != nil {
+				logger.Printf("SnoozeSession: ошибка отправки уведомления об отсрочке, SessionID=%s: %v", sessionID, err)
+			}
+		}(session.ID, session.UserID, session.ServiceType, position, snoozesLeft)
+	}
+
+	return &models.SnoozeSessionResponse{
+		Session:     *session,
+		Position:    position,
+		SnoozesLeft: snoozesLeft,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	carwashStatusModels "carwash_backend/internal/domain/carwash_status/models"
+	"carwash_backend/internal/domain/session/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// checkAcceptingSessions проверяет, принимает ли площадка новые сессии на тип услуги
+// (статус мойки, рабочие часы, статус типа услуги и лимит его очереди)
+// Ошибки чтения статуса не блокируют создание сессии
+func (s *ServiceImpl) checkAcceptingSessions(ctx context.Context, siteID uuid.UUID, serviceType string, now time.Time) error {
+	if s.carwashStatusRepo == nil {
+		return nil
+	}
+
+	status, err := s.carwashStatusRepo.GetCurrentStatus(ctx, siteID)
+	if err != nil {
+		logger.Printf("checkAcceptingSessions: ошибка получения статуса мойки, site_id: %s, error: %v", siteID, err)
+		return nil
+	}
+
+	var schedule *carwashStatusModels.Schedule
+	if status.ScheduleEnabled {
+		schedule, err = s.carwashStatusRepo.GetSchedule(ctx, siteID, now)
+		if err != nil {
+			logger.Printf("checkAcceptingSessions: ошибка получения расписания, site_id: %s, error: %v", siteID, err)
+			schedule = nil
+		}
+	}
+
+	if closedErr := carwashStatusModels.CheckAcceptingSessions(status, schedule, now); closedErr != nil {
+		return closedErr
+	}
+
+	serviceTypeStatus, err := s.carwashStatusRepo.GetServiceTypeStatus(ctx, siteID, serviceType)
+	if err != nil {
+		logger.Printf("checkAcceptingSessions: ошибка получения статуса услуги, site_id: %s, service_type: %s, error: %v", siteID, serviceType, err)
+		return nil
+	}
+	if serviceTypeStatus == nil {
+		return nil
+	}
+
+	queueSize := 0
+	if serviceTypeStatus.MaxQueueSize > 0 {
+		queueSize, err = s.repo.CountQueuedSessions(ctx, siteID, serviceType)
+		if err != nil {
+			logger.Printf("checkAcceptingSessions: ошибка подсчета очереди, site_id: %s, service_type: %s, error: %v", siteID, serviceType, err)
+			return nil
+		}
+	}
+
+	if unavailableErr := carwashStatusModels.CheckServiceTypeAvailable(serviceType, serviceTypeStatus, queueSize); unavailableErr != nil {
+		return unavailableErr
+	}
+	return nil
+}
+
+// CountQueuedSessions подсчитывает сессии в очереди площадки на тип услуги
+func (s *ServiceImpl) CountQueuedSessions(ctx context.Context, siteID uuid.UUID, serviceType string) (int, error) {
+	return s.repo.CountQueuedSessions(ctx, siteID, serviceType)
+}
+
+// NotifyQueueBeforeClosing предупреждает клиентов в очереди и с назначенным боксом о скором закрытии площадки
+// Возвращает количество отправленных уведомлений
+func (s *ServiceImpl) NotifyQueueBeforeClosing(ctx context.Context, siteID uuid.UUID, closesAt time.Time) (int, error) {
+	if s.userService == nil || s.telegramBot == nil {
+		return 0, nil
+	}
+
+	var sessions []models.Session
+	for _, status := range []string{models.SessionStatusInQueue, models.SessionStatusAssigned} {
+		statusSessions, err := s.repo.GetSessionsByStatus(ctx, status)
+		if err != nil {
+			return 0, fmt.Errorf("ошибка получения сессий со статусом %s: %w", status, err)
+		}
+		for _, session := range statusSessions {
+			if session.SiteID == siteID {
+				sessions = append(sessions, session)
+			}
+		}
+	}
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(sessions))
+	for _, session := range sessions {
+		userIDs = append(userIDs, session.UserID)
+	}
+	users, err := s.userService.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения пользователей: %w", err)
+	}
+
+	sent := 0
+	for _, session := range sessions {
+		user, ok := users[session.UserID]
+		if !ok || user.TelegramID == 0 {
+			continue
+		}
+
+		sent++
+		go func(sessionID uuid.UUID, telegramID int64) {
+			if err := s.telegramBot.SendCarwashClosingNotification(telegramID, closesAt.In(carwashStatusModels.ScheduleLocation)); err != nil {
+				logger.Printf("NotifyQueueBeforeClosing: ошибка отправки уведомления для сессии %s: %v", sessionID, err)
+			}
+		}(session.ID, user.TelegramID)
+	}
+
+	logger.Printf("NotifyQueueBeforeClosing: site_id: %s, closes_at: %s, отправлено уведомлений: %d", siteID, closesAt.Format(time.RFC3339), sent)
+	return sent, nil
+}
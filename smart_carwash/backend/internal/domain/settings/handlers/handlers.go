@@ -3,6 +3,8 @@ package handlers
 import (
 	"carwash_backend/internal/domain/settings/models"
 	"carwash_backend/internal/domain/settings/service"
+	"carwash_backend/internal/middleware"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -43,6 +45,16 @@ func (h *Handler) RegisterRoutes(router gin.IRouter) {
 		adminSettingsGroup.PUT("/session-timeout", h.AdminUpdateSessionTimeout)
 		adminSettingsGroup.GET("/cooldown-timeout", h.AdminGetCooldownTimeout)
 		adminSettingsGroup.PUT("/cooldown-timeout", h.AdminUpdateCooldownTimeout)
+		adminSettingsGroup.GET("/overtime", h.AdminGetOvertimeSettings)
+		adminSettingsGroup.PUT("/overtime", h.AdminUpdateOvertimeSettings)
+		adminSettingsGroup.GET("/box-assignment-strategy", h.AdminGetBoxAssignmentStrategy)
+		adminSettingsGroup.PUT("/box-assignment-strategy", h.AdminUpdateBoxAssignmentStrategy)
+		adminSettingsGroup.GET("/queue-priority", h.AdminGetQueuePrioritySettings)
+		adminSettingsGroup.PUT("/queue-priority", h.AdminUpdateQueuePrioritySettings)
+		adminSettingsGroup.GET("/queue-snooze", h.AdminGetQueueSnoozeSettings)
+		adminSettingsGroup.PUT("/queue-snooze", h.AdminUpdateQueueSnoozeSettings)
+		adminSettingsGroup.GET("/queue-jump", h.AdminGetQueueJumpSettings)
+		adminSettingsGroup.PUT("/queue-jump", h.AdminUpdateQueueJumpSettings)
 	}
 }
 
@@ -112,8 +124,15 @@ func (h *Handler) AdminGetSettings(c *gin.Context) {
 		return
 	}
 
+	siteID, err := middleware.SiteIDFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	req := &models.AdminGetSettingsRequest{
 		ServiceType: serviceType,
+		SiteID:      siteID,
 	}
 
 	resp, err := h.service.GetSettings(c.Request.Context(), req)
@@ -341,3 +360,187 @@ func (h *Handler) AdminUpdateCooldownTimeout(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// AdminGetOvertimeSettings получает настройки оплаты простоя в боксе (админка)
+func (h *Handler) AdminGetOvertimeSettings(c *gin.Context) {
+	settings, err := h.service.GetOvertimeSettings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// AdminUpdateOvertimeSettings обновляет настройки оплаты простоя в боксе (админка)
+func (h *Handler) AdminUpdateOvertimeSettings(c *gin.Context) {
+	var req models.OvertimeSettings
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.service.UpdateOvertimeSettings(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := &models.AdminUpdateOvertimeSettingsResponse{
+		Success: true,
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminGetBoxAssignmentStrategy получает стратегию выбора бокса для типа услуги (админка)
+func (h *Handler) AdminGetBoxAssignmentStrategy(c *gin.Context) {
+	var req models.AdminGetBoxAssignmentStrategyRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	strategy, err := h.service.GetBoxAssignmentStrategy(c.Request.Context(), req.ServiceType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := &models.AdminGetBoxAssignmentStrategyResponse{
+		ServiceType: req.ServiceType,
+		Strategy:    strategy,
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminUpdateBoxAssignmentStrategy обновляет стратегию выбора бокса для типа услуги (админка)
+func (h *Handler) AdminUpdateBoxAssignmentStrategy(c *gin.Context) {
+	var req models.AdminUpdateBoxAssignmentStrategyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.service.UpdateBoxAssignmentStrategy(c.Request.Context(), req.ServiceType, req.Strategy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := &models.AdminUpdateBoxAssignmentStrategyResponse{
+		Success: true,
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminGetQueuePrioritySettings получает настройки взвешенной очереди (админка)
+func (h *Handler) AdminGetQueuePrioritySettings(c *gin.Context) {
+	settings, err := h.service.GetQueuePrioritySettings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// AdminUpdateQueuePrioritySettings обновляет настройки взвешенной очереди (админка)
+func (h *Handler) AdminUpdateQueuePrioritySettings(c *gin.Context) {
+	var req models.QueuePrioritySettings
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for class, minutes := range req.BoostMinutes {
+		if minutes < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("фора класса '%s' не может быть отрицательной", class)})
+			return
+		}
+	}
+
+	err := h.service.UpdateQueuePrioritySettings(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := &models.AdminUpdateQueuePrioritySettingsResponse{
+		Success: true,
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminGetQueueSnoozeSettings получает настройки отсрочки назначенного бокса (админка)
+func (h *Handler) AdminGetQueueSnoozeSettings(c *gin.Context) {
+	settings, err := h.service.GetQueueSnoozeSettings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// AdminUpdateQueueSnoozeSettings обновляет настройки отсрочки назначенного бокса (админка)
+func (h *Handler) AdminUpdateQueueSnoozeSettings(c *gin.Context) {
+	var req models.QueueSnoozeSettings
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.service.UpdateQueueSnoozeSettings(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := &models.AdminUpdateQueueSnoozeSettingsResponse{
+		Success: true,
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminGetQueueJumpSettings получает настройки платного пропуска очереди (админка)
+func (h *Handler) AdminGetQueueJumpSettings(c *gin.Context) {
+	settings, err := h.service.GetQueueJumpSettings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// AdminUpdateQueueJumpSettings обновляет настройки платного пропуска очереди (админка)
+func (h *Handler) AdminUpdateQueueJumpSettings(c *gin.Context) {
+	var req models.QueueJumpSettings
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.service.UpdateQueueJumpSettings(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := &models.AdminUpdateQueueJumpSettingsResponse{
+		Success: true,
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -19,6 +19,23 @@ type ServiceSetting struct {
 	DeletedAt    gorm.DeletedAt  `json:"-" gorm:"index"`
 }
 
+// SiteServiceSetting представляет значение настройки услуги, переопределенное для площадки
+// Если для площадки значения нет, используется общая настройка ServiceSetting
+type SiteServiceSetting struct {
+	ID           uuid.UUID       `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SiteID       uuid.UUID       `json:"site_id" gorm:"type:uuid;not null"`
+	ServiceType  string          `json:"service_type" gorm:"not null"`
+	SettingKey   string          `json:"setting_key" gorm:"not null"`
+	SettingValue json.RawMessage `json:"setting_value" gorm:"type:jsonb;not null"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (SiteServiceSetting) TableName() string {
+	return "site_service_settings"
+}
+
 // GetAvailableRentalTimesRequest представляет запрос на получение доступного времени мойки
 type GetAvailableRentalTimesRequest struct {
 	ServiceType string `json:"service_type" binding:"required"`
@@ -42,22 +59,25 @@ type UpdateAvailableRentalTimesResponse struct {
 
 // AdminGetSettingsRequest запрос на получение всех настроек сервиса (админка)
 type AdminGetSettingsRequest struct {
-	ServiceType string `json:"service_type" binding:"required"`
+	ServiceType string     `json:"service_type" binding:"required"`
+	SiteID      *uuid.UUID `json:"site_id"` // Цены площадки (без площадки - общие цены)
 }
 
 // AdminGetSettingsResponse ответ на получение всех настроек сервиса (админка)
 type AdminGetSettingsResponse struct {
-	ServiceType             string `json:"service_type"`
-	PricePerMinute          int    `json:"price_per_minute"`
-	ChemistryPricePerMinute int    `json:"chemistry_price_per_minute"`
-	AvailableRentalTimes    []int  `json:"available_rental_times"`
+	ServiceType             string     `json:"service_type"`
+	SiteID                  *uuid.UUID `json:"site_id,omitempty"`
+	PricePerMinute          int        `json:"price_per_minute"`
+	ChemistryPricePerMinute int        `json:"chemistry_price_per_minute"`
+	AvailableRentalTimes    []int      `json:"available_rental_times"`
 }
 
 // AdminUpdatePricesRequest запрос на обновление цен (админка)
 type AdminUpdatePricesRequest struct {
-	ServiceType             string `json:"service_type" binding:"required"`
-	PricePerMinute          int    `json:"price_per_minute" binding:"required"`
-	ChemistryPricePerMinute *int   `json:"chemistry_price_per_minute"`
+	ServiceType             string     `json:"service_type" binding:"required"`
+	PricePerMinute          int        `json:"price_per_minute" binding:"required"`
+	ChemistryPricePerMinute *int       `json:"chemistry_price_per_minute"`
+	SiteID                  *uuid.UUID `json:"site_id"` // Цены площадки (без площадки - общие цены)
 }
 
 // AdminUpdatePricesResponse ответ на обновление цен (админка)
@@ -164,3 +184,70 @@ type AdminUpdateCooldownTimeoutRequest struct {
 type AdminUpdateCooldownTimeoutResponse struct {
 	Success bool `json:"success"`
 }
+
+// OvertimeSettings настройки оплаты простоя в боксе после завершения сессии
+type OvertimeSettings struct {
+	PricePerMinute int `json:"price_per_minute" binding:"min=0"`     // Цена минуты простоя в копейках (0 - простой не тарифицируется)
+	GraceMinutes   int `json:"grace_minutes" binding:"min=0,max=60"` // Бесплатные минуты после завершения сессии
+	MaxMinutes     int `json:"max_minutes" binding:"required,min=1"` // Простой дольше этого считается другим визитом и не тарифицируется
+}
+
+// AdminUpdateOvertimeSettingsResponse ответ на обновление настроек оплаты простоя (админка)
+type AdminUpdateOvertimeSettingsResponse struct {
+	Success bool `json:"success"`
+}
+
+// AdminGetBoxAssignmentStrategyRequest запрос на получение стратегии выбора бокса (админка)
+type AdminGetBoxAssignmentStrategyRequest struct {
+	ServiceType string `json:"service_type" form:"service_type" binding:"required,oneof=wash air_dry vacuum"`
+}
+
+// AdminGetBoxAssignmentStrategyResponse ответ на получение стратегии выбора бокса (админка)
+type AdminGetBoxAssignmentStrategyResponse struct {
+	ServiceType string `json:"service_type"`
+	Strategy    string `json:"strategy"`
+}
+
+// AdminUpdateBoxAssignmentStrategyRequest запрос на обновление стратегии выбора бокса (админка)
+type AdminUpdateBoxAssignmentStrategyRequest struct {
+	ServiceType string `json:"service_type" binding:"required,oneof=wash air_dry vacuum"`
+	Strategy    string `json:"strategy" binding:"required,oneof=priority_letter least_used_today round_robin nearest_to_entrance"`
+}
+
+// AdminUpdateBoxAssignmentStrategyResponse ответ на обновление стратегии выбора бокса (админка)
+type AdminUpdateBoxAssignmentStrategyResponse struct {
+	Success bool `json:"success"`
+}
+
+// QueuePrioritySettings настройки взвешенной очереди по классам приоритета
+type QueuePrioritySettings struct {
+	BoostMinutes   map[string]int `json:"boost_minutes" binding:"required"` // Фора класса в минутах ожидания (normal, subscriber, fleet, skip_line, vip)
+	MaxWaitMinutes int            `json:"max_wait_minutes" binding:"min=0"` // Сессия, ожидающая дольше, обслуживается вне очереди приоритетов; 0 - ограничение отключено
+}
+
+// AdminUpdateQueuePrioritySettingsResponse ответ на обновление настроек взвешенной очереди (админка)
+type AdminUpdateQueuePrioritySettingsResponse struct {
+	Success bool `json:"success"`
+}
+
+// QueueSnoozeSettings настройки отсрочки назначенного бокса
+type QueueSnoozeSettings struct {
+	MaxSnoozes      int `json:"max_snoozes" binding:"min=0,max=5"`       // Сколько раз можно отложить бокс за сессию (0 - отсрочка выключена)
+	PositionPenalty int `json:"position_penalty" binding:"min=0,max=20"` // Сколько сессий очереди обслуживается перед отложившим бокс
+}
+
+// AdminUpdateQueueSnoozeSettingsResponse ответ на обновление настроек отсрочки бокса (админка)
+type AdminUpdateQueueSnoozeSettingsResponse struct {
+	Success bool `json:"success"`
+}
+
+// QueueJumpSettings настройки платного пропуска очереди
+type QueueJumpSettings struct {
+	Price      int `json:"price" binding:"min=0"`                // Доплата за пропуск очереди в копейках (0 - пропуск выключен)
+	MaxPerHour int `json:"max_per_hour" binding:"min=0,max=100"` // Сколько пропусков очереди разрешено за час (0 - пропуск выключен)
+}
+
+// AdminUpdateQueueJumpSettingsResponse ответ на обновление настроек пропуска очереди (админка)
+type AdminUpdateQueueJumpSettingsResponse struct {
+	Success bool `json:"success"`
+}
@@ -8,12 +8,15 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repository интерфейс для работы с настройками в базе данных
 type Repository interface {
 	GetServiceSetting(ctx context.Context, serviceType, settingKey string) (*models.ServiceSetting, error)
 	UpdateServiceSetting(ctx context.Context, serviceType, settingKey string, settingValue interface{}) error
+	GetSiteServiceSetting(ctx context.Context, siteID *uuid.UUID, serviceType, settingKey string) (*models.ServiceSetting, error)
+	UpdateSiteServiceSetting(ctx context.Context, siteID uuid.UUID, serviceType, settingKey string, settingValue interface{}) error
 	GetAvailableRentalTimes(ctx context.Context, serviceType string) ([]int, error)
 	UpdateAvailableRentalTimes(ctx context.Context, serviceType string, times []int) error
 }
@@ -75,6 +78,54 @@ func (r *RepositoryImpl) UpdateServiceSetting(ctx context.Context, serviceType,
 	return r.db.WithContext(ctx).Save(setting).Error
 }
 
+// GetSiteServiceSetting получает настройку услуги с учетом площадки
+// Если площадка не указана или для нее нет своего значения, возвращается общая настройка
+func (r *RepositoryImpl) GetSiteServiceSetting(ctx context.Context, siteID *uuid.UUID, serviceType, settingKey string) (*models.ServiceSetting, error) {
+	if siteID == nil || *siteID == uuid.Nil {
+		return r.GetServiceSetting(ctx, serviceType, settingKey)
+	}
+
+	var siteSetting models.SiteServiceSetting
+	err := r.db.WithContext(ctx).
+		Where("site_id = ? AND service_type = ? AND setting_key = ?", *siteID, serviceType, settingKey).
+		First(&siteSetting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return r.GetServiceSetting(ctx, serviceType, settingKey)
+		}
+		return nil, err
+	}
+
+	return &models.ServiceSetting{
+		ID:           siteSetting.ID,
+		ServiceType:  siteSetting.ServiceType,
+		SettingKey:   siteSetting.SettingKey,
+		SettingValue: siteSetting.SettingValue,
+		CreatedAt:    siteSetting.CreatedAt,
+		UpdatedAt:    siteSetting.UpdatedAt,
+	}, nil
+}
+
+// UpdateSiteServiceSetting сохраняет значение настройки услуги для площадки
+func (r *RepositoryImpl) UpdateSiteServiceSetting(ctx context.Context, siteID uuid.UUID, serviceType, settingKey string, settingValue interface{}) error {
+	jsonValue, err := json.Marshal(settingValue)
+	if err != nil {
+		return err
+	}
+
+	setting := models.SiteServiceSetting{
+		SiteID:       siteID,
+		ServiceType:  serviceType,
+		SettingKey:   settingKey,
+		SettingValue: jsonValue,
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "site_id"}, {Name: "service_type"}, {Name: "setting_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"setting_value", "updated_at"}),
+	}).Create(&setting).Error
+}
+
 // GetAvailableRentalTimes получает доступное время мойки для определенного типа услуги
 func (r *RepositoryImpl) GetAvailableRentalTimes(ctx context.Context, serviceType string) ([]int, error) {
 	setting, err := r.GetServiceSetting(ctx, serviceType, "available_rental_times")
@@ -5,6 +5,8 @@ import (
 	"carwash_backend/internal/domain/settings/repository"
 	"context"
 	"encoding/json"
+
+	"github.com/google/uuid"
 )
 
 // Service интерфейс для бизнес-логики настроек
@@ -31,6 +33,22 @@ type Service interface {
 	// Методы для управления временем блокировки бокса после завершения сессии
 	GetCooldownTimeout(ctx context.Context) (int, error)
 	UpdateCooldownTimeout(ctx context.Context, timeoutMinutes int) error
+
+	// Методы для управления оплатой простоя в боксе после завершения сессии
+	GetOvertimeSettings(ctx context.Context) (*models.OvertimeSettings, error)
+	UpdateOvertimeSettings(ctx context.Context, settings *models.OvertimeSettings) error
+
+	// Методы для управления стратегией выбора бокса
+	GetBoxAssignmentStrategy(ctx context.Context, serviceType string) (string, error)
+	UpdateBoxAssignmentStrategy(ctx context.Context, serviceType string, strategy string) error
+
+	// Методы для управления взвешенной очередью по классам приоритета
+	GetQueuePrioritySettings(ctx context.Context) (*models.QueuePrioritySettings, error)
+	UpdateQueuePrioritySettings(ctx context.Context, settings *models.QueuePrioritySettings) error
+	GetQueueSnoozeSettings(ctx context.Context) (*models.QueueSnoozeSettings, error)
+	UpdateQueueSnoozeSettings(ctx context.Context, settings *models.QueueSnoozeSettings) error
+	GetQueueJumpSettings(ctx context.Context) (*models.QueueJumpSettings, error)
+	UpdateQueueJumpSettings(ctx context.Context, settings *models.QueueJumpSettings) error
 }
 
 // ServiceImpl реализация Service
@@ -71,8 +89,8 @@ func (s *ServiceImpl) UpdateAvailableRentalTimes(ctx context.Context, req *model
 
 // GetSettings получает все настройки сервиса (админка)
 func (s *ServiceImpl) GetSettings(ctx context.Context, req *models.AdminGetSettingsRequest) (*models.AdminGetSettingsResponse, error) {
-	// Получаем цену за минуту
-	pricePerMinuteSetting, err := s.repo.GetServiceSetting(ctx, req.ServiceType, "price_per_minute")
+	// Получаем цену за минуту (для площадки - с учетом ее собственных цен)
+	pricePerMinuteSetting, err := s.repo.GetSiteServiceSetting(ctx, req.SiteID, req.ServiceType, "price_per_minute")
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +103,7 @@ func (s *ServiceImpl) GetSettings(ctx context.Context, req *models.AdminGetSetti
 	}
 
 	// Получаем цену химии за минуту
-	chemistryPricePerMinuteSetting, err := s.repo.GetServiceSetting(ctx, req.ServiceType, "chemistry_price_per_minute")
+	chemistryPricePerMinuteSetting, err := s.repo.GetSiteServiceSetting(ctx, req.SiteID, req.ServiceType, "chemistry_price_per_minute")
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +123,7 @@ func (s *ServiceImpl) GetSettings(ctx context.Context, req *models.AdminGetSetti
 
 	return &models.AdminGetSettingsResponse{
 		ServiceType:             req.ServiceType,
+		SiteID:                  req.SiteID,
 		PricePerMinute:          pricePerMinute,
 		ChemistryPricePerMinute: chemistryPricePerMinute,
 		AvailableRentalTimes:    availableTimes,
@@ -183,16 +202,194 @@ func (s *ServiceImpl) UpdateCooldownTimeout(ctx context.Context, timeoutMinutes
 	return s.repo.UpdateServiceSetting(ctx, "session", "cooldown_timeout_minutes", timeoutMinutes)
 }
 
+// GetOvertimeSettings получает настройки оплаты простоя в боксе после завершения сессии
+func (s *ServiceImpl) GetOvertimeSettings(ctx context.Context) (*models.OvertimeSettings, error) {
+	settings := &models.OvertimeSettings{
+		PricePerMinute: 0,   // По умолчанию простой не тарифицируется
+		GraceMinutes:   5,   // По умолчанию 5 минут бесплатно
+		MaxMinutes:     180, // По умолчанию простой больше 3 часов считается другим визитом
+	}
+
+	keys := map[string]*int{
+		"overtime_price_per_minute": &settings.PricePerMinute,
+		"overtime_grace_minutes":    &settings.GraceMinutes,
+		"overtime_max_minutes":      &settings.MaxMinutes,
+	}
+
+	for key, target := range keys {
+		setting, err := s.repo.GetServiceSetting(ctx, "session", key)
+		if err != nil {
+			return settings, err
+		}
+		if setting == nil {
+			continue
+		}
+
+		var value int
+		if err := json.Unmarshal(setting.SettingValue, &value); err != nil {
+			return settings, err
+		}
+		*target = value
+	}
+
+	return settings, nil
+}
+
+// UpdateOvertimeSettings обновляет настройки оплаты простоя в боксе после завершения сессии
+func (s *ServiceImpl) UpdateOvertimeSettings(ctx context.Context, settings *models.OvertimeSettings) error {
+	if err := s.repo.UpdateServiceSetting(ctx, "session", "overtime_price_per_minute", settings.PricePerMinute); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateServiceSetting(ctx, "session", "overtime_grace_minutes", settings.GraceMinutes); err != nil {
+		return err
+	}
+	return s.repo.UpdateServiceSetting(ctx, "session", "overtime_max_minutes", settings.MaxMinutes)
+}
+
+// GetQueuePrioritySettings получает настройки взвешенной очереди по классам приоритета
+func (s *ServiceImpl) GetQueuePrioritySettings(ctx context.Context) (*models.QueuePrioritySettings, error) {
+	settings := &models.QueuePrioritySettings{
+		BoostMinutes: map[string]int{ // По умолчанию фора растет от обычного клиента к VIP
+			"normal":     0,
+			"subscriber": 5,
+			"fleet":      10,
+			"skip_line":  30,
+			"vip":        60,
+		},
+		MaxWaitMinutes: 30, // По умолчанию никто не ждет из-за приоритетных классов дольше 30 минут
+	}
+
+	setting, err := s.repo.GetServiceSetting(ctx, "session", "queue_priority_boost_minutes")
+	if err != nil {
+		return settings, err
+	}
+	if setting != nil {
+		var boost map[string]int
+		if err := json.Unmarshal(setting.SettingValue, &boost); err != nil {
+			return settings, err
+		}
+		for class, minutes := range boost {
+			settings.BoostMinutes[class] = minutes
+		}
+	}
+
+	setting, err = s.repo.GetServiceSetting(ctx, "session", "queue_max_wait_minutes")
+	if err != nil {
+		return settings, err
+	}
+	if setting != nil {
+		var maxWait int
+		if err := json.Unmarshal(setting.SettingValue, &maxWait); err != nil {
+			return settings, err
+		}
+		settings.MaxWaitMinutes = maxWait
+	}
+
+	return settings, nil
+}
+
+// UpdateQueuePrioritySettings обновляет настройки взвешенной очереди по классам приоритета
+func (s *ServiceImpl) UpdateQueuePrioritySettings(ctx context.Context, settings *models.QueuePrioritySettings) error {
+	if err := s.repo.UpdateServiceSetting(ctx, "session", "queue_priority_boost_minutes", settings.BoostMinutes); err != nil {
+		return err
+	}
+	return s.repo.UpdateServiceSetting(ctx, "session", "queue_max_wait_minutes", settings.MaxWaitMinutes)
+}
+
+// GetQueueSnoozeSettings получает настройки отсрочки назначенного бокса
+func (s *ServiceImpl) GetQueueSnoozeSettings(ctx context.Context) (*models.QueueSnoozeSettings, error) {
+	settings := &models.QueueSnoozeSettings{
+		MaxSnoozes:      2, // По умолчанию бокс можно отложить дважды
+		PositionPenalty: 2, // По умолчанию перед отложившим обслуживаются две сессии
+	}
+
+	setting, err := s.repo.GetServiceSetting(ctx, "session", "queue_snooze_max_count")
+	if err != nil {
+		return settings, err
+	}
+	if setting != nil {
+		if err := json.Unmarshal(setting.SettingValue, &settings.MaxSnoozes); err != nil {
+			return settings, err
+		}
+	}
+
+	setting, err = s.repo.GetServiceSetting(ctx, "session", "queue_snooze_position_penalty")
+	if err != nil {
+		return settings, err
+	}
+	if setting != nil {
+		if err := json.Unmarshal(setting.SettingValue, &settings.PositionPenalty); err != nil {
+			return settings, err
+		}
+	}
+
+	return settings, nil
+}
+
+// UpdateQueueSnoozeSettings обновляет настройки отсрочки назначенного бокса
+func (s *ServiceImpl) UpdateQueueSnoozeSettings(ctx context.Context, settings *models.QueueSnoozeSettings) error {
+	if err := s.repo.UpdateServiceSetting(ctx, "session", "queue_snooze_max_count", settings.MaxSnoozes); err != nil {
+		return err
+	}
+	return s.repo.UpdateServiceSetting(ctx, "session", "queue_snooze_position_penalty", settings.PositionPenalty)
+}
+
+// GetQueueJumpSettings получает настройки платного пропуска очереди
+func (s *ServiceImpl) GetQueueJumpSettings(ctx context.Context) (*models.QueueJumpSettings, error) {
+	settings := &models.QueueJumpSettings{
+		Price:      10000, // По умолчанию доплата 100 рублей
+		MaxPerHour: 3,     // По умолчанию не больше трех пропусков в час
+	}
+
+	setting, err := s.repo.GetServiceSetting(ctx, "session", "queue_jump_price")
+	if err != nil {
+		return settings, err
+	}
+	if setting != nil {
+		if err := json.Unmarshal(setting.SettingValue, &settings.Price); err != nil {
+			return settings, err
+		}
+	}
+
+	setting, err = s.repo.GetServiceSetting(ctx, "session", "queue_jump_max_per_hour")
+	if err != nil {
+		return settings, err
+	}
+	if setting != nil {
+		if err := json.Unmarshal(setting.SettingValue, &settings.MaxPerHour); err != nil {
+			return settings, err
+		}
+	}
+
+	return settings, nil
+}
+
+// UpdateQueueJumpSettings обновляет настройки платного пропуска очереди
+func (s *ServiceImpl) UpdateQueueJumpSettings(ctx context.Context, settings *models.QueueJumpSettings) error {
+	if err := s.repo.UpdateServiceSetting(ctx, "session", "queue_jump_price", settings.Price); err != nil {
+		return err
+	}
+	return s.repo.UpdateServiceSetting(ctx, "session", "queue_jump_max_per_hour", settings.MaxPerHour)
+}
+
 // UpdatePrices обновляет цены сервиса (админка)
+// С указанной площадкой цены сохраняются только для нее, иначе меняются общие цены
 func (s *ServiceImpl) UpdatePrices(ctx context.Context, req *models.AdminUpdatePricesRequest) (*models.AdminUpdatePricesResponse, error) {
+	updateSetting := func(key string, value int) error {
+		if req.SiteID != nil && *req.SiteID != uuid.Nil {
+			return s.repo.UpdateSiteServiceSetting(ctx, *req.SiteID, req.ServiceType, key, value)
+		}
+		return s.repo.UpdateServiceSetting(ctx, req.ServiceType, key, value)
+	}
+
 	// Обновляем цену за минуту
-	if err := s.repo.UpdateServiceSetting(ctx, req.ServiceType, "price_per_minute", req.PricePerMinute); err != nil {
+	if err := updateSetting("price_per_minute", req.PricePerMinute); err != nil {
 		return nil, err
 	}
 
 	// Обновляем цену химии за минуту
 	if req.ChemistryPricePerMinute != nil {
-		if err := s.repo.UpdateServiceSetting(ctx, req.ServiceType, "chemistry_price_per_minute", *req.ChemistryPricePerMinute); err != nil {
+		if err := updateSetting("chemistry_price_per_minute", *req.ChemistryPricePerMinute); err != nil {
 			return nil, err
 		}
 	}
@@ -276,3 +473,27 @@ func (s *ServiceImpl) AdminUpdateAvailableChemistryTimes(ctx context.Context, re
 		Message: "Доступное время химии успешно обновлено",
 	}, nil
 }
+
+// GetBoxAssignmentStrategy получает стратегию выбора бокса для типа услуги
+func (s *ServiceImpl) GetBoxAssignmentStrategy(ctx context.Context, serviceType string) (string, error) {
+	setting, err := s.repo.GetServiceSetting(ctx, serviceType, "box_assignment_strategy")
+	if err != nil {
+		return "priority_letter", err // По умолчанию по букве приоритета
+	}
+
+	if setting == nil {
+		return "priority_letter", nil // По умолчанию по букве приоритета
+	}
+
+	var strategy string
+	if err := json.Unmarshal(setting.SettingValue, &strategy); err != nil {
+		return "priority_letter", err // По умолчанию по букве приоритета
+	}
+
+	return strategy, nil
+}
+
+// UpdateBoxAssignmentStrategy обновляет стратегию выбора бокса для типа услуги
+func (s *ServiceImpl) UpdateBoxAssignmentStrategy(ctx context.Context, serviceType string, strategy string) error {
+	return s.repo.UpdateServiceSetting(ctx, serviceType, "box_assignment_strategy", strategy)
+}
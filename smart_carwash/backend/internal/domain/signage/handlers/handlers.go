@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"bytes"
+	"carwash_backend/internal/domain/signage/models"
+	"carwash_backend/internal/domain/signage/service"
+	"carwash_backend/internal/logger"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// feedRateLimit сколько запросов ленты разрешено с одного адреса за feedRateWindow
+	feedRateLimit = 30
+	// feedRateWindow окно ограничения частоты запросов ленты
+	feedRateWindow = time.Minute
+)
+
+// Handler структура для обработчиков HTTP запросов табло
+type Handler struct {
+	service service.Service
+	limiter *rateLimiter
+}
+
+// NewHandler создает новый экземпляр Handler
+func NewHandler(service service.Service) *Handler {
+	return &Handler{
+		service: service,
+		limiter: newRateLimiter(feedRateLimit, feedRateWindow),
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для табло
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup, adminMiddleware gin.HandlerFunc) {
+	// Публичные маршруты без авторизации, доступ по токену табло
+	// Ограничение частоты срабатывает до проверки токена, чтобы токены нельзя было перебирать
+	publicRoutes := router.Group("/signage", h.rateLimitMiddleware())
+	{
+		publicRoutes.GET("/feed", h.getFeed)
+		publicRoutes.GET("/display", h.getDisplayPage)
+	}
+
+	adminRoutes := router.Group("/admin/signage/displays", adminMiddleware)
+	{
+		adminRoutes.GET("", h.adminListDisplays)
+		adminRoutes.POST("", h.adminCreateDisplay)
+		adminRoutes.PUT("", h.adminUpdateDisplay)
+		adminRoutes.DELETE("", h.adminDeleteDisplay)
+	}
+}
+
+// rateLimitMiddleware ограничивает частоту запросов публичной ленты по адресу клиента
+// Токен в ключ не входит: иначе каждый новый токен получал бы свой лимит и перебор не ограничивался.
+// ClientIP учитывает X-Forwarded-For только от прокси из TRUSTED_PROXIES, без них это адрес соединения
+func (h *Handler) rateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.limiter.Allow(c.ClientIP()) {
+			logger.WithContext(c).Warnf("signage: превышен лимит запросов, ip: %s", c.ClientIP())
+			c.Header("Retry-After", strconv.Itoa(int(feedRateWindow.Seconds())))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "слишком много запросов"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// getFeed возвращает публичную ленту табло в JSON
+func (h *Handler) getFeed(c *gin.Context) {
+	var req models.GetFeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed, err := h.service.GetFeed(c.Request.Context(), req.Token)
+	if err != nil {
+		h.handleFeedError(c, "getFeed", err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, feed)
+}
+
+// getDisplayPage отдает HTML страницу табло с автообновлением
+func (h *Handler) getDisplayPage(c *gin.Context) {
+	var req models.GetFeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed, err := h.service.GetFeed(c.Request.Context(), req.Token)
+	if err != nil {
+		h.handleFeedError(c, "getDisplayPage", err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := signagePage.Execute(&buf, pageData{Feed: feed}); err != nil {
+		logger.WithContext(c).Errorf("getDisplayPage: ошибка формирования страницы: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// handleFeedError отвечает на ошибку получения ленты табло
+func (h *Handler) handleFeedError(c *gin.Context, method string, err error) {
+	if errors.Is(err, service.ErrInvalidToken) {
+		logger.WithContext(c).Warnf("%s: недействительный токен табло, ip: %s", method, c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	logger.WithContext(c).Errorf("%s: ошибка получения ленты табло: %v", method, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// adminListDisplays получает список табло (админка)
+func (h *Handler) adminListDisplays(c *gin.Context) {
+	resp, err := h.service.AdminListDisplays(c.Request.Context())
+	if err != nil {
+		logger.WithContext(c).Errorf("adminListDisplays: ошибка получения табло: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// adminCreateDisplay создает табло (админка)
+func (h *Handler) adminCreateDisplay(c *gin.Context) {
+	var req models.AdminCreateDisplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("adminCreateDisplay: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"name": req.Name,
+	})
+
+	display, err := h.service.AdminCreateDisplay(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminCreateDisplay: ошибка создания табло: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, display)
+}
+
+// adminUpdateDisplay обновляет табло (админка)
+func (h *Handler) adminUpdateDisplay(c *gin.Context) {
+	var req models.AdminUpdateDisplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("adminUpdateDisplay: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"display_id":   req.ID,
+		"rotate_token": req.RotateToken,
+	})
+
+	display, err := h.service.AdminUpdateDisplay(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminUpdateDisplay: ошибка обновления табло: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, display)
+}
+
+// adminDeleteDisplay удаляет табло (админка)
+func (h *Handler) adminDeleteDisplay(c *gin.Context) {
+	var req models.AdminDeleteDisplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("adminDeleteDisplay: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"display_id": req.ID,
+	})
+
+	resp, err := h.service.AdminDeleteDisplay(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminDeleteDisplay: ошибка удаления табло: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"carwash_backend/internal/domain/signage/models"
+	"html/template"
+)
+
+// serviceTypeLabels названия типов услуг на табло
+var serviceTypeLabels = map[string]string{
+	"wash":    "Мойка",
+	"air_dry": "Обдув",
+	"vacuum":  "Пылесос",
+}
+
+// boxStatusLabels названия статусов боксов на табло
+var boxStatusLabels = map[string]string{
+	"free":        "Свободен",
+	"reserved":    "Ожидает клиента",
+	"busy":        "Занят",
+	"maintenance": "Обслуживание",
+	"cleaning":    "Уборка",
+}
+
+// pageData данные для HTML страницы табло
+type pageData struct {
+	Feed *models.SignageFeed
+}
+
+// signagePage HTML страница табло с автообновлением
+var signagePage = template.Must(template.New("signage").Funcs(template.FuncMap{
+	"serviceType": func(value string) string {
+		if label, ok := serviceTypeLabels[value]; ok {
+			return label
+		}
+		return value
+	},
+	"boxStatus": func(value string) string {
+		if label, ok := boxStatusLabels[value]; ok {
+			return label
+		}
+		return value
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Feed.RefreshSeconds}}">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Автомойка</title>
+<style>
+body { margin: 0; padding: 24px; background: #101418; color: #f2f4f6; font-family: Arial, sans-serif; }
+h2 { margin: 0 0 16px; font-size: 32px; }
+section { margin-bottom: 32px; }
+.calls { display: flex; flex-wrap: wrap; gap: 16px; }
+.call { background: #1f8a4c; border-radius: 12px; padding: 16px 24px; font-size: 40px; font-weight: bold; }
+.queues { display: flex; gap: 16px; }
+.queue { flex: 1; background: #1c232b; border-radius: 12px; padding: 16px; font-size: 24px; }
+.queue b { font-size: 40px; }
+.boxes { display: grid; grid-template-columns: repeat(auto-fill, minmax(160px, 1fr)); gap: 12px; }
+.box { border-radius: 12px; padding: 12px; font-size: 20px; background: #1c232b; }
+.box .number { font-size: 36px; font-weight: bold; }
+.box.free { background: #1f8a4c; }
+.box.reserved { background: #b7791f; }
+.box.busy { background: #9b2c2c; }
+.box.maintenance, .box.cleaning { background: #4a5568; }
+.updated { color: #8a949e; font-size: 16px; }
+</style>
+</head>
+<body>
+<section>
+<h2>Проезжайте в бокс</h2>
+<div class="calls">
+{{range .Feed.Calls}}<div class="call">{{.CarNumber}} &rarr; бокс {{.BoxNumber}}</div>
+{{else}}<div class="updated">Нет вызванных автомобилей</div>
+{{end}}</div>
+</section>
+<section>
+<h2>Очередь</h2>
+<div class="queues">
+{{range .Feed.Queues}}<div class="queue">{{serviceType .ServiceType}}<br>
+в очереди: <b>{{.QueueSize}}</b>, свободно боксов: <b>{{.FreeBoxes}}</b><br>
+{{if .WaitTimeMinutes}}ожидание ~{{.WaitTimeMinutes}} мин{{else}}без ожидания{{end}}</div>
+{{end}}</div>
+</section>
+<section>
+<h2>Боксы</h2>
+<div class="boxes">
+{{range .Feed.Boxes}}<div class="box {{.Status}}"><div class="number">{{.Number}}</div>{{serviceType .ServiceType}}<br>{{boxStatus .Status}}</div>
+{{end}}</div>
+</section>
+<div class="updated">Обновлено {{.Feed.GeneratedAt.Format "15:04:05"}}</div>
+</body>
+</html>
+`))
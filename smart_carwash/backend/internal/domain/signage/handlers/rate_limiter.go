@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter ограничивает количество запросов по ключу в фиксированном окне времени
+type rateLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	window   time.Duration
+	counters map[string]*rateWindow
+	lastGC   time.Time
+}
+
+// rateWindow счетчик запросов в текущем окне
+type rateWindow struct {
+	startedAt time.Time
+	count     int
+}
+
+// newRateLimiter создает ограничитель: не более limit запросов за window
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:    limit,
+		window:   window,
+		counters: make(map[string]*rateWindow),
+		lastGC:   time.Now(),
+	}
+}
+
+// Allow проверяет, можно ли выполнить запрос по ключу, и учитывает его
+func (l *rateLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	// Периодически удаляем устаревшие окна, чтобы карта не росла от случайных ключей
+	if now.Sub(l.lastGC) >= l.window {
+		for k, w := range l.counters {
+			if now.Sub(w.startedAt) >= l.window {
+				delete(l.counters, k)
+			}
+		}
+		l.lastGC = now
+	}
+
+	w, ok := l.counters[key]
+	if !ok || now.Sub(w.startedAt) >= l.window {
+		l.counters[key] = &rateWindow{startedAt: now, count: 1}
+		return true
+	}
+
+	if w.count >= l.limit {
+		return false
+	}
+	w.count++
+	return true
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SignageDisplay табло у въезда с собственным токеном доступа
+type SignageDisplay struct {
+	ID         uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Name       string         `json:"name" gorm:"not null"`                   // Название экрана (например, "Въезд 1")
+	SiteID     uuid.UUID      `json:"site_id" gorm:"type:uuid;index"`         // Площадка, очередь которой показывает экран
+	Token      string         `json:"token" gorm:"not null;uniqueIndex"`      // Токен доступа к ленте
+	IsActive   bool           `json:"is_active" gorm:"not null;default:true"` // Отключенный экран не получает ленту
+	LastSeenAt *time.Time     `json:"last_seen_at,omitempty"`                 // Последнее обращение экрана
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (SignageDisplay) TableName() string {
+	return "signage_displays"
+}
+
+// SignageFeed публичная лента для табло
+type SignageFeed struct {
+	Boxes          []SignageBox   `json:"boxes"`
+	Calls          []SignageCall  `json:"calls"`  // Номера, вызванные в боксы
+	Queues         []SignageQueue `json:"queues"` // Очередь по типам услуг
+	RefreshSeconds int            `json:"refresh_seconds"`
+	GeneratedAt    time.Time      `json:"generated_at"`
+}
+
+// SignageBox состояние бокса на табло
+type SignageBox struct {
+	Number      int    `json:"number"`
+	ServiceType string `json:"service_type"`
+	Status      string `json:"status"`
+}
+
+// SignageCall вызов автомобиля в бокс
+type SignageCall struct {
+	BoxNumber   int       `json:"box_number"`
+	ServiceType string    `json:"service_type"`
+	CarNumber   string    `json:"car_number"` // Частично скрытый госномер
+	CalledAt    time.Time `json:"called_at"`
+}
+
+// SignageQueue очередь по типу услуги на табло
+type SignageQueue struct {
+	ServiceType     string `json:"service_type"`
+	QueueSize       int    `json:"queue_size"`
+	FreeBoxes       int    `json:"free_boxes"`
+	WaitTimeMinutes *int   `json:"wait_time_minutes,omitempty"` // Ожидаемое время ожидания для нового клиента
+}
+
+// GetFeedRequest запрос публичной ленты табло
+type GetFeedRequest struct {
+	Token string `form:"token" binding:"required"`
+}
+
+// AdminListDisplaysResponse ответ на получение списка табло (админка)
+type AdminListDisplaysResponse struct {
+	Displays []SignageDisplay `json:"displays"`
+}
+
+// AdminCreateDisplayRequest запрос на создание табло (админка)
+type AdminCreateDisplayRequest struct {
+	Name   string     `json:"name" binding:"required,max=100"`
+	SiteID *uuid.UUID `json:"site_id,omitempty"` // Площадка (по умолчанию основная)
+}
+
+// AdminUpdateDisplayRequest запрос на обновление табло (админка)
+type AdminUpdateDisplayRequest struct {
+	ID          uuid.UUID  `json:"id" binding:"required"`
+	Name        *string    `json:"name,omitempty" binding:"omitempty,max=100"`
+	IsActive    *bool      `json:"is_active,omitempty"`
+	SiteID      *uuid.UUID `json:"site_id,omitempty"`
+	RotateToken bool       `json:"rotate_token"` // Выпустить новый токен (старый перестанет работать)
+}
+
+// AdminDeleteDisplayRequest запрос на удаление табло (админка)
+type AdminDeleteDisplayRequest struct {
+	ID uuid.UUID `json:"id" binding:"required"`
+}
+
+// AdminDeleteDisplayResponse ответ на удаление табло (админка)
+type AdminDeleteDisplayResponse struct {
+	Success bool `json:"success"`
+}
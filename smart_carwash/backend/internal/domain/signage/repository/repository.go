@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"carwash_backend/internal/domain/signage/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository интерфейс для работы с табло в базе данных
+type Repository interface {
+	CreateDisplay(ctx context.Context, display *models.SignageDisplay) error
+	UpdateDisplay(ctx context.Context, display *models.SignageDisplay) error
+	DeleteDisplay(ctx context.Context, id uuid.UUID) error
+	GetDisplayByID(ctx context.Context, id uuid.UUID) (*models.SignageDisplay, error)
+	GetDisplayByToken(ctx context.Context, token string) (*models.SignageDisplay, error)
+	ListDisplays(ctx context.Context) ([]models.SignageDisplay, error)
+	TouchDisplay(ctx context.Context, id uuid.UUID, seenAt time.Time) error
+}
+
+// PostgresRepository реализация Repository для PostgreSQL
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository создает новый экземпляр PostgresRepository
+func NewPostgresRepository(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{
+		db: db,
+	}
+}
+
+// CreateDisplay создает табло
+func (r *PostgresRepository) CreateDisplay(ctx context.Context, display *models.SignageDisplay) error {
+	return r.db.WithContext(ctx).Create(display).Error
+}
+
+// UpdateDisplay обновляет табло
+func (r *PostgresRepository) UpdateDisplay(ctx context.Context, display *models.SignageDisplay) error {
+	return r.db.WithContext(ctx).Save(display).Error
+}
+
+// DeleteDisplay удаляет табло (мягкое удаление)
+func (r *PostgresRepository) DeleteDisplay(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.SignageDisplay{}, "id = ?", id).Error
+}
+
+// GetDisplayByID получает табло по ID
+func (r *PostgresRepository) GetDisplayByID(ctx context.Context, id uuid.UUID) (*models.SignageDisplay, error) {
+	var display models.SignageDisplay
+	err := r.db.WithContext(ctx).First(&display, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &display, nil
+}
+
+// GetDisplayByToken получает табло по токену
+func (r *PostgresRepository) GetDisplayByToken(ctx context.Context, token string) (*models.SignageDisplay, error) {
+	var display models.SignageDisplay
+	err := r.db.WithContext(ctx).Where("token = ?", token).First(&display).Error
+	if err != nil {
+		return nil, err
+	}
+	return &display, nil
+}
+
+// ListDisplays получает все табло
+func (r *PostgresRepository) ListDisplays(ctx context.Context) ([]models.SignageDisplay, error) {
+	var displays []models.SignageDisplay
+	err := r.db.WithContext(ctx).Order("created_at ASC").Find(&displays).Error
+	return displays, err
+}
+
+// TouchDisplay обновляет время последнего обращения табло
+func (r *PostgresRepository) TouchDisplay(ctx context.Context, id uuid.UUID, seenAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.SignageDisplay{}).
+		Where("id = ?", id).
+		Update("last_seen_at", seenAt).Error
+}
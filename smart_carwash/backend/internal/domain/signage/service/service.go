@@ -0,0 +1,290 @@
+package service
+
+import (
+	queueModels "carwash_backend/internal/domain/queue/models"
+	queueService "carwash_backend/internal/domain/queue/service"
+	sessionModels "carwash_backend/internal/domain/session/models"
+	sessionService "carwash_backend/internal/domain/session/service"
+	"carwash_backend/internal/domain/signage/models"
+	"carwash_backend/internal/domain/signage/repository"
+	siteModels "carwash_backend/internal/domain/site/models"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// RefreshSeconds период обновления табло
+	RefreshSeconds = 15
+	// touchInterval как часто обновлять время последнего обращения табло
+	touchInterval = time.Minute
+)
+
+// ErrInvalidToken токен табло не найден или табло отключено
+var ErrInvalidToken = errors.New("недействительный токен табло")
+
+// Service интерфейс для бизнес-логики табло
+type Service interface {
+	GetFeed(ctx context.Context, token string) (*models.SignageFeed, error)
+	AdminListDisplays(ctx context.Context) (*models.AdminListDisplaysResponse, error)
+	AdminCreateDisplay(ctx context.Context, req *models.AdminCreateDisplayRequest) (*models.SignageDisplay, error)
+	AdminUpdateDisplay(ctx context.Context, req *models.AdminUpdateDisplayRequest) (*models.SignageDisplay, error)
+	AdminDeleteDisplay(ctx context.Context, req *models.AdminDeleteDisplayRequest) (*models.AdminDeleteDisplayResponse, error)
+}
+
+// ServiceImpl реализация Service
+type ServiceImpl struct {
+	repo           repository.Repository
+	queueService   queueService.Service
+	sessionService sessionService.Service
+}
+
+// NewService создает новый экземпляр Service
+func NewService(repo repository.Repository, queueService queueService.Service, sessionService sessionService.Service) *ServiceImpl {
+	return &ServiceImpl{
+		repo:           repo,
+		queueService:   queueService,
+		sessionService: sessionService,
+	}
+}
+
+// GetFeed формирует публичную ленту для табло по токену экрана
+func (s *ServiceImpl) GetFeed(ctx context.Context, token string) (*models.SignageFeed, error) {
+	display, err := s.repo.GetDisplayByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("ошибка проверки токена табло: %w", err)
+	}
+	if !display.IsActive {
+		return nil, ErrInvalidToken
+	}
+
+	now := time.Now()
+	if display.LastSeenAt == nil || now.Sub(*display.LastSeenAt) >= touchInterval {
+		if err := s.repo.TouchDisplay(ctx, display.ID, now); err != nil {
+			logger.Printf("Signage - GetFeed: ошибка обновления времени обращения табло %s: %v", display.ID, err)
+		}
+	}
+
+	// Пользователи нужны для прогноза ожидания по позициям, сами данные пользователей на табло не попадают
+	status, err := s.queueService.GetQueueStatus(ctx, display.SiteID, true)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статуса очереди: %w", err)
+	}
+
+	feed := &models.SignageFeed{
+		Boxes:          make([]models.SignageBox, 0, len(status.AllBoxes)),
+		Calls:          []models.SignageCall{},
+		Queues:         make([]models.SignageQueue, 0, 3),
+		RefreshSeconds: RefreshSeconds,
+		GeneratedAt:    now,
+	}
+
+	boxesByID := make(map[uuid.UUID]washboxModels.WashBox, len(status.AllBoxes))
+	for _, box := range status.AllBoxes {
+		boxesByID[box.ID] = box
+		feed.Boxes = append(feed.Boxes, models.SignageBox{
+			Number:      box.Number,
+			ServiceType: box.ServiceType,
+			Status:      box.Status,
+		})
+	}
+	sort.Slice(feed.Boxes, func(i, j int) bool {
+		return feed.Boxes[i].Number < feed.Boxes[j].Number
+	})
+
+	for _, info := range []queueModels.ServiceQueueInfo{status.WashQueue, status.AirDryQueue, status.VacuumQueue} {
+		feed.Queues = append(feed.Queues, buildQueue(info))
+	}
+
+	// Вызванные в боксы автомобили - сессии с назначенным боксом, ожидающие старта
+	assigned, err := s.sessionService.GetSessionsByStatus(ctx, sessionModels.SessionStatusAssigned)
+	if err != nil {
+		logger.Printf("Signage - GetFeed: ошибка получения назначенных сессий: %v", err)
+		return feed, nil
+	}
+	for _, session := range assigned {
+		if session.BoxID == nil || session.CarNumber == "" {
+			continue
+		}
+		box, ok := boxesByID[*session.BoxID]
+		if !ok {
+			continue
+		}
+		calledAt := session.StatusUpdatedAt
+		if session.AssignedAt != nil {
+			calledAt = *session.AssignedAt
+		}
+		feed.Calls = append(feed.Calls, models.SignageCall{
+			BoxNumber:   box.Number,
+			ServiceType: session.ServiceType,
+			CarNumber:   maskCarNumber(session.CarNumber),
+			CalledAt:    calledAt,
+		})
+	}
+	sort.Slice(feed.Calls, func(i, j int) bool {
+		return feed.Calls[i].CalledAt.After(feed.Calls[j].CalledAt)
+	})
+
+	return feed, nil
+}
+
+// buildQueue формирует очередь по типу услуги для табло
+// Ожидание нового клиента - прогноз для последней позиции очереди, иначе время до освобождения ближайшего бокса
+func buildQueue(info queueModels.ServiceQueueInfo) models.SignageQueue {
+	queue := models.SignageQueue{
+		ServiceType:     info.ServiceType,
+		QueueSize:       info.QueueSize,
+		WaitTimeMinutes: info.WaitTimeMinutes,
+	}
+
+	for _, box := range info.Boxes {
+		if box.Status == washboxModels.StatusFree {
+			queue.FreeBoxes++
+		}
+	}
+
+	var maxETA *int
+	for _, user := range info.UsersInQueue {
+		if user.ETAMinutes != nil && (maxETA == nil || *user.ETAMinutes > *maxETA) {
+			eta := *user.ETAMinutes
+			maxETA = &eta
+		}
+	}
+	if maxETA != nil {
+		queue.WaitTimeMinutes = maxETA
+	}
+
+	return queue
+}
+
+// maskCarNumber частично скрывает госномер для публичного табло
+// Остается видна только первая группа цифр, по которой водитель узнает свой автомобиль: А123ВС77 -> *123****
+func maskCarNumber(carNumber string) string {
+	runes := []rune(carNumber)
+	masked := make([]rune, len(runes))
+
+	digitsShown := false
+	inDigits := false
+	for i, r := range runes {
+		if unicode.IsDigit(r) && !digitsShown {
+			masked[i] = r
+			inDigits = true
+			continue
+		}
+		if inDigits {
+			digitsShown = true
+			inDigits = false
+		}
+		masked[i] = '*'
+	}
+
+	return string(masked)
+}
+
+// generateToken генерирует случайный токен табло
+func generateToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("ошибка генерации токена: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// AdminListDisplays получает список табло (админка)
+func (s *ServiceImpl) AdminListDisplays(ctx context.Context) (*models.AdminListDisplaysResponse, error) {
+	displays, err := s.repo.ListDisplays(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения табло: %w", err)
+	}
+
+	return &models.AdminListDisplaysResponse{
+		Displays: displays,
+	}, nil
+}
+
+// AdminCreateDisplay создает табло с новым токеном (админка)
+func (s *ServiceImpl) AdminCreateDisplay(ctx context.Context, req *models.AdminCreateDisplayRequest) (*models.SignageDisplay, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	display := &models.SignageDisplay{
+		Name:     req.Name,
+		SiteID:   siteModels.DefaultSiteID,
+		Token:    token,
+		IsActive: true,
+	}
+	if req.SiteID != nil && *req.SiteID != uuid.Nil {
+		display.SiteID = *req.SiteID
+	}
+
+	if err := s.repo.CreateDisplay(ctx, display); err != nil {
+		return nil, fmt.Errorf("ошибка создания табло: %w", err)
+	}
+
+	logger.Printf("Signage - AdminCreateDisplay: создано табло %s, name: %s", display.ID, display.Name)
+
+	return display, nil
+}
+
+// AdminUpdateDisplay обновляет табло (админка)
+func (s *ServiceImpl) AdminUpdateDisplay(ctx context.Context, req *models.AdminUpdateDisplayRequest) (*models.SignageDisplay, error) {
+	display, err := s.repo.GetDisplayByID(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("табло не найдено: %w", err)
+	}
+
+	if req.Name != nil {
+		display.Name = *req.Name
+	}
+	if req.IsActive != nil {
+		display.IsActive = *req.IsActive
+	}
+	if req.SiteID != nil && *req.SiteID != uuid.Nil {
+		display.SiteID = *req.SiteID
+	}
+	if req.RotateToken {
+		token, err := generateToken()
+		if err != nil {
+			return nil, err
+		}
+		display.Token = token
+	}
+
+	if err := s.repo.UpdateDisplay(ctx, display); err != nil {
+		return nil, fmt.Errorf("ошибка обновления табло: %w", err)
+	}
+
+	logger.Printf("Signage - AdminUpdateDisplay: табло %s обновлено, is_active: %t, rotate_token: %t", display.ID, display.IsActive, req.RotateToken)
+
+	return display, nil
+}
+
+// AdminDeleteDisplay удаляет табло (админка)
+func (s *ServiceImpl) AdminDeleteDisplay(ctx context.Context, req *models.AdminDeleteDisplayRequest) (*models.AdminDeleteDisplayResponse, error) {
+	if _, err := s.repo.GetDisplayByID(ctx, req.ID); err != nil {
+		return nil, fmt.Errorf("табло не найдено: %w", err)
+	}
+
+	if err := s.repo.DeleteDisplay(ctx, req.ID); err != nil {
+		return nil, fmt.Errorf("ошибка удаления табло: %w", err)
+	}
+
+	return &models.AdminDeleteDisplayResponse{
+		Success: true,
+	}, nil
+}
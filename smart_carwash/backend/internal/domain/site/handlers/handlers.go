@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"carwash_backend/internal/domain/site/models"
+	"carwash_backend/internal/domain/site/service"
+	"carwash_backend/internal/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler структура для обработчиков HTTP запросов площадок
+type Handler struct {
+	service service.Service
+}
+
+// NewHandler создает новый экземпляр Handler
+func NewHandler(service service.Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для площадок
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup, adminMiddleware gin.HandlerFunc) {
+	// Публичный маршрут для выбора площадки в мини-приложении
+	router.GET("/sites", h.listSites)
+
+	adminRoutes := router.Group("/admin/sites", adminMiddleware)
+	{
+		adminRoutes.GET("", h.adminListSites)
+		adminRoutes.POST("", h.adminCreateSite)
+		adminRoutes.PUT("", h.adminUpdateSite)
+		adminRoutes.DELETE("", h.adminDeleteSite)
+	}
+}
+
+// listSites возвращает активные площадки (публичный)
+func (h *Handler) listSites(c *gin.Context) {
+	resp, err := h.service.ListSites(c.Request.Context())
+	if err != nil {
+		logger.WithContext(c).Errorf("listSites: ошибка получения площадок: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// adminListSites возвращает все площадки (админка)
+func (h *Handler) adminListSites(c *gin.Context) {
+	resp, err := h.service.AdminListSites(c.Request.Context())
+	if err != nil {
+		logger.WithContext(c).Errorf("adminListSites: ошибка получения площадок: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// adminCreateSite создает площадку (админка)
+func (h *Handler) adminCreateSite(c *gin.Context) {
+	var req models.AdminCreateSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("adminCreateSite: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"name": req.Name,
+		"code": req.Code,
+	})
+
+	site, err := h.service.AdminCreateSite(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminCreateSite: ошибка создания площадки: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, site)
+}
+
+// adminUpdateSite обновляет площадку (админка)
+func (h *Handler) adminUpdateSite(c *gin.Context) {
+	var req models.AdminUpdateSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("adminUpdateSite: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"site_id":   req.ID,
+		"is_active": req.IsActive,
+	})
+
+	site, err := h.service.AdminUpdateSite(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminUpdateSite: ошибка обновления площадки: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, site)
+}
+
+// adminDeleteSite удаляет площадку (админка)
+func (h *Handler) adminDeleteSite(c *gin.Context) {
+	var req models.AdminDeleteSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("adminDeleteSite: ошибка парсинга запроса: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set("meta", gin.H{
+		"site_id": req.ID,
+	})
+
+	resp, err := h.service.AdminDeleteSite(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("adminDeleteSite: ошибка удаления площадки: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultSiteID площадка по умолчанию
+// К ней относятся данные, созданные до появления нескольких площадок, и запросы без указания площадки
+var DefaultSiteID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Site площадка (отдельная мойка), обслуживаемая общим бэкендом
+type Site struct {
+	ID            uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Name          string         `json:"name" gorm:"not null"`
+	Code          string         `json:"code" gorm:"not null"` // Короткий код площадки (например, "main")
+	Address       *string        `json:"address,omitempty"`
+	IsActive      bool           `json:"is_active" gorm:"not null;default:true"` // Неактивная площадка не показывается клиентам
+	APIKey1C      *string        `json:"-" gorm:"column:api_key_1c"`             // API ключ, по которому 1C площадки проходит авторизацию
+	DahuaUsername *string        `json:"dahua_username,omitempty"`               // Логин камер Dahua площадки (Basic Auth)
+	DahuaPassword *string        `json:"-"`
+	Has1CKey      bool           `json:"has_1c_key" gorm:"-"`     // Задан ли API ключ 1C (сам ключ не отдается)
+	HasDahuaAuth  bool           `json:"has_dahua_auth" gorm:"-"` // Заданы ли учетные данные Dahua
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (Site) TableName() string {
+	return "sites"
+}
+
+// ListSitesResponse ответ на получение списка площадок
+type ListSitesResponse struct {
+	Sites []Site `json:"sites"`
+}
+
+// AdminCreateSiteRequest запрос на создание площадки (админка)
+type AdminCreateSiteRequest struct {
+	Name          string  `json:"name" binding:"required,max=100"`
+	Code          string  `json:"code" binding:"required,max=50"`
+	Address       *string `json:"address,omitempty" binding:"omitempty,max=255"`
+	APIKey1C      *string `json:"api_key_1c,omitempty" binding:"omitempty,max=255"`
+	DahuaUsername *string `json:"dahua_username,omitempty" binding:"omitempty,max=100"`
+	DahuaPassword *string `json:"dahua_password,omitempty" binding:"omitempty,max=255"`
+}
+
+// AdminUpdateSiteRequest запрос на обновление площадки (админка)
+// Пустая строка в api_key_1c, dahua_username или dahua_password сбрасывает значение
+type AdminUpdateSiteRequest struct {
+	ID            uuid.UUID `json:"id" binding:"required"`
+	Name          *string   `json:"name,omitempty" binding:"omitempty,max=100"`
+	Code          *string   `json:"code,omitempty" binding:"omitempty,max=50"`
+	Address       *string   `json:"address,omitempty" binding:"omitempty,max=255"`
+	IsActive      *bool     `json:"is_active,omitempty"`
+	APIKey1C      *string   `json:"api_key_1c,omitempty" binding:"omitempty,max=255"`
+	DahuaUsername *string   `json:"dahua_username,omitempty" binding:"omitempty,max=100"`
+	DahuaPassword *string   `json:"dahua_password,omitempty" binding:"omitempty,max=255"`
+}
+
+// AdminDeleteSiteRequest запрос на удаление площадки (админка)
+type AdminDeleteSiteRequest struct {
+	ID uuid.UUID `json:"id" binding:"required"`
+}
+
+// AdminDeleteSiteResponse ответ на удаление площадки (админка)
+type AdminDeleteSiteResponse struct {
+	Success bool `json:"success"`
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"carwash_backend/internal/domain/site/models"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository интерфейс для работы с площадками в базе данных
+type Repository interface {
+	CreateSite(ctx context.Context, site *models.Site) error
+	UpdateSite(ctx context.Context, site *models.Site) error
+	DeleteSite(ctx context.Context, id uuid.UUID) error
+	GetSiteByID(ctx context.Context, id uuid.UUID) (*models.Site, error)
+	GetSiteBy1CKey(ctx context.Context, apiKey string) (*models.Site, error)
+	GetSiteByDahuaUsername(ctx context.Context, username string) (*models.Site, error)
+	ListSites(ctx context.Context, activeOnly bool) ([]models.Site, error)
+	CountSiteBoxes(ctx context.Context, id uuid.UUID) (int64, error)
+}
+
+// PostgresRepository реализация Repository для PostgreSQL
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository создает новый экземпляр PostgresRepository
+func NewPostgresRepository(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{
+		db: db,
+	}
+}
+
+// CreateSite создает площадку
+func (r *PostgresRepository) CreateSite(ctx context.Context, site *models.Site) error {
+	return r.db.WithContext(ctx).Create(site).Error
+}
+
+// UpdateSite обновляет площадку
+func (r *PostgresRepository) UpdateSite(ctx context.Context, site *models.Site) error {
+	return r.db.WithContext(ctx).Save(site).Error
+}
+
+// DeleteSite удаляет площадку (мягкое удаление)
+func (r *PostgresRepository) DeleteSite(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Site{}, "id = ?", id).Error
+}
+
+// GetSiteByID получает площадку по ID
+func (r *PostgresRepository) GetSiteByID(ctx context.Context, id uuid.UUID) (*models.Site, error) {
+	var site models.Site
+	err := r.db.WithContext(ctx).First(&site, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// GetSiteBy1CKey получает активную площадку по API ключу 1C
+func (r *PostgresRepository) GetSiteBy1CKey(ctx context.Context, apiKey string) (*models.Site, error) {
+	var site models.Site
+	err := r.db.WithContext(ctx).Where("api_key_1c = ? AND is_active = ?", apiKey, true).First(&site).Error
+	if err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// GetSiteByDahuaUsername получает активную площадку по логину камер Dahua
+func (r *PostgresRepository) GetSiteByDahuaUsername(ctx context.Context, username string) (*models.Site, error) {
+	var site models.Site
+	err := r.db.WithContext(ctx).Where("dahua_username = ? AND is_active = ?", username, true).First(&site).Error
+	if err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// ListSites получает площадки, при activeOnly - только активные
+func (r *PostgresRepository) ListSites(ctx context.Context, activeOnly bool) ([]models.Site, error) {
+	var sites []models.Site
+	query := r.db.WithContext(ctx).Order("created_at ASC")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Find(&sites).Error
+	return sites, err
+}
+
+// CountSiteBoxes возвращает количество неудаленных боксов площадки
+func (r *PostgresRepository) CountSiteBoxes(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("wash_boxes").
+		Where("site_id = ? AND deleted_at IS NULL", id).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"carwash_backend/internal/domain/site/models"
+	"carwash_backend/internal/domain/site/repository"
+	"carwash_backend/internal/logger"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSiteNotFound площадка не найдена
+	ErrSiteNotFound = errors.New("площадка не найдена")
+	// ErrSiteInactive площадка отключена
+	ErrSiteInactive = errors.New("площадка отключена")
+	// ErrInvalidCredentials учетные данные не подходят ни к одной площадке
+	ErrInvalidCredentials = errors.New("неверные учетные данные площадки")
+)
+
+// Service интерфейс для бизнес-логики площадок
+type Service interface {
+	ListSites(ctx context.Context) (*models.ListSitesResponse, error)
+	GetSiteByID(ctx context.Context, id uuid.UUID) (*models.Site, error)
+	ResolveSiteID(ctx context.Context, siteID *uuid.UUID) (uuid.UUID, error)
+	AuthenticateBy1CKey(ctx context.Context, apiKey string) (*models.Site, error)
+	AuthenticateDahua(ctx context.Context, username, password string) (*models.Site, error)
+
+	// Административные методы
+	AdminListSites(ctx context.Context) (*models.ListSitesResponse, error)
+	AdminCreateSite(ctx context.Context, req *models.AdminCreateSiteRequest) (*models.Site, error)
+	AdminUpdateSite(ctx context.Context, req *models.AdminUpdateSiteRequest) (*models.Site, error)
+	AdminDeleteSite(ctx context.Context, req *models.AdminDeleteSiteRequest) (*models.AdminDeleteSiteResponse, error)
+}
+
+// ServiceImpl реализация Service
+type ServiceImpl struct {
+	repo repository.Repository
+}
+
+// NewService создает новый экземпляр Service
+func NewService(repo repository.Repository) *ServiceImpl {
+	return &ServiceImpl{
+		repo: repo,
+	}
+}
+
+// ListSites получает активные площадки для выбора в мини-приложении
+func (s *ServiceImpl) ListSites(ctx context.Context) (*models.ListSitesResponse, error) {
+	sites, err := s.repo.ListSites(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения площадок: %w", err)
+	}
+
+	// Клиентам логин камер не нужен
+	for i := range sites {
+		sites[i].DahuaUsername = nil
+	}
+
+	return &models.ListSitesResponse{Sites: sites}, nil
+}
+
+// GetSiteByID получает площадку по ID
+func (s *ServiceImpl) GetSiteByID(ctx context.Context, id uuid.UUID) (*models.Site, error) {
+	site, err := s.repo.GetSiteByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSiteNotFound
+		}
+		return nil, fmt.Errorf("ошибка получения площадки: %w", err)
+	}
+	return site, nil
+}
+
+// ResolveSiteID проверяет площадку из запроса клиента
+// Если площадка не указана, используется площадка по умолчанию
+func (s *ServiceImpl) ResolveSiteID(ctx context.Context, siteID *uuid.UUID) (uuid.UUID, error) {
+	if siteID == nil || *siteID == uuid.Nil {
+		return models.DefaultSiteID, nil
+	}
+
+	site, err := s.GetSiteByID(ctx, *siteID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !site.IsActive {
+		return uuid.Nil, ErrSiteInactive
+	}
+
+	return site.ID, nil
+}
+
+// AuthenticateBy1CKey определяет площадку по API ключу 1C
+func (s *ServiceImpl) AuthenticateBy1CKey(ctx context.Context, apiKey string) (*models.Site, error) {
+	if apiKey == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	site, err := s.repo.GetSiteBy1CKey(ctx, apiKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("ошибка поиска площадки по ключу 1C: %w", err)
+	}
+
+	return site, nil
+}
+
+// AuthenticateDahua определяет площадку по учетным данным камер Dahua
+func (s *ServiceImpl) AuthenticateDahua(ctx context.Context, username, password string) (*models.Site, error) {
+	if username == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	site, err := s.repo.GetSiteByDahuaUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("ошибка поиска площадки по логину Dahua: %w", err)
+	}
+
+	if site.DahuaPassword == nil || subtle.ConstantTimeCompare([]byte(*site.DahuaPassword), []byte(password)) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+
+	return site, nil
+}
+
+// AdminListSites получает все площадки (админка)
+func (s *ServiceImpl) AdminListSites(ctx context.Context) (*models.ListSitesResponse, error) {
+	sites, err := s.repo.ListSites(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения площадок: %w", err)
+	}
+
+	return &models.ListSitesResponse{Sites: fillSiteFlags(sites)}, nil
+}
+
+// AdminCreateSite создает площадку (админка)
+func (s *ServiceImpl) AdminCreateSite(ctx context.Context, req *models.AdminCreateSiteRequest) (*models.Site, error) {
+	site := &models.Site{
+		Name:          strings.TrimSpace(req.Name),
+		Code:          strings.TrimSpace(req.Code),
+		Address:       req.Address,
+		IsActive:      true,
+		APIKey1C:      emptyToNil(req.APIKey1C),
+		DahuaUsername: emptyToNil(req.DahuaUsername),
+		DahuaPassword: emptyToNil(req.DahuaPassword),
+	}
+
+	if err := s.repo.CreateSite(ctx, site); err != nil {
+		return nil, fmt.Errorf("ошибка создания площадки: %w", err)
+	}
+
+	logger.Printf("Site - AdminCreateSite: создана площадка %s, code: %s", site.ID, site.Code)
+
+	return fillFlags(site), nil
+}
+
+// AdminUpdateSite обновляет площадку (админка)
+func (s *ServiceImpl) AdminUpdateSite(ctx context.Context, req *models.AdminUpdateSiteRequest) (*models.Site, error) {
+	site, err := s.GetSiteByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		site.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Code != nil {
+		site.Code = strings.TrimSpace(*req.Code)
+	}
+	if req.Address != nil {
+		site.Address = emptyToNil(req.Address)
+	}
+	if req.IsActive != nil {
+		if !*req.IsActive && site.ID == models.DefaultSiteID {
+			return nil, fmt.Errorf("площадку по умолчанию нельзя отключить")
+		}
+		site.IsActive = *req.IsActive
+	}
+	if req.APIKey1C != nil {
+		site.APIKey1C = emptyToNil(req.APIKey1C)
+	}
+	if req.DahuaUsername != nil {
+		site.DahuaUsername = emptyToNil(req.DahuaUsername)
+	}
+	if req.DahuaPassword != nil {
+		site.DahuaPassword = emptyToNil(req.DahuaPassword)
+	}
+
+	if err := s.repo.UpdateSite(ctx, site); err != nil {
+		return nil, fmt.Errorf("ошибка обновления площадки: %w", err)
+	}
+
+	logger.Printf("Site - AdminUpdateSite: площадка %s обновлена, is_active: %t", site.ID, site.IsActive)
+
+	return fillFlags(site), nil
+}
+
+// AdminDeleteSite удаляет площадку без боксов (админка)
+func (s *ServiceImpl) AdminDeleteSite(ctx context.Context, req *models.AdminDeleteSiteRequest) (*models.AdminDeleteSiteResponse, error) {
+	if req.ID == models.DefaultSiteID {
+		return nil, fmt.Errorf("площадку по умолчанию нельзя удалить")
+	}
+
+	if _, err := s.GetSiteByID(ctx, req.ID); err != nil {
+		return nil, err
+	}
+
+	boxes, err := s.repo.CountSiteBoxes(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки боксов площадки: %w", err)
+	}
+	if boxes > 0 {
+		return nil, fmt.Errorf("у площадки есть боксы (%d), сначала удалите или перенесите их", boxes)
+	}
+
+	if err := s.repo.DeleteSite(ctx, req.ID); err != nil {
+		return nil, fmt.Errorf("ошибка удаления площадки: %w", err)
+	}
+
+	logger.Printf("Site - AdminDeleteSite: площадка %s удалена", req.ID)
+
+	return &models.AdminDeleteSiteResponse{Success: true}, nil
+}
+
+// fillFlags заполняет признаки наличия учетных данных, сами данные наружу не отдаются
+func fillFlags(site *models.Site) *models.Site {
+	site.Has1CKey = site.APIKey1C != nil
+	site.HasDahuaAuth = site.DahuaUsername != nil && site.DahuaPassword != nil
+	return site
+}
+
+// fillSiteFlags заполняет признаки наличия учетных данных для списка площадок
+func fillSiteFlags(sites []models.Site) []models.Site {
+	for i := range sites {
+		fillFlags(&sites[i])
+	}
+	return sites
+}
+
+// emptyToNil превращает пустую строку в nil
+func emptyToNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
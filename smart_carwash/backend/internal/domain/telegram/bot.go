@@ -4,7 +4,9 @@ import (
 	"carwash_backend/internal/logger"
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"carwash_backend/internal/config"
 	"carwash_backend/internal/domain/user/models"
@@ -38,6 +40,12 @@ type NotificationService interface {
 	SendSessionNotification(telegramID int64, notificationType NotificationType, cooldownMinutes *int) error
 	SendBoxAssignmentNotification(telegramID int64, boxNumber int) error
 	SendSessionReassignmentNotification(telegramID int64, serviceType string) error
+	SendQueuePositionNotification(telegramID int64, serviceType string, position int) error
+	SendQueueWaitNotification(telegramID int64, serviceType string, etaMinutes int) error
+	SendQueueEmptyNotification(telegramID int64, serviceType string) error
+	SendSessionSnoozedNotification(telegramID int64, serviceType string, position int, snoozesLeft int) error
+	SendQueueJumpRefundNotification(telegramID int64, serviceType string, amount int) error
+	SendCarwashClosingNotification(telegramID int64, closesAt time.Time) error
 }
 
 // Bot структура для работы с Telegram ботом
@@ -133,6 +141,12 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 		return
 	}
 
+	// Обрабатываем команду /notify (настройки уведомлений об очереди)
+	if message.IsCommand() && message.Command() == "notify" {
+		b.handleNotifyCommand(message)
+		return
+	}
+
 	// Отправляем сообщение с помощью
 	b.sendHelpMessage(message.Chat.ID)
 }
@@ -172,7 +186,8 @@ func (b *Bot) sendHelpMessage(chatID int64) {
 	// Формируем сообщение с помощью
 	var messageText strings.Builder
 	messageText.WriteString("Доступные команды:\n\n")
-	messageText.WriteString("/start - Начать работу с ботом")
+	messageText.WriteString("/start - Начать работу с ботом\n")
+	messageText.WriteString("/notify - Уведомления об очереди")
 
 	// Отправляем сообщение с клавиатурой
 	msg := tgbotapi.NewMessage(chatID, messageText.String())
@@ -184,6 +199,133 @@ func (b *Bot) sendHelpMessage(chatID int64) {
 	}
 }
 
+// handleNotifyCommand обрабатывает команду /notify
+// Примеры: /notify position 3, /notify wait 10, /notify empty wash, /notify off
+func (b *Bot) handleNotifyCommand(message *tgbotapi.Message) {
+	ctx := context.Background()
+
+	user, err := b.service.GetUserByTelegramID(ctx, message.From.ID)
+	if err != nil {
+		logger.Printf("Ошибка получения пользователя для /notify: %v", err)
+		b.sendErrorMessage(message.Chat.ID)
+		return
+	}
+
+	req := &models.UpdateQueueNotificationsRequest{
+		UserID:                      user.ID,
+		QueueNotifyPosition:         user.QueueNotifyPosition,
+		QueueNotifyWaitMinutes:      user.QueueNotifyWaitMinutes,
+		QueueNotifyEmptyServiceType: user.QueueNotifyEmptyServiceType,
+		QueueNotifyEmptySiteID:      user.QueueNotifyEmptySiteID,
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		b.sendNotifySettings(message.Chat.ID, user)
+		return
+	}
+
+	switch args[0] {
+	case "off":
+		req.QueueNotifyPosition = nil
+		req.QueueNotifyWaitMinutes = nil
+		req.QueueNotifyEmptyServiceType = nil
+	case "position", "wait":
+		if len(args) < 2 {
+			b.sendNotifyUsage(message.Chat.ID)
+			return
+		}
+		var value *int
+		if args[1] != "off" {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed < 1 || (args[0] == "position" && parsed > 50) || (args[0] == "wait" && parsed > 180) {
+				b.sendNotifyUsage(message.Chat.ID)
+				return
+			}
+			value = &parsed
+		}
+		if args[0] == "position" {
+			req.QueueNotifyPosition = value
+		} else {
+			req.QueueNotifyWaitMinutes = value
+		}
+	case "empty":
+		if len(args) < 2 {
+			b.sendNotifyUsage(message.Chat.ID)
+			return
+		}
+		switch args[1] {
+		case "off":
+			req.QueueNotifyEmptyServiceType = nil
+		case "wash", "air_dry", "vacuum":
+			serviceType := args[1]
+			req.QueueNotifyEmptyServiceType = &serviceType
+		default:
+			b.sendNotifyUsage(message.Chat.ID)
+			return
+		}
+	default:
+		b.sendNotifyUsage(message.Chat.ID)
+		return
+	}
+
+	resp, err := b.service.UpdateQueueNotifications(ctx, req)
+	if err != nil {
+		logger.Printf("Ошибка обновления настроек уведомлений: %v", err)
+		b.sendErrorMessage(message.Chat.ID)
+		return
+	}
+
+	b.sendNotifySettings(message.Chat.ID, &resp.User)
+}
+
+// sendNotifySettings отправляет текущие настройки уведомлений об очереди
+func (b *Bot) sendNotifySettings(chatID int64, user *models.User) {
+	var messageText strings.Builder
+	messageText.WriteString("Уведомления об очереди:\n\n")
+
+	if user.QueueNotifyPosition != nil {
+		messageText.WriteString(fmt.Sprintf("• при позиции в очереди %d и ближе\n", *user.QueueNotifyPosition))
+	} else {
+		messageText.WriteString("• позиция в очереди - выключено\n")
+	}
+	if user.QueueNotifyWaitMinutes != nil {
+		messageText.WriteString(fmt.Sprintf("• при ожидании %d мин и меньше\n", *user.QueueNotifyWaitMinutes))
+	} else {
+		messageText.WriteString("• время ожидания - выключено\n")
+	}
+	if user.QueueNotifyEmptyServiceType != nil {
+		messageText.WriteString(fmt.Sprintf("• когда опустеет очередь %s\n", serviceTypeText(*user.QueueNotifyEmptyServiceType)))
+	} else {
+		messageText.WriteString("• пустая очередь - выключено\n")
+	}
+
+	msg := tgbotapi.NewMessage(chatID, messageText.String())
+	_, err := b.bot.Send(msg)
+	if err != nil {
+		logger.Printf("Ошибка отправки сообщения: %v", err)
+	}
+
+	b.sendNotifyUsage(chatID)
+}
+
+// sendNotifyUsage отправляет подсказку по команде /notify
+func (b *Bot) sendNotifyUsage(chatID int64) {
+	var messageText strings.Builder
+	messageText.WriteString("Настройка уведомлений:\n\n")
+	messageText.WriteString("/notify position 3 - когда вы станете 3-м в очереди\n")
+	messageText.WriteString("/notify wait 10 - когда ожидание станет не больше 10 минут\n")
+	messageText.WriteString("/notify empty wash - однократно, когда опустеет очередь (wash, air_dry, vacuum)\n")
+	messageText.WriteString("/notify position off - выключить одно уведомление\n")
+	messageText.WriteString("/notify off - выключить все уведомления")
+
+	msg := tgbotapi.NewMessage(chatID, messageText.String())
+	_, err := b.bot.Send(msg)
+	if err != nil {
+		logger.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
 // sendErrorMessage отправляет сообщение об ошибке
 func (b *Bot) sendErrorMessage(chatID int64) {
 	// Отправляем сообщение об ошибке
@@ -249,19 +391,7 @@ func (b *Bot) SendBoxAssignmentNotification(telegramID int64, boxNumber int) err
 
 // SendSessionReassignmentNotification отправляет уведомление о переназначении сессии
 func (b *Bot) SendSessionReassignmentNotification(telegramID int64, serviceType string) error {
-	var serviceText string
-	switch serviceType {
-	case "wash":
-		serviceText = "мойки"
-	case "air_dry":
-		serviceText = "обдува"
-	case "vacuum":
-		serviceText = "пылесоса"
-	default:
-		serviceText = "услуги"
-	}
-
-	messageText := fmt.Sprintf("🔄 Ваша сессия %s была переназначена на другой бокс. Пожалуйста, ожидайте уведомления о новом боксе.\n\nПерейдите в мини приложение по кнопке в левом нижнем углу ↙️↙️↙️", serviceText)
+	messageText := fmt.Sprintf("🔄 Ваша сессия %s была переназначена на другой бокс. Пожалуйста, ожидайте уведомления о новом боксе.\n\nПерейдите в мини приложение по кнопке в левом нижнем углу ↙️↙️↙️", serviceTypeText(serviceType))
 
 	// Отправляем сообщение
 	msg := tgbotapi.NewMessage(telegramID, messageText)
@@ -274,3 +404,125 @@ func (b *Bot) SendSessionReassignmentNotification(telegramID int64, serviceType
 
 	return nil
 }
+
+// SendQueuePositionNotification отправляет уведомление о достижении позиции в очереди
+func (b *Bot) SendQueuePositionNotification(telegramID int64, serviceType string, position int) error {
+	messageText := fmt.Sprintf("🚗 Вы %d-й в очереди %s. Будьте готовы подъехать к мойке!\n\nПерейдите в мини приложение по кнопке в левом нижнем углу ↙️↙️↙️", position, serviceTypeText(serviceType))
+
+	msg := tgbotapi.NewMessage(telegramID, messageText)
+	msg.ParseMode = "HTML"
+
+	_, err := b.bot.Send(msg)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки уведомления о позиции в очереди: %v", err)
+	}
+
+	return nil
+}
+
+// SendQueueWaitNotification отправляет уведомление о снижении прогноза ожидания
+func (b *Bot) SendQueueWaitNotification(telegramID int64, serviceType string, etaMinutes int) error {
+	var messageText string
+	if etaMinutes <= 0 {
+		messageText = fmt.Sprintf("⏱ Бокс для %s освободится с минуты на минуту. Будьте готовы подъехать к мойке!", serviceTypeText(serviceType))
+	} else {
+		messageText = fmt.Sprintf("⏱ До назначения бокса для %s осталось около %d мин. Будьте готовы подъехать к мойке!", serviceTypeText(serviceType), etaMinutes)
+	}
+	messageText += "\n\nПерейдите в мини приложение по кнопке в левом нижнем углу ↙️↙️↙️"
+
+	msg := tgbotapi.NewMessage(telegramID, messageText)
+	msg.ParseMode = "HTML"
+
+	_, err := b.bot.Send(msg)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки уведомления об ожидании в очереди: %v", err)
+	}
+
+	return nil
+}
+
+// SendQueueEmptyNotification отправляет уведомление об опустевшей очереди
+func (b *Bot) SendQueueEmptyNotification(telegramID int64, serviceType string) error {
+	messageText := fmt.Sprintf("✅ Очередь %s пуста, приезжайте!\n\nПерейдите в мини приложение по кнопке в левом нижнем углу ↙️↙️↙️", serviceTypeText(serviceType))
+
+	msg := tgbotapi.NewMessage(telegramID, messageText)
+	msg.ParseMode = "HTML"
+
+	_, err := b.bot.Send(msg)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки уведомления о пустой очереди: %v", err)
+	}
+
+	return nil
+}
+
+// SendSessionSnoozedNotification отправляет уведомление об отсрочке назначенного бокса
+func (b *Bot) SendSessionSnoozedNotification(telegramID int64, serviceType string, position int, snoozesLeft int) error {
+	messageText := fmt.Sprintf("⏸ Бокс отложен и передан следующему клиенту. Вы вернулись в очередь %s", serviceTypeText(serviceType))
+	if position > 0 {
+		messageText += fmt.Sprintf(" на %d-ю позицию", position)
+	}
+	messageText += ". Мы сообщим, когда будет назначен новый бокс."
+	if snoozesLeft > 0 {
+		messageText += fmt.Sprintf("\n\nОтложить бокс можно еще %d раз.", snoozesLeft)
+	} else {
+		messageText += "\n\nБольше откладывать бокс нельзя."
+	}
+	messageText += "\n\nПерейдите в мини приложение по кнопке в левом нижнем углу ↙️↙️↙️"
+
+	msg := tgbotapi.NewMessage(telegramID, messageText)
+	msg.ParseMode = "HTML"
+
+	_, err := b.bot.Send(msg)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки уведомления об отсрочке бокса: %v", err)
+	}
+
+	return nil
+}
+
+// SendQueueJumpRefundNotification отправляет уведомление о возврате доплаты за пропуск очереди
+func (b *Bot) SendQueueJumpRefundNotification(telegramID int64, serviceType string, amount int) error {
+	messageText := fmt.Sprintf("💸 Пропуск очереди %s не ускорил назначение бокса, поэтому доплата %d ₽ возвращена на карту.", serviceTypeText(serviceType), amount/100)
+	messageText += "\n\nПерейдите в мини приложение по кнопке в левом нижнем углу ↙️↙️↙️"
+
+	msg := tgbotapi.NewMessage(telegramID, messageText)
+	msg.ParseMode = "HTML"
+
+	_, err := b.bot.Send(msg)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки уведомления о возврате доплаты за пропуск очереди: %v", err)
+	}
+
+	return nil
+}
+
+// SendCarwashClosingNotification предупреждает клиента в очереди о скором закрытии мойки
+func (b *Bot) SendCarwashClosingNotification(telegramID int64, closesAt time.Time) error {
+	messageText := fmt.Sprintf("🕙 Мойка закрывается в %s, прием новых сессий остановлен.", closesAt.Format("15:04"))
+	messageText += "\n\nЕсли бокс не будет назначен до закрытия, сессия будет отменена, а оплата возвращена."
+
+	msg := tgbotapi.NewMessage(telegramID, messageText)
+	msg.ParseMode = "HTML"
+
+	_, err := b.bot.Send(msg)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки уведомления о закрытии мойки: %v", err)
+	}
+
+	return nil
+}
+
+// serviceTypeText название типа услуги в родительном падеже
+func serviceTypeText(serviceType string) string {
+	switch serviceType {
+	case "wash":
+		return "мойки"
+	case "air_dry":
+		return "обдува"
+	case "vacuum":
+		return "пылесоса"
+	default:
+		return "услуги"
+	}
+}
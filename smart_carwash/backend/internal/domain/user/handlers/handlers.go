@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"carwash_backend/internal/logger"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"carwash_backend/internal/domain/user/models"
 	"carwash_backend/internal/domain/user/service"
+	"carwash_backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -29,9 +31,10 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 	userRoutes := router.Group("/users")
 	{
 		userRoutes.POST("", h.createUser)
-		userRoutes.GET("/by-telegram-id", h.getUserByTelegramID) // telegram_id в query параметре
-		userRoutes.PUT("/car-number", h.updateCarNumber)         // Обновление номера машины
-		userRoutes.PUT("/email", h.updateEmail)                  // Обновление email
+		userRoutes.GET("/by-telegram-id", h.getUserByTelegramID)           // telegram_id в query параметре
+		userRoutes.PUT("/car-number", h.updateCarNumber)                   // Обновление номера машины
+		userRoutes.PUT("/email", h.updateEmail)                            // Обновление email
+		userRoutes.PUT("/queue-notifications", h.updateQueueNotifications) // Настройки уведомлений об очереди
 	}
 
 	// Административные маршруты
@@ -116,6 +119,11 @@ func (h *Handler) updateCarNumber(c *gin.Context) {
 	resp, err := h.service.UpdateCarNumber(c.Request.Context(), &req)
 	if err != nil {
 		logger.WithContext(c).Errorf("API Error - updateCarNumber: ошибка обновления номера машины, user_id: %s, car_number: %s, error: %v", req.UserID.String(), req.CarNumber, err)
+		var plateErr *utils.LicensePlateValidationError
+		if errors.As(err, &plateErr) {
+			c.JSON(http.StatusBadRequest, plateErr)
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -153,6 +161,37 @@ func (h *Handler) updateEmail(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// updateQueueNotifications обработчик для обновления настроек уведомлений об очереди
+func (h *Handler) updateQueueNotifications(c *gin.Context) {
+	var req models.UpdateQueueNotificationsRequest
+
+	// Парсим JSON из тела запроса
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithContext(c).Errorf("API Error - updateQueueNotifications: ошибка парсинга JSON, error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Логируем мета-параметры
+	c.Set("meta", gin.H{
+		"user_id":                         req.UserID.String(),
+		"queue_notify_position":           req.QueueNotifyPosition,
+		"queue_notify_wait_minutes":       req.QueueNotifyWaitMinutes,
+		"queue_notify_empty_service_type": req.QueueNotifyEmptyServiceType,
+	})
+
+	// Обновляем настройки уведомлений
+	resp, err := h.service.UpdateQueueNotifications(c.Request.Context(), &req)
+	if err != nil {
+		logger.WithContext(c).Errorf("API Error - updateQueueNotifications: ошибка обновления настроек уведомлений, user_id: %s, error: %v", req.UserID.String(), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Возвращаем результат
+	c.JSON(http.StatusOK, resp)
+}
+
 // adminListUsers обработчик для получения списка пользователей для администратора
 func (h *Handler) adminListUsers(c *gin.Context) {
 	// Получаем параметры пагинации из query
@@ -9,18 +9,22 @@ import (
 
 // User представляет пользователя системы
 type User struct {
-	ID              uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	TelegramID      int64          `json:"telegram_id" gorm:"uniqueIndex"`
-	Username        string         `json:"username"`
-	FirstName       string         `json:"first_name"`
-	LastName        string         `json:"last_name"`
-	CarNumber       string         `json:"car_number"`
-	CarNumberCountry string        `json:"car_number_country" gorm:"default:'RUS'"`
-	Email           string         `json:"email"`
-	IsAdmin         bool           `json:"is_admin" gorm:"default:false"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                          uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	TelegramID                  int64          `json:"telegram_id" gorm:"uniqueIndex"`
+	Username                    string         `json:"username"`
+	FirstName                   string         `json:"first_name"`
+	LastName                    string         `json:"last_name"`
+	CarNumber                   string         `json:"car_number"`
+	CarNumberCountry            string         `json:"car_number_country" gorm:"default:'RUS'"`
+	Email                       string         `json:"email"`
+	IsAdmin                     bool           `json:"is_admin" gorm:"default:false"`
+	QueueNotifyPosition         *int           `json:"queue_notify_position"`           // Уведомить, когда позиция в очереди станет не больше N
+	QueueNotifyWaitMinutes      *int           `json:"queue_notify_wait_minutes"`       // Уведомить, когда прогноз ожидания станет не больше N минут
+	QueueNotifyEmptyServiceType *string        `json:"queue_notify_empty_service_type"` // Однократно уведомить, когда опустеет очередь на услугу
+	QueueNotifyEmptySiteID      *uuid.UUID     `json:"queue_notify_empty_site_id"`      // Площадка, на которой ожидается опустевшая очередь
+	CreatedAt                   time.Time      `json:"created_at"`
+	UpdatedAt                   time.Time      `json:"updated_at"`
+	DeletedAt                   gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // CreateUserRequest представляет запрос на создание пользователя
@@ -97,3 +101,19 @@ type UpdateEmailResponse struct {
 	Success bool `json:"success"`
 	User    User `json:"user"`
 }
+
+// UpdateQueueNotificationsRequest запрос на обновление настроек уведомлений об очереди
+// Пустое значение выключает соответствующее уведомление
+type UpdateQueueNotificationsRequest struct {
+	UserID                      uuid.UUID  `json:"user_id" binding:"required"`
+	QueueNotifyPosition         *int       `json:"queue_notify_position" binding:"omitempty,min=1,max=50"`
+	QueueNotifyWaitMinutes      *int       `json:"queue_notify_wait_minutes" binding:"omitempty,min=1,max=180"`
+	QueueNotifyEmptyServiceType *string    `json:"queue_notify_empty_service_type" binding:"omitempty,oneof=wash air_dry vacuum"`
+	QueueNotifyEmptySiteID      *uuid.UUID `json:"queue_notify_empty_site_id"` // Площадка подписки на опустевшую очередь, по умолчанию - площадка по умолчанию
+}
+
+// UpdateQueueNotificationsResponse ответ на обновление настроек уведомлений об очереди
+type UpdateQueueNotificationsResponse struct {
+	Success bool `json:"success"`
+	User    User `json:"user"`
+}
@@ -17,6 +17,8 @@ type Repository interface {
 	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error)
 	GetUserByCarNumber(ctx context.Context, carNumber string) (*models.User, error)
 	UpdateUser(ctx context.Context, user *models.User) error
+	GetUsersAwaitingEmptyQueue(ctx context.Context) ([]models.User, error)
+	ClearQueueNotifyEmpty(ctx context.Context, userID uuid.UUID) error
 
 	// Административные методы
 	GetUsersWithPagination(ctx context.Context, limit int, offset int) ([]models.User, int, error)
@@ -97,6 +99,23 @@ func (r *PostgresRepository) UpdateUser(ctx context.Context, user *models.User)
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
+// GetUsersAwaitingEmptyQueue получает пользователей, ожидающих уведомления об опустевшей очереди
+func (r *PostgresRepository) GetUsersAwaitingEmptyQueue(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).Where("queue_notify_empty_service_type IS NOT NULL").Find(&users).Error
+	return users, err
+}
+
+// ClearQueueNotifyEmpty снимает подписку пользователя на уведомление об опустевшей очереди
+func (r *PostgresRepository) ClearQueueNotifyEmpty(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"queue_notify_empty_service_type": nil,
+			"queue_notify_empty_site_id":      nil,
+		}).Error
+}
+
 // GetUsersWithPagination получает пользователей с пагинацией
 func (r *PostgresRepository) GetUsersWithPagination(ctx context.Context, limit int, offset int) ([]models.User, int, error) {
 	var users []models.User
@@ -1,6 +1,8 @@
 package service
 
 import (
+	siteModels "carwash_backend/internal/domain/site/models"
+	siteService "carwash_backend/internal/domain/site/service"
 	"carwash_backend/internal/domain/user/models"
 	"carwash_backend/internal/domain/user/repository"
 	"carwash_backend/internal/utils"
@@ -21,6 +23,9 @@ type Service interface {
 	GetUserByCarNumber(ctx context.Context, carNumber string) (*models.User, error)
 	UpdateCarNumber(ctx context.Context, req *models.UpdateCarNumberRequest) (*models.UpdateCarNumberResponse, error)
 	UpdateEmail(ctx context.Context, req *models.UpdateEmailRequest) (*models.UpdateEmailResponse, error)
+	UpdateQueueNotifications(ctx context.Context, req *models.UpdateQueueNotificationsRequest) (*models.UpdateQueueNotificationsResponse, error)
+	GetUsersAwaitingEmptyQueue(ctx context.Context) ([]models.User, error)
+	ClearQueueNotifyEmpty(ctx context.Context, userID uuid.UUID) error
 
 	// Административные методы
 	AdminListUsers(ctx context.Context, req *models.AdminListUsersRequest) (*models.AdminListUsersResponse, error)
@@ -29,7 +34,8 @@ type Service interface {
 
 // ServiceImpl реализация Service
 type ServiceImpl struct {
-	repo repository.Repository
+	repo        repository.Repository
+	siteService siteService.Service // Опциональный сервис площадок
 }
 
 // NewService создает новый экземпляр Service
@@ -37,6 +43,23 @@ func NewService(repo repository.Repository) *ServiceImpl {
 	return &ServiceImpl{repo: repo}
 }
 
+// SetSiteService устанавливает сервис площадок
+func (s *ServiceImpl) SetSiteService(siteService siteService.Service) {
+	s.siteService = siteService
+}
+
+// resolveSiteID определяет площадку подписки на опустевшую очередь
+// Без выбранной площадки используется площадка по умолчанию
+func (s *ServiceImpl) resolveSiteID(ctx context.Context, siteID *uuid.UUID) (uuid.UUID, error) {
+	if s.siteService != nil {
+		return s.siteService.ResolveSiteID(ctx, siteID)
+	}
+	if siteID == nil || *siteID == uuid.Nil {
+		return siteModels.DefaultSiteID, nil
+	}
+	return *siteID, nil
+}
+
 // CreateUser создает нового пользователя
 func (s *ServiceImpl) CreateUser(ctx context.Context, req *models.CreateUserRequest) (*models.User, error) {
 	// Проверяем, существует ли пользователь
@@ -125,13 +148,13 @@ func (s *ServiceImpl) AdminGetUser(ctx context.Context, req *models.AdminGetUser
 
 // UpdateCarNumber обновляет номер машины пользователя
 func (s *ServiceImpl) UpdateCarNumber(ctx context.Context, req *models.UpdateCarNumberRequest) (*models.UpdateCarNumberResponse, error) {
-	// Нормализация номера машины (без валидации)
-	normalizedCarNumber := utils.NormalizeLicensePlate(req.CarNumber)
-
 	// Устанавливаем дефолтную страну если не указана
-	country := req.CarNumberCountry
-	if country == "" {
-		country = "RUS"
+	country := utils.NormalizeCarNumberCountry(req.CarNumberCountry)
+
+	// Валидация и нормализация номера машины по правилам страны
+	normalizedCarNumber, err := utils.ValidateLicensePlate(req.CarNumber, country)
+	if err != nil {
+		return nil, err
 	}
 
 	// Получаем пользователя
@@ -180,3 +203,45 @@ func (s *ServiceImpl) UpdateEmail(ctx context.Context, req *models.UpdateEmailRe
 		User:    *user,
 	}, nil
 }
+
+// UpdateQueueNotifications обновляет настройки уведомлений об очереди
+func (s *ServiceImpl) UpdateQueueNotifications(ctx context.Context, req *models.UpdateQueueNotificationsRequest) (*models.UpdateQueueNotificationsResponse, error) {
+	// Получаем пользователя
+	user, err := s.repo.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Обновляем настройки целиком, незаданные уведомления выключаются
+	user.QueueNotifyPosition = req.QueueNotifyPosition
+	user.QueueNotifyWaitMinutes = req.QueueNotifyWaitMinutes
+	user.QueueNotifyEmptyServiceType = req.QueueNotifyEmptyServiceType
+	user.QueueNotifyEmptySiteID = nil
+	if req.QueueNotifyEmptyServiceType != nil {
+		// Очередь опустевает на конкретной площадке, поэтому площадка сохраняется вместе с подпиской
+		siteID, err := s.resolveSiteID(ctx, req.QueueNotifyEmptySiteID)
+		if err != nil {
+			return nil, err
+		}
+		user.QueueNotifyEmptySiteID = &siteID
+	}
+	err = s.repo.UpdateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UpdateQueueNotificationsResponse{
+		Success: true,
+		User:    *user,
+	}, nil
+}
+
+// GetUsersAwaitingEmptyQueue получает пользователей, ожидающих уведомления об опустевшей очереди
+func (s *ServiceImpl) GetUsersAwaitingEmptyQueue(ctx context.Context) ([]models.User, error) {
+	return s.repo.GetUsersAwaitingEmptyQueue(ctx)
+}
+
+// ClearQueueNotifyEmpty снимает подписку на уведомление об опустевшей очереди после его отправки
+func (s *ServiceImpl) ClearQueueNotifyEmpty(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.ClearQueueNotifyEmpty(ctx, userID)
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"carwash_backend/internal/domain/washbox/models"
+	"carwash_backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 	"context"
@@ -14,6 +15,7 @@ import (
 func (h *Handler) cashierListWashBoxes(c *gin.Context) {
 	// Получаем параметры фильтрации из query
 	var req models.CashierListWashBoxesRequest
+	req.SiteID = middleware.SiteIDFromContext(c)
 
 	// Статус
 	if status := c.Query("status"); status != "" {
@@ -70,6 +72,8 @@ func (h *Handler) cashierSetMaintenance(c *gin.Context) {
 		return
 	}
 
+	req.SiteID = middleware.SiteIDFromContext(c)
+
 	// Переводим бокс в режим обслуживания
 	ctx := c.Request.Context()
 	if cashierAny, ok := c.Get("cashier_id"); ok {
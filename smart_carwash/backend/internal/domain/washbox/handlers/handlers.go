@@ -6,6 +6,7 @@ import (
 
 	"carwash_backend/internal/domain/washbox/models"
 	"carwash_backend/internal/domain/washbox/service"
+	"carwash_backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -40,6 +41,7 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup, cleanerMiddleware gin.
 		adminRoutes.PUT("", h.adminUpdateWashBox)
 		adminRoutes.DELETE("", h.adminDeleteWashBox)
 		adminRoutes.GET("/by-id", h.adminGetWashBox)
+		adminRoutes.GET("/assignment-logs", h.adminListAssignmentLogs)
 	}
 
 	// Административные маршруты для логов уборки
@@ -76,6 +78,14 @@ func (h *Handler) adminListWashBoxes(c *gin.Context) {
 		req.Status = &status
 	}
 
+	// Площадка
+	siteID, err := middleware.SiteIDFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.SiteID = siteID
+
 	// Тип услуги
 	if serviceType := c.Query("service_type"); serviceType != "" {
 		req.ServiceType = &serviceType
@@ -212,6 +222,10 @@ func (h *Handler) adminUpdateWashBox(c *gin.Context) {
 		req.Priority = nil
 		req.LightCoilRegister = nil
 		req.ChemistryCoilRegister = nil
+		req.ModbusDevice = nil
+		req.ModbusRegisters = nil
+		req.DeviceChannels = nil
+		req.PresenceSensorRegister = nil
 		// Комментарий не редактируем
 		req.Comment = nil
 
@@ -341,6 +355,7 @@ func (h *Handler) adminGetWashBox(c *gin.Context) {
 // cleanerListWashBoxes обработчик для получения списка боксов для уборщика
 func (h *Handler) cleanerListWashBoxes(c *gin.Context) {
 	var req models.CleanerListWashBoxesRequest
+	req.SiteID = middleware.SiteIDFromContext(c)
 
 	// Парсим параметры из query
 	if limitStr := c.Query("limit"); limitStr != "" {
@@ -374,6 +389,7 @@ func (h *Handler) cleanerStartCleaning(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.SiteID = middleware.SiteIDFromContext(c)
 
 	// Получаем ID уборщика из контекста
 	cleanerIDInterface, exists := c.Get("cleaner_id")
@@ -502,3 +518,20 @@ func (h *Handler) cleanerGetCleaningHistory(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// adminListAssignmentLogs обработчик для получения журнала выбора боксов
+func (h *Handler) adminListAssignmentLogs(c *gin.Context) {
+	var req models.AdminListAssignmentLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.AdminListAssignmentLogs(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Стратегии выбора бокса для сессии из очереди
+const (
+	AssignmentStrategyPriorityLetter    = "priority_letter"     // По букве приоритета, среди равных - случайно
+	AssignmentStrategyLeastUsedToday    = "least_used_today"    // Наименее загруженный за сегодня (равномерный износ)
+	AssignmentStrategyRoundRobin        = "round_robin"         // По очереди: дольше всех не назначавшийся
+	AssignmentStrategyNearestToEntrance = "nearest_to_entrance" // Ближайший к въезду по положению бокса
+)
+
+// DefaultAssignmentStrategy стратегия выбора бокса по умолчанию
+const DefaultAssignmentStrategy = AssignmentStrategyPriorityLetter
+
+// BoxAssignmentCandidate бокс-кандидат с оценкой стратегии
+type BoxAssignmentCandidate struct {
+	BoxID     uuid.UUID `json:"box_id"`
+	BoxNumber int       `json:"box_number"`
+	Priority  string    `json:"priority"`
+	Position  int       `json:"position"`
+	Score     *float64  `json:"score,omitempty"` // Значение, по которому стратегия упорядочила боксы
+}
+
+// SelectBoxRequest запрос на выбор бокса для сессии
+type SelectBoxRequest struct {
+	SessionID     uuid.UUID
+	ServiceType   string
+	Candidates    []WashBox
+	CooldownMatch bool // Кандидаты - боксы в кулдауне этого же клиента
+}
+
+// BoxAssignmentLog запись журнала решений о выборе бокса
+type BoxAssignmentLog struct {
+	ID            uuid.UUID       `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SessionID     uuid.UUID       `json:"session_id" gorm:"type:uuid;index"`
+	ServiceType   string          `json:"service_type"`
+	Strategy      string          `json:"strategy"`
+	BoxID         uuid.UUID       `json:"box_id" gorm:"type:uuid"`
+	BoxNumber     int             `json:"box_number" gorm:"index"`
+	CooldownMatch bool            `json:"cooldown_match"`
+	Candidates    json.RawMessage `json:"candidates" gorm:"type:jsonb;not null"` // Кандидаты в порядке предпочтения стратегии
+	Reason        string          `json:"reason"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"index"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (BoxAssignmentLog) TableName() string {
+	return "box_assignment_logs"
+}
+
+// AdminListAssignmentLogsRequest запрос на получение журнала выбора боксов (админка)
+type AdminListAssignmentLogsRequest struct {
+	SessionID *uuid.UUID `json:"session_id" form:"session_id"`
+	BoxNumber *int       `json:"box_number" form:"box_number"`
+	Limit     int        `json:"limit" form:"limit"`
+	Offset    int        `json:"offset" form:"offset"`
+}
+
+// AdminListAssignmentLogsResponse ответ на получение журнала выбора боксов (админка)
+type AdminListAssignmentLogsResponse struct {
+	Logs  []BoxAssignmentLog `json:"logs"`
+	Total int64              `json:"total"`
+}
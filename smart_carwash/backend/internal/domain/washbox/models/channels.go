@@ -0,0 +1,167 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// Роли каналов устройств бокса
+const (
+	ChannelLight     = "light"     // Свет
+	ChannelChemistry = "chemistry" // Химия
+	ChannelGate      = "gate"      // Ворота
+	ChannelFoamGun   = "foam_gun"  // Пенный пистолет
+	ChannelWax       = "wax"       // Воск
+	ChannelOsmosis   = "osmosis"   // Осмос
+	ChannelVacuum    = "vacuum"    // Мотор пылесоса
+)
+
+// Типы каналов устройств
+const (
+	ChannelTypeCoil     = "coil"     // Coil: устройство включается записью true
+	ChannelTypeRegister = "register" // Holding register: устройство включается записью OnValue
+)
+
+// Режимы каналов: когда устройство включается без участия администратора
+const (
+	ChannelModeSession   = "session"   // На время активной сессии
+	ChannelModeChemistry = "chemistry" // Пока в сессии включена химия
+	ChannelModeManual    = "manual"    // Только вручную
+)
+
+// DeviceChannel канал устройства бокса (свет, химия, ворота, пенный пистолет и т.п.)
+// Сессия и химия управляют каналами по режиму, администратор - по роли. Inverted нужен для
+// реле с нормально замкнутыми контактами: устройство включено, когда в coil записан false
+type DeviceChannel struct {
+	Role     string   `json:"role" binding:"required,oneof=light chemistry gate foam_gun wax osmosis vacuum"`
+	Register string   `json:"register" binding:"required"`
+	Type     string   `json:"type,omitempty" binding:"omitempty,oneof=coil register"`            // По умолчанию coil
+	Mode     string   `json:"mode,omitempty" binding:"omitempty,oneof=session chemistry manual"` // По умолчанию chemistry для химии, session для остальных
+	Inverted bool     `json:"inverted,omitempty"`                                                // Для coil: устройство включено при false
+	OnValue  *float64 `json:"on_value,omitempty"`                                                // Для register: значение включенного устройства, по умолчанию 1
+	OffValue *float64 `json:"off_value,omitempty"`                                               // Для register: значение выключенного устройства, по умолчанию 0
+}
+
+// ChannelType тип канала с учетом значения по умолчанию
+func (c DeviceChannel) ChannelType() string {
+	if c.Type == "" {
+		return ChannelTypeCoil
+	}
+	return c.Type
+}
+
+// ChannelMode режим канала с учетом значения по умолчанию
+func (c DeviceChannel) ChannelMode() string {
+	switch {
+	case c.Mode != "":
+		return c.Mode
+	case c.Role == ChannelChemistry:
+		return ChannelModeChemistry
+	default:
+		return ChannelModeSession
+	}
+}
+
+// CoilValue значение coil, которое включает или выключает устройство
+func (c DeviceChannel) CoilValue(on bool) bool {
+	return on != c.Inverted
+}
+
+// CoilOn включено ли устройство при прочитанном значении coil
+func (c DeviceChannel) CoilOn(value bool) bool {
+	return value != c.Inverted
+}
+
+// RegisterValue значение holding register, которое включает или выключает устройство
+func (c DeviceChannel) RegisterValue(on bool) float64 {
+	if on {
+		if c.OnValue != nil {
+			return *c.OnValue
+		}
+		return 1
+	}
+	if c.OffValue != nil {
+		return *c.OffValue
+	}
+	return 0
+}
+
+// Channels каналы устройств бокса
+// light_coil_register и chemistry_coil_register - сокращенная запись каналов света и химии:
+// они используются, если в device_channels нет канала с той же ролью
+func (b *WashBox) Channels() []DeviceChannel {
+	channels := make([]DeviceChannel, 0, len(b.DeviceChannels)+2)
+	if _, ok := b.findDeviceChannel(ChannelLight); !ok && b.LightCoilRegister != nil && *b.LightCoilRegister != "" {
+		channels = append(channels, DeviceChannel{Role: ChannelLight, Register: *b.LightCoilRegister})
+	}
+	if _, ok := b.findDeviceChannel(ChannelChemistry); !ok && b.ChemistryCoilRegister != nil && *b.ChemistryCoilRegister != "" {
+		channels = append(channels, DeviceChannel{Role: ChannelChemistry, Register: *b.ChemistryCoilRegister})
+	}
+	return append(channels, b.DeviceChannels...)
+}
+
+// FindChannel ищет канал бокса по роли
+func (b *WashBox) FindChannel(role string) (DeviceChannel, bool) {
+	for _, channel := range b.Channels() {
+		if channel.Role == role {
+			return channel, true
+		}
+	}
+	return DeviceChannel{}, false
+}
+
+// ChannelsByMode каналы бокса с заданным режимом
+func (b *WashBox) ChannelsByMode(mode string) []DeviceChannel {
+	var channels []DeviceChannel
+	for _, channel := range b.Channels() {
+		if channel.ChannelMode() == mode {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// findDeviceChannel ищет канал по роли только в device_channels
+func (b *WashBox) findDeviceChannel(role string) (DeviceChannel, bool) {
+	for _, channel := range b.DeviceChannels {
+		if channel.Role == role {
+			return channel, true
+		}
+	}
+	return DeviceChannel{}, false
+}
+
+// ValidateDeviceChannels проверяет каналы: hex адрес, одна роль - один канал, значения по типу канала
+func ValidateDeviceChannels(channels []DeviceChannel) error {
+	roles := make(map[string]bool, len(channels))
+	for _, channel := range channels {
+		if roles[channel.Role] {
+			return fmt.Errorf("канал %s указан несколько раз", channel.Role)
+		}
+		roles[channel.Role] = true
+
+		if err := ValidateRegister(channel.Register); err != nil {
+			return fmt.Errorf("регистр %s канала %s: %w", channel.Register, channel.Role, err)
+		}
+
+		if channel.ChannelType() == ChannelTypeCoil {
+			if channel.OnValue != nil || channel.OffValue != nil {
+				return fmt.Errorf("значения включения задаются только для канала типа register (%s)", channel.Role)
+			}
+			continue
+		}
+
+		if channel.Inverted {
+			return fmt.Errorf("инверсия задается только для канала типа coil (%s)", channel.Role)
+		}
+		for _, value := range []*float64{channel.OnValue, channel.OffValue} {
+			if value != nil && (*value < 0 || *value > math.MaxUint16 || *value != math.Trunc(*value)) {
+				return fmt.Errorf("значение канала %s должно быть целым от 0 до 65535", channel.Role)
+			}
+		}
+		if channel.RegisterValue(true) == channel.RegisterValue(false) {
+			return fmt.Errorf("значения включения и выключения канала %s совпадают", channel.Role)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Параметры бокса, которые задаются через holding registers
+const (
+	RegisterPumpPressure      = "pump_pressure"      // Давление насоса
+	RegisterFoamConcentration = "foam_concentration" // Концентрация пены
+	RegisterCountdown         = "countdown"          // Табло обратного отсчета: оставшееся время сессии в секундах
+)
+
+// Типы значений holding registers
+const (
+	RegisterTypeUint16  = "uint16"
+	RegisterTypeInt16   = "int16"
+	RegisterTypeFloat32 = "float32"
+)
+
+// BoxRegister определение holding register бокса
+// При старте и продлении сессии в регистр пишется ActiveValue (для countdown - оставшееся время сессии),
+// при завершении - IdleValue (для countdown - 0). Значение умножается на Scale перед записью:
+// например, давление в барах при Scale 10 пишется в десятых долях бара
+type BoxRegister struct {
+	Parameter   string   `json:"parameter" binding:"required,max=24"`
+	Register    string   `json:"register" binding:"required"`
+	DataType    string   `json:"data_type,omitempty" binding:"omitempty,oneof=uint16 int16 float32"` // По умолчанию uint16
+	WordOrder   string   `json:"word_order,omitempty" binding:"omitempty,oneof=big little"`          // Для float32: big - старшее слово первым
+	Scale       *float64 `json:"scale,omitempty"`                                                    // По умолчанию 1
+	ActiveValue *float64 `json:"active_value,omitempty"`                                             // Значение на время сессии, nil - не менять
+	IdleValue   *float64 `json:"idle_value,omitempty"`                                               // Значение без сессии, nil - не менять
+}
+
+// RawValue значение в единицах регистра с учетом множителя
+func (r BoxRegister) RawValue(value float64) float64 {
+	if r.Scale == nil {
+		return value
+	}
+	return value * *r.Scale
+}
+
+// FindRegister ищет определение регистра бокса по параметру
+func (b *WashBox) FindRegister(parameter string) (*BoxRegister, bool) {
+	for i := range b.ModbusRegisters {
+		if b.ModbusRegisters[i].Parameter == parameter {
+			return &b.ModbusRegisters[i], true
+		}
+	}
+	return nil, false
+}
+
+// ValidateBoxRegisters проверяет определения регистров: hex адрес, уникальные параметры и адреса
+func ValidateBoxRegisters(registers []BoxRegister) error {
+	parameters := make(map[string]bool, len(registers))
+	addresses := make(map[uint32]string, len(registers))
+	for _, register := range registers {
+		if parameters[register.Parameter] {
+			return fmt.Errorf("параметр %s указан несколько раз", register.Parameter)
+		}
+		parameters[register.Parameter] = true
+
+		address, err := parseRegister(register.Register)
+		if err != nil {
+			return fmt.Errorf("регистр %s параметра %s: %w", register.Register, register.Parameter, err)
+		}
+
+		words := uint32(1)
+		if register.DataType == RegisterTypeFloat32 {
+			words = 2
+		}
+		for offset := uint32(0); offset < words; offset++ {
+			if other, ok := addresses[address+offset]; ok {
+				return fmt.Errorf("регистры параметров %s и %s пересекаются", other, register.Parameter)
+			}
+			addresses[address+offset] = register.Parameter
+		}
+
+		if register.Scale != nil && *register.Scale == 0 {
+			return fmt.Errorf("множитель параметра %s не может быть 0", register.Parameter)
+		}
+	}
+	return nil
+}
+
+// ValidateRegister проверяет адрес регистра в hex формате 0x0000
+func ValidateRegister(register string) error {
+	_, err := parseRegister(register)
+	return err
+}
+
+// parseRegister разбирает адрес регистра в hex формате 0x0000
+func parseRegister(register string) (uint32, error) {
+	lower := strings.ToLower(register)
+	if !strings.HasPrefix(lower, "0x") {
+		return 0, fmt.Errorf("ожидается hex формат 0x0000")
+	}
+	address, err := strconv.ParseUint(lower[2:], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("ожидается hex формат 0x0000")
+	}
+	return uint32(address), nil
+}
@@ -25,27 +25,35 @@ const (
 
 // WashBox представляет бокс автомойки
 type WashBox struct {
-	ID                            uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	Number                        int            `json:"number" gorm:"uniqueIndex"`
-	Status                        string         `json:"status" gorm:"default:free"`
-	ServiceType                   string         `json:"service_type" gorm:"default:wash"`
-	ChemistryEnabled              bool           `json:"chemistry_enabled" gorm:"default:true"`
-	Priority                      string         `json:"priority" gorm:"type:varchar(1);default:'A';check:priority ~ '^[A-Z]$'"`
-	LightCoilRegister             *string        `json:"light_coil_register"`
-	ChemistryCoilRegister         *string        `json:"chemistry_coil_register"`
-	Comment                       *string        `json:"comment" gorm:"type:varchar(1000)"`
-	CleaningReservedBy            *uuid.UUID     `json:"cleaning_reserved_by" gorm:"type:uuid;index"`
-	CleaningStartedAt             *time.Time     `json:"cleaning_started_at"`
-	LastCompletedSessionUserID    *uuid.UUID     `json:"last_completed_session_user_id" gorm:"type:uuid;index"`
-	LastCompletedSessionCarNumber *string        `json:"last_completed_session_car_number"`
-	LastCompletedAt               *time.Time     `json:"last_completed_at"`
-	CooldownUntil                 *time.Time     `json:"cooldown_until"`
-	LightStatus                   *bool          `json:"light_status,omitempty" gorm:"-"`     // Статус света (не хранится в БД, заполняется из modbus_connection_statuses)
-	ChemistryStatus               *bool          `json:"chemistry_status,omitempty" gorm:"-"` // Статус химии (не хранится в БД, заполняется из modbus_connection_statuses)
-	CanBeCleaned                  *bool          `json:"can_be_cleaned,omitempty" gorm:"-"`   // Можно ли убирать бокс (не хранится в БД, вычисляется динамически)
-	CreatedAt                     time.Time      `json:"created_at"`
-	UpdatedAt                     time.Time      `json:"updated_at"`
-	DeletedAt                     gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                            uuid.UUID       `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Number                        int             `json:"number" gorm:"uniqueIndex"` // Номер бокса уникален во всей системе, а не в пределах площадки
+	SiteID                        uuid.UUID       `json:"site_id" gorm:"type:uuid;index"`
+	Status                        string          `json:"status" gorm:"default:free"`
+	ServiceType                   string          `json:"service_type" gorm:"default:wash"`
+	ChemistryEnabled              bool            `json:"chemistry_enabled" gorm:"default:true"`
+	Priority                      string          `json:"priority" gorm:"type:varchar(1);default:'A';check:priority ~ '^[A-Z]$'"`
+	Position                      int             `json:"position" gorm:"default:0"` // Положение относительно въезда (меньше - ближе)
+	LightCoilRegister             *string         `json:"light_coil_register"`
+	ChemistryCoilRegister         *string         `json:"chemistry_coil_register"`
+	ModbusDevice                  *string         `json:"modbus_device" gorm:"type:varchar(100)"`             // Имя ПЛК на modbus сервере (nil - устройство по умолчанию)
+	ModbusRegisters               []BoxRegister   `json:"modbus_registers" gorm:"type:jsonb;serializer:json"` // Holding registers бокса (давление, пена, табло)
+	DeviceChannels                []DeviceChannel `json:"device_channels" gorm:"type:jsonb;serializer:json"`  // Каналы устройств бокса (ворота, пенный пистолет, воск и т.п.)
+	PresenceSensorRegister        *string         `json:"presence_sensor_register" gorm:"type:varchar(10)"`   // Дискретный вход датчика присутствия автомобиля
+	CarPresent                    *bool           `json:"car_present"`                                        // Машина в боксе по датчику (nil - датчика нет или он не прочитан)
+	CarPresentChangedAt           *time.Time      `json:"car_present_changed_at"`
+	Comment                       *string         `json:"comment" gorm:"type:varchar(1000)"`
+	CleaningReservedBy            *uuid.UUID      `json:"cleaning_reserved_by" gorm:"type:uuid;index"`
+	CleaningStartedAt             *time.Time      `json:"cleaning_started_at"`
+	LastCompletedSessionUserID    *uuid.UUID      `json:"last_completed_session_user_id" gorm:"type:uuid;index"`
+	LastCompletedSessionCarNumber *string         `json:"last_completed_session_car_number"`
+	LastCompletedAt               *time.Time      `json:"last_completed_at"`
+	CooldownUntil                 *time.Time      `json:"cooldown_until"`
+	LightStatus                   *bool           `json:"light_status,omitempty" gorm:"-"`     // Статус света (не хранится в БД, заполняется из modbus_connection_statuses)
+	ChemistryStatus               *bool           `json:"chemistry_status,omitempty" gorm:"-"` // Статус химии (не хранится в БД, заполняется из modbus_connection_statuses)
+	CanBeCleaned                  *bool           `json:"can_be_cleaned,omitempty" gorm:"-"`   // Можно ли убирать бокс (не хранится в БД, вычисляется динамически)
+	CreatedAt                     time.Time       `json:"created_at"`
+	UpdatedAt                     time.Time       `json:"updated_at"`
+	DeletedAt                     gorm.DeletedAt  `json:"-" gorm:"index"`
 }
 
 // GetQueueStatusResponse представляет ответ на получение статуса очереди и боксов
@@ -57,27 +65,39 @@ type GetQueueStatusResponse struct {
 
 // AdminCreateWashBoxRequest запрос на создание бокса мойки
 type AdminCreateWashBoxRequest struct {
-	Number                int     `json:"number" binding:"required"`
-	Status                string  `json:"status" binding:"required,oneof=free reserved busy maintenance cleaning"`
-	ServiceType           string  `json:"service_type" binding:"required,oneof=wash air_dry vacuum"`
-	ChemistryEnabled      *bool   `json:"chemistry_enabled"`
-	Priority              string  `json:"priority" binding:"required,len=1"`
-	LightCoilRegister     *string `json:"light_coil_register"`
-	ChemistryCoilRegister *string `json:"chemistry_coil_register"`
-	Comment               *string `json:"comment" binding:"omitempty,max=1000"`
+	Number                 int             `json:"number" binding:"required"`
+	Status                 string          `json:"status" binding:"required,oneof=free reserved busy maintenance cleaning"`
+	ServiceType            string          `json:"service_type" binding:"required,oneof=wash air_dry vacuum"`
+	ChemistryEnabled       *bool           `json:"chemistry_enabled"`
+	Priority               string          `json:"priority" binding:"required,len=1"`
+	Position               int             `json:"position" binding:"min=0"`
+	LightCoilRegister      *string         `json:"light_coil_register"`
+	ChemistryCoilRegister  *string         `json:"chemistry_coil_register"`
+	ModbusDevice           *string         `json:"modbus_device" binding:"omitempty,max=100"`
+	ModbusRegisters        []BoxRegister   `json:"modbus_registers" binding:"omitempty,max=16,dive"`
+	DeviceChannels         []DeviceChannel `json:"device_channels" binding:"omitempty,max=16,dive"`
+	PresenceSensorRegister *string         `json:"presence_sensor_register"`
+	Comment                *string         `json:"comment" binding:"omitempty,max=1000"`
+	SiteID                 *uuid.UUID      `json:"site_id"` // Площадка бокса (по умолчанию основная)
 }
 
 // AdminUpdateWashBoxRequest запрос на обновление бокса мойки
 type AdminUpdateWashBoxRequest struct {
-	ID                    uuid.UUID `json:"id" binding:"required"`
-	Number                *int      `json:"number"`
-	Status                *string   `json:"status" binding:"omitempty,oneof=free reserved busy maintenance cleaning"`
-	ServiceType           *string   `json:"service_type" binding:"omitempty,oneof=wash air_dry vacuum"`
-	ChemistryEnabled      *bool     `json:"chemistry_enabled"`
-	Priority              *string   `json:"priority" binding:"omitempty,len=1"`
-	LightCoilRegister     *string   `json:"light_coil_register"`
-	ChemistryCoilRegister *string   `json:"chemistry_coil_register"`
-	Comment               *string   `json:"comment" binding:"omitempty,max=1000"`
+	ID                     uuid.UUID        `json:"id" binding:"required"`
+	Number                 *int             `json:"number"`
+	Status                 *string          `json:"status" binding:"omitempty,oneof=free reserved busy maintenance cleaning"`
+	ServiceType            *string          `json:"service_type" binding:"omitempty,oneof=wash air_dry vacuum"`
+	ChemistryEnabled       *bool            `json:"chemistry_enabled"`
+	Priority               *string          `json:"priority" binding:"omitempty,len=1"`
+	Position               *int             `json:"position" binding:"omitempty,min=0"`
+	LightCoilRegister      *string          `json:"light_coil_register"`
+	ChemistryCoilRegister  *string          `json:"chemistry_coil_register"`
+	ModbusDevice           *string          `json:"modbus_device" binding:"omitempty,max=100"`
+	ModbusRegisters        *[]BoxRegister   `json:"modbus_registers" binding:"omitempty,max=16,dive"` // Заменяет определения целиком, [] - удаляет все
+	DeviceChannels         *[]DeviceChannel `json:"device_channels" binding:"omitempty,max=16,dive"`  // Заменяет каналы целиком, [] - удаляет все
+	PresenceSensorRegister *string          `json:"presence_sensor_register"`                         // Пустая строка - датчика нет
+	Comment                *string          `json:"comment" binding:"omitempty,max=1000"`
+	SiteID                 *uuid.UUID       `json:"site_id"`
 }
 
 // AdminDeleteWashBoxRequest запрос на удаление бокса мойки
@@ -92,10 +112,11 @@ type AdminGetWashBoxRequest struct {
 
 // AdminListWashBoxesRequest запрос на получение списка боксов мойки
 type AdminListWashBoxesRequest struct {
-	Status      *string `json:"status" binding:"omitempty,oneof=free reserved busy maintenance cleaning"`
-	ServiceType *string `json:"service_type" binding:"omitempty,oneof=wash air_dry vacuum"`
-	Limit       *int    `json:"limit"`
-	Offset      *int    `json:"offset"`
+	Status      *string    `json:"status" binding:"omitempty,oneof=free reserved busy maintenance cleaning"`
+	ServiceType *string    `json:"service_type" binding:"omitempty,oneof=wash air_dry vacuum"`
+	SiteID      *uuid.UUID `json:"site_id"`
+	Limit       *int       `json:"limit"`
+	Offset      *int       `json:"offset"`
 }
 
 // AdminCreateWashBoxResponse ответ на создание бокса мойки
@@ -105,8 +126,9 @@ type AdminCreateWashBoxResponse struct {
 
 // CleanerListWashBoxesRequest запрос на получение списка боксов для уборщика
 type CleanerListWashBoxesRequest struct {
-	Limit  *int `json:"limit"`
-	Offset *int `json:"offset"`
+	Limit  *int      `json:"limit"`
+	Offset *int      `json:"offset"`
+	SiteID uuid.UUID `json:"-"` // Площадка уборщика
 }
 
 // CleanerListWashBoxesResponse ответ на получение списка боксов для уборщика
@@ -118,6 +140,7 @@ type CleanerListWashBoxesResponse struct {
 // CleanerStartCleaningRequest запрос на начало уборки
 type CleanerStartCleaningRequest struct {
 	WashBoxID uuid.UUID `json:"wash_box_id" binding:"required"`
+	SiteID    uuid.UUID `json:"-"` // Площадка уборщика
 }
 
 // CleanerStartCleaningResponse ответ на начало уборки
@@ -187,10 +210,11 @@ type AdminDeleteWashBoxResponse struct {
 
 // CashierListWashBoxesRequest запрос на получение списка боксов мойки для кассира
 type CashierListWashBoxesRequest struct {
-	Status      *string `json:"status" binding:"omitempty,oneof=free reserved busy maintenance"`
-	ServiceType *string `json:"service_type" binding:"omitempty,oneof=wash air_dry vacuum"`
-	Limit       *int    `json:"limit"`
-	Offset      *int    `json:"offset"`
+	Status      *string   `json:"status" binding:"omitempty,oneof=free reserved busy maintenance"`
+	ServiceType *string   `json:"service_type" binding:"omitempty,oneof=wash air_dry vacuum"`
+	SiteID      uuid.UUID `json:"-"` // Площадка кассира
+	Limit       *int      `json:"limit"`
+	Offset      *int      `json:"offset"`
 }
 
 // CashierListWashBoxesResponse ответ на получение списка боксов мойки для кассира
@@ -203,7 +227,8 @@ type CashierListWashBoxesResponse struct {
 
 // CashierSetMaintenanceRequest запрос на перевод бокса в режим обслуживания
 type CashierSetMaintenanceRequest struct {
-	ID uuid.UUID `json:"id" binding:"required"`
+	ID     uuid.UUID `json:"id" binding:"required"`
+	SiteID uuid.UUID `json:"-"` // Площадка кассира
 }
 
 // CashierSetMaintenanceResponse ответ на перевод бокса в режим обслуживания
@@ -211,3 +236,19 @@ type CashierSetMaintenanceResponse struct {
 	WashBox WashBox `json:"wash_box"`
 	Message string  `json:"message"`
 }
+
+// FilterBySite оставляет только боксы указанной площадки
+// Пустой siteID означает все площадки
+func FilterBySite(boxes []WashBox, siteID uuid.UUID) []WashBox {
+	if siteID == uuid.Nil {
+		return boxes
+	}
+
+	filtered := make([]WashBox, 0, len(boxes))
+	for _, box := range boxes {
+		if box.SiteID == siteID {
+			filtered = append(filtered, box)
+		}
+	}
+	return filtered
+}
@@ -25,11 +25,11 @@ type Repository interface {
 	GetWashBoxByNumberIncludingDeleted(ctx context.Context, number int) (*models.WashBox, error)
 	UpdateWashBox(ctx context.Context, box *models.WashBox) (*models.WashBox, error)
 	DeleteWashBox(ctx context.Context, id uuid.UUID) error
-	GetWashBoxesWithFilters(ctx context.Context, status *string, serviceType *string, limit int, offset int) ([]models.WashBox, int, error)
+	GetWashBoxesWithFilters(ctx context.Context, status *string, serviceType *string, siteID *uuid.UUID, limit int, offset int) ([]models.WashBox, int, error)
 	RestoreWashBox(ctx context.Context, id uuid.UUID, status string, serviceType string) (*models.WashBox, error)
 
 	// Методы для уборщиков
-	GetWashBoxesForCleaner(ctx context.Context, limit int, offset int) ([]models.WashBox, int, error)
+	GetWashBoxesForCleaner(ctx context.Context, siteID uuid.UUID, limit int, offset int) ([]models.WashBox, int, error)
 	StartCleaning(ctx context.Context, washBoxID uuid.UUID) error
 	CompleteCleaning(ctx context.Context, washBoxID uuid.UUID) error
 	CompleteCleaningWithStatus(ctx context.Context, washBoxID uuid.UUID, nextStatus string) error
@@ -56,6 +56,10 @@ type Repository interface {
 	// Методы с блокировкой для предотвращения дедлоков
 	GetWashBoxByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.WashBox, error)
 	UpdateWashBoxStatusInTransaction(ctx context.Context, id uuid.UUID, status string) error
+
+	// Методы для журнала выбора боксов
+	CreateAssignmentLog(ctx context.Context, log *models.BoxAssignmentLog) error
+	ListAssignmentLogs(ctx context.Context, req *models.AdminListAssignmentLogsRequest) ([]models.BoxAssignmentLog, int64, error)
 }
 
 // PostgresRepository реализация Repository для PostgreSQL
@@ -164,7 +168,7 @@ func (r *PostgresRepository) DeleteWashBox(ctx context.Context, id uuid.UUID) er
 }
 
 // GetWashBoxesWithFilters получает боксы мойки с фильтрацией
-func (r *PostgresRepository) GetWashBoxesWithFilters(ctx context.Context, status *string, serviceType *string, limit int, offset int) ([]models.WashBox, int, error) {
+func (r *PostgresRepository) GetWashBoxesWithFilters(ctx context.Context, status *string, serviceType *string, siteID *uuid.UUID, limit int, offset int) ([]models.WashBox, int, error) {
 	var boxes []models.WashBox
 	var total int64
 
@@ -179,6 +183,9 @@ func (r *PostgresRepository) GetWashBoxesWithFilters(ctx context.Context, status
 		if serviceType != nil {
 			query = query.Where("service_type = ?", *serviceType)
 		}
+		if siteID != nil {
+			query = query.Where("site_id = ?", *siteID)
+		}
 
 		return query.Count(&total).Error
 	}()
@@ -197,6 +204,9 @@ func (r *PostgresRepository) GetWashBoxesWithFilters(ctx context.Context, status
 		if serviceType != nil {
 			query = query.Where("service_type = ?", *serviceType)
 		}
+		if siteID != nil {
+			query = query.Where("site_id = ?", *siteID)
+		}
 
 		return query.Order("number ASC").Limit(limit).Offset(offset).Find(&boxes).Error
 	}()
@@ -231,18 +241,18 @@ func (r *PostgresRepository) RestoreWashBox(ctx context.Context, id uuid.UUID, s
 }
 
 // GetWashBoxesForCleaner получает список боксов для уборщика
-func (r *PostgresRepository) GetWashBoxesForCleaner(ctx context.Context, limit int, offset int) ([]models.WashBox, int, error) {
+func (r *PostgresRepository) GetWashBoxesForCleaner(ctx context.Context, siteID uuid.UUID, limit int, offset int) ([]models.WashBox, int, error) {
 	var boxes []models.WashBox
 	var total int64
 
 	// Подсчитываем общее количество
-	err := r.db.WithContext(ctx).Model(&models.WashBox{}).Count(&total).Error
+	err := r.db.WithContext(ctx).Model(&models.WashBox{}).Where("site_id = ?", siteID).Count(&total).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Получаем боксы с пагинацией
-	query := r.db.WithContext(ctx).Order("number ASC")
+	// Получаем боксы площадки с пагинацией
+	query := r.db.WithContext(ctx).Where("site_id = ?", siteID).Order("number ASC")
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
@@ -538,3 +548,41 @@ func (r *PostgresRepository) GetWashBoxByIDForUpdate(ctx context.Context, id uui
 func (r *PostgresRepository) UpdateWashBoxStatusInTransaction(ctx context.Context, id uuid.UUID, status string) error {
 	return r.db.WithContext(ctx).Model(&models.WashBox{}).Where("id = ?", id).Update("status", status).Error
 }
+
+// CreateAssignmentLog сохраняет решение о выборе бокса
+func (r *PostgresRepository) CreateAssignmentLog(ctx context.Context, log *models.BoxAssignmentLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// ListAssignmentLogs получает журнал выбора боксов с фильтрами и пагинацией
+func (r *PostgresRepository) ListAssignmentLogs(ctx context.Context, req *models.AdminListAssignmentLogsRequest) ([]models.BoxAssignmentLog, int64, error) {
+	var logs []models.BoxAssignmentLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.BoxAssignmentLog{})
+	if req.SessionID != nil {
+		query = query.Where("session_id = ?", *req.SessionID)
+	}
+	if req.BoxNumber != nil {
+		query = query.Where("box_number = ?", *req.BoxNumber)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := req.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
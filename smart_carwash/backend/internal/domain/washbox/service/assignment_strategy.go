@@ -0,0 +1,271 @@
+package service
+
+import (
+	sessionRepository "carwash_backend/internal/domain/session/repository"
+	"carwash_backend/internal/domain/washbox/models"
+	"carwash_backend/internal/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// BoxAssignmentStrategy стратегия выбора бокса для сессии из очереди
+type BoxAssignmentStrategy interface {
+	// Name возвращает название стратегии, под которым она задается в настройках
+	Name() string
+	// Rank упорядочивает кандидатов от наиболее к наименее предпочтительному
+	Rank(ctx context.Context, candidates []models.WashBox) ([]models.BoxAssignmentCandidate, error)
+}
+
+// newCandidate формирует кандидата для журнала выбора бокса
+func newCandidate(box models.WashBox, score *float64) models.BoxAssignmentCandidate {
+	return models.BoxAssignmentCandidate{
+		BoxID:     box.ID,
+		BoxNumber: box.Number,
+		Priority:  box.Priority,
+		Position:  box.Position,
+		Score:     score,
+	}
+}
+
+// rankByScore упорядочивает боксы по возрастанию оценки, при равенстве - по букве приоритета и номеру
+func rankByScore(boxes []models.WashBox, score func(models.WashBox) float64) []models.BoxAssignmentCandidate {
+	sorted := make([]models.WashBox, len(boxes))
+	copy(sorted, boxes)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, sj := score(sorted[i]), score(sorted[j])
+		if si != sj {
+			return si < sj
+		}
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority < sorted[j].Priority
+		}
+		return sorted[i].Number < sorted[j].Number
+	})
+
+	result := make([]models.BoxAssignmentCandidate, 0, len(sorted))
+	for _, box := range sorted {
+		value := score(box)
+		result = append(result, newCandidate(box, &value))
+	}
+	return result
+}
+
+// priorityLetterStrategy выбирает бокс по букве приоритета (A -> Z), среди равных - случайно
+type priorityLetterStrategy struct{}
+
+// Name возвращает название стратегии
+func (priorityLetterStrategy) Name() string {
+	return models.AssignmentStrategyPriorityLetter
+}
+
+// Rank упорядочивает боксы по букве приоритета с перемешиванием внутри группы
+func (priorityLetterStrategy) Rank(ctx context.Context, candidates []models.WashBox) ([]models.BoxAssignmentCandidate, error) {
+	boxes := make([]models.WashBox, len(candidates))
+	copy(boxes, candidates)
+
+	result := make([]models.BoxAssignmentCandidate, 0, len(boxes))
+	for _, box := range shuffleBoxesWithSamePriority(boxes) {
+		result = append(result, newCandidate(box, nil))
+	}
+	return result, nil
+}
+
+// leastUsedTodayStrategy выбирает бокс с наименьшим временем работы за сегодня (равномерный износ)
+type leastUsedTodayStrategy struct {
+	sessionRepo sessionRepository.Repository
+}
+
+// Name возвращает название стратегии
+func (leastUsedTodayStrategy) Name() string {
+	return models.AssignmentStrategyLeastUsedToday
+}
+
+// Rank упорядочивает боксы по возрастанию минут работы с начала суток
+func (s leastUsedTodayStrategy) Rank(ctx context.Context, candidates []models.WashBox) ([]models.BoxAssignmentCandidate, error) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	busyMinutes, err := s.sessionRepo.GetBusyMinutesSinceForBoxes(ctx, boxIDs(candidates), startOfDay)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения загрузки боксов: %w", err)
+	}
+
+	return rankByScore(candidates, func(box models.WashBox) float64 {
+		return busyMinutes[box.ID]
+	}), nil
+}
+
+// roundRobinStrategy выбирает бокс, на который дольше всех не назначались сессии
+type roundRobinStrategy struct {
+	sessionRepo sessionRepository.Repository
+}
+
+// Name возвращает название стратегии
+func (roundRobinStrategy) Name() string {
+	return models.AssignmentStrategyRoundRobin
+}
+
+// Rank упорядочивает боксы по времени последнего назначения, боксы без назначений - первыми
+func (s roundRobinStrategy) Rank(ctx context.Context, candidates []models.WashBox) ([]models.BoxAssignmentCandidate, error) {
+	lastAssigned, err := s.sessionRepo.GetLastAssignedAtForBoxes(ctx, boxIDs(candidates))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения времени последних назначений: %w", err)
+	}
+
+	return rankByScore(candidates, func(box models.WashBox) float64 {
+		assignedAt, ok := lastAssigned[box.ID]
+		if !ok {
+			return 0
+		}
+		return float64(assignedAt.Unix())
+	}), nil
+}
+
+// nearestToEntranceStrategy выбирает ближайший к въезду бокс
+type nearestToEntranceStrategy struct{}
+
+// Name возвращает название стратегии
+func (nearestToEntranceStrategy) Name() string {
+	return models.AssignmentStrategyNearestToEntrance
+}
+
+// Rank упорядочивает боксы по положению относительно въезда
+func (nearestToEntranceStrategy) Rank(ctx context.Context, candidates []models.WashBox) ([]models.BoxAssignmentCandidate, error) {
+	return rankByScore(candidates, func(box models.WashBox) float64 {
+		return float64(box.Position)
+	}), nil
+}
+
+// boxIDs возвращает ID боксов
+func boxIDs(boxes []models.WashBox) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(boxes))
+	for _, box := range boxes {
+		ids = append(ids, box.ID)
+	}
+	return ids
+}
+
+// assignmentStrategy возвращает стратегию выбора бокса по названию
+func (s *ServiceImpl) assignmentStrategy(name string) BoxAssignmentStrategy {
+	switch name {
+	case models.AssignmentStrategyLeastUsedToday:
+		return leastUsedTodayStrategy{sessionRepo: s.sessionRepo}
+	case models.AssignmentStrategyRoundRobin:
+		return roundRobinStrategy{sessionRepo: s.sessionRepo}
+	case models.AssignmentStrategyNearestToEntrance:
+		return nearestToEntranceStrategy{}
+	default:
+		return priorityLetterStrategy{}
+	}
+}
+
+// SelectBoxForSession выбирает бокс для сессии по стратегии, настроенной для типа услуги
+// Возвращает запись журнала решения; вызывающий сохраняет её через CreateAssignmentLog
+// после того, как назначение бокса зафиксировано
+func (s *ServiceImpl) SelectBoxForSession(ctx context.Context, req *models.SelectBoxRequest) (*models.WashBox, *models.BoxAssignmentLog, error) {
+	if len(req.Candidates) == 0 {
+		return nil, nil, errors.New("нет боксов-кандидатов")
+	}
+
+	strategyName := models.DefaultAssignmentStrategy
+	if s.settingsService != nil {
+		name, err := s.settingsService.GetBoxAssignmentStrategy(ctx, req.ServiceType)
+		if err != nil {
+			logger.Printf("SelectBoxForSession: ошибка получения стратегии, service_type: %s, error: %v", req.ServiceType, err)
+		} else {
+			strategyName = name
+		}
+	}
+
+	strategy := s.assignmentStrategy(strategyName)
+	reason := fmt.Sprintf("стратегия %s", strategy.Name())
+	if strategy.Name() != strategyName {
+		reason = fmt.Sprintf("неизвестная стратегия '%s', использована %s", strategyName, strategy.Name())
+	}
+
+	ranked, err := strategy.Rank(ctx, req.Candidates)
+	if err != nil {
+		// Стратегия не смогла оценить боксы - выбираем по букве приоритета, чтобы не останавливать очередь
+		logger.Printf("SelectBoxForSession: ошибка стратегии %s, используем %s: %v", strategy.Name(), models.AssignmentStrategyPriorityLetter, err)
+		reason = fmt.Sprintf("ошибка стратегии %s (%v), использована %s", strategy.Name(), err, models.AssignmentStrategyPriorityLetter)
+		strategy = priorityLetterStrategy{}
+		ranked, _ = strategy.Rank(ctx, req.Candidates)
+	}
+
+	chosen := ranked[0]
+	var box *models.WashBox
+	for i := range req.Candidates {
+		if req.Candidates[i].ID == chosen.BoxID {
+			box = &req.Candidates[i]
+			break
+		}
+	}
+
+	if req.CooldownMatch {
+		reason += ", выбор среди боксов в кулдауне клиента"
+	}
+	if len(ranked) > 1 {
+		numbers := make([]string, 0, len(ranked)-1)
+		for _, candidate := range ranked[1:] {
+			numbers = append(numbers, fmt.Sprintf("%d", candidate.BoxNumber))
+		}
+		reason += fmt.Sprintf(", альтернативы по порядку: %s", strings.Join(numbers, ", "))
+	}
+	if chosen.Score != nil {
+		reason += fmt.Sprintf(", оценка выбранного бокса: %.2f", *chosen.Score)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"service":        "washbox",
+		"method":         "SelectBoxForSession",
+		"session_id":     req.SessionID,
+		"service_type":   req.ServiceType,
+		"strategy":       strategy.Name(),
+		"box_number":     chosen.BoxNumber,
+		"candidates":     len(ranked),
+		"cooldown_match": req.CooldownMatch,
+	}).Info("Выбран бокс для сессии: " + reason)
+
+	candidatesJSON, err := json.Marshal(ranked)
+	if err != nil {
+		candidatesJSON = []byte("[]")
+	}
+
+	return box, &models.BoxAssignmentLog{
+		SessionID:     req.SessionID,
+		ServiceType:   req.ServiceType,
+		Strategy:      strategy.Name(),
+		BoxID:         chosen.BoxID,
+		BoxNumber:     chosen.BoxNumber,
+		CooldownMatch: req.CooldownMatch,
+		Candidates:    candidatesJSON,
+		Reason:        reason,
+	}, nil
+}
+
+// CreateAssignmentLog сохраняет решение о выборе бокса в журнал
+func (s *ServiceImpl) CreateAssignmentLog(ctx context.Context, log *models.BoxAssignmentLog) error {
+	return s.repo.CreateAssignmentLog(ctx, log)
+}
+
+// AdminListAssignmentLogs получает журнал выбора боксов (админка)
+func (s *ServiceImpl) AdminListAssignmentLogs(ctx context.Context, req *models.AdminListAssignmentLogsRequest) (*models.AdminListAssignmentLogsResponse, error) {
+	logs, total, err := s.repo.ListAssignmentLogs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AdminListAssignmentLogsResponse{
+		Logs:  logs,
+		Total: total,
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	modbusRepository "carwash_backend/internal/domain/modbus/repository"
 	sessionRepository "carwash_backend/internal/domain/session/repository"
 	"carwash_backend/internal/domain/settings/service"
+	siteModels "carwash_backend/internal/domain/site/models"
 	"carwash_backend/internal/domain/washbox/models"
 	"carwash_backend/internal/domain/washbox/repository"
 	"carwash_backend/internal/logger"
@@ -62,6 +63,11 @@ type Service interface {
 	SetCooldownByCarNumber(ctx context.Context, boxID uuid.UUID, carNumber string, cooldownUntil time.Time) error
 	ClearCooldown(ctx context.Context, boxID uuid.UUID) error
 	CheckCooldownExpired(ctx context.Context) error
+
+	// Методы для выбора бокса из очереди
+	SelectBoxForSession(ctx context.Context, req *models.SelectBoxRequest) (*models.WashBox, *models.BoxAssignmentLog, error)
+	CreateAssignmentLog(ctx context.Context, log *models.BoxAssignmentLog) error
+	AdminListAssignmentLogs(ctx context.Context, req *models.AdminListAssignmentLogsRequest) (*models.AdminListAssignmentLogsResponse, error)
 }
 
 // ServiceImpl реализация Service
@@ -243,6 +249,13 @@ func (s *ServiceImpl) AdminCreateWashBox(ctx context.Context, req *models.AdminC
 		if err != nil {
 			return nil, err
 		}
+		// Восстановленный бокс переносим на запрошенную площадку
+		if req.SiteID != nil && *req.SiteID != uuid.Nil && restoredBox.SiteID != *req.SiteID {
+			restoredBox.SiteID = *req.SiteID
+			if restoredBox, err = s.repo.UpdateWashBox(ctx, restoredBox); err != nil {
+				return nil, err
+			}
+		}
 		return &models.AdminCreateWashBoxResponse{
 			WashBox: *restoredBox,
 		}, nil
@@ -254,6 +267,11 @@ func (s *ServiceImpl) AdminCreateWashBox(ctx context.Context, req *models.AdminC
 		Status:      req.Status,
 		ServiceType: req.ServiceType,
 		Priority:    req.Priority,
+		Position:    req.Position,
+		SiteID:      siteModels.DefaultSiteID,
+	}
+	if req.SiteID != nil && *req.SiteID != uuid.Nil {
+		washBox.SiteID = *req.SiteID
 	}
 
 	// Устанавливаем химию по умолчанию в зависимости от типа услуги
@@ -264,6 +282,30 @@ func (s *ServiceImpl) AdminCreateWashBox(ctx context.Context, req *models.AdminC
 		washBox.ChemistryEnabled = req.ServiceType == "wash"
 	}
 
+	washBox.LightCoilRegister = req.LightCoilRegister
+	washBox.ChemistryCoilRegister = req.ChemistryCoilRegister
+	washBox.ModbusDevice = req.ModbusDevice
+	washBox.ModbusRegisters = []models.BoxRegister{}
+	if req.ModbusRegisters != nil {
+		if err := models.ValidateBoxRegisters(req.ModbusRegisters); err != nil {
+			return nil, err
+		}
+		washBox.ModbusRegisters = req.ModbusRegisters
+	}
+	washBox.DeviceChannels = []models.DeviceChannel{}
+	if req.DeviceChannels != nil {
+		if err := models.ValidateDeviceChannels(req.DeviceChannels); err != nil {
+			return nil, err
+		}
+		washBox.DeviceChannels = req.DeviceChannels
+	}
+	if req.PresenceSensorRegister != nil && *req.PresenceSensorRegister != "" {
+		if err := models.ValidateRegister(*req.PresenceSensorRegister); err != nil {
+			return nil, fmt.Errorf("регистр датчика присутствия: %w", err)
+		}
+		washBox.PresenceSensorRegister = req.PresenceSensorRegister
+	}
+
 	createdBox, err := s.repo.CreateWashBox(ctx, washBox)
 	if err != nil {
 		return nil, err
@@ -316,6 +358,10 @@ func (s *ServiceImpl) AdminUpdateWashBox(ctx context.Context, req *models.AdminU
 		existingBox.Priority = *req.Priority
 	}
 
+	if req.Position != nil {
+		existingBox.Position = *req.Position
+	}
+
 	if req.LightCoilRegister != nil {
 		existingBox.LightCoilRegister = req.LightCoilRegister
 	}
@@ -324,30 +370,72 @@ func (s *ServiceImpl) AdminUpdateWashBox(ctx context.Context, req *models.AdminU
 		existingBox.ChemistryCoilRegister = req.ChemistryCoilRegister
 	}
 
+	if req.ModbusDevice != nil {
+		// Пустое имя возвращает бокс на устройство по умолчанию
+		existingBox.ModbusDevice = req.ModbusDevice
+		if *req.ModbusDevice == "" {
+			existingBox.ModbusDevice = nil
+		}
+	}
+
+	if req.ModbusRegisters != nil {
+		if err := models.ValidateBoxRegisters(*req.ModbusRegisters); err != nil {
+			return nil, err
+		}
+		existingBox.ModbusRegisters = *req.ModbusRegisters
+	}
+
+	if req.DeviceChannels != nil {
+		if err := models.ValidateDeviceChannels(*req.DeviceChannels); err != nil {
+			return nil, err
+		}
+		existingBox.DeviceChannels = *req.DeviceChannels
+	}
+
+	if req.PresenceSensorRegister != nil {
+		// Пустая строка означает, что датчика нет
+		var register *string
+		if *req.PresenceSensorRegister != "" {
+			if err := models.ValidateRegister(*req.PresenceSensorRegister); err != nil {
+				return nil, fmt.Errorf("регистр датчика присутствия: %w", err)
+			}
+			register = req.PresenceSensorRegister
+		}
+		// Состояние прежнего датчика не относится к новому
+		if existingBox.PresenceSensorRegister == nil || register == nil || *existingBox.PresenceSensorRegister != *register {
+			existingBox.CarPresent = nil
+			existingBox.CarPresentChangedAt = nil
+		}
+		existingBox.PresenceSensorRegister = register
+	}
+
+	if req.SiteID != nil && *req.SiteID != uuid.Nil && *req.SiteID != existingBox.SiteID {
+		// Переносить на другую площадку можно только бокс без клиента
+		if existingBox.Status == models.StatusReserved || existingBox.Status == models.StatusBusy {
+			return nil, errors.New("нельзя перенести на другую площадку занятый бокс")
+		}
+		existingBox.SiteID = *req.SiteID
+	}
+
 	// Сохраняем изменения
 	updatedBox, err := s.repo.UpdateWashBox(ctx, existingBox)
 	if err != nil {
 		return nil, err
 	}
 
-	// Если вручную перевели бокс в статус free — выключаем свет и химию
+	// Если вручную перевели бокс в статус free — выключаем свет, химию и остальные каналы сессии
+	// Каналами в ручном режиме управляет администратор, их не трогаем
 	if req.Status != nil && *req.Status == models.StatusFree && s.modbusAdapter != nil {
-		// Свет
-		if updatedBox.LightCoilRegister != nil && *updatedBox.LightCoilRegister != "" {
-			if err := s.modbusAdapter.WriteLightCoil(ctx, updatedBox.ID, *updatedBox.LightCoilRegister, false); err != nil {
-				logger.WithFields(logrus.Fields{
-					"washbox_id": updatedBox.ID,
-					"register":   *updatedBox.LightCoilRegister,
-				}).WithError(err).Error("не удалось выключить свет при переводе бокса в 'free'")
+		for _, channel := range updatedBox.Channels() {
+			if channel.ChannelMode() == models.ChannelModeManual {
+				continue
 			}
-		}
-		// Химия
-		if updatedBox.ChemistryCoilRegister != nil && *updatedBox.ChemistryCoilRegister != "" {
-			if err := s.modbusAdapter.WriteChemistryCoil(ctx, updatedBox.ID, *updatedBox.ChemistryCoilRegister, false); err != nil {
+			if err := s.modbusAdapter.WriteChannel(ctx, updatedBox.ID, channel, false); err != nil {
 				logger.WithFields(logrus.Fields{
 					"washbox_id": updatedBox.ID,
-					"register":   *updatedBox.ChemistryCoilRegister,
-				}).WithError(err).Error("не удалось выключить химию при переводе бокса в 'free'")
+					"channel":    channel.Role,
+					"register":   channel.Register,
+				}).WithError(err).Error("не удалось выключить канал при переводе бокса в 'free'")
 			}
 		}
 	}
@@ -411,7 +499,7 @@ func (s *ServiceImpl) AdminListWashBoxes(ctx context.Context, req *models.AdminL
 	}
 
 	// Получаем боксы с фильтрацией
-	boxes, total, err := s.repo.GetWashBoxesWithFilters(ctx, req.Status, req.ServiceType, limit, offset)
+	boxes, total, err := s.repo.GetWashBoxesWithFilters(ctx, req.Status, req.ServiceType, req.SiteID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -482,8 +570,8 @@ func (s *ServiceImpl) CashierListWashBoxes(ctx context.Context, req *models.Cash
 		offset = *req.Offset
 	}
 
-	// Получаем боксы с фильтрацией
-	boxes, total, err := s.repo.GetWashBoxesWithFilters(ctx, req.Status, req.ServiceType, limit, offset)
+	// Получаем боксы площадки кассира с фильтрацией
+	boxes, total, err := s.repo.GetWashBoxesWithFilters(ctx, req.Status, req.ServiceType, &req.SiteID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -500,7 +588,7 @@ func (s *ServiceImpl) CashierListWashBoxes(ctx context.Context, req *models.Cash
 func (s *ServiceImpl) CashierSetMaintenance(ctx context.Context, req *models.CashierSetMaintenanceRequest) (*models.CashierSetMaintenanceResponse, error) {
 	// Получаем бокс по ID
 	washBox, err := s.repo.GetWashBoxByID(ctx, req.ID)
-	if err != nil {
+	if err != nil || washBox.SiteID != req.SiteID {
 		return nil, errors.New("бокс не найден")
 	}
 
@@ -537,7 +625,7 @@ func (s *ServiceImpl) CleanerListWashBoxes(ctx context.Context, req *models.Clea
 		offset = *req.Offset
 	}
 
-	boxes, total, err := s.repo.GetWashBoxesForCleaner(ctx, limit, offset)
+	boxes, total, err := s.repo.GetWashBoxesForCleaner(ctx, req.SiteID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -650,6 +738,9 @@ func (s *ServiceImpl) CleanerStartCleaning(ctx context.Context, req *models.Clea
 	if err != nil {
 		return nil, err
 	}
+	if washBox.SiteID != req.SiteID {
+		return nil, errors.New("бокс не найден")
+	}
 
 	isSpecial := s.isSpecialCleanerBox(washBox)
 
@@ -729,12 +820,12 @@ func (s *ServiceImpl) CleanerStartCleaning(ctx context.Context, req *models.Clea
 		return nil, err
 	}
 
-	// Включаем свет в боксе при начале уборки, если задан регистр и доступен адаптер Modbus
-	if s.modbusAdapter != nil && washBox.LightCoilRegister != nil && *washBox.LightCoilRegister != "" {
-		if err := s.modbusAdapter.WriteLightCoil(ctx, req.WashBoxID, *washBox.LightCoilRegister, true); err != nil {
+	// Включаем свет в боксе при начале уборки, если задан канал света и доступен адаптер Modbus
+	if light, ok := washBox.FindChannel(models.ChannelLight); ok && s.modbusAdapter != nil {
+		if err := s.modbusAdapter.WriteChannel(ctx, req.WashBoxID, light, true); err != nil {
 			logger.WithFields(logrus.Fields{
 				"washbox_id": req.WashBoxID,
-				"register":   *washBox.LightCoilRegister,
+				"register":   light.Register,
 			}).WithError(err).Error("не удалось включить свет при старте уборки")
 		}
 	}
@@ -787,12 +878,12 @@ func (s *ServiceImpl) CleanerCompleteCleaning(ctx context.Context, req *models.C
 		return nil, err
 	}
 
-	// Выключаем свет в боксе при завершении уборки, если задан регистр и доступен адаптер Modbus
-	if s.modbusAdapter != nil && washBox.LightCoilRegister != nil && *washBox.LightCoilRegister != "" {
-		if err := s.modbusAdapter.WriteLightCoil(ctx, req.WashBoxID, *washBox.LightCoilRegister, false); err != nil {
+	// Выключаем свет в боксе при завершении уборки, если задан канал света и доступен адаптер Modbus
+	if light, ok := washBox.FindChannel(models.ChannelLight); ok && s.modbusAdapter != nil {
+		if err := s.modbusAdapter.WriteChannel(ctx, req.WashBoxID, light, false); err != nil {
 			logger.WithFields(logrus.Fields{
 				"washbox_id": req.WashBoxID,
-				"register":   *washBox.LightCoilRegister,
+				"register":   light.Register,
 			}).WithError(err).Error("не удалось выключить свет при завершении уборки")
 		}
 	}
@@ -966,13 +1057,15 @@ func (s *ServiceImpl) AutoCompleteExpiredCleanings(ctx context.Context) error {
 			continue // Продолжаем с другими, если одна не удалась
 		}
 
-		// Пытаемся выключить свет, если известен регистр
-		if s.modbusAdapter != nil && box != nil && box.LightCoilRegister != nil && *box.LightCoilRegister != "" {
-			if err := s.modbusAdapter.WriteLightCoil(ctx, log.WashBoxID, *box.LightCoilRegister, false); err != nil {
-				logger.WithFields(logrus.Fields{
-					"washbox_id": log.WashBoxID,
-					"register":   *box.LightCoilRegister,
-				}).WithError(err).Error("не удалось выключить свет при авто-завершении уборки")
+		// Пытаемся выключить свет, если задан канал света
+		if s.modbusAdapter != nil && box != nil {
+			if light, ok := box.FindChannel(models.ChannelLight); ok {
+				if err := s.modbusAdapter.WriteChannel(ctx, log.WashBoxID, light, false); err != nil {
+					logger.WithFields(logrus.Fields{
+						"washbox_id": log.WashBoxID,
+						"register":   light.Register,
+					}).WithError(err).Error("не удалось выключить свет при авто-завершении уборки")
+				}
 			}
 		}
 	}
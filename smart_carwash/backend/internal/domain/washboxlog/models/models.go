@@ -31,6 +31,15 @@ const (
 	ActionChemistryOff    ActionType = "chemistry_off"
 )
 
+// ChannelAction возвращает действие включения или выключения канала устройства бокса
+// Для света и химии совпадает с ActionLightOn, ActionChemistryOff и т.п.
+func ChannelAction(role string, on bool) ActionType {
+	if on {
+		return ActionType(role + "_on")
+	}
+	return ActionType(role + "_off")
+}
+
 // WashBoxChangeLog запись об изменении состояния бокса
 type WashBoxChangeLog struct {
 	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
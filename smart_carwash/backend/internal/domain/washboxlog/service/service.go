@@ -1,6 +1,9 @@
 package service
 
 import (
+	realtimeModels "carwash_backend/internal/domain/realtime/models"
+	realtimeService "carwash_backend/internal/domain/realtime/service"
+	washboxModels "carwash_backend/internal/domain/washbox/models"
 	washboxRepository "carwash_backend/internal/domain/washbox/repository"
 	"carwash_backend/internal/domain/washboxlog/models"
 	"carwash_backend/internal/domain/washboxlog/repository"
@@ -19,12 +22,18 @@ type Service interface {
 type ServiceImpl struct {
 	repo        repository.Repository
 	washboxRepo washboxRepository.Repository
+	publisher   realtimeService.Publisher // Опциональная отправка изменений в поток событий
 }
 
 func NewService(repo repository.Repository, washboxRepo washboxRepository.Repository) *ServiceImpl {
 	return &ServiceImpl{repo: repo, washboxRepo: washboxRepo}
 }
 
+// SetEventPublisher устанавливает отправку изменений боксов в поток событий
+func (s *ServiceImpl) SetEventPublisher(publisher realtimeService.Publisher) {
+	s.publisher = publisher
+}
+
 // deriveActorType пытается извлечь тип актера из контекста
 func deriveActorType(ctx context.Context) string {
 	// приоритет: dahua/anpr -> роль -> кассир -> уборщик -> пользователь -> system
@@ -54,20 +63,21 @@ func (s *ServiceImpl) shouldSkip(boxNumber int) bool {
 	return boxNumber == 40
 }
 
-func (s *ServiceImpl) getBoxNumber(ctx context.Context, boxID uuid.UUID) (int, error) {
+func (s *ServiceImpl) getBox(ctx context.Context, boxID uuid.UUID) (*washboxModels.WashBox, error) {
 	box, err := s.washboxRepo.GetWashBoxByID(ctx, boxID)
 	if err != nil || box == nil {
-		return 0, err
+		return &washboxModels.WashBox{ID: boxID}, err
 	}
-	return box.Number, nil
+	return box, nil
 }
 
 func (s *ServiceImpl) RecordStatusChange(ctx context.Context, boxID uuid.UUID, oldStatus string, newStatus string, source *string) error {
-	// Получаем номер бокса
-	boxNumber, err := s.getBoxNumber(ctx, boxID)
+	// Получаем бокс
+	box, err := s.getBox(ctx, boxID)
 	if err != nil {
 		return err
 	}
+	boxNumber := box.Number
 	// Пропускаем специальный бокс
 	if s.shouldSkip(boxNumber) {
 		return nil
@@ -86,15 +96,31 @@ func (s *ServiceImpl) RecordStatusChange(ctx context.Context, boxID uuid.UUID, o
 		Source:     source,
 		CreatedAt:  time.Now(),
 	}
+	if s.publisher != nil {
+		s.publisher.Publish(realtimeModels.Event{
+			Type:     realtimeModels.EventTypeBoxStatus,
+			Channels: []string{realtimeModels.ChannelCashier, realtimeModels.ChannelAdmin},
+			SiteID:   box.SiteID,
+			Data: realtimeModels.BoxStatusEvent{
+				BoxID:       boxID,
+				BoxNumber:   boxNumber,
+				ServiceType: box.ServiceType,
+				PrevStatus:  oldStatus,
+				Status:      newStatus,
+				ActorType:   actor,
+			},
+		})
+	}
 	return s.repo.Insert(ctx, item)
 }
 
 func (s *ServiceImpl) RecordCoilChange(ctx context.Context, boxID uuid.UUID, action models.ActionType, prevValue *bool, newValue bool, source *string) error {
-	// Получаем номер бокса
-	boxNumber, err := s.getBoxNumber(ctx, boxID)
+	// Получаем бокс
+	box, err := s.getBox(ctx, boxID)
 	if err != nil {
 		return err
 	}
+	boxNumber := box.Number
 	// Пропускаем специальный бокс
 	if s.shouldSkip(boxNumber) {
 		return nil
@@ -112,6 +138,22 @@ func (s *ServiceImpl) RecordCoilChange(ctx context.Context, boxID uuid.UUID, act
 		Source:    source,
 		CreatedAt: time.Now(),
 	}
+	if s.publisher != nil {
+		// Состояние катушек нужно только администратору
+		s.publisher.Publish(realtimeModels.Event{
+			Type:     realtimeModels.EventTypeCoilState,
+			Channels: []string{realtimeModels.ChannelAdmin},
+			SiteID:   box.SiteID,
+			Data: realtimeModels.CoilStateEvent{
+				BoxID:     boxID,
+				BoxNumber: boxNumber,
+				Action:    string(action),
+				PrevValue: prevValue,
+				Value:     newValue,
+				ActorType: actor,
+			},
+		})
+	}
 	return s.repo.Insert(ctx, item)
 }
 
@@ -22,6 +22,7 @@ type Metrics struct {
 	QueueMetrics          *prometheus.GaugeVec
 	ErrorMetrics          *prometheus.CounterVec
 	MultipleSessionMetrics *prometheus.CounterVec
+	ModbusOutboxMetrics    *prometheus.GaugeVec
 }
 
 // NewMetrics создает новый экземпляр метрик
@@ -99,6 +100,13 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"type", "user_id", "time_diff"},
 		),
+		ModbusOutboxMetrics: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "modbus_outbox_commands",
+				Help: "Number of pending Modbus commands in the outbox",
+			},
+			[]string{"state"},
+		),
 	}
 }
 
@@ -167,3 +175,8 @@ func (m *Metrics) RecordMultipleSession(sessionType, userID, timeDiff string) {
 func (m *Metrics) UpdateDatabaseConnections(state string, count float64) {
 	m.DatabaseConnections.WithLabelValues(state).Set(count)
 }
+
+// UpdateModbusOutbox обновляет количество команд в очереди Modbus (state: pending, overdue)
+func (m *Metrics) UpdateModbusOutbox(state string, count float64) {
+	m.ModbusOutboxMetrics.WithLabelValues(state).Set(count)
+}
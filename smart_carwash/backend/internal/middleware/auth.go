@@ -37,8 +37,11 @@ func CashierMiddleware(authService authService.Service) gin.HandlerFunc {
 			return
 		}
 
-		// Устанавливаем ID кассира в контекст
+		// Устанавливаем ID кассира и его площадку в контекст
 		c.Set("cashier_id", claims.ID)
+		if claims.SiteID != nil {
+			c.Set(SiteIDContextKey, *claims.SiteID)
+		}
 		c.Next()
 	}
 }
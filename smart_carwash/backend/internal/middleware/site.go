@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"fmt"
+
+	siteModels "carwash_backend/internal/domain/site/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SiteIDContextKey ключ контекста с площадкой, определенной по учетным данным запроса
+const SiteIDContextKey = "site_id"
+
+// SiteIDFromContext возвращает площадку, определенную middleware по учетным данным
+// Если площадка не определена (старый токен, общий ключ), возвращается площадка по умолчанию
+func SiteIDFromContext(c *gin.Context) uuid.UUID {
+	if value, exists := c.Get(SiteIDContextKey); exists {
+		if siteID, ok := value.(uuid.UUID); ok && siteID != uuid.Nil {
+			return siteID
+		}
+	}
+	return siteModels.DefaultSiteID
+}
+
+// SiteIDFromQuery разбирает необязательный параметр site_id
+// Возвращает nil, если параметр не передан
+func SiteIDFromQuery(c *gin.Context) (*uuid.UUID, error) {
+	value := c.Query("site_id")
+	if value == "" {
+		return nil, nil
+	}
+
+	siteID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный site_id: %w", err)
+	}
+	return &siteID, nil
+}
+
+// RequestSiteID определяет площадку запроса: параметр site_id, затем площадка из учетных данных,
+// иначе площадка по умолчанию
+func RequestSiteID(c *gin.Context) (uuid.UUID, error) {
+	siteID, err := SiteIDFromQuery(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if siteID != nil && *siteID != uuid.Nil {
+		return *siteID, nil
+	}
+	return SiteIDFromContext(c), nil
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	userService "carwash_backend/internal/domain/user/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// TelegramUserIDContextKey ключ контекста с ID пользователя, подтвержденного данными Telegram
+	TelegramUserIDContextKey = "telegram_user_id"
+	// telegramInitDataHeader заголовок с initData мини-приложения Telegram
+	telegramInitDataHeader = "X-Telegram-Init-Data"
+	// telegramInitDataMaxAge срок действия initData с момента авторизации в Telegram
+	telegramInitDataMaxAge = 24 * time.Hour
+)
+
+// TelegramUserMiddleware создает middleware для проверки пользователя мини-приложения Telegram
+// initData передается в заголовке X-Telegram-Init-Data или в параметре init_data
+// (EventSource в браузере не умеет передавать заголовки). Подпись проверяется токеном бота,
+// ID пользователя берется только из подписанных данных
+func TelegramUserMiddleware(botToken string, userService userService.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		initData := c.GetHeader(telegramInitDataHeader)
+		if initData == "" {
+			initData = c.Query("init_data")
+		}
+
+		if initData == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Данные Telegram не предоставлены"})
+			c.Abort()
+			return
+		}
+
+		telegramID, err := ValidateTelegramInitData(initData, botToken, time.Now())
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительные данные Telegram"})
+			c.Abort()
+			return
+		}
+
+		user, err := userService.GetUserByTelegramID(c.Request.Context(), telegramID)
+		if err != nil || user == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
+			c.Abort()
+			return
+		}
+
+		c.Set(TelegramUserIDContextKey, user.ID)
+		c.Next()
+	}
+}
+
+// ValidateTelegramInitData проверяет подпись initData мини-приложения Telegram и возвращает Telegram ID пользователя
+// Алгоритм: секрет = HMAC-SHA256("WebAppData", токен бота), подпись = HMAC-SHA256(секрет, строка проверки),
+// где строка проверки - пары key=value без hash, отсортированные по ключу и разделенные переводом строки
+func ValidateTelegramInitData(initData, botToken string, now time.Time) (int64, error) {
+	if botToken == "" {
+		return 0, errors.New("токен бота не настроен")
+	}
+
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return 0, err
+	}
+
+	hash := values.Get("hash")
+	if hash == "" {
+		return 0, errors.New("нет подписи")
+	}
+
+	pairs := make([]string, 0, len(values))
+	for key := range values {
+		if key == "hash" {
+			continue
+		}
+		pairs = append(pairs, key+"="+values.Get(key))
+	}
+	sort.Strings(pairs)
+
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+	mac := hmac.New(sha256.New, secret.Sum(nil))
+	mac.Write([]byte(strings.Join(pairs, "\n")))
+
+	expected, err := hex.DecodeString(hash)
+	if err != nil || !hmac.Equal(mac.Sum(nil), expected) {
+		return 0, errors.New("неверная подпись")
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return 0, errors.New("некорректный auth_date")
+	}
+	if now.Sub(time.Unix(authDate, 0)) > telegramInitDataMaxAge {
+		return 0, errors.New("данные устарели")
+	}
+
+	var user struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(values.Get("user")), &user); err != nil || user.ID == 0 {
+		return 0, errors.New("нет пользователя")
+	}
+
+	return user.ID, nil
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -9,6 +11,196 @@ var RussianToEnglish = map[rune]rune{
 	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M',
 	'Н': 'H', 'О': 'O', 'Р': 'P', 'С': 'C', 'Т': 'T',
 	'У': 'Y', 'Х': 'X',
+	'І': 'I', // Белорусская и украинская "І"
+}
+
+// Коды стран госномеров (ISO 3166-1 alpha-3)
+const (
+	CountryRUS = "RUS"
+	CountryBLR = "BLR"
+	CountryKAZ = "KAZ"
+	CountryARM = "ARM"
+	CountryKGZ = "KGZ"
+	CountryUZB = "UZB"
+	CountryUKR = "UKR"
+
+	// DefaultCarNumberCountry страна госномера по умолчанию
+	DefaultCarNumberCountry = CountryRUS
+)
+
+// Коды ошибок валидации госномера
+const (
+	LicensePlateErrorEmpty              = "car_number_empty"
+	LicensePlateErrorUnsupportedCountry = "car_number_country_unsupported"
+	LicensePlateErrorInvalidFormat      = "car_number_invalid_format"
+)
+
+// LicensePlateValidationError структурированная ошибка валидации госномера
+type LicensePlateValidationError struct {
+	Code    string `json:"code"`    // Машиночитаемый код ошибки
+	Field   string `json:"field"`   // Поле запроса, к которому относится ошибка
+	Country string `json:"country"` // Страна, по правилам которой проверялся номер
+	Value   string `json:"value"`   // Исходное значение номера
+	Message string `json:"error"`   // Сообщение для пользователя
+}
+
+// Error реализует интерфейс error
+func (e *LicensePlateValidationError) Error() string {
+	return e.Message
+}
+
+// plateFormat описывает одну серию госномеров страны
+type plateFormat struct {
+	series  string         // Название серии (standard, taxi, military и т.д.)
+	pattern *regexp.Regexp // Шаблон нормализованного номера с группами
+	layout  string         // Каноническое отображение номера
+}
+
+// Буквы, допустимые в госномерах (после перевода кириллицы в латиницу)
+const (
+	rusLetters = "ABEKMHOPCTYX"
+	blrLetters = "ABEIKMHOPCTX"
+	ukrLetters = "ABCEHIKMOPTX"
+
+	// rusRegion код региона РФ: две цифры или трехзначные коды (102, 716, 799, 977 и т.д.)
+	// Ограничение первой цифры убирает неоднозначность между сериями такси и прицепов
+	rusRegion = `(\d{2}|[1279]\d{2})`
+)
+
+// plateFormats шаблоны госномеров по странам
+// Номера проверяются в нормализованном виде: без пробелов и дефисов, латиницей
+var plateFormats = map[string][]plateFormat{
+	CountryRUS: {
+		{"standard", regexp.MustCompile(`^([` + rusLetters + `])(\d{3})([` + rusLetters + `]{2})` + rusRegion + `$`), "$1 $2 $3 $4"},
+		{"taxi", regexp.MustCompile(`^([` + rusLetters + `]{2})(\d{3})` + rusRegion + `$`), "$1 $2 $3"},
+		{"trailer", regexp.MustCompile(`^([` + rusLetters + `]{2})(\d{4})` + rusRegion + `$`), "$1 $2 $3"},
+		{"police", regexp.MustCompile(`^([` + rusLetters + `])(\d{4})` + rusRegion + `$`), "$1 $2 $3"},
+		{"military", regexp.MustCompile(`^(\d{4})([` + rusLetters + `]{2})` + rusRegion + `$`), "$1 $2 $3"},
+		{"diplomatic", regexp.MustCompile(`^(\d{3})(CD)(\d)` + rusRegion + `$`), "$1 $2 $3 $4"},
+		{"diplomatic", regexp.MustCompile(`^(\d{3})([DT])(\d{3})` + rusRegion + `$`), "$1 $2 $3 $4"},
+	},
+	CountryBLR: {
+		{"standard", regexp.MustCompile(`^(\d{4})([` + blrLetters + `]{2})([0-8])$`), "$1 $2-$3"},
+		{"electric", regexp.MustCompile(`^(E)(\d{3})([` + blrLetters + `]{2})([0-8])$`), "$1 $2 $3-$4"},
+		{"trailer", regexp.MustCompile(`^([` + blrLetters + `])(\d{4})([` + blrLetters + `])([0-8])$`), "$1$2 $3-$4"},
+	},
+	CountryKAZ: {
+		{"standard", regexp.MustCompile(`^(\d{3})([A-Z]{3})(\d{2})$`), "$1 $2 $3"},
+		{"legal", regexp.MustCompile(`^(\d{3})([A-Z]{2})(\d{2})$`), "$1 $2 $3"},
+		{"legacy", regexp.MustCompile(`^([A-Z])(\d{3})([A-Z]{2,3})$`), "$1 $2 $3"},
+	},
+	CountryARM: {
+		{"standard", regexp.MustCompile(`^(\d{2})([A-Z]{2})(\d{3})$`), "$1 $2 $3"},
+		{"legacy", regexp.MustCompile(`^(\d{3})([A-Z]{2})(\d{2})$`), "$1 $2 $3"},
+	},
+	CountryKGZ: {
+		{"standard", regexp.MustCompile(`^(\d{2})(\d{3})([A-Z]{3})$`), "$1 $2 $3"},
+		{"legal", regexp.MustCompile(`^(\d{2})(\d{3})([A-Z]{2})$`), "$1 $2 $3"},
+		{"legacy", regexp.MustCompile(`^([A-Z])(\d{4})([A-Z]{2})$`), "$1 $2 $3"},
+	},
+	CountryUZB: {
+		{"standard", regexp.MustCompile(`^(\d{2})([A-Z])(\d{3})([A-Z]{2})$`), "$1 $2 $3 $4"},
+		{"legal", regexp.MustCompile(`^(\d{2})(\d{3})([A-Z]{3})$`), "$1 $2 $3"},
+	},
+	CountryUKR: {
+		{"standard", regexp.MustCompile(`^([` + ukrLetters + `]{2})(\d{4})([` + ukrLetters + `]{2})$`), "$1 $2 $3"},
+		{"legacy", regexp.MustCompile(`^(\d{3})(\d{2})([` + ukrLetters + `]{2})$`), "$1-$2 $3"},
+	},
+}
+
+// ParsedLicensePlate результат разбора госномера
+type ParsedLicensePlate struct {
+	Country    string // Страна госномера
+	Normalized string // Нормализованный номер (хранится в БД и используется для поиска)
+	Formatted  string // Каноническое отображение номера
+	Series     string // Серия номера (standard, taxi, military и т.д.)
+}
+
+// IsSupportedCarNumberCountry проверяет, поддерживается ли страна госномера
+func IsSupportedCarNumberCountry(country string) bool {
+	_, ok := plateFormats[NormalizeCarNumberCountry(country)]
+	return ok
+}
+
+// NormalizeCarNumberCountry приводит код страны к верхнему регистру
+// Пустое значение заменяется страной по умолчанию
+func NormalizeCarNumberCountry(country string) string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		return DefaultCarNumberCountry
+	}
+	return country
+}
+
+// ParseLicensePlate нормализует и проверяет госномер по правилам страны
+// Возвращает *LicensePlateValidationError если номер не соответствует ни одной серии
+func ParseLicensePlate(licensePlate, country string) (*ParsedLicensePlate, error) {
+	country = NormalizeCarNumberCountry(country)
+
+	formats, ok := plateFormats[country]
+	if !ok {
+		return nil, &LicensePlateValidationError{
+			Code:    LicensePlateErrorUnsupportedCountry,
+			Field:   "car_number_country",
+			Country: country,
+			Value:   licensePlate,
+			Message: fmt.Sprintf("страна госномера '%s' не поддерживается", country),
+		}
+	}
+
+	normalized := NormalizeLicensePlate(strings.TrimSpace(licensePlate))
+	if normalized == "" {
+		return nil, &LicensePlateValidationError{
+			Code:    LicensePlateErrorEmpty,
+			Field:   "car_number",
+			Country: country,
+			Value:   licensePlate,
+			Message: "госномер не указан",
+		}
+	}
+
+	for _, format := range formats {
+		if format.pattern.MatchString(normalized) {
+			return &ParsedLicensePlate{
+				Country:    country,
+				Normalized: normalized,
+				Formatted:  format.pattern.ReplaceAllString(normalized, format.layout),
+				Series:     format.series,
+			}, nil
+		}
+	}
+
+	return nil, &LicensePlateValidationError{
+		Code:    LicensePlateErrorInvalidFormat,
+		Field:   "car_number",
+		Country: country,
+		Value:   licensePlate,
+		Message: fmt.Sprintf("неверный формат госномера '%s' для страны %s", licensePlate, country),
+	}
+}
+
+// ValidateLicensePlate проверяет госномер и возвращает его нормализованный вид
+func ValidateLicensePlate(licensePlate, country string) (string, error) {
+	parsed, err := ParseLicensePlate(licensePlate, country)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Normalized, nil
+}
+
+// FormatLicensePlate возвращает каноническое отображение госномера
+// Если номер не соответствует формату страны, возвращается нормализованный номер
+// Примеры:
+//
+//	"а123вс77", "RUS" -> "A 123 BC 77"
+//	"1234АВ7", "BLR" -> "1234 AB-7"
+//	"123ABC02", "KAZ" -> "123 ABC 02"
+func FormatLicensePlate(licensePlate, country string) string {
+	parsed, err := ParseLicensePlate(licensePlate, country)
+	if err != nil {
+		return NormalizeLicensePlate(licensePlate)
+	}
+	return parsed.Formatted
 }
 
 // NormalizeLicensePlate нормализует госномер без валидации
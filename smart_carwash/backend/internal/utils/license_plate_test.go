@@ -206,3 +206,134 @@ func TestReplaceRussianWithEnglish(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLicensePlate(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		country    string
+		normalized string
+		formatted  string
+		series     string
+	}{
+		// Россия
+		{"RUS standard", "а123вс77", "RUS", "A123BC77", "A 123 BC 77", "standard"},
+		{"RUS standard 3-digit region", "О111ОО799", "RUS", "O111OO799", "O 111 OO 799", "standard"},
+		{"RUS default country", "А123ВС77", "", "A123BC77", "A 123 BC 77", "standard"},
+		{"RUS taxi", "АВ12377", "RUS", "AB12377", "AB 123 77", "taxi"},
+		{"RUS trailer", "АВ123477", "RUS", "AB123477", "AB 1234 77", "trailer"},
+		{"RUS police", "А123477", "RUS", "A123477", "A 1234 77", "police"},
+		{"RUS military", "1234АВ50", "RUS", "1234AB50", "1234 AB 50", "military"},
+		{"RUS diplomatic CD", "001CD177", "RUS", "001CD177", "001 CD 1 77", "diplomatic"},
+		{"RUS diplomatic D", "123D45677", "RUS", "123D45677", "123 D 456 77", "diplomatic"},
+
+		// Беларусь
+		{"BLR standard", "1234 АВ-7", "BLR", "1234AB7", "1234 AB-7", "standard"},
+		{"BLR standard with І", "1234 ІК-1", "BLR", "1234IK1", "1234 IK-1", "standard"},
+		{"BLR electric", "E123AB7", "BLR", "E123AB7", "E 123 AB-7", "electric"},
+		{"BLR trailer", "A1234B-7", "BLR", "A1234B7", "A1234 B-7", "trailer"},
+
+		// Казахстан
+		{"KAZ standard", "123 ABC 02", "kaz", "123ABC02", "123 ABC 02", "standard"},
+		{"KAZ legal", "123AB02", "KAZ", "123AB02", "123 AB 02", "legal"},
+		{"KAZ legacy", "A123BCD", "KAZ", "A123BCD", "A 123 BCD", "legacy"},
+
+		// Армения
+		{"ARM standard", "12 AB 345", "ARM", "12AB345", "12 AB 345", "standard"},
+		{"ARM legacy", "123AB45", "ARM", "123AB45", "123 AB 45", "legacy"},
+
+		// Киргизия
+		{"KGZ standard", "01 123 ABC", "KGZ", "01123ABC", "01 123 ABC", "standard"},
+		{"KGZ legal", "01123AB", "KGZ", "01123AB", "01 123 AB", "legal"},
+		{"KGZ legacy", "B1234AB", "KGZ", "B1234AB", "B 1234 AB", "legacy"},
+
+		// Узбекистан
+		{"UZB standard", "01 A 123 BC", "UZB", "01A123BC", "01 A 123 BC", "standard"},
+		{"UZB legal", "01123ABC", "UZB", "01123ABC", "01 123 ABC", "legal"},
+
+		// Украина
+		{"UKR standard", "АА 1234 ВВ", "UKR", "AA1234BB", "AA 1234 BB", "standard"},
+		{"UKR legacy", "123-45 КА", "UKR", "12345KA", "123-45 KA", "legacy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseLicensePlate(tt.input, tt.country)
+			if err != nil {
+				t.Fatalf("ParseLicensePlate() unexpected error: %v", err)
+			}
+			if parsed.Normalized != tt.normalized {
+				t.Errorf("ParseLicensePlate() normalized = %v, want %v", parsed.Normalized, tt.normalized)
+			}
+			if parsed.Formatted != tt.formatted {
+				t.Errorf("ParseLicensePlate() formatted = %v, want %v", parsed.Formatted, tt.formatted)
+			}
+			if parsed.Series != tt.series {
+				t.Errorf("ParseLicensePlate() series = %v, want %v", parsed.Series, tt.series)
+			}
+		})
+	}
+}
+
+func TestParseLicensePlateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		country string
+		code    string
+		field   string
+	}{
+		{"Empty plate", "", "RUS", LicensePlateErrorEmpty, "car_number"},
+		{"Only spaces and dashes", " - ", "RUS", LicensePlateErrorEmpty, "car_number"},
+		{"Unsupported country", "A123BC77", "DEU", LicensePlateErrorUnsupportedCountry, "car_number_country"},
+		{"RUS invalid letter", "Б123ВС77", "RUS", LicensePlateErrorInvalidFormat, "car_number"},
+		{"RUS latin letter not in alphabet", "Z123BC77", "RUS", LicensePlateErrorInvalidFormat, "car_number"},
+		{"RUS too short", "A12BC77", "RUS", LicensePlateErrorInvalidFormat, "car_number"},
+		{"RUS region too long", "A123BC7777", "RUS", LicensePlateErrorInvalidFormat, "car_number"},
+		{"BLR region out of range", "1234AB9", "BLR", LicensePlateErrorInvalidFormat, "car_number"},
+		{"RUS plate for BLR", "A123BC77", "BLR", LicensePlateErrorInvalidFormat, "car_number"},
+		{"UKR invalid letter", "AA1234ZZ", "UKR", LicensePlateErrorInvalidFormat, "car_number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLicensePlate(tt.input, tt.country)
+			if err == nil {
+				t.Fatalf("ParseLicensePlate() expected error, got nil")
+			}
+			validationErr, ok := err.(*LicensePlateValidationError)
+			if !ok {
+				t.Fatalf("ParseLicensePlate() error type = %T, want *LicensePlateValidationError", err)
+			}
+			if validationErr.Code != tt.code {
+				t.Errorf("ParseLicensePlate() code = %v, want %v", validationErr.Code, tt.code)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("ParseLicensePlate() field = %v, want %v", validationErr.Field, tt.field)
+			}
+		})
+	}
+}
+
+func TestFormatLicensePlate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		country  string
+		expected string
+	}{
+		{"RUS valid", "а123вс77", "RUS", "A 123 BC 77"},
+		{"BLR valid", "1234АВ7", "BLR", "1234 AB-7"},
+		{"Invalid falls back to normalized", "Б-123", "RUS", "Б123"},
+		{"Empty", "", "RUS", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatLicensePlate(tt.input, tt.country)
+			if result != tt.expected {
+				t.Errorf("FormatLicensePlate() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+ALTER TABLE sessions DROP COLUMN IF EXISTS is_vip;
+DROP TABLE IF EXISTS plate_list_blocks;
+DROP TABLE IF EXISTS plate_list_entries;
@@ -0,0 +1,38 @@
+-- Списки госномеров: черный список и VIP
+CREATE TABLE IF NOT EXISTS plate_list_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    car_number VARCHAR(20) NOT NULL,
+    car_number_country VARCHAR(3) NOT NULL DEFAULT 'RUS',
+    list_type VARCHAR(16) NOT NULL,
+    vip_privilege VARCHAR(16),
+    reason TEXT NOT NULL,
+    expires_at TIMESTAMP,
+    created_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+-- Один номер может быть только один раз в каждом списке
+CREATE UNIQUE INDEX IF NOT EXISTS idx_plate_list_entries_car_number_list_type
+    ON plate_list_entries(car_number, list_type) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_plate_list_entries_list_type ON plate_list_entries(list_type);
+CREATE INDEX IF NOT EXISTS idx_plate_list_entries_deleted_at ON plate_list_entries(deleted_at);
+
+-- Журнал блокировок по черному списку
+CREATE TABLE IF NOT EXISTS plate_list_blocks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entry_id UUID NOT NULL REFERENCES plate_list_entries(id) ON DELETE CASCADE,
+    car_number VARCHAR(20) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    user_id UUID,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_plate_list_blocks_car_number ON plate_list_blocks(car_number);
+CREATE INDEX IF NOT EXISTS idx_plate_list_blocks_source ON plate_list_blocks(source);
+CREATE INDEX IF NOT EXISTS idx_plate_list_blocks_created_at ON plate_list_blocks(created_at);
+
+-- Признак VIP сессии для приоритета в очереди
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS is_vip BOOLEAN NOT NULL DEFAULT FALSE;
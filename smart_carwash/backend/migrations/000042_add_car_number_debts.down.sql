@@ -0,0 +1,2 @@
+ALTER TABLE sessions DROP COLUMN IF EXISTS debt_amount;
+DROP TABLE IF EXISTS car_number_debts;
@@ -0,0 +1,27 @@
+-- Долги за простой в боксе после завершения сессии (по данным ANPR о выезде)
+CREATE TABLE IF NOT EXISTS car_number_debts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    car_number VARCHAR(20) NOT NULL,
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    box_id UUID,
+    session_ended_at TIMESTAMP NOT NULL,
+    exit_at TIMESTAMP NOT NULL,
+    overtime_minutes INTEGER NOT NULL,
+    price_per_minute INTEGER NOT NULL,
+    amount INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    settled_via VARCHAR(16),
+    settled_session_id UUID,
+    settled_at TIMESTAMP,
+    comment TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Простой по одной сессии тарифицируется один раз
+CREATE UNIQUE INDEX IF NOT EXISTS idx_car_number_debts_session_id ON car_number_debts(session_id);
+CREATE INDEX IF NOT EXISTS idx_car_number_debts_car_number_status ON car_number_debts(car_number, status);
+CREATE INDEX IF NOT EXISTS idx_car_number_debts_created_at ON car_number_debts(created_at);
+
+-- Сумма долга, включенная в основной платеж сессии
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS debt_amount INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Удаляем время назначения бокса, старта и завершения сессии
+DROP INDEX IF EXISTS idx_sessions_service_type_completed_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS assigned_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS started_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,7 @@
+-- Добавляем время назначения бокса, старта и завершения сессии для расчета времени ожидания в очереди
+ALTER TABLE sessions ADD COLUMN assigned_at TIMESTAMP;
+ALTER TABLE sessions ADD COLUMN started_at TIMESTAMP;
+ALTER TABLE sessions ADD COLUMN completed_at TIMESTAMP;
+
+-- Индекс для выборки статистики длительности завершенных сессий
+CREATE INDEX idx_sessions_service_type_completed_at ON sessions(service_type, completed_at) WHERE status = 'complete';
@@ -0,0 +1,5 @@
+-- Удаляем настройки стратегии выбора бокса
+DELETE FROM service_settings WHERE setting_key = 'box_assignment_strategy';
+
+DROP TABLE IF EXISTS box_assignment_logs;
+ALTER TABLE wash_boxes DROP COLUMN IF EXISTS position;
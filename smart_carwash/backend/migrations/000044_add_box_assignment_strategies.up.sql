@@ -0,0 +1,28 @@
+-- Положение бокса относительно въезда (меньше - ближе) для стратегии nearest_to_entrance
+ALTER TABLE wash_boxes ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+-- Журнал решений о выборе бокса для сессии
+CREATE TABLE IF NOT EXISTS box_assignment_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL,
+    service_type VARCHAR(32) NOT NULL,
+    strategy VARCHAR(32) NOT NULL,
+    box_id UUID NOT NULL,
+    box_number INTEGER NOT NULL,
+    cooldown_match BOOLEAN NOT NULL DEFAULT FALSE,
+    candidates JSONB NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_box_assignment_logs_session_id ON box_assignment_logs(session_id);
+CREATE INDEX IF NOT EXISTS idx_box_assignment_logs_box_number ON box_assignment_logs(box_number);
+CREATE INDEX IF NOT EXISTS idx_box_assignment_logs_created_at ON box_assignment_logs(created_at);
+
+-- Стратегия выбора бокса по умолчанию для каждого типа услуги
+INSERT INTO service_settings (service_type, setting_key, setting_value, created_at, updated_at)
+VALUES
+    ('wash', 'box_assignment_strategy', '"priority_letter"', NOW(), NOW()),
+    ('air_dry', 'box_assignment_strategy', '"priority_letter"', NOW(), NOW()),
+    ('vacuum', 'box_assignment_strategy', '"priority_letter"', NOW(), NOW())
+ON CONFLICT (service_type, setting_key) DO NOTHING;
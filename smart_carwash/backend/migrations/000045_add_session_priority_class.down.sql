@@ -0,0 +1,4 @@
+-- Удаляем настройки взвешенной очереди
+DELETE FROM service_settings WHERE service_type = 'session' AND setting_key IN ('queue_priority_boost_minutes', 'queue_max_wait_minutes');
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS priority_class;
@@ -0,0 +1,12 @@
+-- Класс приоритета сессии в очереди: normal, subscriber, fleet, skip_line, vip
+ALTER TABLE sessions ADD COLUMN priority_class VARCHAR(16) NOT NULL DEFAULT 'normal';
+
+-- Сессии VIP номеров, созданные до появления классов
+UPDATE sessions SET priority_class = 'vip' WHERE is_vip = TRUE;
+
+-- Настройки взвешенной очереди: фора классов в минутах ожидания и максимальное ожидание обычных клиентов
+INSERT INTO service_settings (service_type, setting_key, setting_value, created_at, updated_at)
+VALUES
+    ('session', 'queue_priority_boost_minutes', '{"normal": 0, "subscriber": 5, "fleet": 10, "skip_line": 30, "vip": 60}', NOW(), NOW()),
+    ('session', 'queue_max_wait_minutes', '30', NOW(), NOW())
+ON CONFLICT (service_type, setting_key) DO NOTHING;
@@ -0,0 +1,14 @@
+-- Табло у въезда: у каждого экрана свой токен доступа к публичной ленте
+CREATE TABLE IF NOT EXISTS signage_displays (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_seen_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_signage_displays_token ON signage_displays(token) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_signage_displays_deleted_at ON signage_displays(deleted_at);
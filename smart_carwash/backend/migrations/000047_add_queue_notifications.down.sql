@@ -0,0 +1,9 @@
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS is_queue_position_notification_sent,
+DROP COLUMN IF EXISTS is_queue_wait_notification_sent;
+
+DROP INDEX IF EXISTS idx_users_queue_notify_empty_service_type;
+
+ALTER TABLE users DROP COLUMN IF EXISTS queue_notify_empty_service_type;
+ALTER TABLE users DROP COLUMN IF EXISTS queue_notify_wait_minutes;
+ALTER TABLE users DROP COLUMN IF EXISTS queue_notify_position;
@@ -0,0 +1,12 @@
+-- Настройки уведомлений об очереди в Telegram (по умолчанию выключены)
+ALTER TABLE users ADD COLUMN queue_notify_position INTEGER;
+ALTER TABLE users ADD COLUMN queue_notify_wait_minutes INTEGER;
+ALTER TABLE users ADD COLUMN queue_notify_empty_service_type VARCHAR(20);
+
+-- Подписчики на уведомление об опустевшей очереди выбираются при каждой обработке очереди
+CREATE INDEX idx_users_queue_notify_empty_service_type ON users(queue_notify_empty_service_type) WHERE queue_notify_empty_service_type IS NOT NULL;
+
+-- Флаги отправленных уведомлений об очереди, чтобы каждое уведомление отправлялось один раз за сессию
+ALTER TABLE sessions
+ADD COLUMN is_queue_position_notification_sent BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN is_queue_wait_notification_sent BOOLEAN NOT NULL DEFAULT FALSE;
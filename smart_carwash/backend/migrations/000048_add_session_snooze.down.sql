@@ -0,0 +1,5 @@
+-- Удаляем настройки отсрочки бокса
+DELETE FROM service_settings WHERE service_type = 'session' AND setting_key IN ('queue_snooze_max_count', 'queue_snooze_position_penalty');
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS queue_entered_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS snooze_count;
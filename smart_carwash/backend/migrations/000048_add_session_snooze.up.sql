@@ -0,0 +1,12 @@
+-- Отсрочка назначенного бокса: сколько раз сессия отложила бокс
+ALTER TABLE sessions ADD COLUMN snooze_count INTEGER NOT NULL DEFAULT 0;
+
+-- Время, от которого считается ожидание в очереди (для отложивших бокс вместо created_at)
+ALTER TABLE sessions ADD COLUMN queue_entered_at TIMESTAMP;
+
+-- Настройки отсрочки: максимальное количество отсрочек за сессию и штраф в позициях очереди
+INSERT INTO service_settings (service_type, setting_key, setting_value, created_at, updated_at)
+VALUES
+    ('session', 'queue_snooze_max_count', '2', NOW(), NOW()),
+    ('session', 'queue_snooze_position_penalty', '2', NOW(), NOW())
+ON CONFLICT (service_type, setting_key) DO NOTHING;
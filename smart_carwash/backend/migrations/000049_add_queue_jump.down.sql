@@ -0,0 +1,9 @@
+-- Удаляем настройки пропуска очереди
+DELETE FROM service_settings WHERE service_type = 'session' AND setting_key IN ('queue_jump_price', 'queue_jump_max_per_hour');
+
+DROP INDEX IF EXISTS idx_sessions_queue_jumped_at;
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS is_queue_jump_settled;
+ALTER TABLE sessions DROP COLUMN IF EXISTS queue_jump_expected_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS queue_jumped_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS queue_jump_payment_id;
@@ -0,0 +1,14 @@
+-- Платный пропуск очереди: платеж, время покупки и прогноз назначения бокса без пропуска
+ALTER TABLE sessions ADD COLUMN queue_jump_payment_id UUID;
+ALTER TABLE sessions ADD COLUMN queue_jumped_at TIMESTAMP;
+ALTER TABLE sessions ADD COLUMN queue_jump_expected_at TIMESTAMP;
+ALTER TABLE sessions ADD COLUMN is_queue_jump_settled BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_sessions_queue_jumped_at ON sessions(queue_jumped_at) WHERE queue_jumped_at IS NOT NULL;
+
+-- Настройки пропуска очереди: доплата в копейках и максимум пропусков в час
+INSERT INTO service_settings (service_type, setting_key, setting_value, created_at, updated_at)
+VALUES
+    ('session', 'queue_jump_price', '10000', NOW(), NOW()),
+    ('session', 'queue_jump_max_per_hour', '3', NOW(), NOW())
+ON CONFLICT (service_type, setting_key) DO NOTHING;
@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS site_service_settings;
+
+DROP INDEX IF EXISTS idx_carwash_status_history_site_id;
+DROP INDEX IF EXISTS idx_carwash_status_site_id;
+ALTER TABLE carwash_status_history DROP COLUMN IF EXISTS site_id;
+ALTER TABLE carwash_status DROP COLUMN IF EXISTS site_id;
+
+DROP INDEX IF EXISTS idx_cleaners_site_id;
+DROP INDEX IF EXISTS idx_cashiers_site_id;
+DROP INDEX IF EXISTS idx_sessions_site_id_status;
+DROP INDEX IF EXISTS idx_wash_boxes_site_id;
+
+ALTER TABLE signage_displays DROP COLUMN IF EXISTS site_id;
+ALTER TABLE cleaners DROP COLUMN IF EXISTS site_id;
+ALTER TABLE cashiers DROP COLUMN IF EXISTS site_id;
+ALTER TABLE sessions DROP COLUMN IF EXISTS site_id;
+ALTER TABLE wash_boxes DROP COLUMN IF EXISTS site_id;
+
+DROP TABLE IF EXISTS sites;
@@ -0,0 +1,55 @@
+-- Площадки (отдельные мойки), обслуживаемые одним бэкендом
+CREATE TABLE IF NOT EXISTS sites (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    address VARCHAR(255),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    api_key_1c VARCHAR(255),
+    dahua_username VARCHAR(100),
+    dahua_password VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sites_code ON sites(code) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sites_api_key_1c ON sites(api_key_1c) WHERE deleted_at IS NULL AND api_key_1c IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sites_dahua_username ON sites(dahua_username) WHERE deleted_at IS NULL AND dahua_username IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_sites_deleted_at ON sites(deleted_at);
+
+-- Площадка по умолчанию: к ней относятся все данные, созданные до появления нескольких площадок
+INSERT INTO sites (id, name, code, is_active, created_at, updated_at)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Основная площадка', 'main', TRUE, NOW(), NOW())
+ON CONFLICT (id) DO NOTHING;
+
+-- Боксы, сессии, персонал и табло принадлежат площадке
+ALTER TABLE wash_boxes ADD COLUMN IF NOT EXISTS site_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES sites(id);
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS site_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES sites(id);
+ALTER TABLE cashiers ADD COLUMN IF NOT EXISTS site_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES sites(id);
+ALTER TABLE cleaners ADD COLUMN IF NOT EXISTS site_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES sites(id);
+ALTER TABLE signage_displays ADD COLUMN IF NOT EXISTS site_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES sites(id);
+
+CREATE INDEX IF NOT EXISTS idx_wash_boxes_site_id ON wash_boxes(site_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_site_id_status ON sessions(site_id, status);
+CREATE INDEX IF NOT EXISTS idx_cashiers_site_id ON cashiers(site_id);
+CREATE INDEX IF NOT EXISTS idx_cleaners_site_id ON cleaners(site_id);
+
+-- Статус открыта/закрыта ведется отдельно по каждой площадке
+ALTER TABLE carwash_status ADD COLUMN IF NOT EXISTS site_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES sites(id);
+ALTER TABLE carwash_status_history ADD COLUMN IF NOT EXISTS site_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES sites(id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_carwash_status_site_id ON carwash_status(site_id);
+CREATE INDEX IF NOT EXISTS idx_carwash_status_history_site_id ON carwash_status_history(site_id, created_at DESC);
+
+-- Цены площадки: переопределяют общие настройки service_settings для своей площадки
+CREATE TABLE IF NOT EXISTS site_service_settings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    site_id UUID NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    service_type VARCHAR(50) NOT NULL,
+    setting_key VARCHAR(100) NOT NULL,
+    setting_value JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(site_id, service_type, setting_key)
+);
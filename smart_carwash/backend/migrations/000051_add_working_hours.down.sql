@@ -0,0 +1,10 @@
+ALTER TABLE carwash_status_history DROP COLUMN IF EXISTS source;
+
+ALTER TABLE carwash_status DROP COLUMN IF EXISTS closing_notice_for;
+ALTER TABLE carwash_status DROP COLUMN IF EXISTS schedule_override_until;
+ALTER TABLE carwash_status DROP COLUMN IF EXISTS closed_by_schedule;
+ALTER TABLE carwash_status DROP COLUMN IF EXISTS stop_sessions_before_close_minutes;
+ALTER TABLE carwash_status DROP COLUMN IF EXISTS schedule_enabled;
+
+DROP TABLE IF EXISTS carwash_special_days;
+DROP TABLE IF EXISTS carwash_working_hours;
@@ -0,0 +1,37 @@
+-- Рабочие часы площадки по дням недели (weekday: 0 - воскресенье ... 6 - суббота)
+-- Время закрытия раньше или равное времени открытия означает работу после полуночи
+CREATE TABLE IF NOT EXISTS carwash_working_hours (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    site_id UUID NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    open_time VARCHAR(5) NOT NULL DEFAULT '00:00',
+    close_time VARCHAR(5) NOT NULL DEFAULT '00:00',
+    is_day_off BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(site_id, weekday)
+);
+
+-- Исключения из недельного расписания: праздники (is_closed) и особые дни со своими часами
+CREATE TABLE IF NOT EXISTS carwash_special_days (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    site_id UUID NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    is_closed BOOLEAN NOT NULL DEFAULT FALSE,
+    open_time VARCHAR(5),
+    close_time VARCHAR(5),
+    reason VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(site_id, date)
+);
+
+-- Настройки расписания и состояние автоматического открытия/закрытия
+ALTER TABLE carwash_status ADD COLUMN IF NOT EXISTS schedule_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE carwash_status ADD COLUMN IF NOT EXISTS stop_sessions_before_close_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE carwash_status ADD COLUMN IF NOT EXISTS closed_by_schedule BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE carwash_status ADD COLUMN IF NOT EXISTS schedule_override_until TIMESTAMP;
+ALTER TABLE carwash_status ADD COLUMN IF NOT EXISTS closing_notice_for TIMESTAMP;
+
+-- Источник изменения статуса: admin - вручную, system - по расписанию
+ALTER TABLE carwash_status_history ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'admin';
@@ -0,0 +1,4 @@
+ALTER TABLE carwash_status DROP COLUMN IF EXISTS drain_started_by;
+ALTER TABLE carwash_status DROP COLUMN IF EXISTS drain_reason;
+ALTER TABLE carwash_status DROP COLUMN IF EXISTS drain_deadline;
+ALTER TABLE carwash_status DROP COLUMN IF EXISTS drain_started_at;
@@ -0,0 +1,6 @@
+-- Режим закрытия с дообслуживанием: новые сессии не принимаются, очередь обслуживается,
+-- мойка закрывается, когда не останется сессий, или в крайний срок
+ALTER TABLE carwash_status ADD COLUMN IF NOT EXISTS drain_started_at TIMESTAMP;
+ALTER TABLE carwash_status ADD COLUMN IF NOT EXISTS drain_deadline TIMESTAMP;
+ALTER TABLE carwash_status ADD COLUMN IF NOT EXISTS drain_reason TEXT;
+ALTER TABLE carwash_status ADD COLUMN IF NOT EXISTS drain_started_by UUID;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS carwash_service_type_status;
@@ -0,0 +1,14 @@
+-- Статус отдельных типов услуг площадки: закрытие услуги с причиной и лимит очереди
+-- Отсутствие записи означает, что услуга открыта и очередь не ограничена
+CREATE TABLE IF NOT EXISTS carwash_service_type_status (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    site_id UUID NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    service_type VARCHAR(20) NOT NULL,
+    is_closed BOOLEAN NOT NULL DEFAULT FALSE,
+    closed_reason TEXT,
+    max_queue_size INTEGER NOT NULL DEFAULT 0 CHECK (max_queue_size >= 0), -- 0 - без ограничения
+    updated_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(site_id, service_type)
+);
@@ -0,0 +1 @@
+ALTER TABLE wash_boxes DROP COLUMN IF EXISTS modbus_device;
@@ -0,0 +1,3 @@
+-- Имя ПЛК на modbus сервере, к которому подключены регистры бокса
+-- NULL - устройство по умолчанию
+ALTER TABLE wash_boxes ADD COLUMN IF NOT EXISTS modbus_device VARCHAR(100);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_modbus_operations_command_id;
+ALTER TABLE modbus_operations DROP COLUMN IF EXISTS attempt;
+ALTER TABLE modbus_operations DROP COLUMN IF EXISTS command_id;
+
+DROP TABLE IF EXISTS modbus_outbox;
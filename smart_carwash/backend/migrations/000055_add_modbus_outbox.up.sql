@@ -0,0 +1,31 @@
+-- Очередь команд Modbus: команда записывается в одной транзакции с изменением сессии,
+-- диспетчер применяет команды по порядку для каждого бокса с повторами
+CREATE TABLE IF NOT EXISTS modbus_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    seq BIGSERIAL NOT NULL,
+    box_id UUID NOT NULL,
+    session_id UUID,
+    operation VARCHAR(32) NOT NULL,
+    coil_type VARCHAR(16) NOT NULL,
+    register VARCHAR(10) NOT NULL,
+    value BOOLEAN NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deadline TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_error TEXT,
+    alerted_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_modbus_outbox_seq ON modbus_outbox(seq);
+CREATE INDEX IF NOT EXISTS idx_modbus_outbox_pending ON modbus_outbox(status, seq);
+CREATE INDEX IF NOT EXISTS idx_modbus_outbox_box_id ON modbus_outbox(box_id);
+
+-- Каждая попытка выполнения команды из очереди сохраняется отдельной операцией
+ALTER TABLE modbus_operations ADD COLUMN IF NOT EXISTS command_id UUID;
+ALTER TABLE modbus_operations ADD COLUMN IF NOT EXISTS attempt INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_modbus_operations_command_id ON modbus_operations(command_id);
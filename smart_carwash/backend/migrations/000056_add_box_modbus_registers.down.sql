@@ -0,0 +1,9 @@
+ALTER TABLE modbus_operations DROP COLUMN IF EXISTS numeric_value;
+
+DELETE FROM modbus_outbox WHERE command_type <> 'coil';
+ALTER TABLE modbus_outbox DROP COLUMN IF EXISTS numeric_value;
+ALTER TABLE modbus_outbox DROP COLUMN IF EXISTS word_order;
+ALTER TABLE modbus_outbox DROP COLUMN IF EXISTS data_type;
+ALTER TABLE modbus_outbox DROP COLUMN IF EXISTS command_type;
+
+ALTER TABLE wash_boxes DROP COLUMN IF EXISTS modbus_registers;
@@ -0,0 +1,12 @@
+-- Holding registers бокса (давление насоса, концентрация пены, табло обратного отсчета)
+-- Массив определений: параметр, регистр, тип значения, порядок слов, множитель, значения в сессии и без нее
+ALTER TABLE wash_boxes ADD COLUMN IF NOT EXISTS modbus_registers JSONB NOT NULL DEFAULT '[]';
+
+-- Очередь команд Modbus хранит и записи holding registers
+ALTER TABLE modbus_outbox ADD COLUMN IF NOT EXISTS command_type VARCHAR(16) NOT NULL DEFAULT 'coil';
+ALTER TABLE modbus_outbox ADD COLUMN IF NOT EXISTS data_type VARCHAR(10);
+ALTER TABLE modbus_outbox ADD COLUMN IF NOT EXISTS word_order VARCHAR(10);
+ALTER TABLE modbus_outbox ADD COLUMN IF NOT EXISTS numeric_value DOUBLE PRECISION;
+
+-- Значение, записанное в holding register (для coils - NULL)
+ALTER TABLE modbus_operations ADD COLUMN IF NOT EXISTS numeric_value DOUBLE PRECISION;
@@ -0,0 +1,5 @@
+ALTER TABLE sessions DROP COLUMN IF EXISTS no_car_since;
+
+ALTER TABLE wash_boxes DROP COLUMN IF EXISTS car_present_changed_at;
+ALTER TABLE wash_boxes DROP COLUMN IF EXISTS car_present;
+ALTER TABLE wash_boxes DROP COLUMN IF EXISTS presence_sensor_register;
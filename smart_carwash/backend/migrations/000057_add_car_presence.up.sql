@@ -0,0 +1,8 @@
+-- Датчик присутствия автомобиля в боксе: дискретный вход ПЛК, который опрашивает modbus сервер
+ALTER TABLE wash_boxes ADD COLUMN IF NOT EXISTS presence_sensor_register VARCHAR(10);
+-- Последнее известное состояние датчика (NULL - датчика нет или он еще не прочитан)
+ALTER TABLE wash_boxes ADD COLUMN IF NOT EXISTS car_present BOOLEAN;
+ALTER TABLE wash_boxes ADD COLUMN IF NOT EXISTS car_present_changed_at TIMESTAMP WITH TIME ZONE;
+
+-- С какого момента в боксе активной сессии нет машины; заполняется, когда отсутствие превысило порог
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS no_car_since TIMESTAMP WITH TIME ZONE;
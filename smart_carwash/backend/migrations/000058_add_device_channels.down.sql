@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS modbus_channel_statuses;
+
+ALTER TABLE modbus_outbox DROP COLUMN IF EXISTS channel_on;
+ALTER TABLE wash_boxes DROP COLUMN IF EXISTS device_channels;
@@ -0,0 +1,30 @@
+-- Каналы устройств бокса (ворота, пенный пистолет, воск, осмос, пылесос)
+-- Массив определений: роль, регистр, тип (coil или register), режим, инверсия, значения включения и выключения.
+-- light_coil_register и chemistry_coil_register остаются сокращенной записью каналов света и химии
+ALTER TABLE wash_boxes ADD COLUMN IF NOT EXISTS device_channels JSONB NOT NULL DEFAULT '[]';
+
+-- Включено ли устройство после выполнения команды (для команд каналов; у инвертированного coil отличается от value)
+ALTER TABLE modbus_outbox ADD COLUMN IF NOT EXISTS channel_on BOOLEAN;
+
+-- Состояние каждого канала бокса: включено устройство или нет
+CREATE TABLE IF NOT EXISTS modbus_channel_statuses (
+    box_id UUID NOT NULL REFERENCES wash_boxes(id) ON DELETE CASCADE,
+    role VARCHAR(24) NOT NULL,
+    register VARCHAR(10) NOT NULL DEFAULT '',
+    "on" BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (box_id, role)
+);
+
+-- Переносим известные статусы света и химии
+INSERT INTO modbus_channel_statuses (box_id, role, register, "on", updated_at)
+SELECT s.box_id, 'light', COALESCE(b.light_coil_register, ''), s.light_status, COALESCE(s.last_seen, CURRENT_TIMESTAMP)
+FROM modbus_connection_statuses s JOIN wash_boxes b ON b.id = s.box_id
+WHERE s.light_status IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+INSERT INTO modbus_channel_statuses (box_id, role, register, "on", updated_at)
+SELECT s.box_id, 'chemistry', COALESCE(b.chemistry_coil_register, ''), s.chemistry_status, COALESCE(s.last_seen, CURRENT_TIMESTAMP)
+FROM modbus_connection_statuses s JOIN wash_boxes b ON b.id = s.box_id
+WHERE s.chemistry_status IS NOT NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN IF EXISTS queue_notify_empty_site_id;
@@ -0,0 +1,7 @@
+-- Площадка подписки на уведомление об опустевшей очереди
+ALTER TABLE users ADD COLUMN queue_notify_empty_site_id UUID REFERENCES sites(id);
+
+-- Подписки, оформленные до появления площадки, относятся к площадке по умолчанию
+UPDATE users
+SET queue_notify_empty_site_id = '00000000-0000-0000-0000-000000000001'
+WHERE queue_notify_empty_service_type IS NOT NULL;
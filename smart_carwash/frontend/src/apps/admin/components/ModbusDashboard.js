@@ -435,6 +435,19 @@ const ModbusDashboard = () => {
                     <strong>Регистр химии:</strong> {box.chemistry_coil_register}
                   </BoxInfo>
                 )}
+
+                {box.modbus_device && (
+                  <BoxInfo theme={theme}>
+                    <strong>Устройство:</strong> {box.modbus_device}
+                  </BoxInfo>
+                )}
+
+                {box.presence_sensor_register && (
+                  <BoxInfo theme={theme}>
+                    <strong>Машина в боксе:</strong>{' '}
+                    {box.car_present === null || box.car_present === undefined ? 'нет данных' : box.car_present ? 'да' : 'нет'}
+                  </BoxInfo>
+                )}
                 
                 {/* Статусы света и химии */}
                 {(box.light_status !== null && box.light_status !== undefined) && (
@@ -345,7 +345,8 @@ const PaymentManagement = () => {
   const paymentTypeOptions = [
     { value: '', label: 'Все типы' },
     { value: 'main', label: 'Основной' },
-    { value: 'extension', label: 'Продление' }
+    { value: 'extension', label: 'Продление' },
+    { value: 'queue_jump', label: 'Пропуск очереди' }
   ];
 
   const paymentMethodOptions = [
@@ -738,7 +739,7 @@ const PaymentManagement = () => {
                       </StatusBadge>
                     </TableCell>
                     <TableCell theme={theme}>
-                      {payment.payment_type === 'main' ? 'Основной' : 'Продление'}
+                      {payment.payment_type === 'main' ? 'Основной' : payment.payment_type === 'queue_jump' ? 'Пропуск очереди' : 'Продление'}
                     </TableCell>
                     <TableCell theme={theme}>
                       {payment.payment_method === 'tinkoff' ? 'Tinkoff' : 
@@ -193,6 +193,14 @@ const EmptyMessage = styled.div`
   font-style: italic;
 `;
 
+// Названия классов приоритета очереди
+const PRIORITY_CLASS_LABELS = {
+  subscriber: 'Абонемент',
+  fleet: 'Автопарк',
+  skip_line: 'Без очереди',
+  vip: 'VIP'
+};
+
 const QueueStatus = () => {
   const theme = getTheme('light');
   const [queueData, setQueueData] = useState(null);
@@ -344,6 +352,8 @@ const QueueStatus = () => {
                   <UserDetails>
                     Ожидает с: {user.waiting_since}
                     {user.car_number && ` • Номер машины: ${user.car_number}`}
+                    {PRIORITY_CLASS_LABELS[user.priority_class] && ` • Класс: ${PRIORITY_CLASS_LABELS[user.priority_class]}`}
+                    {user.snooze_count > 0 && ` • Отложил бокс: ${user.snooze_count}`}
                   </UserDetails>
                 </UserInfo>
                 <div style={{ display: 'flex', alignItems: 'center', gap: '10px' }}>
@@ -422,6 +432,8 @@ const QueueStatus = () => {
                   <UserDetails>
                     Ожидает с: {user.waiting_since}
                     {user.car_number && ` • Номер машины: ${user.car_number}`}
+                    {PRIORITY_CLASS_LABELS[user.priority_class] && ` • Класс: ${PRIORITY_CLASS_LABELS[user.priority_class]}`}
+                    {user.snooze_count > 0 && ` • Отложил бокс: ${user.snooze_count}`}
                   </UserDetails>
                 </UserInfo>
                 <div style={{ display: 'flex', alignItems: 'center', gap: '10px' }}>
@@ -500,6 +512,8 @@ const QueueStatus = () => {
                   <UserDetails>
                     Ожидает с: {user.waiting_since}
                     {user.car_number && ` • Номер машины: ${user.car_number}`}
+                    {PRIORITY_CLASS_LABELS[user.priority_class] && ` • Класс: ${PRIORITY_CLASS_LABELS[user.priority_class]}`}
+                    {user.snooze_count > 0 && ` • Отложил бокс: ${user.snooze_count}`}
                   </UserDetails>
                 </UserInfo>
                 <div style={{ display: 'flex', alignItems: 'center', gap: '10px' }}>
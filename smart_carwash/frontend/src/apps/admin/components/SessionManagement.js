@@ -1167,7 +1167,7 @@ const SessionManagement = () => {
                   
                   <DetailGroup>
                     <DetailLabel theme={theme}>Номер машины:</DetailLabel>
-                    <DetailValue theme={theme}>{sessionDetails.car_number || 'Не указан'}</DetailValue>
+                    <DetailValue theme={theme}>{sessionDetails.car_number_formatted || sessionDetails.car_number || 'Не указан'}</DetailValue>
                   </DetailGroup>
                   
                   <DetailGroup>
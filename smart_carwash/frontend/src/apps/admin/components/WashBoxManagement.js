@@ -543,6 +543,8 @@ const WashBoxManagement = () => {
     priority: 'A',
     lightCoilRegister: '',
     chemistryCoilRegister: '',
+    modbusDevice: '',
+    presenceSensorRegister: '',
     comment: ''
   });
   const navigate = useNavigate();
@@ -716,13 +718,15 @@ const WashBoxManagement = () => {
         priority: formData.priority.toUpperCase() || 'A',
         light_coil_register: formData.lightCoilRegister || null,
         chemistry_coil_register: formData.chemistryCoilRegister || null,
+        modbus_device: formData.modbusDevice || null,
+        presence_sensor_register: formData.presenceSensorRegister || null,
         comment: formData.comment || null
       };
       
       await ApiService.createWashBox(washBoxData);
       setSuccess('Бокс успешно создан');
       setShowCreateModal(false);
-      setFormData({ number: '', status: 'free', serviceType: 'wash', chemistryEnabled: true, priority: 'A', lightCoilRegister: '', chemistryCoilRegister: '', comment: '' });
+      setFormData({ number: '', status: 'free', serviceType: 'wash', chemistryEnabled: true, priority: 'A', lightCoilRegister: '', chemistryCoilRegister: '', modbusDevice: '', presenceSensorRegister: '', comment: '' });
       fetchWashBoxes();
     } catch (err) {
       if (err.response?.data?.error) {
@@ -752,6 +756,10 @@ const WashBoxManagement = () => {
         priority: formData.priority.toUpperCase() || 'A',
         light_coil_register: formData.lightCoilRegister || null,
         chemistry_coil_register: formData.chemistryCoilRegister || null,
+        // Пустая строка сбрасывает устройство на устройство по умолчанию
+        modbus_device: formData.modbusDevice,
+        // Пустая строка убирает датчик присутствия
+        presence_sensor_register: formData.presenceSensorRegister,
         comment: formData.comment || null
       };
       
@@ -759,7 +767,7 @@ const WashBoxManagement = () => {
       setSuccess('Бокс успешно обновлен');
       setShowEditModal(false);
       setEditingWashBox(null);
-      setFormData({ number: '', status: 'free', serviceType: 'wash', chemistryEnabled: true, priority: 'A', lightCoilRegister: '', chemistryCoilRegister: '', comment: '' });
+      setFormData({ number: '', status: 'free', serviceType: 'wash', chemistryEnabled: true, priority: 'A', lightCoilRegister: '', chemistryCoilRegister: '', modbusDevice: '', presenceSensorRegister: '', comment: '' });
       fetchWashBoxes();
     } catch (err) {
       if (err.response?.data?.error) {
@@ -807,6 +815,8 @@ const WashBoxManagement = () => {
       priority: washBox.priority || 'A',
       lightCoilRegister: washBox.light_coil_register || '',
       chemistryCoilRegister: washBox.chemistry_coil_register || '',
+      modbusDevice: washBox.modbus_device || '',
+      presenceSensorRegister: washBox.presence_sensor_register || '',
       comment: washBox.comment || ''
     });
     setShowEditModal(true);
@@ -817,7 +827,7 @@ const WashBoxManagement = () => {
     setShowCreateModal(false);
     setShowEditModal(false);
     setEditingWashBox(null);
-    setFormData({ number: '', status: 'free', serviceType: 'wash', chemistryEnabled: true, priority: 'A', lightCoilRegister: '', chemistryCoilRegister: '', comment: '' });
+    setFormData({ number: '', status: 'free', serviceType: 'wash', chemistryEnabled: true, priority: 'A', lightCoilRegister: '', chemistryCoilRegister: '', modbusDevice: '', presenceSensorRegister: '', comment: '' });
     setError('');
   };
 
@@ -1317,6 +1327,33 @@ const WashBoxManagement = () => {
                   </small>
                 </FormGroup>
               )}
+
+              <FormGroup>
+                <Label theme={theme}>Modbus устройство</Label>
+                <Input
+                  type="text"
+                  value={formData.modbusDevice}
+                  onChange={(e) => setFormData({ ...formData, modbusDevice: e.target.value })}
+                  placeholder="plc1"
+                />
+                <small style={{ color: '#666', fontSize: '12px' }}>
+                  Имя ПЛК из MODBUS_DEVICES на modbus сервере (пусто - устройство по умолчанию)
+                </small>
+              </FormGroup>
+
+              <FormGroup>
+                <Label theme={theme}>Датчик присутствия</Label>
+                <Input
+                  type="text"
+                  value={formData.presenceSensorRegister}
+                  onChange={(e) => setFormData({ ...formData, presenceSensorRegister: e.target.value })}
+                  placeholder="0x0020"
+                  pattern="0x[0-9a-fA-F]{1,4}"
+                />
+                <small style={{ color: '#666', fontSize: '12px' }}>
+                  Дискретный вход датчика автомобиля в боксе (пусто - нет датчика)
+                </small>
+              </FormGroup>
               
               <ButtonGroup>
                 <Button theme={theme} type="button" onClick={closeModals}>
@@ -1517,6 +1554,37 @@ const WashBoxManagement = () => {
                     )}
                   </FormGroup>
                 )}
+
+                {!isLimitedAdmin && (
+                  <FormGroup>
+                    <Label theme={theme}>Modbus устройство</Label>
+                    <Input
+                      type="text"
+                      value={formData.modbusDevice}
+                      onChange={(e) => setFormData({ ...formData, modbusDevice: e.target.value })}
+                      placeholder="plc1"
+                    />
+                    <small style={{ color: '#666', fontSize: '12px' }}>
+                      Пусто - устройство по умолчанию
+                    </small>
+                  </FormGroup>
+                )}
+
+                {!isLimitedAdmin && (
+                  <FormGroup>
+                    <Label theme={theme}>Датчик присутствия</Label>
+                    <Input
+                      type="text"
+                      value={formData.presenceSensorRegister}
+                      onChange={(e) => setFormData({ ...formData, presenceSensorRegister: e.target.value })}
+                      placeholder="0x0020"
+                      pattern="0x[0-9a-fA-F]{1,4}"
+                    />
+                    <small style={{ color: '#666', fontSize: '12px' }}>
+                      Дискретный вход датчика автомобиля (пусто - нет датчика)
+                    </small>
+                  </FormGroup>
+                )}
               </TwoColumnGrid>
               
               <FullWidthFormGroup>
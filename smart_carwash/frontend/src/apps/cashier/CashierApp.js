@@ -868,7 +868,7 @@ const CashierApp = () => {
                                   </StatusBadge>
                                 </Td>
                                 <Td theme={theme}>{getServiceTypeText(session.service_type)}</Td>
-                                <Td theme={theme}>{session.car_number || 'Не указан'}</Td>
+                                <Td theme={theme}>{session.car_number_formatted || session.car_number || 'Не указан'}</Td>
                                 <BoxNumberTd theme={theme}>{session.box_number ? `№${session.box_number}` : 'Не назначен'}</BoxNumberTd>
                                 <Td theme={theme}>
                                   <ChemistryStatus theme={theme} style={{ color: chemistryStatus.color }}>
@@ -949,7 +949,7 @@ const CashierApp = () => {
                               <MobileCardDetail>
                                 <MobileCardLabel theme={theme}>Номер машины</MobileCardLabel>
                                 <MobileCardValue theme={theme}>
-                                  {session.car_number || 'Не указан'}
+                                  {session.car_number_formatted || session.car_number || 'Не указан'}
                                 </MobileCardValue>
                               </MobileCardDetail>
                               
@@ -330,7 +330,7 @@ const SessionCardComponent = ({ session, onStart, onComplete, onCancel, onEnable
         <DetailItem>
           <DetailLabel theme={theme}>Номер машины</DetailLabel>
           <DetailValue theme={theme}>
-            {session.car_number || 'Не указан'}
+            {session.car_number_formatted || session.car_number || 'Не указан'}
           </DetailValue>
         </DetailItem>
 
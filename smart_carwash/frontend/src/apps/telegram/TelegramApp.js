@@ -5,6 +5,7 @@ import styled from 'styled-components';
 import Header from './components/Header';
 import WashInfo from './components/WashInfo/WashInfo';
 import PaymentPage from './components/PaymentPage';
+import SiteSelector from './components/SiteSelector';
 import ApiService from '../../shared/services/ApiService';
 import { getTheme } from '../../shared/styles/theme';
 // import { SettingsProvider } from '../../shared/contexts/SettingsContext';
@@ -437,6 +438,11 @@ const TelegramApp = () => {
   useEffect(() => {
     fetchCarwashStatus();
   }, []);
+
+  // Смена площадки: после обновления статуса мойки очередь перезагружается для выбранной площадки
+  const handleSiteChange = () => {
+    fetchCarwashStatus();
+  };
   
   // Запускаем поллинг при монтировании компонента
   useEffect(() => {
@@ -738,8 +744,24 @@ const TelegramApp = () => {
         marginBottom: '30px',
         lineHeight: '1.5'
       }}>
-        Не работает из-за технических проблем.
+        {carwashStatus?.next_open_at
+          ? `Мойка закрыта: ${carwashStatus.closed_reason || 'нерабочее время'}.`
+          : 'Не работает из-за технических проблем.'}
       </div>
+      {carwashStatus?.next_open_at && (
+        <div style={{
+          fontSize: '20px',
+          color: themeObject.textColor
+        }}>
+          Откроется {new Date(carwashStatus.next_open_at).toLocaleString('ru-RU', {
+            day: '2-digit',
+            month: '2-digit',
+            hour: '2-digit',
+            minute: '2-digit',
+            timeZone: 'Europe/Moscow'
+          })}
+        </div>
+      )}
       <div style={{
         fontSize: '20px',
         color: themeObject.textColor,
@@ -759,6 +781,7 @@ const TelegramApp = () => {
               path="/" 
               element={
                 <>
+                  <SiteSelector theme={theme} onChange={handleSiteChange} />
                   {carwashStatusLoading ? (
                     <p>Загрузка информации о мойке...</p>
                   ) : carwashStatus?.is_closed ? (
@@ -71,6 +71,38 @@ const ServiceSelector = ({ onSelect, theme = 'light', user }) => {
     { id: 'air_dry', name: 'Воздух для продувки', description: 'Сушка автомобиля воздухом', hasChemistry: false }
   ];
   
+  // Статусы типов услуг площадки (закрытие услуги и заполненная очередь)
+  const [serviceTypeStatuses, setServiceTypeStatuses] = useState({});
+
+  useEffect(() => {
+    ApiService.getCarwashStatus()
+      .then(data => {
+        const statuses = {};
+        (data.service_types || []).forEach(status => {
+          statuses[status.service_type] = status;
+        });
+        setServiceTypeStatuses(statuses);
+      })
+      .catch(error => {
+        console.error('Ошибка при загрузке статусов услуг:', error);
+      });
+  }, []);
+
+  // Причина недоступности услуги или null, если услуга доступна
+  const getServiceUnavailableReason = (serviceId) => {
+    const status = serviceTypeStatuses[serviceId];
+    if (!status) {
+      return null;
+    }
+    if (status.is_closed) {
+      return status.closed_reason ? `Временно не работает: ${status.closed_reason}` : 'Временно не работает';
+    }
+    if (status.queue_full) {
+      return 'Очередь заполнена, попробуйте позже';
+    }
+    return null;
+  };
+
   // Загрузка доступного времени мойки при выборе услуги
   useEffect(() => {
     try {
@@ -491,17 +523,23 @@ const ServiceSelector = ({ onSelect, theme = 'light', user }) => {
           </Card>
         ) : (
           // Показываем все услуги для выбора
-          serviceTypes.map((service) => (
-            <Card 
-              key={service.id} 
-              theme={theme} 
-              className={styles.serviceCard}
-              onClick={() => handleServiceSelect(service)}
-            >
-              <h3 className={`${styles.serviceName} ${themeClass}`}>{service.name}</h3>
-              <p className={`${styles.serviceDescription} ${themeClass}`}>{service.description}</p>
-            </Card>
-          ))
+          serviceTypes.map((service) => {
+            const unavailableReason = getServiceUnavailableReason(service.id);
+            return (
+              <Card 
+                key={service.id} 
+                theme={theme} 
+                className={`${styles.serviceCard} ${unavailableReason ? styles.unavailable : ''}`}
+                onClick={() => !unavailableReason && handleServiceSelect(service)}
+              >
+                <h3 className={`${styles.serviceName} ${themeClass}`}>{service.name}</h3>
+                <p className={`${styles.serviceDescription} ${themeClass}`}>{service.description}</p>
+                {unavailableReason && (
+                  <p className={styles.unavailableReason}>{unavailableReason}</p>
+                )}
+              </Card>
+            );
+          })
         )}
       </div>
       
@@ -163,6 +163,8 @@ const SessionDetails = ({ theme = 'light', user }) => {
   const [selectedChemistryTime, setSelectedChemistryTime] = useState(null);
   const [loadingChemistryTimes, setLoadingChemistryTimes] = useState(false);
   const [isCanceling, setIsCanceling] = useState(false);
+  const [isSnoozing, setIsSnoozing] = useState(false);
+  const [isJumpingQueue, setIsJumpingQueue] = useState(false);
   const [sessionPayments, setSessionPayments] = useState(null);
   const [loadingPayments, setLoadingPayments] = useState(false);
   
@@ -647,6 +649,48 @@ const SessionDetails = ({ theme = 'light', user }) => {
     }
   };
 
+  // Обработчик отсрочки назначенного бокса
+  const handleSnoozeSession = async () => {
+    if (!session || !user) return;
+
+    if (!window.confirm('Не успеваете? Бокс будет передан следующему клиенту, а вы вернетесь в начало очереди. Отложить бокс?')) return;
+
+    try {
+      setIsSnoozing(true);
+      const response = await ApiService.snoozeSession(session.id, user.id);
+
+      // Обновляем информацию о сессии
+      setSession(response.session);
+      setBox(null);
+      alert(`Бокс отложен. Ваша позиция в очереди: ${response.position}`);
+    } catch (error) {
+      alert('Ошибка при отсрочке бокса: ' + (error.response?.data?.error || error.message));
+    } finally {
+      setIsSnoozing(false);
+    }
+  };
+
+  // Функция для платного пропуска очереди
+  const handleQueueJump = async () => {
+    if (!session || !user) return;
+
+    if (!window.confirm('Пропустить очередь за доплату? Если бокс не будет назначен раньше, доплата вернется автоматически.')) return;
+
+    try {
+      setIsJumpingQueue(true);
+      const response = await ApiService.jumpQueue(session.id, user.id);
+
+      // Открываем страницу оплаты, сессия поднимется в очереди после оплаты
+      if (response && response.payment && response.payment.payment_url) {
+        window.open(response.payment.payment_url, '_blank');
+      }
+    } catch (error) {
+      alert('Ошибка при пропуске очереди: ' + (error.response?.data?.error || error.message));
+    } finally {
+      setIsJumpingQueue(false);
+    }
+  };
+
   const themeClass = theme === 'dark' ? styles.dark : styles.light;
   
   if (loading) {
@@ -811,6 +855,32 @@ const SessionDetails = ({ theme = 'light', user }) => {
             </Button>
           )}
 
+          {/* Кнопка отсрочки назначенного бокса */}
+          {session.status === 'assigned' && session.box_id && (
+            <Button 
+              theme={theme} 
+              onClick={handleSnoozeSession}
+              disabled={isSnoozing || actionLoading}
+              loading={isSnoozing}
+              style={{ width: '100%', backgroundColor: '#FF9800' }}
+            >
+              {isSnoozing ? 'Откладываем...' : 'Не успеваю - отложить бокс'}
+            </Button>
+          )}
+
+          {/* Кнопка платного пропуска очереди */}
+          {session.status === 'in_queue' && !session.queue_jumped_at && (
+            <Button 
+              theme={theme} 
+              onClick={handleQueueJump}
+              disabled={isJumpingQueue || actionLoading}
+              loading={isJumpingQueue}
+              style={{ width: '100%', backgroundColor: '#9C27B0' }}
+            >
+              {isJumpingQueue ? 'Создаем платеж...' : 'Пропустить очередь за доплату'}
+            </Button>
+          )}
+
           {/* Кнопка отмены сессии */}
           {canCancelSession && (
             <Button 
@@ -859,7 +929,7 @@ const SessionDetails = ({ theme = 'light', user }) => {
         
         <div className={`${styles.infoRow} ${themeClass}`}>
           <div className={`${styles.infoLabel} ${themeClass}`}>Номер машины:</div>
-          <div className={`${styles.infoValue} ${themeClass}`}>{session.car_number || 'Не указан'}</div>
+          <div className={`${styles.infoValue} ${themeClass}`}>{session.car_number_formatted || session.car_number || 'Не указан'}</div>
         </div>
         
         <div className={`${styles.infoRow} ${themeClass}`}>
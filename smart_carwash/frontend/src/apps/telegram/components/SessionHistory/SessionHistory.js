@@ -102,7 +102,7 @@ const SessionHistory = ({ user, theme = 'light' }) => {
                   {session.car_number && (
                     <div className={styles.carNumberInfo}>
                       <span className={`${styles.carNumberText} ${themeClass}`}>
-                        Номер машины: {session.car_number}
+                        Номер машины: {session.car_number_formatted || session.car_number}
                       </span>
                     </div>
                   )}
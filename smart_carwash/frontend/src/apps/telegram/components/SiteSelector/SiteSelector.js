@@ -0,0 +1,73 @@
+import React, { useEffect, useState } from 'react';
+import styles from './SiteSelector.module.css';
+import ApiService from '../../../../shared/services/ApiService';
+import { getSelectedSiteId, setSelectedSiteId } from '../../../../shared/utils/siteSelection';
+
+/**
+ * Компонент SiteSelector - выбор площадки мойки
+ * Отображается только если активных площадок больше одной
+ * @param {Object} props - Свойства компонента
+ * @param {string} props.theme - Тема оформления ('light' или 'dark')
+ * @param {Function} props.onChange - Вызывается с ID площадки после смены выбора
+ */
+const SiteSelector = ({ theme = 'light', onChange }) => {
+  const [sites, setSites] = useState([]);
+  const [siteId, setSiteId] = useState(getSelectedSiteId());
+
+  const themeClass = theme === 'dark' ? styles.dark : styles.light;
+
+  useEffect(() => {
+    const loadSites = async () => {
+      try {
+        const response = await ApiService.getSites();
+        const activeSites = response.sites || [];
+        setSites(activeSites);
+
+        // Сбрасываем сохраненную площадку, если она больше не активна
+        const selected = getSelectedSiteId();
+        if (selected && !activeSites.some(site => site.id === selected)) {
+          setSelectedSiteId(null);
+          setSiteId(null);
+          if (onChange) onChange(null);
+        }
+      } catch (error) {
+        console.error('Ошибка при загрузке площадок:', error);
+      }
+    };
+
+    loadSites();
+  }, []);
+
+  const handleChange = (event) => {
+    const value = event.target.value || null;
+    setSelectedSiteId(value);
+    setSiteId(value);
+    if (onChange) onChange(value);
+  };
+
+  if (sites.length <= 1) {
+    return null;
+  }
+
+  // Без сохраненного выбора показываем первую площадку - основную (список упорядочен по дате создания)
+
+  return (
+    <div className={`${styles.container} ${themeClass}`}>
+      <label className={styles.label} htmlFor="site-selector">Площадка</label>
+      <select
+        id="site-selector"
+        className={`${styles.select} ${themeClass}`}
+        value={siteId || sites[0].id}
+        onChange={handleChange}
+      >
+        {sites.map(site => (
+          <option key={site.id} value={site.id}>
+            {site.address ? `${site.name} (${site.address})` : site.name}
+          </option>
+        ))}
+      </select>
+    </div>
+  );
+};
+
+export default SiteSelector;
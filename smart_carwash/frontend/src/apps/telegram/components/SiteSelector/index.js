@@ -0,0 +1,3 @@
+import SiteSelector from './SiteSelector';
+
+export default SiteSelector;
@@ -15,6 +15,7 @@
 import axios from 'axios';
 import { v4 as uuidv4 } from 'uuid';
 import { toSnakeCase, toSnakeCaseQuery } from '../utils/snakeCase';
+import { getSelectedSiteId } from '../utils/siteSelection';
 
 // Создаем экземпляр axios с базовой конфигурацией
 const api = axios.create({
@@ -93,10 +94,10 @@ const ApiService = {
 
   // === МЕТОДЫ ДЛЯ РАБОТЫ С ОЧЕРЕДЬЮ ===
   
-  // Получение статуса очереди
-  getQueueStatus: async () => {
+  // Получение статуса очереди (по умолчанию - выбранной площадки)
+  getQueueStatus: async (siteId = getSelectedSiteId()) => {
     try {
-      const response = await api.get('/queue-status');
+      const response = await api.get('/queue-status', { params: siteId ? { site_id: siteId } : {} });
       return response.data;
     } catch (error) {
       console.error('Ошибка при получении статуса очереди:', error);
@@ -179,6 +180,23 @@ const ApiService = {
     }
   },
 
+  // Обновление настроек уведомлений об очереди (null выключает уведомление)
+  updateQueueNotifications: async (userId, { position = null, waitMinutes = null, emptyServiceType = null } = {}) => {
+    try {
+      const response = await api.put('/users/queue-notifications', {
+        user_id: userId,
+        queue_notify_position: position,
+        queue_notify_wait_minutes: waitMinutes,
+        queue_notify_empty_service_type: emptyServiceType,
+        queue_notify_empty_site_id: emptyServiceType ? (getSelectedSiteId() || null) : null // Площадка, выбранная в мини-приложении
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Ошибка при обновлении настроек уведомлений:', error);
+      throw error;
+    }
+  },
+
 
   // === МЕТОДЫ ДЛЯ TELEGRAM ПРИЛОЖЕНИЯ ===
 
@@ -238,7 +256,7 @@ const ApiService = {
   // Расчет цены услуги
   calculatePrice: async (data) => {
     try {
-      const snakeData = toSnakeCase(data);
+      const snakeData = toSnakeCase({ siteId: getSelectedSiteId() || undefined, ...data });
       const response = await api.post('/payments/calculate-price', snakeData);
       return response.data;
     } catch (error) {
@@ -264,6 +282,7 @@ const ApiService = {
     try {
       // Добавляем обязательное поле idempotency_key
       const sessionData = {
+        siteId: getSelectedSiteId() || undefined, // Площадка, выбранная в мини-приложении
         ...data,
         idempotencyKey: uuidv4() // Генерируем уникальный ключ
       };
@@ -445,6 +464,34 @@ const ApiService = {
     }
   },
 
+  // Отсрочка назначенного бокса: бокс передается следующему клиенту, сессия возвращается в очередь
+  async snoozeSession(sessionId, userId) {
+    try {
+      const response = await api.post('/sessions/snooze', {
+        session_id: sessionId,
+        user_id: userId
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Ошибка отсрочки бокса:', error);
+      throw error;
+    }
+  },
+
+  // Платный пропуск очереди: создает платеж, после оплаты сессия поднимается в очереди
+  async jumpQueue(sessionId, userId) {
+    try {
+      const response = await api.post('/sessions/queue-jump', {
+        session_id: sessionId,
+        user_id: userId
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Ошибка создания платежа за пропуск очереди:', error);
+      throw error;
+    }
+  },
+
   // === МЕТОДЫ ДЛЯ РАБОТЫ С ПЛАТЕЖАМИ ===
   
   // Получение списка платежей (админка)
@@ -690,6 +737,59 @@ const ApiService = {
     }
   },
 
+  // Очередь команд Modbus
+  getModbusOutbox: async (params = {}) => {
+    try {
+      const queryString = toSnakeCaseQuery(params);
+      const response = await api.get(`/admin/modbus/outbox?${queryString}`);
+      return response.data;
+    } catch (error) {
+      console.error('Ошибка получения очереди команд Modbus:', error);
+      throw error;
+    }
+  },
+
+  // Запись параметра бокса в holding register
+  writeModbusRegister: async (boxId, parameter, value) => {
+    try {
+      const response = await api.post('/admin/modbus/register', {
+        box_id: boxId,
+        parameter: parameter,
+        value: value
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Ошибка записи параметра бокса:', error);
+      throw error;
+    }
+  },
+
+  // Включение или выключение канала устройства бокса (ворота, пылесос и т.п.)
+  writeModbusChannel: async (boxId, role, on) => {
+    try {
+      const response = await api.post('/admin/modbus/channel', {
+        box_id: boxId,
+        role: role,
+        on: on
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Ошибка управления каналом бокса:', error);
+      throw error;
+    }
+  },
+
+  // Текущие значения holding registers бокса
+  getModbusRegisters: async (boxId) => {
+    try {
+      const response = await api.get(`/admin/modbus/registers?box_id=${boxId}`);
+      return response.data;
+    } catch (error) {
+      console.error('Ошибка получения регистров бокса:', error);
+      throw error;
+    }
+  },
+
 
   // === МЕТОДЫ ДЛЯ ИСТОРИИ ИЗМЕНЕНИЙ БОКСОВ (АДМИНКА) ===
   getWashboxChangeLogs: async (filters = {}) => {
@@ -884,9 +984,9 @@ const ApiService = {
 
   // === МЕТОДЫ ДЛЯ РАБОТЫ СО СТАТУСОМ МОЙКИ ===
   
-  // Получение текущего статуса мойки (публичный)
-  getCarwashStatus: async () => {
-    const response = await api.get('/carwash/status');
+  // Получение текущего статуса мойки (публичный, по умолчанию - выбранной площадки)
+  getCarwashStatus: async (siteId = getSelectedSiteId()) => {
+    const response = await api.get('/carwash/status', { params: siteId ? { site_id: siteId } : {} });
     return response.data;
   },
 
@@ -915,6 +1015,99 @@ const ApiService = {
     return response.data;
   },
 
+  // Получение расписания работы площадки (админка)
+  getCarwashSchedule: async (siteId) => {
+    const response = await api.get('/admin/carwash/schedule', { params: siteId ? { site_id: siteId } : {} });
+    return response.data;
+  },
+
+  // Изменение расписания работы площадки (админка)
+  // schedule: { site_id, enabled, stop_sessions_before_close_minutes, working_hours: [{ weekday, open_time, close_time, is_day_off }] }
+  updateCarwashSchedule: async (schedule) => {
+    const response = await api.put('/admin/carwash/schedule', schedule);
+    return response.data;
+  },
+
+  // Добавление или изменение особого дня (праздник, сокращенный день) (админка)
+  upsertCarwashSpecialDay: async (specialDay) => {
+    const response = await api.post('/admin/carwash/special-days', specialDay);
+    return response.data;
+  },
+
+  // Удаление особого дня (админка)
+  deleteCarwashSpecialDay: async (id) => {
+    const response = await api.delete('/admin/carwash/special-days', { data: { id } });
+    return response.data;
+  },
+
+  // Ход закрытия мойки с дообслуживанием очереди (админка)
+  getCarwashDrainStatus: async (siteId) => {
+    const response = await api.get('/admin/carwash/drain', { params: siteId ? { site_id: siteId } : {} });
+    return response.data;
+  },
+
+  // Закрытие мойки с дообслуживанием очереди: новые сессии не принимаются, мойка закроется
+  // после обслуживания очереди или через deadlineMinutes минут (админка)
+  startCarwashDrain: async ({ siteId, reason, deadlineMinutes }) => {
+    const response = await api.post('/admin/carwash/drain', {
+      site_id: siteId || undefined,
+      reason: reason || null,
+      deadline_minutes: deadlineMinutes
+    });
+    return response.data;
+  },
+
+  // Отмена закрытия с дообслуживанием (админка)
+  cancelCarwashDrain: async (siteId) => {
+    const response = await api.post('/admin/carwash/drain/cancel', siteId ? { site_id: siteId } : {});
+    return response.data;
+  },
+
+  // Статусы и очереди типов услуг (админка)
+  getCarwashServiceTypes: async (siteId) => {
+    const response = await api.get('/admin/carwash/service-types', { params: siteId ? { site_id: siteId } : {} });
+    return response.data;
+  },
+
+  // Открытие/закрытие типа услуги и лимит очереди (админка)
+  // status: { site_id, service_type, is_closed, closed_reason, max_queue_size, cancel_queued }
+  updateCarwashServiceType: async (status) => {
+    const response = await api.put('/admin/carwash/service-types', status);
+    return response.data;
+  },
+
+  // === МЕТОДЫ ДЛЯ РАБОТЫ С ПЛОЩАДКАМИ ===
+
+  // Получение активных площадок (публичный, для выбора в мини-приложении)
+  getSites: async () => {
+    const response = await api.get('/sites');
+    return response.data;
+  },
+
+  // Получение всех площадок (админка)
+  getAdminSites: async () => {
+    const response = await api.get('/admin/sites');
+    return response.data;
+  },
+
+  // Создание площадки (админка)
+  createSite: async (data) => {
+    const response = await api.post('/admin/sites', toSnakeCase(data));
+    return response.data;
+  },
+
+  // Обновление площадки (админка)
+  updateSite: async (id, data) => {
+    const response = await api.put('/admin/sites', toSnakeCase({ id, ...data }));
+    return response.data;
+  },
+
+  // Удаление площадки (админка)
+  deleteSite: async (id) => {
+    const response = await api.delete('/admin/sites', { data: { id } });
+    return response.data;
+  },
+
 };
 
 // Добавляем перехватчик для обработки ошибок
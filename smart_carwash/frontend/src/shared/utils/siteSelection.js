@@ -0,0 +1,25 @@
+// Выбранная площадка мини-приложения хранится в localStorage
+
+const SITE_STORAGE_KEY = 'siteId';
+
+// Получение выбранной площадки (null - площадка по умолчанию)
+export const getSelectedSiteId = () => {
+  try {
+    return localStorage.getItem(SITE_STORAGE_KEY) || null;
+  } catch (error) {
+    return null;
+  }
+};
+
+// Сохранение выбранной площадки
+export const setSelectedSiteId = (siteId) => {
+  try {
+    if (siteId) {
+      localStorage.setItem(SITE_STORAGE_KEY, siteId);
+    } else {
+      localStorage.removeItem(SITE_STORAGE_KEY);
+    }
+  } catch (error) {
+    console.error('Ошибка сохранения площадки:', error);
+  }
+};
@@ -203,6 +203,7 @@ export const calculateSessionTotalCost = (sessionPayments) => {
     
     const mainPayment = sessionPayments.main_payment;
     const extensionPayments = sessionPayments.extension_payments || [];
+    const queueJumpPayments = sessionPayments.queue_jump_payments || [];
     
     let totalAmount = 0;
     let totalRefunded = 0;
@@ -235,6 +236,20 @@ export const calculateSessionTotalCost = (sessionPayments) => {
         }
     });
     
+    // Добавляем доплату за пропуск очереди (с учетом автоматического возврата)
+    queueJumpPayments.forEach((payment) => {
+        if (payment.status === 'succeeded' || payment.status === 'refunded') {
+            totalAmount += payment.amount || 0;
+            totalRefunded += payment.refunded_amount || 0;
+            breakdown.push({
+                type: 'queue_jump',
+                description: 'Пропуск очереди',
+                amount: payment.amount || 0,
+                refunded: payment.refunded_amount || 0
+            });
+        }
+    });
+    
     const finalAmount = totalAmount - totalRefunded;
     
     return {
@@ -312,6 +327,16 @@ export const formatSessionDetailedCost = (sessionPayments) => {
         });
     }
     
+    // Пропуск очереди
+    const queueJumpPayment = cost.breakdown.find(p => p.type === 'queue_jump');
+    if (queueJumpPayment) {
+        details.push({
+            label: 'Пропуск очереди',
+            value: formatAmount(queueJumpPayment.amount),
+            refunded: queueJumpPayment.refunded > 0 ? formatAmount(queueJumpPayment.refunded) : null
+        });
+    }
+    
     // Общая стоимость
     let totalCost = formatAmount(cost.totalAmount);
     if (cost.totalRefunded > 0) {